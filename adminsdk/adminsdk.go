@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package adminsdk integrates the configtx package with fabric-admin-sdk.
+//
+// Channel genesis blocks, config blocks and config update envelopes are
+// fabric-protos-go messages in both libraries and can be passed directly,
+// e.g. a block from configtx.NewApplicationChannelGenesisBlock to the
+// admin SDK's peer channel join. The helpers here bridge the signing
+// identities: a configtx.SigningIdentity can be used wherever the admin SDK
+// expects an identity.SigningIdentity, and admin SDK identities can sign
+// config updates and envelopes built with configtx.
+package adminsdk
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// SigningIdentity has the method set of the identity.SigningIdentity
+// interface of fabric-admin-sdk, so values can be passed between the two
+// packages without this package depending on the admin SDK.
+type SigningIdentity interface {
+	// MspID returns the MSP ID of the identity.
+	MspID() string
+	// Credentials returns the PEM encoded certificate of the identity.
+	Credentials() []byte
+	// Sign signs the message.
+	Sign(message []byte) ([]byte, error)
+}
+
+type signingIdentity struct {
+	signer *configtx.SigningIdentity
+}
+
+// NewSigningIdentity returns an admin SDK signing identity that signs with
+// the given configtx signing identity.
+func NewSigningIdentity(signer *configtx.SigningIdentity) SigningIdentity {
+	return &signingIdentity{signer: signer}
+}
+
+func (s *signingIdentity) MspID() string {
+	return s.signer.MSPID
+}
+
+func (s *signingIdentity) Credentials() []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.signer.Certificate.Raw,
+	})
+}
+
+func (s *signingIdentity) Sign(message []byte) ([]byte, error) {
+	return s.signer.Sign(rand.Reader, message, nil)
+}
+
+// CreateConfigSignature creates a config signature for the given config
+// update, e.g. the output of ConfigTx.ComputeMarshaledUpdate, using an
+// admin SDK signing identity. The signature can be passed to
+// configtx.NewEnvelope.
+func CreateConfigSignature(id SigningIdentity, marshaledUpdate []byte) (*cb.ConfigSignature, error) {
+	header, err := signatureHeader(id)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := id.Sign(append(append([]byte{}, header...), marshaledUpdate...))
+	if err != nil {
+		return nil, fmt.Errorf("signing config update: %w", err)
+	}
+
+	return &cb.ConfigSignature{
+		SignatureHeader: header,
+		Signature:       signature,
+	}, nil
+}
+
+// SignEnvelope signs an envelope, e.g. the output of configtx.NewEnvelope,
+// using an admin SDK signing identity so it can be submitted to the
+// ordering service.
+func SignEnvelope(id SigningIdentity, e *cb.Envelope) error {
+	header, err := signatureHeader(id)
+	if err != nil {
+		return err
+	}
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(e.Payload, payload)
+	if err != nil {
+		return fmt.Errorf("unmarshaling envelope payload: %w", err)
+	}
+
+	if payload.Header == nil {
+		return errors.New("envelope payload header is missing")
+	}
+	payload.Header.SignatureHeader = header
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling payload: %w", err)
+	}
+
+	signature, err := id.Sign(payloadBytes)
+	if err != nil {
+		return fmt.Errorf("signing envelope payload: %w", err)
+	}
+
+	e.Payload = payloadBytes
+	e.Signature = signature
+
+	return nil
+}
+
+// signatureHeader returns the marshaled signature header of the identity.
+func signatureHeader(id SigningIdentity) ([]byte, error) {
+	creator, err := proto.Marshal(&mb.SerializedIdentity{
+		Mspid:   id.MspID(),
+		IdBytes: id.Credentials(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling serialized identity: %w", err)
+	}
+
+	nonce := make([]byte, 24)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get random bytes: %w", err)
+	}
+
+	header, err := proto.Marshal(&cb.SignatureHeader{
+		Creator: creator,
+		Nonce:   nonce,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling signature header: %w", err)
+	}
+
+	return header, nil
+}
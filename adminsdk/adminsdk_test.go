@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package adminsdk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewSigningIdentity(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	signer, privKey := newSigningIdentity(t)
+	id := NewSigningIdentity(signer)
+
+	gt.Expect(id.MspID()).To(Equal("Org1MSP"))
+
+	block, _ := pem.Decode(id.Credentials())
+	gt.Expect(block).NotTo(BeNil())
+	gt.Expect(block.Bytes).To(Equal(signer.Certificate.Raw))
+
+	signature, err := id.Sign([]byte("message"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	digest := sha256.Sum256([]byte("message"))
+	gt.Expect(verify(t, &privKey.PublicKey, digest[:], signature)).To(BeTrue())
+}
+
+func TestCreateConfigSignature(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	signer, privKey := newSigningIdentity(t)
+	marshaledUpdate := protoMarshal(t, &cb.ConfigUpdate{ChannelId: "testchannel"})
+
+	configSignature, err := CreateConfigSignature(NewSigningIdentity(signer), marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectCreator(t, configSignature.SignatureHeader, signer)
+
+	digest := sha256.Sum256(append(append([]byte{}, configSignature.SignatureHeader...), marshaledUpdate...))
+	gt.Expect(verify(t, &privKey.PublicKey, digest[:], configSignature.Signature)).To(BeTrue())
+
+	_, err = configtx.NewEnvelope(marshaledUpdate, configSignature)
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestSignEnvelope(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	signer, privKey := newSigningIdentity(t)
+	envelope, err := configtx.NewEnvelope(protoMarshal(t, &cb.ConfigUpdate{ChannelId: "testchannel"}))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = SignEnvelope(NewSigningIdentity(signer), envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	expectCreator(t, payload.Header.SignatureHeader, signer)
+
+	digest := sha256.Sum256(envelope.Payload)
+	gt.Expect(verify(t, &privKey.PublicKey, digest[:], envelope.Signature)).To(BeTrue())
+
+	err = SignEnvelope(NewSigningIdentity(signer), &cb.Envelope{})
+	gt.Expect(err).To(MatchError("envelope payload header is missing"))
+}
+
+func expectCreator(t *testing.T, signatureHeaderBytes []byte, signer *configtx.SigningIdentity) {
+	gt := NewGomegaWithT(t)
+
+	signatureHeader := &cb.SignatureHeader{}
+	err := proto.Unmarshal(signatureHeaderBytes, signatureHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signatureHeader.Nonce).To(HaveLen(24))
+
+	creator := &mb.SerializedIdentity{}
+	err = proto.Unmarshal(signatureHeader.Creator, creator)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(creator.Mspid).To(Equal(signer.MSPID))
+	gt.Expect(creator.IdBytes).To(Equal(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: signer.Certificate.Raw})))
+}
+
+func verify(t *testing.T, pubKey *ecdsa.PublicKey, digest, signature []byte) bool {
+	gt := NewGomegaWithT(t)
+
+	sig := struct{ R, S *big.Int }{}
+	_, err := asn1.Unmarshal(signature, &sig)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return ecdsa.Verify(pubKey, digest, sig.R, sig.S)
+}
+
+func newSigningIdentity(t *testing.T) (*configtx.SigningIdentity, *ecdsa.PrivateKey) {
+	gt := NewGomegaWithT(t)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin.org1.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(derBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return &configtx.SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privKey,
+		MSPID:       "Org1MSP",
+	}, privKey
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	gt := NewGomegaWithT(t)
+
+	b, err := proto.Marshal(msg)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return b
+}
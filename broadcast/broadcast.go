@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package broadcast provides a client that submits envelopes, such as
+// signed config updates, to the AtomicBroadcast service of an ordering
+// service node.
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+)
+
+// Stream is a Broadcast stream to an ordering service node. The stream
+// returned by the gRPC AtomicBroadcast client implements it.
+type Stream interface {
+	Send(*cb.Envelope) error
+	Recv() (*ob.BroadcastResponse, error)
+	CloseSend() error
+}
+
+// Broadcaster opens Broadcast streams to an ordering service node.
+type Broadcaster interface {
+	Broadcast(ctx context.Context) (Stream, error)
+}
+
+// BroadcasterFunc is a function that implements Broadcaster.
+type BroadcasterFunc func(ctx context.Context) (Stream, error)
+
+// Broadcast calls f(ctx).
+func (f BroadcasterFunc) Broadcast(ctx context.Context) (Stream, error) {
+	return f(ctx)
+}
+
+// FromAtomicBroadcastClient returns a Broadcaster that opens streams with
+// the gRPC client, e.g. one created with ob.NewAtomicBroadcastClient.
+func FromAtomicBroadcastClient(client ob.AtomicBroadcastClient) Broadcaster {
+	return BroadcasterFunc(func(ctx context.Context) (Stream, error) {
+		return client.Broadcast(ctx)
+	})
+}
+
+// StatusError is returned when the ordering service node responds to a
+// submission with a status other than SUCCESS.
+type StatusError struct {
+	// Status is the status returned by the node.
+	Status cb.Status
+	// Info is the additional information returned by the node, which
+	// usually describes why the envelope was rejected.
+	Info string
+}
+
+// Error returns the status and the info string returned by the node.
+func (e *StatusError) Error() string {
+	if e.Info == "" {
+		return fmt.Sprintf("orderer responded with status %s", e.Status)
+	}
+
+	return fmt.Sprintf("orderer responded with status %s: %s", e.Status, e.Info)
+}
+
+// Transient reports whether the status indicates that the node could not
+// process the envelope at the moment, e.g. because a consensus leader is
+// being elected, so that submitting it again may succeed. Other statuses,
+// such as BAD_REQUEST and FORBIDDEN, reject the envelope itself.
+func (e *StatusError) Transient() bool {
+	return e.Status == cb.Status_SERVICE_UNAVAILABLE
+}
+
+// ConnectionError is returned when the Broadcast stream to the ordering
+// service node cannot be opened or fails before the node responds.
+type ConnectionError struct {
+	Err error
+}
+
+// Error returns the message of the underlying error.
+func (e *ConnectionError) Error() string {
+	return fmt.Sprintf("broadcast stream failed: %s", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConnectionError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err, as returned by Client.Submit, is a
+// failure that may not occur when the envelope is submitted again: a
+// SERVICE_UNAVAILABLE status or a failed connection to the node.
+func IsTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Transient()
+	}
+
+	var connErr *ConnectionError
+	return errors.As(err, &connErr)
+}
+
+// RetryPolicy controls how often and how quickly Client.Submit resubmits an
+// envelope after a transient failure. The backoff before each retry starts
+// at InitialBackoff and is multiplied by Multiplier after every retry, up
+// to MaxBackoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of submission attempts. Values less
+	// than two disable retries.
+	MaxAttempts int
+	// InitialBackoff is the backoff before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the backoff. Zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the backoff grows after each
+	// retry. Values less than one keep the backoff constant.
+	Multiplier float64
+}
+
+// DefaultRetryPolicy is a retry policy suited to riding out a consensus
+// leader election.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Multiplier:     2,
+}
+
+// next returns the backoff that follows backoff.
+func (p RetryPolicy) next(backoff time.Duration) time.Duration {
+	if p.Multiplier > 1 {
+		backoff = time.Duration(float64(backoff) * p.Multiplier)
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+
+	return backoff
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetry makes the client resubmit envelopes after transient failures
+// according to the policy. By default envelopes are submitted once.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// Client submits envelopes to an ordering service node.
+type Client struct {
+	broadcaster Broadcaster
+	retry       RetryPolicy
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient returns a client that submits envelopes over streams opened by
+// the broadcaster.
+func NewClient(broadcaster Broadcaster, opts ...Option) *Client {
+	c := &Client{
+		broadcaster: broadcaster,
+		sleep:       sleep,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Submit sends the envelope to the ordering service node and waits for its
+// response. If the node does not accept the envelope, the returned error
+// wraps a StatusError carrying the node's status and info string; if the
+// stream fails, it wraps a ConnectionError. Transient failures are retried
+// according to the client's retry policy.
+func (c *Client) Submit(ctx context.Context, env *cb.Envelope) error {
+	if env == nil {
+		return errors.New("envelope is required")
+	}
+
+	backoff := c.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.submit(ctx, env)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || !IsTransient(err) || attempt >= c.retry.MaxAttempts {
+			if attempt > 1 {
+				return fmt.Errorf("submitting envelope failed after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+
+		err = c.sleep(ctx, backoff)
+		if err != nil {
+			return fmt.Errorf("submitting envelope failed after %d attempts: %w", attempt, err)
+		}
+
+		backoff = c.retry.next(backoff)
+	}
+}
+
+// submit makes a single submission attempt. The stream of the attempt is
+// opened with its own context, which is canceled when the attempt ends so
+// that the stream is released before the next attempt.
+func (c *Client) submit(ctx context.Context, env *cb.Envelope) error {
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.broadcaster.Broadcast(attemptCtx)
+	if err != nil {
+		return &ConnectionError{Err: err}
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(env)
+	if err != nil {
+		return &ConnectionError{Err: err}
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return &ConnectionError{Err: err}
+	}
+
+	if resp.Status != cb.Status_SUCCESS {
+		return &StatusError{Status: resp.Status, Info: resp.Info}
+	}
+
+	return nil
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	. "github.com/onsi/gomega"
+)
+
+// fakeStream responds to sent envelopes with response, or fails with err if
+// it is not nil.
+type fakeStream struct {
+	response *ob.BroadcastResponse
+	err      error
+	sent     []*cb.Envelope
+}
+
+func (s *fakeStream) Send(env *cb.Envelope) error {
+	s.sent = append(s.sent, env)
+	return nil
+}
+
+func (s *fakeStream) Recv() (*ob.BroadcastResponse, error) {
+	return s.response, s.err
+}
+
+func (s *fakeStream) CloseSend() error {
+	return nil
+}
+
+// fakeBroadcaster returns the streams in order and records the contexts
+// they were opened with.
+type fakeBroadcaster struct {
+	streams  []*fakeStream
+	opened   int
+	contexts []context.Context
+}
+
+func (b *fakeBroadcaster) Broadcast(ctx context.Context) (Stream, error) {
+	stream := b.streams[b.opened]
+	b.opened++
+	b.contexts = append(b.contexts, ctx)
+	return stream, nil
+}
+
+func statusStream(status cb.Status, info string) *fakeStream {
+	return &fakeStream{response: &ob.BroadcastResponse{Status: status, Info: info}}
+}
+
+func TestSubmit(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	env := &cb.Envelope{Payload: []byte("payload")}
+	stream := statusStream(cb.Status_SUCCESS, "")
+	client := NewClient(&fakeBroadcaster{streams: []*fakeStream{stream}})
+
+	err := client.Submit(context.Background(), env)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(stream.sent).To(Equal([]*cb.Envelope{env}))
+}
+
+func TestSubmitRetry(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	broadcaster := &fakeBroadcaster{
+		streams: []*fakeStream{
+			statusStream(cb.Status_SERVICE_UNAVAILABLE, "no Raft leader"),
+			{err: errors.New("connection reset")},
+			statusStream(cb.Status_SUCCESS, ""),
+		},
+	}
+	client := NewClient(broadcaster, WithRetry(RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+		Multiplier:     4,
+	}))
+
+	var backoffs []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		backoffs = append(backoffs, d)
+		return nil
+	}
+
+	err := client.Submit(context.Background(), &cb.Envelope{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(broadcaster.opened).To(Equal(3))
+	gt.Expect(backoffs).To(Equal([]time.Duration{time.Second, 3 * time.Second}))
+	for _, ctx := range broadcaster.contexts {
+		gt.Expect(ctx.Err()).To(Equal(context.Canceled))
+	}
+}
+
+func TestSubmitFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		streams     []*fakeStream
+		maxAttempts int
+		opened      int
+		transient   bool
+		expectedErr string
+	}{
+		{
+			name:        "permanent status is not retried",
+			streams:     []*fakeStream{statusStream(cb.Status_BAD_REQUEST, "config update for existing channel did not pass initial checks")},
+			maxAttempts: 3,
+			opened:      1,
+			transient:   false,
+			expectedErr: "orderer responded with status BAD_REQUEST: config update for existing channel did not pass initial checks",
+		},
+		{
+			name:        "transient status without retries",
+			streams:     []*fakeStream{statusStream(cb.Status_SERVICE_UNAVAILABLE, "no Raft leader")},
+			opened:      1,
+			transient:   true,
+			expectedErr: "orderer responded with status SERVICE_UNAVAILABLE: no Raft leader",
+		},
+		{
+			name: "retries exhausted",
+			streams: []*fakeStream{
+				statusStream(cb.Status_SERVICE_UNAVAILABLE, "no Raft leader"),
+				{err: errors.New("connection reset")},
+			},
+			maxAttempts: 2,
+			opened:      2,
+			transient:   true,
+			expectedErr: "submitting envelope failed after 2 attempts: broadcast stream failed: connection reset",
+		},
+		{
+			name: "permanent status after retry",
+			streams: []*fakeStream{
+				statusStream(cb.Status_SERVICE_UNAVAILABLE, ""),
+				statusStream(cb.Status_FORBIDDEN, "implicit policy evaluation failed"),
+			},
+			maxAttempts: 3,
+			opened:      2,
+			transient:   false,
+			expectedErr: "submitting envelope failed after 2 attempts: orderer responded with status FORBIDDEN: implicit policy evaluation failed",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			broadcaster := &fakeBroadcaster{streams: tc.streams}
+			client := NewClient(broadcaster, WithRetry(RetryPolicy{MaxAttempts: tc.maxAttempts}))
+			client.sleep = func(context.Context, time.Duration) error { return nil }
+
+			err := client.Submit(context.Background(), &cb.Envelope{})
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(IsTransient(err)).To(Equal(tc.transient))
+			gt.Expect(broadcaster.opened).To(Equal(tc.opened))
+		})
+	}
+}
+
+func TestSubmitStatusError(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	client := NewClient(&fakeBroadcaster{streams: []*fakeStream{statusStream(cb.Status_BAD_REQUEST, "bad config")}})
+
+	err := client.Submit(context.Background(), &cb.Envelope{})
+
+	var statusErr *StatusError
+	gt.Expect(errors.As(err, &statusErr)).To(BeTrue())
+	gt.Expect(statusErr.Status).To(Equal(cb.Status_BAD_REQUEST))
+	gt.Expect(statusErr.Info).To(Equal("bad config"))
+}
+
+func TestSubmitContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	broadcaster := &fakeBroadcaster{
+		streams: []*fakeStream{statusStream(cb.Status_SERVICE_UNAVAILABLE, "")},
+	}
+	client := NewClient(broadcaster, WithRetry(DefaultRetryPolicy))
+
+	err := client.Submit(ctx, &cb.Envelope{})
+	gt.Expect(err).To(MatchError("orderer responded with status SERVICE_UNAVAILABLE"))
+	gt.Expect(broadcaster.opened).To(Equal(1))
+}
+
+func TestSubmitNilEnvelope(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	err := NewClient(&fakeBroadcaster{}).Submit(context.Background(), nil)
+	gt.Expect(err).To(MatchError("envelope is required"))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// The path elements that separate the path of a group from the name of
+// one of its values or policies in a config path.
+const (
+	valuesPathElement   = "Values"
+	policiesPathElement = "Policies"
+)
+
+// ValueAt returns a copy of the value at path in the updated config, which
+// addresses the value by the path of its group and its key, e.g.
+// /Channel/Application/Org1/Values/AnchorPeers for the value read by
+// ConfigValue(msg, "Application", "Org1", "AnchorPeers"). The value can be
+// decoded by unmarshaling its Value into the message of its type, e.g.
+// pb.AnchorPeers.
+func (c *ConfigTx) ValueAt(path string) (*cb.ConfigValue, error) {
+	elements, err := parseValuePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := configValueAtPath(c.updated.ChannelGroup, elements)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Clone(value).(*cb.ConfigValue), nil
+}
+
+// SetValueAt sets the value at path, e.g.
+// /Channel/Application/Org1/Values/AnchorPeers, in the updated config to
+// the message, which is added if it does not exist. The group of the value
+// must exist. An empty mod policy keeps the mod policy of an existing
+// value and is invalid for a new value. SetValueAt does not validate the
+// message, so it can set values that are not modeled by this package.
+func (c *ConfigTx) SetValueAt(path string, value proto.Message, modPolicy string) error {
+	if value == nil {
+		return errors.New("value is required")
+	}
+
+	elements, err := parseValuePath(path)
+	if err != nil {
+		return err
+	}
+
+	groupPath, key := elements[:len(elements)-1], elements[len(elements)-1]
+	group, err := configGroupAtPath(c.updated.ChannelGroup, groupPath)
+	if err != nil {
+		return err
+	}
+
+	if modPolicy == "" {
+		existing, ok := group.Values[key]
+		if !ok {
+			return fmt.Errorf("mod policy is required for new value %s", path)
+		}
+		modPolicy = existing.ModPolicy
+	}
+
+	err = setValue(group, &standardConfigValue{key: key, value: value}, modPolicy)
+	if err != nil {
+		return err
+	}
+
+	c.notify(valuePath(groupPath), "SetValueAt")
+
+	return nil
+}
+
+// RemoveValueAt removes the value at path, e.g.
+// /Channel/Application/Org1/Values/AnchorPeers, from the updated config.
+func (c *ConfigTx) RemoveValueAt(path string) error {
+	elements, err := parseValuePath(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = configValueAtPath(c.updated.ChannelGroup, elements)
+	if err != nil {
+		return err
+	}
+
+	groupPath, key := elements[:len(elements)-1], elements[len(elements)-1]
+	group, _ := configGroupAtPath(c.updated.ChannelGroup, groupPath)
+	delete(group.Values, key)
+
+	c.notify(valuePath(groupPath), "RemoveValueAt")
+
+	return nil
+}
+
+// parseValuePath returns the path elements of the value at path in the
+// form taken by ConfigValue: the groups beneath the channel group followed
+// by the value key.
+func parseValuePath(path string) ([]string, error) {
+	element, elements, err := parseConfigPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if element != ElementValue {
+		return nil, fmt.Errorf("invalid value path '%s': must be of the form /%s/<group>.../%s/<key>", path, ChannelGroupKey, valuesPathElement)
+	}
+
+	return elements, nil
+}
+
+// elementPath returns the config path of the value or policy name of the
+// group at groupPath, in the form parsed by parseConfigPath.
+func elementPath(groupPath string, element ElementType, name string) string {
+	if element == ElementPolicy {
+		return groupPath + "/" + policiesPathElement + "/" + name
+	}
+
+	return groupPath + "/" + valuesPathElement + "/" + name
+}
+
+// parseConfigPath returns the type of the element at path and its path
+// elements beneath the channel group. The path of a group lists its
+// groups, e.g. /Channel/Application, and the path of a value or policy
+// adds a Values or Policies element and its name to the path of its
+// group, e.g. /Channel/Orderer/Values/BatchSize. The path elements of a
+// value or policy are the groups followed by its name.
+func parseConfigPath(path string) (ElementType, []string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", nil, fmt.Errorf("invalid config path '%s': must be an absolute config path", path)
+	}
+
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for _, element := range elements {
+		if element == "" {
+			return "", nil, fmt.Errorf("invalid config path '%s': empty path element", path)
+		}
+	}
+
+	if elements[0] != ChannelGroupKey {
+		return "", nil, fmt.Errorf("path must start with %s", ChannelGroupKey)
+	}
+
+	if len(elements) >= 3 {
+		name := elements[len(elements)-1]
+		groupPath := elements[1 : len(elements)-2]
+		switch elements[len(elements)-2] {
+		case valuesPathElement:
+			return ElementValue, append(groupPath, name), nil
+		case policiesPathElement:
+			return ElementPolicy, append(groupPath, name), nil
+		}
+	}
+
+	return ElementGroup, elements[1:], nil
+}
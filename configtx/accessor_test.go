@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestValueAt(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	value, err := c.ValueAt("/Channel/Orderer/Values/BatchSize")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(value.ModPolicy).To(Equal(AdminsPolicyKey))
+
+	batchSize := &ob.BatchSize{}
+	err = proto.Unmarshal(value.Value, batchSize)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(batchSize.MaxMessageCount).To(Equal(uint32(100)))
+
+	// The returned value is a copy
+	value.ModPolicy = "Readers"
+	gt.Expect(channelGroup.Groups[OrdererGroupKey].Values[orderer.BatchSizeKey].ModPolicy).To(Equal(AdminsPolicyKey))
+}
+
+func TestSetValueAt(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	var mutations []Mutation
+	c := New(&cb.Config{ChannelGroup: channelGroup}, WithObserver(func(m Mutation) { mutations = append(mutations, m) }))
+
+	err = c.SetValueAt("/Channel/Orderer/Values/BatchSize", &ob.BatchSize{MaxMessageCount: 20}, "")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.SetValueAt("/Channel/Orderer/OrdererOrg/Values/Custom", &cb.Consortium{Name: "custom"}, "/Channel/Orderer/Admins")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchSize.MaxMessageCount).To(Equal(uint32(20)))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Values[orderer.BatchSizeKey].ModPolicy).To(Equal(AdminsPolicyKey))
+
+	value, err := c.ValueAt("/Channel/Orderer/OrdererOrg/Values/Custom")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(value.ModPolicy).To(Equal("/Channel/Orderer/Admins"))
+	gt.Expect(value.Value).To(Equal(marshalOrPanic(&cb.Consortium{Name: "custom"})))
+
+	err = c.RemoveValueAt("/Channel/Orderer/OrdererOrg/Values/Custom")
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.ValueAt("/Channel/Orderer/OrdererOrg/Values/Custom")
+	gt.Expect(err).To(MatchError("config value /Channel/Orderer/OrdererOrg/Custom does not exist"))
+
+	gt.Expect(mutations).To(Equal([]Mutation{
+		{Path: "/Channel/Orderer", Operation: "SetValueAt"},
+		{Path: "/Channel/Orderer/OrdererOrg", Operation: "SetValueAt"},
+		{Path: "/Channel/Orderer/OrdererOrg", Operation: "RemoveValueAt"},
+	}))
+}
+
+func TestValueAtFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		path        string
+		expectedErr string
+	}{
+		{
+			testName:    "relative path",
+			path:        "Channel/Orderer/Values/BatchSize",
+			expectedErr: "invalid config path 'Channel/Orderer/Values/BatchSize': must be an absolute config path",
+		},
+		{
+			testName:    "empty element",
+			path:        "/Channel//Values/BatchSize",
+			expectedErr: "invalid config path '/Channel//Values/BatchSize': empty path element",
+		},
+		{
+			testName:    "missing values element",
+			path:        "/Channel/Orderer/BatchSize",
+			expectedErr: "invalid value path '/Channel/Orderer/BatchSize': must be of the form /Channel/<group>.../Values/<key>",
+		},
+		{
+			testName:    "path outside of channel group",
+			path:        "/Orderer/Values/BatchSize",
+			expectedErr: "path must start with Channel",
+		},
+		{
+			testName:    "missing group",
+			path:        "/Channel/Application/Values/ACLs",
+			expectedErr: "config group /Channel/Application does not exist",
+		},
+		{
+			testName:    "missing value",
+			path:        "/Channel/Orderer/Values/Missing",
+			expectedErr: "config value /Channel/Orderer/Missing does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			_, err = c.ValueAt(tc.path)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+			err = c.RemoveValueAt(tc.path)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestSetValueAtFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.SetValueAt("/Channel/Orderer/Values/Custom", &cb.Consortium{}, "")
+	gt.Expect(err).To(MatchError("mod policy is required for new value /Channel/Orderer/Values/Custom"))
+	err = c.SetValueAt("/Channel/Orderer/Values/Custom", nil, AdminsPolicyKey)
+	gt.Expect(err).To(MatchError("value is required"))
+	err = c.SetValueAt("/Channel/Orderer/Missing/Values/Custom", &cb.Consortium{}, AdminsPolicyKey)
+	gt.Expect(err).To(MatchError("config group /Channel/Orderer/Missing does not exist"))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// hostProfile maps host names like a DNS lookup would, lower casing them
+// and encoding internationalized names as punycode, but allows the
+// underscores found in the host names of some container networks.
+var hostProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false))
+
+// normalizeHost returns the canonical form of a host name, so that anchor
+// peers and endpoints that only differ cosmetically compare equal: it is
+// lower cased, stripped of the trailing dot of fully qualified names and
+// internationalized names are encoded as punycode. IP addresses are
+// returned in their canonical form.
+func normalizeHost(host string) (string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.String(), nil
+	}
+
+	trimmed := strings.TrimSuffix(host, ".")
+	if trimmed == "" {
+		return host, nil
+	}
+
+	normalized, err := hostProfile.ToASCII(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("invalid host %s: %w", host, err)
+	}
+
+	if normalized == "" {
+		return "", fmt.Errorf("invalid host %q", host)
+	}
+
+	return normalized, nil
+}
+
+// sameAddress reports whether two host:port addresses are equal once
+// their hosts are normalized. Addresses that cannot be parsed are compared
+// as is.
+func sameAddress(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	normalizedA, err := parseEndpointAddress(a)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := parseEndpointAddress(b)
+	if err != nil {
+		return false
+	}
+
+	return normalizedA == normalizedB
+}
+
+// sameHost reports whether two host names are equal once normalized.
+func sameHost(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	normalizedA, err := normalizeHost(a)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := normalizeHost(b)
+	if err != nil {
+		return false
+	}
+
+	return normalizedA == normalizedB
+}
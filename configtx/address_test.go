@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	. "github.com/onsi/gomega"
+)
+
+func TestNormalizeHost(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{host: "peer0.org1.example.com", expected: "peer0.org1.example.com"},
+		{host: "Peer0.Org1.Example.COM", expected: "peer0.org1.example.com"},
+		{host: "peer0.org1.example.com.", expected: "peer0.org1.example.com"},
+		{host: "peer0.bücher.example", expected: "peer0.xn--bcher-kva.example"},
+		{host: "PEER0.BÜCHER.EXAMPLE.", expected: "peer0.xn--bcher-kva.example"},
+		{host: "peer0_org1", expected: "peer0_org1"},
+		{host: "10.0.0.1", expected: "10.0.0.1"},
+		{host: "2001:DB8::1", expected: "2001:db8::1"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.host, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			host, err := normalizeHost(tc.host)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(host).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestAnchorPeerHostNormalization(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	org := c.Application().Organization("Org1")
+
+	err = org.AddAnchorPeer(Address{Host: "Peer0.Org1.Example.COM.", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = org.AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = org.AddAnchorPeer(Address{Host: "peer1.bücher.example", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	anchorPeers, err := org.AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(Equal([]Address{
+		{Host: "peer0.org1.example.com", Port: 7051},
+		{Host: "peer1.xn--bcher-kva.example", Port: 7051},
+	}))
+
+	err = org.AddAnchorPeer(Address{Host: "-peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).To(MatchError(`invalid host -peer0.org1.example.com: idna: invalid label "-peer0"`))
+	err = org.AddAnchorPeer(Address{Host: "\u00ad", Port: 7051})
+	gt.Expect(err).To(MatchError(`invalid host "\u00ad"`))
+
+	// Anchor peers set before normalization are matched regardless of case.
+	orgGroup := c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+	err = setValue(orgGroup, anchorPeersValue([]*pb.AnchorPeer{{Host: "PEER2.org1.example.com", Port: 7051}}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = org.RemoveAnchorPeer(Address{Host: "peer2.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	anchorPeers, err = org.AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(BeEmpty())
+}
+
+func TestOrdererEndpointHostNormalization(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, _, _ := basePlanReplaceOrdererOrg(t)
+
+	err := c.Orderer().Organization("OrdererOrg").SetEndpoint(Address{Host: "Orderer.Example.COM.", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().Organization("OrdererOrg").SetEndpoint(Address{Host: "orderer.example.com", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().AddOrdererEndpoint("OrdererOrg", "ORDERER.example.com:7050")
+	gt.Expect(err).To(MatchError("endpoint orderer.example.com:7050 already exists in orderer org OrdererOrg"))
+	err = c.Orderer().AddOrdererEndpoint("OrdererOrg", "orderer.bücher.example.:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orderer, err := c.Orderer().Organization("OrdererOrg").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orderer.OrdererEndpoints).To(Equal([]string{
+		"localhost:123",
+		"orderer.example.com:7050",
+		"orderer.xn--bcher-kva.example:7050",
+	}))
+
+	err = c.Orderer().RemoveOrdererEndpoint("OrdererOrg", "Orderer.Bücher.Example:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().Organization("OrdererOrg").RemoveEndpoint(Address{Host: "ORDERER.EXAMPLE.COM", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orderer, err = c.Orderer().Organization("OrdererOrg").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orderer.OrdererEndpoints).To(Equal([]string{"localhost:123"}))
+
+	err = c.Channel().AddLegacyOrdererAddress("Other.Example.com:7050")
+	gt.Expect(err).To(MatchError("orderer address other.example.com:7050 already exists"))
+	err = c.Channel().RemoveLegacyOrdererAddress("OTHER.example.com.:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
@@ -9,6 +9,8 @@ package configtx
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -22,12 +24,17 @@ type Application struct {
 	Capabilities  []string
 	Policies      map[string]Policy
 	ACLs          map[string]string
+	// Extensions are the custom values of the application group, keyed by
+	// value key. Only the values whose message types are registered with
+	// WithValueTypes are decoded.
+	Extensions map[string]proto.Message
 }
 
 // ApplicationGroup encapsulates the part of the config that controls
 // application channels.
 type ApplicationGroup struct {
 	applicationGroup *cb.ConfigGroup
+	tx               *ConfigTx
 }
 
 // ApplicationOrg encapsulates the parts of the config that control
@@ -35,19 +42,22 @@ type ApplicationGroup struct {
 type ApplicationOrg struct {
 	orgGroup *cb.ConfigGroup
 	name     string
+	tx       *ConfigTx
 }
 
 // MSP returns an OrganizationMSP object that can be used to configure the organization's MSP.
 func (a *ApplicationOrg) MSP() *OrganizationMSP {
 	return &OrganizationMSP{
 		configGroup: a.orgGroup,
+		path:        a.path(),
+		tx:          a.tx,
 	}
 }
 
 // Application returns the application group the updated config.
 func (c *ConfigTx) Application() *ApplicationGroup {
 	applicationGroup := c.updated.ChannelGroup.Groups[ApplicationGroupKey]
-	return &ApplicationGroup{applicationGroup: applicationGroup}
+	return &ApplicationGroup{applicationGroup: applicationGroup, tx: c}
 }
 
 // Organization returns the application org from the updated config.
@@ -56,20 +66,22 @@ func (a *ApplicationGroup) Organization(name string) *ApplicationOrg {
 	if !ok {
 		return nil
 	}
-	return &ApplicationOrg{name: name, orgGroup: organizationGroup}
+	return &ApplicationOrg{name: name, orgGroup: organizationGroup, tx: a.tx}
 }
 
 // SetOrganization sets the organization config group for the given application
 // org key in an existing Application configuration's Groups map.
 // If the application org already exists in the current configuration, its value will be overwritten.
 func (a *ApplicationGroup) SetOrganization(org Organization) error {
-	orgGroup, err := newApplicationOrgConfigGroup(org)
+	orgGroup, err := newApplicationOrgConfigGroup(org, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create application org %s: %v", org.Name, err)
+		return fmt.Errorf("failed to create application org %s: %w", org.Name, err)
 	}
 
 	a.applicationGroup.Groups[org.Name] = orgGroup
 
+	a.tx.notify(a.path(), "SetOrganization")
+
 	return nil
 }
 
@@ -77,18 +89,28 @@ func (a *ApplicationGroup) SetOrganization(org Organization) error {
 // Removal will panic if the application group does not exist.
 func (a *ApplicationGroup) RemoveOrganization(orgName string) {
 	delete(a.applicationGroup.Groups, orgName)
+
+	a.tx.notify(a.path(), "RemoveOrganization")
 }
 
 // Configuration returns the existing application configuration values from a config
 // transaction as an Application type. This can be used to retrieve existing values for the application
 // prior to updating the application configuration.
 func (a *ApplicationGroup) Configuration() (Application, error) {
+	if a.tx != nil {
+		return a.tx.cache.applicationConfiguration(a.configuration)
+	}
+
+	return a.configuration()
+}
+
+func (a *ApplicationGroup) configuration() (Application, error) {
 	var applicationOrgs []Organization
 	for orgName := range a.applicationGroup.Groups {
 		orgConfig, err := a.Organization(orgName).Configuration()
 
 		if err != nil {
-			return Application{}, fmt.Errorf("retrieving application org %s: %v", orgName, err)
+			return Application{}, fmt.Errorf("retrieving application org %s: %w", orgName, err)
 		}
 
 		applicationOrgs = append(applicationOrgs, orgConfig)
@@ -96,17 +118,22 @@ func (a *ApplicationGroup) Configuration() (Application, error) {
 
 	capabilities, err := a.Capabilities()
 	if err != nil {
-		return Application{}, fmt.Errorf("retrieving application capabilities: %v", err)
+		return Application{}, fmt.Errorf("retrieving application capabilities: %w", err)
 	}
 
 	policies, err := a.Policies()
 	if err != nil {
-		return Application{}, fmt.Errorf("retrieving application policies: %v", err)
+		return Application{}, fmt.Errorf("retrieving application policies: %w", err)
 	}
 
 	acls, err := a.ACLs()
 	if err != nil {
-		return Application{}, fmt.Errorf("retrieving application acls: %v", err)
+		return Application{}, fmt.Errorf("retrieving application acls: %w", err)
+	}
+
+	extensions, err := getExtensions(a.applicationGroup, a.tx.valueTypes())
+	if err != nil {
+		return Application{}, fmt.Errorf("retrieving application extensions: %w", err)
 	}
 
 	return Application{
@@ -114,13 +141,14 @@ func (a *ApplicationGroup) Configuration() (Application, error) {
 		Capabilities:  capabilities,
 		Policies:      policies,
 		ACLs:          acls,
+		Extensions:    extensions,
 	}, nil
 }
 
 // Configuration returns the existing application org configuration values
 // from the updated config.
 func (a *ApplicationOrg) Configuration() (Organization, error) {
-	org, err := getOrganization(a.orgGroup, a.name)
+	org, err := getOrganization(a.orgGroup, a.name, a.tx.certificates())
 	if err != nil {
 		return Organization{}, err
 	}
@@ -132,7 +160,7 @@ func (a *ApplicationOrg) Configuration() (Organization, error) {
 func (a *ApplicationGroup) Capabilities() ([]string, error) {
 	capabilities, err := getCapabilities(a.applicationGroup)
 	if err != nil {
-		return nil, fmt.Errorf("retrieving application capabilities: %v", err)
+		return nil, fmt.Errorf("retrieving application capabilities: %w", err)
 	}
 
 	return capabilities, nil
@@ -152,6 +180,10 @@ func (a *ApplicationGroup) AddCapability(capability string) error {
 		return err
 	}
 
+	a.tx.checkCapabilityLevels()
+
+	a.tx.notify(a.path(), "AddCapability")
+
 	return nil
 }
 
@@ -167,6 +199,8 @@ func (a *ApplicationGroup) RemoveCapability(capability string) error {
 		return err
 	}
 
+	a.tx.notify(a.path(), "RemoveCapability")
+
 	return nil
 }
 
@@ -181,9 +215,13 @@ func (a *ApplicationGroup) Policies() (map[string]Policy, error) {
 func (a *ApplicationGroup) SetPolicy(modPolicy, policyName string, policy Policy) error {
 	err := setPolicy(a.applicationGroup, modPolicy, policyName, policy)
 	if err != nil {
-		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
+		return fmt.Errorf("failed to set policy '%s': %w", policyName, err)
 	}
 
+	a.tx.checkImplicitMetaPolicy(configPath(ChannelGroupKey, ApplicationGroupKey), a.applicationGroup, policyName, policy)
+
+	a.tx.notify(a.path(), "SetPolicy")
+
 	return nil
 }
 
@@ -196,9 +234,48 @@ func (a *ApplicationGroup) RemovePolicy(policyName string) error {
 	}
 
 	removePolicy(a.applicationGroup, policyName, policies)
+
+	a.tx.notify(a.path(), "RemovePolicy")
+
 	return nil
 }
 
+// MajorityOfOrgsPolicy returns an explicit signature policy requiring
+// signatures of identities with the given role from a majority of the
+// application orgs in the updated config. It can be used in place of a
+// MAJORITY ImplicitMeta policy.
+func (a *ApplicationGroup) MajorityOfOrgsPolicy(role string) (Policy, error) {
+	var mspIDs []string
+	for orgName, orgGroup := range a.applicationGroup.Groups {
+		msp, err := getMSPConfig(orgGroup, a.tx.certificates())
+		if err != nil {
+			return Policy{}, fmt.Errorf("retrieving MSP of application org %s: %w", orgName, err)
+		}
+		mspIDs = append(mspIDs, msp.Name)
+	}
+	sort.Strings(mspIDs)
+
+	policy, err := MajorityOfOrgs(role, mspIDs...)
+	if err != nil {
+		return Policy{}, fmt.Errorf("generating majority policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// RefreshMajorityOfOrgsPolicy recomputes the majority policy for the given
+// role from the current application orgs and sets it as policyName.
+// It should be called after application orgs are added or removed to keep
+// a policy created with MajorityOfOrgsPolicy in sync with the membership.
+func (a *ApplicationGroup) RefreshMajorityOfOrgsPolicy(modPolicy, policyName, role string) error {
+	policy, err := a.MajorityOfOrgsPolicy(role)
+	if err != nil {
+		return err
+	}
+
+	return a.SetPolicy(modPolicy, policyName, policy)
+}
+
 // Policies returns the map of policies for a specific application org in
 // the updated config..
 func (a *ApplicationOrg) Policies() (map[string]Policy, error) {
@@ -210,9 +287,13 @@ func (a *ApplicationOrg) Policies() (map[string]Policy, error) {
 func (a *ApplicationOrg) SetPolicy(modPolicy, policyName string, policy Policy) error {
 	err := setPolicy(a.orgGroup, modPolicy, policyName, policy)
 	if err != nil {
-		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
+		return fmt.Errorf("failed to set policy '%s': %w", policyName, err)
 	}
 
+	a.tx.checkImplicitMetaPolicy(configPath(ChannelGroupKey, ApplicationGroupKey, a.name), a.orgGroup, policyName, policy)
+
+	a.tx.notify(a.path(), "SetPolicy")
+
 	return nil
 }
 
@@ -224,6 +305,9 @@ func (a *ApplicationOrg) RemovePolicy(policyName string) error {
 	}
 
 	removePolicy(a.orgGroup, policyName, policies)
+
+	a.tx.notify(a.path(), "RemovePolicy")
+
 	return nil
 }
 
@@ -239,7 +323,7 @@ func (a *ApplicationOrg) AnchorPeers() ([]Address, error) {
 
 	err := proto.Unmarshal(anchorPeerConfigValue.Value, anchorPeersProto)
 	if err != nil {
-		return nil, fmt.Errorf("failed unmarshaling %s's anchor peer endpoints: %v", a.name, err)
+		return nil, fmt.Errorf("failed unmarshaling %s's anchor peer endpoints: %w", a.name, err)
 	}
 
 	if len(anchorPeersProto.AnchorPeers) == 0 {
@@ -258,7 +342,9 @@ func (a *ApplicationOrg) AnchorPeers() ([]Address, error) {
 }
 
 // AddAnchorPeer adds an anchor peer to an application org's configuration
-// in the updated config.
+// in the updated config. The host is normalized: it is lower cased,
+// stripped of a trailing dot and internationalized names are encoded as
+// punycode.
 func (a *ApplicationOrg) AddAnchorPeer(newAnchorPeer Address) error {
 	anchorPeersProto := &pb.AnchorPeers{}
 
@@ -266,30 +352,38 @@ func (a *ApplicationOrg) AddAnchorPeer(newAnchorPeer Address) error {
 		// Unmarshal existing anchor peers if the config value exists
 		err := proto.Unmarshal(anchorPeerConfigValue.Value, anchorPeersProto)
 		if err != nil {
-			return fmt.Errorf("failed unmarshaling anchor peer endpoints: %v", err)
+			return fmt.Errorf("failed unmarshaling anchor peer endpoints: %w", err)
 		}
 	}
 
+	host, err := normalizeHost(newAnchorPeer.Host)
+	if err != nil {
+		return err
+	}
+
 	// Persist existing anchor peers if found
 	anchorProtos := anchorPeersProto.AnchorPeers
 
 	for _, anchorPeer := range anchorProtos {
-		if anchorPeer.Host == newAnchorPeer.Host && anchorPeer.Port == int32(newAnchorPeer.Port) {
+		if sameHost(anchorPeer.Host, host) && anchorPeer.Port == int32(newAnchorPeer.Port) {
 			return nil
 		}
 	}
 
 	// Append new anchor peer to anchorProtos
 	anchorProtos = append(anchorProtos, &pb.AnchorPeer{
-		Host: newAnchorPeer.Host,
+		Host: host,
 		Port: int32(newAnchorPeer.Port),
 	})
 
 	// Add anchor peers config value back to application org
-	err := setValue(a.orgGroup, anchorPeersValue(anchorProtos), AdminsPolicyKey)
+	err = setValue(a.orgGroup, anchorPeersValue(anchorProtos), AdminsPolicyKey)
 	if err != nil {
 		return err
 	}
+
+	a.tx.notify(a.path(), "AddAnchorPeer")
+
 	return nil
 }
 
@@ -302,19 +396,19 @@ func (a *ApplicationOrg) RemoveAnchorPeer(anchorPeerToRemove Address) error {
 		// Unmarshal existing anchor peers if the config value exists
 		err := proto.Unmarshal(anchorPeerConfigValue.Value, anchorPeersProto)
 		if err != nil {
-			return fmt.Errorf("failed unmarshaling anchor peer endpoints for application org %s: %v", a.name, err)
+			return fmt.Errorf("failed unmarshaling anchor peer endpoints for application org %s: %w", a.name, err)
 		}
 	}
 
 	existingAnchorPeers := anchorPeersProto.AnchorPeers[:0]
 	for _, anchorPeer := range anchorPeersProto.AnchorPeers {
-		if anchorPeer.Host != anchorPeerToRemove.Host || anchorPeer.Port != int32(anchorPeerToRemove.Port) {
+		if !sameHost(anchorPeer.Host, anchorPeerToRemove.Host) || anchorPeer.Port != int32(anchorPeerToRemove.Port) {
 			existingAnchorPeers = append(existingAnchorPeers, anchorPeer)
 
 			// Add anchor peers config value back to application org
 			err := setValue(a.orgGroup, anchorPeersValue(existingAnchorPeers), AdminsPolicyKey)
 			if err != nil {
-				return fmt.Errorf("failed to remove anchor peer %v from org %s: %v", anchorPeerToRemove, a.name, err)
+				return fmt.Errorf("failed to remove anchor peer %v from org %s: %w", anchorPeerToRemove, a.name, err)
 			}
 
 			return nil
@@ -328,7 +422,87 @@ func (a *ApplicationOrg) RemoveAnchorPeer(anchorPeerToRemove Address) error {
 	// Add anchor peers config value back to application org
 	err := setValue(a.orgGroup, anchorPeersValue(existingAnchorPeers), AdminsPolicyKey)
 	if err != nil {
-		return fmt.Errorf("failed to remove anchor peer %v from org %s: %v", anchorPeerToRemove, a.name, err)
+		return fmt.Errorf("failed to remove anchor peer %v from org %s: %w", anchorPeerToRemove, a.name, err)
+	}
+
+	a.tx.notify(a.path(), "RemoveAnchorPeer")
+
+	return nil
+}
+
+// VerifyAnchorPeers verifies that the anchor peers of the application orgs
+// in the updated config are plausible, catching addresses copied from
+// another org: no anchor peer may be declared twice, whether by the same
+// org or by different orgs, or be an orderer endpoint of an orderer org or
+// a legacy orderer address of the channel. A warning is emitted for each
+// org without anchor peers, since the peers of other orgs cannot discover
+// its peers. The error wraps ValidationErrors with a finding for each
+// implausible anchor peer.
+func (a *ApplicationGroup) VerifyAnchorPeers() error {
+	endpointOwners := map[string]string{}
+
+	channelGroup := a.tx.updated.ChannelGroup
+	if ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]; ok {
+		for _, orgName := range sortedKeys(ordererGroup.Groups) {
+			endpoints, err := ordererAddresses(ordererGroup.Groups[orgName], EndpointsKey)
+			if err != nil {
+				return fmt.Errorf("retrieving endpoints of orderer org %s: %w", orgName, err)
+			}
+			for _, endpoint := range endpoints {
+				endpointOwners[strings.ToLower(endpoint)] = fmt.Sprintf("an orderer endpoint of orderer org %s", orgName)
+			}
+		}
+	}
+
+	legacyAddresses, err := ordererAddresses(channelGroup, OrdererAddressesKey)
+	if err != nil {
+		return fmt.Errorf("retrieving orderer addresses: %w", err)
+	}
+	for _, address := range legacyAddresses {
+		endpointOwners[strings.ToLower(address)] = "an orderer address of the channel"
+	}
+
+	var findings ValidationErrors
+	anchorPeerOwners := map[string]string{}
+
+	for _, orgName := range sortedKeys(a.applicationGroup.Groups) {
+		org := a.Organization(orgName)
+
+		anchorPeers, err := org.AnchorPeers()
+		if err != nil {
+			findings = append(findings, err)
+			continue
+		}
+
+		if len(anchorPeers) == 0 {
+			a.tx.warn(Warning{
+				Path:    org.path(),
+				Message: fmt.Sprintf("application org %s has no anchor peers; peers of other orgs cannot discover its peers", orgName),
+			})
+			continue
+		}
+
+		for _, anchorPeer := range anchorPeers {
+			address := strings.ToLower(fmt.Sprintf("%s:%d", anchorPeer.Host, anchorPeer.Port))
+
+			if owner, ok := anchorPeerOwners[address]; ok {
+				if owner == orgName {
+					findings = append(findings, fmt.Errorf("anchor peer %s is declared more than once by application org %s", address, orgName))
+				} else {
+					findings = append(findings, fmt.Errorf("anchor peer %s of application org %s is also an anchor peer of application org %s", address, orgName, owner))
+				}
+				continue
+			}
+			anchorPeerOwners[address] = orgName
+
+			if owner, ok := endpointOwners[address]; ok {
+				findings = append(findings, fmt.Errorf("anchor peer %s of application org %s is %s", address, orgName, owner))
+			}
+		}
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("invalid anchor peers: %w", findings)
 	}
 
 	return nil
@@ -353,15 +527,122 @@ func (a *ApplicationGroup) ACLs() (map[string]string, error) {
 
 // SetACLs sets ACLS to an existing channel config application.
 // If an ACL already exist in current configuration, it will be replaced with new ACL.
+// ACLs of resources the peers only support at a higher application capability
+// level than the one of the application are rejected.
 func (a *ApplicationGroup) SetACLs(acls map[string]string) error {
-	err := setValue(a.applicationGroup, aclValues(acls), AdminsPolicyKey)
+	capabilities, err := a.Capabilities()
 	if err != nil {
 		return err
 	}
 
+	err = ValidationErrors(aclCapabilityGaps(acls, capabilities)).err()
+	if err != nil {
+		return fmt.Errorf("unsupported ACLs: %w", err)
+	}
+
+	err = setValue(a.applicationGroup, aclValues(acls), AdminsPolicyKey)
+	if err != nil {
+		return err
+	}
+
+	a.tx.notify(a.path(), "SetACLs")
+
+	return nil
+}
+
+// VerifyCapabilities verifies that the ACL resources and the policies of
+// the application in the updated config are supported by the application
+// capability level, e.g. that the ACLs of _lifecycle resources are only
+// set when the peers run the V2_0 lifecycle, catching configs the peers
+// would reject or ignore. The error wraps ValidationErrors with a finding
+// for each capability gap.
+func (a *ApplicationGroup) VerifyCapabilities() error {
+	capabilities, err := a.Capabilities()
+	if err != nil {
+		return err
+	}
+
+	acls, err := a.ACLs()
+	if err != nil {
+		return fmt.Errorf("retrieving ACLs: %w", err)
+	}
+
+	findings := ValidationErrors(aclCapabilityGaps(acls, capabilities))
+
+	for _, feature := range applicationPolicyCapabilities {
+		if _, ok := a.applicationGroup.Policies[feature.name]; ok {
+			if gap := capabilityGap(fmt.Sprintf("policy %s", feature.name), feature.capability, capabilities); gap != nil {
+				findings = append(findings, gap)
+			}
+		}
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("unsupported application features: %w", findings)
+	}
+
 	return nil
 }
 
+// aclResourceCapabilities are the application capabilities the peers
+// require to enforce the ACLs of resources with the prefixes.
+var aclResourceCapabilities = []struct {
+	prefix     string
+	capability string
+}{
+	{"_lifecycle/", "V2_0"},
+	{"gateway/", "V2_4"},
+}
+
+// applicationPolicyCapabilities are the application capabilities required
+// by the features that evaluate the application policies with the names.
+var applicationPolicyCapabilities = []struct {
+	name       string
+	capability string
+}{
+	{EndorsementPolicyKey, "V2_0"},
+	{LifecycleEndorsementPolicyKey, "V2_0"},
+}
+
+// aclCapabilityGaps returns an error for each ACL whose resource requires
+// a higher application capability level than the capabilities enable.
+func aclCapabilityGaps(acls map[string]string, capabilities []string) []error {
+	resources := make([]string, 0, len(acls))
+	for resource := range acls {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	var errs []error
+	for _, resource := range resources {
+		for _, required := range aclResourceCapabilities {
+			if !strings.HasPrefix(resource, required.prefix) {
+				continue
+			}
+			if gap := capabilityGap(fmt.Sprintf("ACL resource %s", resource), required.capability, capabilities); gap != nil {
+				errs = append(errs, gap)
+			}
+		}
+	}
+
+	return errs
+}
+
+// capabilityGap returns an error if the capability level of capabilities
+// is lower than the required capability of the feature.
+func capabilityGap(feature, required string, capabilities []string) error {
+	level := capabilityLevel(capabilities)
+	if compareCapabilityLevels(level, capabilityLevel([]string{required})) >= 0 {
+		return nil
+	}
+
+	if level == nil {
+		return fmt.Errorf("%s requires application capability %s, but no application capability level is enabled", feature, required)
+	}
+
+	return fmt.Errorf("%s requires application capability %s, but the application capability level is %s", feature, required, capabilityLevelString(level))
+}
+
 // RemoveACLs a list of ACLs from given channel config application.
 // Specifying acls that do not exist in the application ConfigGroup of the channel config will not return a error.
 // Removal will panic if application group does not exist.
@@ -380,6 +661,8 @@ func (a *ApplicationGroup) RemoveACLs(acls []string) error {
 		return err
 	}
 
+	a.tx.notify(a.path(), "RemoveACLs")
+
 	return nil
 }
 
@@ -388,7 +671,7 @@ func (a *ApplicationGroup) RemoveACLs(acls []string) error {
 func (a *ApplicationOrg) SetMSP(updatedMSP MSP) error {
 	currentMSP, err := a.MSP().Configuration()
 	if err != nil {
-		return fmt.Errorf("retrieving msp: %v", err)
+		return fmt.Errorf("retrieving msp: %w", err)
 	}
 
 	if currentMSP.Name != updatedMSP.Name {
@@ -405,13 +688,17 @@ func (a *ApplicationOrg) SetMSP(updatedMSP MSP) error {
 		return err
 	}
 
+	a.tx.notify(a.path(), "SetMSP")
+
+	a.tx.checkAdminCerts(a.path(), updatedMSP)
+
 	return nil
 }
 
 func (a *ApplicationOrg) setMSPConfig(updatedMSP MSP) error {
 	mspConfig, err := newMSPConfig(updatedMSP)
 	if err != nil {
-		return fmt.Errorf("new msp config: %v", err)
+		return fmt.Errorf("new msp config: %w", err)
 	}
 
 	err = setValue(a.orgGroup, mspValue(mspConfig), AdminsPolicyKey)
@@ -436,6 +723,11 @@ func newApplicationGroupTemplate(application Application) (*cb.ConfigGroup, erro
 	}
 
 	if len(application.ACLs) > 0 {
+		err = ValidationErrors(aclCapabilityGaps(application.ACLs, application.Capabilities)).err()
+		if err != nil {
+			return nil, fmt.Errorf("unsupported ACLs: %w", err)
+		}
+
 		err = setValue(applicationGroup, aclValues(application.ACLs), AdminsPolicyKey)
 		if err != nil {
 			return nil, err
@@ -449,6 +741,11 @@ func newApplicationGroupTemplate(application Application) (*cb.ConfigGroup, erro
 		}
 	}
 
+	err = setExtensions(applicationGroup, application.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, org := range application.Organizations {
 		applicationGroup.Groups[org.Name] = newConfigGroup()
 	}
@@ -456,19 +753,26 @@ func newApplicationGroupTemplate(application Application) (*cb.ConfigGroup, erro
 	return applicationGroup, nil
 }
 
-// newApplicationGroup returns the application component of the channel
+// NewApplicationGroup returns the application component of the channel
 // configuration with the entire configuration for application organizations.
 // By default, it sets the mod_policy of all elements to "Admins".
-func newApplicationGroup(application Application) (*cb.ConfigGroup, error) {
+// It can be used to compose custom channel config trees.
+func NewApplicationGroup(application Application) (*cb.ConfigGroup, error) {
+	return newApplicationGroup(application, nil)
+}
+
+// newApplicationGroup returns the application group, converting the MSPs of
+// the organizations with mspConfigs.
+func newApplicationGroup(application Application, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
 	applicationGroup, err := newApplicationGroupTemplate(application)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, org := range application.Organizations {
-		applicationGroup.Groups[org.Name], err = newOrgConfigGroup(org)
+		applicationGroup.Groups[org.Name], err = newOrgConfigGroup(org, mspConfigs)
 		if err != nil {
-			return nil, fmt.Errorf("org group '%s': %v", org.Name, err)
+			return nil, fmt.Errorf("org group '%s': %w", org.Name, err)
 		}
 	}
 
@@ -11,14 +11,17 @@ import (
 	"crypto/ecdsa"
 	"crypto/x509"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-config/protolator/protoext/peerext"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
 	. "github.com/onsi/gomega"
 )
 
@@ -592,6 +595,72 @@ func TestAnchorPeers(t *testing.T) {
 	gt.Expect(anchorPeers).To(HaveLen(0))
 }
 
+func TestVerifyAnchorPeers(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup := newConfigGroup()
+
+	application, _ := baseApplication(t)
+	org3MSP, _ := baseMSP(t)
+	application.Organizations = append(application.Organizations, Organization{
+		Name:     "Org3",
+		Policies: applicationOrgStandardPolicies(),
+		MSP:      org3MSP,
+	})
+	applicationGroup, err := newApplicationGroupTemplate(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[ApplicationGroupKey] = applicationGroup
+
+	ordererOrgGroup := newConfigGroup()
+	err = setValue(ordererOrgGroup, endpointsValue([]string{"orderer.example.com:7050"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererGroup := newConfigGroup()
+	ordererGroup.Groups["OrdererOrg"] = ordererOrgGroup
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+
+	err = setValue(applicationGroup.Groups["Org1"], anchorPeersValue([]*pb.AnchorPeer{
+		{Host: "peer0.org1.example.com", Port: 7051},
+		{Host: "peer0.org1.example.com", Port: 7051},
+	}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(applicationGroup.Groups["Org2"], anchorPeersValue([]*pb.AnchorPeer{
+		{Host: "PEER0.org1.example.com", Port: 7051},
+		{Host: "peer0.org2.example.com", Port: 7051},
+		{Host: "orderer.example.com", Port: 7050},
+		{Host: "legacy.example.com", Port: 7050},
+	}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var warnings []Warning
+	c := New(&cb.Config{ChannelGroup: channelGroup}, WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+
+	err = c.Channel().AddLegacyOrdererAddress("legacy.example.com:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	warnings = nil
+	err = c.Application().VerifyAnchorPeers()
+	gt.Expect(err).To(MatchError("invalid anchor peers: " +
+		"anchor peer peer0.org1.example.com:7051 is declared more than once by application org Org1; " +
+		"anchor peer peer0.org1.example.com:7051 of application org Org2 is also an anchor peer of application org Org1; " +
+		"anchor peer orderer.example.com:7050 of application org Org2 is an orderer endpoint of orderer org OrdererOrg; " +
+		"anchor peer legacy.example.com:7050 of application org Org2 is an orderer address of the channel"))
+
+	var findings ValidationErrors
+	gt.Expect(errors.As(err, &findings)).To(BeTrue())
+	gt.Expect(findings).To(HaveLen(4))
+
+	gt.Expect(warnings).To(Equal([]Warning{
+		{
+			Path:    "/Channel/Application/Org3",
+			Message: "application org Org3 has no anchor peers; peers of other orgs cannot discover its peers",
+		},
+	}))
+}
+
 func TestSetACL(t *testing.T) {
 	t.Parallel()
 
@@ -618,6 +687,29 @@ func TestSetACL(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			testName: "ACL resource requires a higher capability level",
+			newACL: map[string]string{
+				"_lifecycle/CommitChaincodeDefinition": "/Channel/Application/Writers",
+				"gateway/Submit":                       "/Channel/Application/Writers",
+			},
+			expectedErr: "unsupported ACLs: " +
+				"ACL resource _lifecycle/CommitChaincodeDefinition requires application capability V2_0, but the application capability level is V1_3; " +
+				"ACL resource gateway/Submit requires application capability V2_4, but the application capability level is V1_3",
+		},
+		{
+			testName: "ACL resource supported by the capability level",
+			configMod: func(config *cb.Config) {
+				err := setValue(config.ChannelGroup.Groups[ApplicationGroupKey], capabilitiesValue([]string{"V2_0"}), AdminsPolicyKey)
+				if err != nil {
+					panic(err)
+				}
+			},
+			newACL: map[string]string{"_lifecycle/CommitChaincodeDefinition": "/Channel/Application/Writers"},
+			expectedACL: map[string]string{
+				"_lifecycle/CommitChaincodeDefinition": "/Channel/Application/Writers",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -652,6 +744,42 @@ func TestSetACL(t *testing.T) {
 	}
 }
 
+func TestVerifyApplicationCapabilities(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	application.Capabilities = []string{"V2_0"}
+	application.Policies[LifecycleEndorsementPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement"}
+	application.ACLs = map[string]string{"_lifecycle/CommitChaincodeDefinition": "/Channel/Application/Writers"}
+	applicationGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[ApplicationGroupKey] = applicationGroup
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.Application().VerifyCapabilities()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().RemoveCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().VerifyCapabilities()
+	gt.Expect(err).To(MatchError("unsupported application features: " +
+		"ACL resource _lifecycle/CommitChaincodeDefinition requires application capability V2_0, but no application capability level is enabled; " +
+		"policy LifecycleEndorsement requires application capability V2_0, but no application capability level is enabled"))
+
+	var findings ValidationErrors
+	gt.Expect(errors.As(err, &findings)).To(BeTrue())
+	gt.Expect(findings).To(HaveLen(2))
+
+	application.Capabilities = []string{"V1_4_2"}
+	_, err = NewApplicationGroup(application)
+	gt.Expect(err).To(MatchError("unsupported ACLs: ACL resource _lifecycle/CommitChaincodeDefinition requires application capability V2_0, but the application capability level is V1_4_2"))
+}
+
 func TestAppOrgRemoveACL(t *testing.T) {
 	t.Parallel()
 
@@ -723,7 +851,7 @@ func TestSetApplicationOrg(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	application, _ := baseApplication(t)
-	appGroup, err := newApplicationGroup(application)
+	appGroup, err := NewApplicationGroup(application)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1537,7 +1665,7 @@ func TestApplicationOrg(t *testing.T) {
 	}
 	channelGroup, err := newChannelGroup(channel)
 	gt.Expect(err).NotTo(HaveOccurred())
-	orgGroup, err := newApplicationOrgConfigGroup(channel.Application.Organizations[0])
+	orgGroup, err := newApplicationOrgConfigGroup(channel.Application.Organizations[0], nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 	channelGroup.Groups[ApplicationGroupKey].Groups["Org1"] = orgGroup
 
@@ -1585,7 +1713,7 @@ func TestAppOrgRemoveApplicationOrg(t *testing.T) {
 	}
 	channelGroup, err := newChannelGroup(channel)
 	gt.Expect(err).NotTo(HaveOccurred())
-	orgGroup, err := newOrgConfigGroup(channel.Application.Organizations[0])
+	orgGroup, err := newOrgConfigGroup(channel.Application.Organizations[0], nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 	channelGroup.Groups[ApplicationGroupKey].Groups["Org1"] = orgGroup
 
@@ -1615,7 +1743,7 @@ func TestAppOrgRemoveApplicationOrgPolicy(t *testing.T) {
 			Rule: "MAJORITY Endorsement",
 		}
 
-		orgGroup, err := newOrgConfigGroup(org)
+		orgGroup, err := newOrgConfigGroup(org, nil)
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		applicationGroup.Groups[org.Name] = orgGroup
@@ -1628,7 +1756,7 @@ func TestAppOrgRemoveApplicationOrgPolicy(t *testing.T) {
 	c := New(config)
 
 	application.Organizations[0].Policies = applicationOrgStandardPolicies()
-	expectedOrgConfigGroup, _ := newOrgConfigGroup(application.Organizations[0])
+	expectedOrgConfigGroup, _ := newOrgConfigGroup(application.Organizations[0], nil)
 	expectedPolicies := expectedOrgConfigGroup.Policies
 
 	applicationOrg1 := c.Application().Organization("Org1")
@@ -1649,7 +1777,7 @@ func TestAppOrgRemoveApplicationOrgPolicyFailures(t *testing.T) {
 	application, _ := baseApplication(t)
 	for _, org := range application.Organizations {
 		org.Policies = applicationOrgStandardPolicies()
-		orgGroup, err := newOrgConfigGroup(org)
+		orgGroup, err := newOrgConfigGroup(org, nil)
 		gt.Expect(err).NotTo(HaveOccurred())
 		applicationGroup.Groups[org.Name] = orgGroup
 	}
@@ -1682,7 +1810,7 @@ func TestSetApplicationOrgPolicy(t *testing.T) {
 	for _, org := range application.Organizations {
 		org.Policies = applicationOrgStandardPolicies()
 
-		orgGroup, err := newOrgConfigGroup(org)
+		orgGroup, err := newOrgConfigGroup(org, nil)
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		applicationGroup.Groups[org.Name] = orgGroup
@@ -1695,7 +1823,7 @@ func TestSetApplicationOrgPolicy(t *testing.T) {
 	c := New(config)
 
 	application.Organizations[0].Policies = applicationOrgStandardPolicies()
-	expectedOrgConfigGroup, _ := newOrgConfigGroup(application.Organizations[0])
+	expectedOrgConfigGroup, _ := newOrgConfigGroup(application.Organizations[0], nil)
 	expectedPolicies := expectedOrgConfigGroup.Policies
 	expectedPolicies["TestPolicy"] = expectedPolicies[EndorsementPolicyKey]
 
@@ -1719,7 +1847,7 @@ func TestSetApplicationOrgPolicyFailures(t *testing.T) {
 	for _, org := range application.Organizations {
 		org.Policies = applicationOrgStandardPolicies()
 
-		orgGroup, err := newOrgConfigGroup(org)
+		orgGroup, err := newOrgConfigGroup(org, nil)
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		applicationGroup.Groups[org.Name] = orgGroup
@@ -1879,7 +2007,7 @@ func TestApplicationMSP(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	application, _ := baseApplication(t)
-	applicationGroup, err := newApplicationGroup(application)
+	applicationGroup, err := NewApplicationGroup(application)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1927,7 +2055,7 @@ func TestSetApplicationMSPFailure(t *testing.T) {
 				return msp
 			},
 			orgName:     "Org1",
-			expectedErr: "invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7",
+			expectedErr: "invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7; intermediate cert not signed by any root certs of this MSP. serial number: {{intermediateSerial}}",
 		},
 		{
 			spec: "root ca cert is not a ca",
@@ -1942,7 +2070,7 @@ func TestSetApplicationMSPFailure(t *testing.T) {
 				return msp
 			},
 			orgName:     "Org1",
-			expectedErr: "invalid root cert: must be a CA certificate. serial number: 7",
+			expectedErr: "invalid root cert: must be a CA certificate. serial number: 7; intermediate cert not signed by any root certs of this MSP. serial number: {{intermediateSerial}}",
 		},
 		{
 			spec: "invalid intermediate ca keyusage",
@@ -1956,7 +2084,7 @@ func TestSetApplicationMSPFailure(t *testing.T) {
 				return msp
 			},
 			orgName:     "Org1",
-			expectedErr: "invalid intermediate cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7",
+			expectedErr: "invalid intermediate cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7; intermediate cert not signed by any root certs of this MSP. serial number: 7",
 		},
 		{
 			spec: "invalid intermediate cert -- not signed by root cert",
@@ -2017,9 +2145,11 @@ func TestSetApplicationMSPFailure(t *testing.T) {
 			org1MSP, err := c.Application().Organization("Org1").MSP().Configuration()
 			gt.Expect(err).NotTo(HaveOccurred())
 
+			intermediateSerial := org1MSP.IntermediateCerts[0].SerialNumber.String()
+
 			org1MSP = tc.mspMod(org1MSP)
 			err = c.Application().Organization(tc.orgName).SetMSP(org1MSP)
-			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(err).To(MatchError(strings.Replace(tc.expectedErr, "{{intermediateSerial}}", intermediateSerial, -1)))
 		})
 	}
 }
@@ -2400,6 +2530,87 @@ func TestSetApplicationMSP(t *testing.T) {
 	gt.Expect(buf.String()).To(MatchJSON(expectedConfigJSON))
 }
 
+func TestMajorityOfOrgsPolicy(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseApplicationConf, _ := baseApplication(t)
+	baseApplicationConf.Organizations[0].MSP.Name = "Org1MSP"
+	baseApplicationConf.Organizations[1].MSP.Name = "Org2MSP"
+
+	applicationGroup, err := NewApplicationGroup(baseApplicationConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: applicationGroup,
+			},
+		},
+	})
+
+	a := c.Application()
+	policy, err := a.MajorityOfOrgsPolicy("admin")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policy).To(Equal(Policy{
+		Type: SignaturePolicyType,
+		Rule: "AND('Org1MSP.admin', 'Org2MSP.admin')",
+	}))
+
+	err = a.SetPolicy(AdminsPolicyKey, "MajorityAdmins", policy)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for _, name := range []string{"Org3", "Org4"} {
+		org := baseApplicationConf.Organizations[0]
+		org.Name = name
+		org.MSP.Name = name + "MSP"
+		err = a.SetOrganization(org)
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	err = a.RefreshMajorityOfOrgsPolicy(AdminsPolicyKey, "MajorityAdmins", "admin")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	policies, err := a.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies["MajorityAdmins"]).To(Equal(Policy{
+		Type: SignaturePolicyType,
+		Rule: "OUTOF(3, 'Org1MSP.admin', 'Org2MSP.admin', 'Org3MSP.admin', 'Org4MSP.admin')",
+	}))
+
+	a.RemoveOrganization("Org4")
+	err = a.RefreshMajorityOfOrgsPolicy(AdminsPolicyKey, "MajorityAdmins", "admin")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	policies, err = a.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies["MajorityAdmins"]).To(Equal(Policy{
+		Type: SignaturePolicyType,
+		Rule: "OUTOF(2, 'Org1MSP.admin', 'Org2MSP.admin', 'Org3MSP.admin')",
+	}))
+}
+
+func TestMajorityOfOrgsPolicyFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: newConfigGroup(),
+			},
+		},
+	})
+
+	_, err := c.Application().MajorityOfOrgsPolicy("admin")
+	gt.Expect(err).To(MatchError("generating majority policy: at least one MSP ID is required"))
+
+	err = c.Application().RefreshMajorityOfOrgsPolicy(AdminsPolicyKey, "MajorityAdmins", "admin")
+	gt.Expect(err).To(MatchError("generating majority policy: at least one MSP ID is required"))
+}
+
 func baseApplication(t *testing.T) (Application, []*ecdsa.PrivateKey) {
 	org1BaseMSP, org1PrivKey := baseMSP(t)
 	org2BaseMSP, org2PrivKey := baseMSP(t)
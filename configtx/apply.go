@@ -0,0 +1,361 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Apply changes the updated config so that it converges to the desired
+// channel configuration, which describes the complete end state of the
+// channel, e.g. as decoded from a declarative specification:
+//
+// - Organizations of the desired configuration are added or replaced if
+// their groups differ from those built from the desired configuration,
+// and organizations that are not part of it are removed.
+//
+// - Policies are replaced if their type or rule differs and removed if
+// they are not part of the desired configuration. Replaced policies keep
+// their mod policy and added ones use Admins.
+//
+// - Capabilities are added and removed to match the desired capabilities,
+// and the application ACLs and orderer values are set to the desired
+// ones.
+//
+// - Extensions of the channel and application are set if they differ, and
+// the custom values registered with WithValueTypes that are not part of
+// the desired configuration are removed.
+//
+// The application and consortiums are only converged if the config
+// contains their groups. Elements that already match the desired
+// configuration are left untouched, so that the computed update only
+// contains the differences. If any change fails, an error is returned and
+// the updated config is left unchanged.
+func (c *ConfigTx) Apply(desired Channel) error {
+	channelGroup := c.updated.ChannelGroup
+
+	if _, ok := channelGroup.Groups[ApplicationGroupKey]; !ok && len(desired.Application.Organizations) > 0 {
+		return errors.New("config does not contain an application group")
+	}
+	if _, ok := channelGroup.Groups[ConsortiumsGroupKey]; !ok && len(desired.Consortiums) > 0 {
+		return errors.New("config does not contain a consortiums group")
+	}
+	if _, ok := channelGroup.Groups[OrdererGroupKey]; ok && desired.Orderer.OrdererType == "" {
+		return errors.New("desired orderer configuration is required")
+	}
+
+	// changes are applied to a copy of the updated config, which replaces
+	// it once all of them succeeded
+	scratch := ConfigTx{
+		original: c.original,
+		updated:  proto.Clone(c.updated).(*cb.Config),
+		options:  c.options,
+	}
+	scratch.options.observers = nil
+
+	err := scratch.applyChannel(desired)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := channelGroup.Groups[OrdererGroupKey]; ok {
+		err = scratch.applyOrderer(desired.Orderer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		err = scratch.applyApplication(desired.Application)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		err = scratch.applyConsortiums(desired.Consortiums)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.updated.ChannelGroup = scratch.updated.ChannelGroup
+
+	c.notify(configPath(ChannelGroupKey), "Apply")
+
+	return nil
+}
+
+// applyChannel converges the channel capabilities and policies.
+func (c *ConfigTx) applyChannel(desired Channel) error {
+	ch := c.Channel()
+
+	capabilities, err := ch.Capabilities()
+	if err != nil {
+		return fmt.Errorf("retrieving channel capabilities: %w", err)
+	}
+
+	err = applyCapabilities(capabilities, desired.Capabilities, ch.AddCapability, ch.RemoveCapability)
+	if err != nil {
+		return fmt.Errorf("applying channel capabilities: %w", err)
+	}
+
+	err = applyPolicies(ch.channelGroup, desired.Policies, ch.SetPolicy, ch.RemovePolicy)
+	if err != nil {
+		return fmt.Errorf("applying channel policies: %w", err)
+	}
+
+	err = applyExtensions(ch.channelGroup, desired.Extensions, c.valueTypes(), ch.SetExtension, ch.RemoveExtension)
+	if err != nil {
+		return fmt.Errorf("applying channel extensions: %w", err)
+	}
+
+	return nil
+}
+
+// applyOrderer converges the orderer values, capabilities, policies and
+// organizations.
+func (c *ConfigTx) applyOrderer(desired Orderer) error {
+	o := c.Orderer()
+
+	err := o.SetConfiguration(desired)
+	if err != nil {
+		return fmt.Errorf("applying orderer configuration: %w", err)
+	}
+
+	capabilities, err := o.Capabilities()
+	if err != nil {
+		return fmt.Errorf("retrieving orderer capabilities: %w", err)
+	}
+
+	err = applyCapabilities(capabilities, desired.Capabilities, o.AddCapability, o.RemoveCapability)
+	if err != nil {
+		return fmt.Errorf("applying orderer capabilities: %w", err)
+	}
+
+	err = applyPolicies(o.ordererGroup, desired.Policies, o.SetPolicy, o.RemovePolicy)
+	if err != nil {
+		return fmt.Errorf("applying orderer policies: %w", err)
+	}
+
+	return applyOrganizations(o.ordererGroup, desired.Organizations, newOrdererOrgConfigGroup, o.SetOrganization, o.RemoveOrganization)
+}
+
+// applyApplication converges the application capabilities, policies, ACLs
+// and organizations.
+func (c *ConfigTx) applyApplication(desired Application) error {
+	a := c.Application()
+
+	capabilities, err := a.Capabilities()
+	if err != nil {
+		return fmt.Errorf("retrieving application capabilities: %w", err)
+	}
+
+	err = applyCapabilities(capabilities, desired.Capabilities, a.AddCapability, a.RemoveCapability)
+	if err != nil {
+		return fmt.Errorf("applying application capabilities: %w", err)
+	}
+
+	err = applyPolicies(a.applicationGroup, desired.Policies, a.SetPolicy, a.RemovePolicy)
+	if err != nil {
+		return fmt.Errorf("applying application policies: %w", err)
+	}
+
+	acls, err := a.ACLs()
+	if err != nil {
+		return fmt.Errorf("retrieving application ACLs: %w", err)
+	}
+
+	if !sameStringMaps(acls, desired.ACLs) {
+		err = a.SetACLs(desired.ACLs)
+		if err != nil {
+			return fmt.Errorf("applying application ACLs: %w", err)
+		}
+	}
+
+	err = applyExtensions(a.applicationGroup, desired.Extensions, c.valueTypes(), a.SetExtension, a.RemoveExtension)
+	if err != nil {
+		return fmt.Errorf("applying application extensions: %w", err)
+	}
+
+	return applyOrganizations(a.applicationGroup, desired.Organizations, newApplicationOrgConfigGroup, a.SetOrganization, a.RemoveOrganization)
+}
+
+// applyConsortiums converges the consortiums and their organizations.
+// Consortiums that do not exist are added with the default channel
+// creation policy; the channel creation policy of existing ones is kept.
+func (c *ConfigTx) applyConsortiums(desired []Consortium) error {
+	consortiumsGroup := c.Consortiums().consortiumsGroup
+
+	names := map[string]bool{}
+	for _, consortium := range desired {
+		names[consortium.Name] = true
+
+		if _, ok := consortiumsGroup.Groups[consortium.Name]; !ok {
+			consortiumGroup, err := newConsortiumGroup(consortium, nil)
+			if err != nil {
+				return fmt.Errorf("applying consortium %s: %w", consortium.Name, err)
+			}
+			consortiumsGroup.Groups[consortium.Name] = consortiumGroup
+			continue
+		}
+
+		cg := c.Consortium(consortium.Name)
+		err := applyOrganizations(cg.consortiumGroup, consortium.Organizations, newOrgConfigGroup, cg.SetOrganization, cg.RemoveOrganization)
+		if err != nil {
+			return fmt.Errorf("applying consortium %s: %w", consortium.Name, err)
+		}
+	}
+
+	for _, name := range sortedKeys(consortiumsGroup.Groups) {
+		if !names[name] {
+			c.Consortiums().RemoveConsortium(name)
+		}
+	}
+
+	return nil
+}
+
+// applyOrganizations sets the organizations whose groups differ from those
+// built by newOrgGroup and removes the organizations of the group that are
+// not desired.
+func applyOrganizations(
+	group *cb.ConfigGroup,
+	desired []Organization,
+	newOrgGroup func(Organization, *mspConfigCache) (*cb.ConfigGroup, error),
+	set func(Organization) error,
+	remove func(name string),
+) error {
+	names := map[string]bool{}
+	for _, org := range desired {
+		names[org.Name] = true
+
+		orgGroup, err := newOrgGroup(org, nil)
+		if err != nil {
+			return fmt.Errorf("org group '%s': %w", org.Name, err)
+		}
+		if existing, ok := group.Groups[org.Name]; ok && sameGroupContent(existing, orgGroup) {
+			continue
+		}
+
+		err = set(org)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(group.Groups) {
+		if !names[name] {
+			remove(name)
+		}
+	}
+
+	return nil
+}
+
+// applyPolicies sets the desired policies of the group whose type or rule
+// differs and removes the policies that are not desired.
+func applyPolicies(
+	group *cb.ConfigGroup,
+	desired map[string]Policy,
+	set func(modPolicy, policyName string, policy Policy) error,
+	remove func(policyName string) error,
+) error {
+	current, err := getPolicies(group.Policies)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		policy := desired[name]
+		if existing, ok := current[name]; ok && existing.Type == policy.Type && existing.Rule == policy.Rule {
+			continue
+		}
+
+		modPolicy := AdminsPolicyKey
+		if existing, ok := group.Policies[name]; ok {
+			modPolicy = existing.ModPolicy
+		}
+
+		err := set(modPolicy, name, policy)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(group.Policies) {
+		if _, ok := desired[name]; !ok {
+			err := remove(name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyCapabilities adds the desired capabilities that are missing and
+// removes the current capabilities that are not desired.
+func applyCapabilities(current, desired []string, add, remove func(capability string) error) error {
+	for _, capability := range desired {
+		if !containsString(current, capability) {
+			err := add(capability)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, capability := range current {
+		if !containsString(desired, capability) {
+			err := remove(capability)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// containsString reports whether the slice contains s.
+func containsString(slice []string, s string) bool {
+	for _, element := range slice {
+		if element == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameStringMaps reports whether the maps have the same entries.
+func sameStringMaps(m1, m2 map[string]string) bool {
+	if len(m1) != len(m2) {
+		return false
+	}
+
+	for key, value := range m1 {
+		if v, ok := m2[key]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
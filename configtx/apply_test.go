@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	desired := profile
+	desired.Application.Organizations = []Organization{profile.Application.Organizations[0]}
+	newOrg := profile.Application.Organizations[1]
+	newOrg.Name = "Org3"
+	newOrg.MSP.Name = "Org3MSP"
+	desired.Application.Organizations = append(desired.Application.Organizations, newOrg)
+	desired.Application.Policies = map[string]Policy{
+		ReadersPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "ANY Readers"},
+		WritersPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "ANY Writers"},
+		AdminsPolicyKey:  {Type: ImplicitMetaPolicyType, Rule: "ANY Admins"},
+	}
+	desired.Application.Capabilities = []string{"V2_0"}
+
+	var mutations []Mutation
+	c := New(config, WithObserver(func(m Mutation) { mutations = append(mutations, m) }))
+	err = c.Apply(desired)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mutations).To(Equal([]Mutation{{Path: "/Channel", Operation: "Apply"}}))
+
+	differences, err := DiffAt([]string{ChannelGroupKey}, c.OriginalConfig(), c.UpdatedConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(ConsistOf(
+		Difference{Path: "/Channel/Application/Policies/Admins", Element: ElementPolicy, Change: ChangeModified},
+		Difference{Path: "/Channel/Application/Values/Capabilities", Element: ElementValue, Change: ChangeModified},
+		Difference{Path: "/Channel/Application/Org2", Element: ElementGroup, Change: ChangeRemoved},
+		Difference{Path: "/Channel/Application/Org3", Element: ElementGroup, Change: ChangeAdded},
+	))
+
+	application, err := c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(application.Capabilities).To(Equal([]string{"V2_0"}))
+	gt.Expect(application.Policies[AdminsPolicyKey]).To(Equal(Policy{Type: ImplicitMetaPolicyType, Rule: "ANY Admins"}))
+
+	// applying the desired state again does not change the config
+	converged := c.UpdatedConfig()
+	err = c.Apply(desired)
+	gt.Expect(err).NotTo(HaveOccurred())
+	differences, err = DiffAt([]string{ChannelGroupKey}, converged, c.UpdatedConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(BeEmpty())
+}
+
+func TestApplyWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	err = c.Apply(profile)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).To(MatchError("failed to compute update: no differences detected between original and updated config"))
+}
+
+func TestApplyFailures(t *testing.T) {
+	t.Parallel()
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	invalidPolicies := profile
+	invalidPolicies.Application.Policies = map[string]Policy{
+		AdminsPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "INVALID"},
+	}
+	withoutOrderer := profile
+	withoutOrderer.Orderer = Orderer{}
+	withConsortiums := profile
+	withConsortiums.Consortiums = []Consortium{{Name: "SampleConsortium"}}
+
+	tests := []struct {
+		name        string
+		desired     Channel
+		expectedErr string
+	}{
+		{
+			name:        "when a policy is invalid",
+			desired:     invalidPolicies,
+			expectedErr: "applying application policies: failed to set policy 'Admins': invalid implicit meta policy rule: 'INVALID': expected two space separated tokens, but got 1",
+		},
+		{
+			name:        "when the orderer configuration is missing",
+			desired:     withoutOrderer,
+			expectedErr: "desired orderer configuration is required",
+		},
+		{
+			name:        "when the config does not contain consortiums",
+			desired:     withConsortiums,
+			expectedErr: "config does not contain a consortiums group",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+			config, err := ConfigFromBlock(block)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			c := New(config)
+			err = c.Apply(tc.desired)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(proto.Equal(c.UpdatedConfig(), c.OriginalConfig())).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ArtifactType is the kind of config artifact found in a file.
+type ArtifactType string
+
+// Config artifact types.
+const (
+	// ArtifactBlock is a config block, e.g. the output of
+	// `peer channel fetch config` or a channel genesis block.
+	ArtifactBlock ArtifactType = "block"
+	// ArtifactEnvelope is a config update transaction, e.g. a channel
+	// creation transaction or a signed config update.
+	ArtifactEnvelope ArtifactType = "envelope"
+	// ArtifactConfig is a channel config.
+	ArtifactConfig ArtifactType = "config"
+	// ArtifactConfigUpdate is a config update, e.g. the output of
+	// ConfigTx.ComputeMarshaledUpdate.
+	ArtifactConfigUpdate ArtifactType = "config update"
+)
+
+// Artifact is a decoded config artifact.
+type Artifact struct {
+	Type ArtifactType
+	// Block is set for blocks.
+	Block *cb.Block
+	// Envelope is set for envelopes.
+	Envelope *cb.Envelope
+	// Config is set for blocks and configs.
+	Config *cb.Config
+	// ConfigUpdate is set for envelopes and config updates.
+	ConfigUpdate *cb.ConfigUpdate
+	// Signatures are the signatures of the config update of an envelope.
+	Signatures []*cb.ConfigSignature
+}
+
+// ReadConfigFile reads a file containing a marshaled config block, config
+// update envelope, config or config update and decodes it according to
+// its detected type.
+func ReadConfigFile(path string) (Artifact, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	artifact, err := decodeArtifact(raw)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	return artifact, nil
+}
+
+// ReadBlockFile reads a file containing a config block, such as the output
+// of `peer channel fetch config`, or a channel config and returns a config
+// transaction for the config it contains.
+func ReadBlockFile(path string, opts ...Option) (ConfigTx, error) {
+	artifact, err := ReadConfigFile(path)
+	if err != nil {
+		return ConfigTx{}, err
+	}
+
+	if artifact.Config == nil {
+		return ConfigTx{}, fmt.Errorf("%s contains a %s, not a config block or config", path, artifact.Type)
+	}
+
+	return New(artifact.Config, opts...), nil
+}
+
+// artifactExtensions are the file name extensions conventionally used for
+// artifacts of a type, e.g. by `peer channel fetch` and configtxgen.
+var artifactExtensions = map[string]ArtifactType{
+	".block": ArtifactBlock,
+	".tx":    ArtifactEnvelope,
+}
+
+// WriteArtifact marshals a config block, config update envelope, config
+// or config update to the file at path, creating its directory if needed.
+// The file contains the marshaled message only, so it can be consumed by
+// the peer CLI and configtxlator. The message is checked to be detected
+// as its type when read back, and a file named with the extension of
+// another type, e.g. a config update written to a .block file, is
+// rejected.
+func WriteArtifact(path string, msg proto.Message) error {
+	var artifactType ArtifactType
+	switch msg.(type) {
+	case *cb.Block:
+		artifactType = ArtifactBlock
+	case *cb.Envelope:
+		artifactType = ArtifactEnvelope
+	case *cb.Config:
+		artifactType = ArtifactConfig
+	case *cb.ConfigUpdate:
+		artifactType = ArtifactConfigUpdate
+	default:
+		return fmt.Errorf("unsupported artifact message %T", msg)
+	}
+
+	err := checkArtifactExtension(path, artifactType)
+	if err != nil {
+		return err
+	}
+
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling %s: %w", artifactType, err)
+	}
+
+	if len(raw) == 0 {
+		return fmt.Errorf("%s is empty", artifactType)
+	}
+
+	artifact, err := decodeArtifact(raw)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", artifactType, err)
+	}
+	if artifact.Type != artifactType {
+		return fmt.Errorf("invalid %s: it would be read back as a %s", artifactType, artifact.Type)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory of %s: %w", path, err)
+	}
+
+	err = ioutil.WriteFile(path, raw, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ReadArtifact reads a file written by WriteArtifact, or by any other tool,
+// and decodes it according to its detected type like ReadConfigFile. In
+// addition, a file named with the extension of a type other than the one
+// it contains, e.g. a .tx file containing a block, is rejected.
+func ReadArtifact(path string) (Artifact, error) {
+	artifact, err := ReadConfigFile(path)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	err = checkArtifactExtension(path, artifact.Type)
+	if err != nil {
+		return Artifact{}, err
+	}
+
+	return artifact, nil
+}
+
+// checkArtifactExtension returns an error if the extension of the file at
+// path is conventionally used for artifacts of a type other than
+// artifactType.
+func checkArtifactExtension(path string, artifactType ArtifactType) error {
+	expectedType, ok := artifactExtensions[filepath.Ext(path)]
+	if ok && expectedType != artifactType {
+		return fmt.Errorf("%s is named like %s files but contains a %s", path, expectedType, artifactType)
+	}
+
+	return nil
+}
+
+// decodeArtifact detects the type of a marshaled config artifact and
+// decodes it.
+func decodeArtifact(raw []byte) (Artifact, error) {
+	if len(raw) == 0 {
+		return Artifact{}, errors.New("file is empty")
+	}
+
+	block := &cb.Block{}
+	if proto.Unmarshal(raw, block) == nil && block.Header != nil && block.Data != nil && len(block.Data.Data) > 0 {
+		config, err := ConfigFromBlock(block)
+		if err != nil {
+			return Artifact{}, fmt.Errorf("block %d is not a config block: %w", block.Header.Number, err)
+		}
+
+		return Artifact{
+			Type:   ArtifactBlock,
+			Block:  block,
+			Config: config,
+		}, nil
+	}
+
+	envelope := &cb.Envelope{}
+	if proto.Unmarshal(raw, envelope) == nil && len(envelope.Payload) > 0 {
+		configUpdateEnvelope, err := ConfigUpdateEnvelopeFromEnvelope(envelope)
+		if err == nil {
+			configUpdate := &cb.ConfigUpdate{}
+			err = proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, configUpdate)
+			if err != nil {
+				return Artifact{}, fmt.Errorf("unmarshaling config update of envelope: %w", err)
+			}
+
+			return Artifact{
+				Type:         ArtifactEnvelope,
+				Envelope:     envelope,
+				ConfigUpdate: configUpdate,
+				Signatures:   configUpdateEnvelope.Signatures,
+			}, nil
+		}
+	}
+
+	configUpdate := &cb.ConfigUpdate{}
+	if proto.Unmarshal(raw, configUpdate) == nil && configUpdate.ChannelId != "" && configUpdate.WriteSet != nil {
+		return Artifact{
+			Type:         ArtifactConfigUpdate,
+			ConfigUpdate: configUpdate,
+		}, nil
+	}
+
+	config := &cb.Config{}
+	if proto.Unmarshal(raw, config) == nil && config.ChannelGroup != nil {
+		return Artifact{
+			Type:   ArtifactConfig,
+			Config: config,
+		}, nil
+	}
+
+	return Artifact{}, errors.New("not a config block, envelope, config or config update")
+}
+
+// ConfigUpdateEnvelopeFromEnvelope extracts the config update envelope from
+// a CONFIG_UPDATE envelope, e.g. a channel creation transaction written by
+// configtxgen, to access its marshaled config update and signatures.
+func ConfigUpdateEnvelopeFromEnvelope(envelope *cb.Envelope) (*cb.ConfigUpdateEnvelope, error) {
+	if envelope == nil {
+		return nil, errors.New("envelope is required")
+	}
+
+	payload := &cb.Payload{}
+	err := proto.Unmarshal(envelope.Payload, payload)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling payload: %w", err)
+	}
+
+	if payload.Header == nil {
+		return nil, errors.New("payload header is missing")
+	}
+
+	channelHeader := &cb.ChannelHeader{}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling channel header: %w", err)
+	}
+
+	if channelHeader.Type != int32(cb.HeaderType_CONFIG_UPDATE) {
+		return nil, fmt.Errorf("envelope has header type %s, not %s", cb.HeaderType(channelHeader.Type), cb.HeaderType_CONFIG_UPDATE)
+	}
+
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+	err = proto.Unmarshal(payload.Data, configUpdateEnvelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update envelope: %w", err)
+	}
+
+	return configUpdateEnvelope, nil
+}
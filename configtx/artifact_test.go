@@ -0,0 +1,222 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestReadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "artifacts")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	err = c.Application().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signature := &cb.ConfigSignature{SignatureHeader: []byte("header"), Signature: []byte("signature")}
+	envelope, err := NewEnvelope(marshaledUpdate, signature)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, configUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		file               string
+		msg                proto.Message
+		expectedType       ArtifactType
+		expectConfig       bool
+		expectConfigUpdate bool
+	}{
+		{file: "config.block", msg: block, expectedType: ArtifactBlock, expectConfig: true},
+		{file: "update.tx", msg: envelope, expectedType: ArtifactEnvelope, expectConfigUpdate: true},
+		{file: "config.pb", msg: config, expectedType: ArtifactConfig, expectConfig: true},
+		{file: "update.pb", msg: configUpdate, expectedType: ArtifactConfigUpdate, expectConfigUpdate: true},
+	}
+
+	for _, tc := range tests {
+		file := filepath.Join(dir, tc.file)
+		writeFile(t, file, protoMarshal(t, tc.msg))
+
+		artifact, err := ReadConfigFile(file)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(artifact.Type).To(Equal(tc.expectedType))
+
+		if tc.expectConfig {
+			gt.Expect(proto.Equal(artifact.Config, config)).To(BeTrue())
+
+			c, err := ReadBlockFile(file)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(c.OriginalConfig(), config)).To(BeTrue())
+		}
+
+		if tc.expectConfigUpdate {
+			gt.Expect(proto.Equal(artifact.ConfigUpdate, configUpdate)).To(BeTrue())
+
+			_, err := ReadBlockFile(file)
+			gt.Expect(err).To(MatchError(file + " contains a " + string(tc.expectedType) + ", not a config block or config"))
+		}
+	}
+
+	artifact, err := ReadConfigFile(filepath.Join(dir, "update.tx"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(artifact.Signatures).To(HaveLen(1))
+	gt.Expect(proto.Equal(artifact.Signatures[0], signature)).To(BeTrue())
+}
+
+func TestReadConfigFileFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "artifacts")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		testName    string
+		contents    []byte
+		expectedErr string
+	}{
+		{
+			testName:    "when the file is empty",
+			contents:    []byte{},
+			expectedErr: "file is empty",
+		},
+		{
+			testName:    "when the file is not a config artifact",
+			contents:    []byte("not a config artifact"),
+			expectedErr: "not a config block, envelope, config or config update",
+		},
+		{
+			testName: "when the block is not a config block",
+			contents: protoMarshal(t, &cb.Block{
+				Header: &cb.BlockHeader{Number: 7},
+				Data:   &cb.BlockData{Data: [][]byte{protoMarshal(t, &cb.Envelope{})}},
+			}),
+			expectedErr: "block 7 is not a config block: block does not contain a config",
+		},
+	}
+
+	for _, tc := range tests {
+		file := filepath.Join(dir, "artifact")
+		writeFile(t, file, tc.contents)
+
+		_, err := ReadConfigFile(file)
+		gt.Expect(err).To(MatchError("decoding " + file + ": " + tc.expectedErr))
+	}
+
+	_, err = ReadConfigFile(filepath.Join(dir, "missing"))
+	gt.Expect(err).To(MatchError(ContainSubstring("reading " + filepath.Join(dir, "missing"))))
+	gt.Expect(errors.Is(err, os.ErrNotExist)).To(BeTrue())
+}
+
+func TestWriteArtifact(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "artifacts")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	err = c.Application().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	envelope, err := NewEnvelope(marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, configUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		file         string
+		msg          proto.Message
+		expectedType ArtifactType
+	}{
+		{file: "blocks/config.block", msg: block, expectedType: ArtifactBlock},
+		{file: "update.tx", msg: envelope, expectedType: ArtifactEnvelope},
+		{file: "config.pb", msg: config, expectedType: ArtifactConfig},
+		{file: "update.pb", msg: configUpdate, expectedType: ArtifactConfigUpdate},
+	}
+
+	for _, tc := range tests {
+		file := filepath.Join(dir, tc.file)
+		err := WriteArtifact(file, tc.msg)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		artifact, err := ReadArtifact(file)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(artifact.Type).To(Equal(tc.expectedType))
+	}
+
+	err = WriteArtifact(filepath.Join(dir, "update.block"), configUpdate)
+	gt.Expect(err).To(MatchError(filepath.Join(dir, "update.block") + " is named like block files but contains a config update"))
+
+	err = WriteArtifact(filepath.Join(dir, "data.block"), &cb.Block{
+		Header: &cb.BlockHeader{Number: 7},
+		Data:   &cb.BlockData{Data: [][]byte{protoMarshal(t, &cb.Envelope{})}},
+	})
+	gt.Expect(err).To(MatchError("invalid block: block 7 is not a config block: block does not contain a config"))
+
+	err = WriteArtifact(filepath.Join(dir, "config.pb"), &cb.Config{})
+	gt.Expect(err).To(MatchError("config is empty"))
+
+	err = WriteArtifact(filepath.Join(dir, "config.pb"), &cb.Config{Sequence: 1})
+	gt.Expect(err).To(MatchError("invalid config: not a config block, envelope, config or config update"))
+
+	err = WriteArtifact(filepath.Join(dir, "header.pb"), &cb.BlockHeader{})
+	gt.Expect(err).To(MatchError("unsupported artifact message *common.BlockHeader"))
+
+	file := filepath.Join(dir, "config.tx")
+	writeFile(t, file, protoMarshal(t, block))
+	_, err = ReadArtifact(file)
+	gt.Expect(err).To(MatchError(file + " is named like envelope files but contains a block"))
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	gt := NewGomegaWithT(t)
+
+	b, err := proto.Marshal(msg)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return b
+}
@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// EnableAudit records every mutation of the updated config made through
+// the ConfigTx in an audit log, which is returned by AuditLog. Changes made
+// directly to the config returned by UpdatedConfig are not recorded; they
+// are attributed to the next mutation of the same group.
+func EnableAudit() Option {
+	return func(o *options) {
+		o.audit = true
+	}
+}
+
+// ChangeRecord is a mutation of the updated config recorded in the audit
+// log.
+type ChangeRecord struct {
+	// Time is the time of the mutation according to the clock of the
+	// ConfigTx.
+	Time time.Time
+	// Caller is the function outside of this package that called the
+	// mutating method, with its file and line, e.g.
+	// "main.rotateCerts (/src/rotate.go:42)".
+	Caller string
+	// Operation is the name of the mutating method, e.g. AddAnchorPeer.
+	Operation string
+	// Path is the config path of the group that was modified, e.g.
+	// /Channel/Application/Org1.
+	Path string
+	// Changes are the elements of the group changed by the mutation.
+	Changes []AuditedChange
+}
+
+// AuditedChange is a config element changed by a mutation.
+type AuditedChange struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Application/Org1/Values/AnchorPeers.
+	Path    string
+	Element ElementType
+	Change  ChangeType
+	// Old and New describe the element before and after the mutation like
+	// the content of an UpdateChange, followed by its mod policy. Old is
+	// empty for added elements and New for removed ones.
+	Old string
+	New string
+}
+
+// AuditLog returns the mutations recorded since the ConfigTx was created
+// with EnableAudit, oldest first. It returns nil if auditing is not
+// enabled.
+func (c *ConfigTx) AuditLog() []ChangeRecord {
+	return c.audit.records()
+}
+
+// auditLog holds the recorded mutations and a snapshot of the updated
+// config after the last of them, against which the next mutation is
+// compared. It is referenced by pointer so that copies of a ConfigTx share
+// the log, like they share the updated config.
+type auditLog struct {
+	mutex    sync.Mutex
+	snapshot *cb.ConfigGroup
+	log      []ChangeRecord
+}
+
+// newAuditLog returns an audit log of mutations of the config.
+func newAuditLog(config *cb.Config) *auditLog {
+	return &auditLog{snapshot: proto.Clone(config.ChannelGroup).(*cb.ConfigGroup)}
+}
+
+// record records the mutation of the group at path in the channel group
+// and updates the snapshot of the group.
+func (a *auditLog) record(channelGroup *cb.ConfigGroup, path, operation string, now time.Time) {
+	if a == nil {
+		return
+	}
+
+	caller := auditCaller()
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	before, _ := configGroupAtPath(a.snapshot, elements[1:])
+	after, _ := configGroupAtPath(channelGroup, elements[1:])
+
+	record := ChangeRecord{
+		Time:      now,
+		Caller:    caller,
+		Operation: operation,
+		Path:      path,
+	}
+
+	switch {
+	case before == nil && after == nil:
+	case before == nil:
+		record.Changes = []AuditedChange{{Path: path, Element: ElementGroup, Change: ChangeAdded, New: describeGroupModPolicy(after)}}
+	case after == nil:
+		record.Changes = []AuditedChange{{Path: path, Element: ElementGroup, Change: ChangeRemoved, Old: describeGroupModPolicy(before)}}
+	default:
+		for _, difference := range diffGroups(path, before, after) {
+			record.Changes = append(record.Changes, auditedChange(difference, before, after, path))
+		}
+	}
+
+	a.log = append(a.log, record)
+
+	if len(elements) == 1 {
+		a.snapshot = proto.Clone(channelGroup).(*cb.ConfigGroup)
+		return
+	}
+
+	parent, _ := configGroupAtPath(a.snapshot, elements[1:len(elements)-1])
+	if parent == nil {
+		// the parent was created by a change that was not recorded
+		a.snapshot = proto.Clone(channelGroup).(*cb.ConfigGroup)
+		return
+	}
+
+	name := elements[len(elements)-1]
+	if after == nil {
+		delete(parent.Groups, name)
+	} else {
+		parent.Groups[name] = proto.Clone(after).(*cb.ConfigGroup)
+	}
+}
+
+// records returns a copy of the recorded mutations.
+func (a *auditLog) records() []ChangeRecord {
+	if a == nil {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return append([]ChangeRecord(nil), a.log...)
+}
+
+// auditedChange describes the content of a difference between the groups
+// before and after a mutation of the group at groupPath.
+func auditedChange(difference Difference, before, after *cb.ConfigGroup, groupPath string) AuditedChange {
+	change := AuditedChange{
+		Path:    difference.Path,
+		Element: difference.Element,
+		Change:  difference.Change,
+	}
+
+	// the path elements of the difference beneath the group at groupPath
+	_, elements, err := parseConfigPath(difference.Path)
+	if err != nil {
+		return change
+	}
+	elements = elements[len(strings.Split(groupPath, "/"))-2:]
+
+	// values and policies are described from the group that holds them
+	groupElements, name := elements, ""
+	if difference.Element != ElementGroup {
+		groupElements, name = elements[:len(elements)-1], elements[len(elements)-1]
+	}
+
+	describe := func(group *cb.ConfigGroup) string {
+		group, _ = configGroupAtPath(group, groupElements)
+		if group == nil {
+			return ""
+		}
+
+		switch difference.Element {
+		case ElementValue:
+			value, ok := group.Values[name]
+			if !ok {
+				return ""
+			}
+			content, err := describeValue(name, value.Value)
+			if err != nil {
+				content = fmt.Sprintf("<%d bytes>", len(value.Value))
+			}
+			return fmt.Sprintf("%s (mod policy %s)", content, value.ModPolicy)
+		case ElementPolicy:
+			policy, ok := group.Policies[name]
+			if !ok {
+				return ""
+			}
+			return fmt.Sprintf("%s (mod policy %s)", policyRuleString(policy.Policy), policy.ModPolicy)
+		default:
+			return describeGroupModPolicy(group)
+		}
+	}
+
+	if change.Change != ChangeAdded {
+		change.Old = describe(before)
+	}
+	if change.Change != ChangeRemoved {
+		change.New = describe(after)
+	}
+
+	return change
+}
+
+// describeGroupModPolicy describes the mod policy of a group.
+func describeGroupModPolicy(group *cb.ConfigGroup) string {
+	return fmt.Sprintf("mod policy %s", group.ModPolicy)
+}
+
+// auditPackage is the prefix of the names of the functions of this
+// package.
+const auditPackage = "github.com/hyperledger/fabric-config/configtx."
+
+// auditCaller returns the first function on the call stack that is not
+// part of this package, with its file and line.
+func auditCaller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, auditPackage) || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
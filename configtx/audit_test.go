@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestAuditLog(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := New(config, EnableAudit(), WithClock(fixedClock(now)))
+
+	err = c.Orderer().BatchSize().SetMaxMessageCount(42)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// copies of the ConfigTx share the audit log
+	copied := c
+	copied.Application().RemoveOrganization("Org2")
+
+	// failed mutations are not recorded
+	err = c.Application().Organization("Org1").SetPolicy(AdminsPolicyKey, "Invalid", Policy{Type: "Unknown"})
+	gt.Expect(err).To(HaveOccurred())
+
+	records := c.AuditLog()
+	gt.Expect(records).To(HaveLen(3))
+
+	for _, record := range records {
+		gt.Expect(record.Time).To(Equal(now))
+		gt.Expect(record.Caller).To(ContainSubstring("configtx.TestAuditLog"))
+		gt.Expect(record.Caller).To(ContainSubstring("audit_test.go:"))
+	}
+
+	gt.Expect(records[0].Operation).To(Equal("SetMaxMessageCount"))
+	gt.Expect(records[0].Path).To(Equal("/Channel/Orderer"))
+	gt.Expect(records[0].Changes).To(HaveLen(1))
+	gt.Expect(records[0].Changes[0].Path).To(Equal("/Channel/Orderer/Values/BatchSize"))
+	gt.Expect(records[0].Changes[0].Element).To(Equal(ElementValue))
+	gt.Expect(records[0].Changes[0].Change).To(Equal(ChangeModified))
+	gt.Expect(records[0].Changes[0].Old).To(ContainSubstring(`"maxMessageCount":100`))
+	gt.Expect(records[0].Changes[0].New).To(ContainSubstring(`"maxMessageCount":42`))
+	gt.Expect(records[0].Changes[0].New).To(HaveSuffix("(mod policy Admins)"))
+
+	gt.Expect(records[1]).To(Equal(ChangeRecord{
+		Time:      now,
+		Caller:    records[1].Caller,
+		Operation: "AddAnchorPeer",
+		Path:      "/Channel/Application/Org1",
+		Changes: []AuditedChange{
+			{
+				Path:    "/Channel/Application/Org1/Values/AnchorPeers",
+				Element: ElementValue,
+				Change:  ChangeAdded,
+				New:     `{"anchorPeers":[{"host":"peer0.org1","port":7051}]} (mod policy Admins)`,
+			},
+		},
+	}))
+
+	gt.Expect(records[2].Operation).To(Equal("RemoveOrganization"))
+	gt.Expect(records[2].Path).To(Equal("/Channel/Application"))
+	gt.Expect(records[2].Changes).To(Equal([]AuditedChange{
+		{
+			Path:    "/Channel/Application/Org2",
+			Element: ElementGroup,
+			Change:  ChangeRemoved,
+			Old:     "mod policy Admins",
+		},
+	}))
+}
+
+func TestAuditLogDisabled(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(c.AuditLog()).To(BeNil())
+}
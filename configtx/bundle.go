@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/jsonpb"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// OrgBundleVersion is the version of the org bundle format written by
+// ExportOrgBundle.
+const OrgBundleVersion = 1
+
+// orgBundle is the JSON encoding of an org bundle. The MSP is encoded
+// using the canonical protobuf JSON mapping of the Fabric MSP config, as
+// it appears in the channel config.
+type orgBundle struct {
+	Version          int                        `json:"version"`
+	Name             string                     `json:"name"`
+	MSP              json.RawMessage            `json:"msp"`
+	Policies         map[string]orgBundlePolicy `json:"policies,omitempty"`
+	AnchorPeers      []orgBundleAddress         `json:"anchor_peers,omitempty"`
+	OrdererEndpoints []string                   `json:"orderer_endpoints,omitempty"`
+}
+
+type orgBundlePolicy struct {
+	Type string `json:"type"`
+	Rule string `json:"rule"`
+}
+
+type orgBundleAddress struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+// ExportOrgBundle encodes the organization, i.e. its MSP, policies, anchor
+// peers and orderer endpoints, as a single JSON org bundle. An organization
+// joining a channel hands the bundle to the existing members, who pass the
+// organization returned by ImportOrgBundle to SetOrganization.
+func ExportOrgBundle(org Organization) ([]byte, error) {
+	if org.Name == "" {
+		return nil, errors.New("organization name is required")
+	}
+
+	fabricMSPConfig, err := org.MSP.toProto()
+	if err != nil {
+		return nil, fmt.Errorf("converting fabric msp config to proto: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = (&jsonpb.Marshaler{}).Marshal(buf, fabricMSPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("encoding msp config of %s as JSON: %w", org.Name, err)
+	}
+
+	bundle := orgBundle{
+		Version:          OrgBundleVersion,
+		Name:             org.Name,
+		MSP:              buf.Bytes(),
+		OrdererEndpoints: org.OrdererEndpoints,
+	}
+
+	if len(org.Policies) > 0 {
+		bundle.Policies = map[string]orgBundlePolicy{}
+		for name, policy := range org.Policies {
+			bundle.Policies[name] = orgBundlePolicy{Type: policy.Type, Rule: policy.Rule}
+		}
+	}
+
+	for _, anchorPeer := range org.AnchorPeers {
+		bundle.AnchorPeers = append(bundle.AnchorPeers, orgBundleAddress{Host: anchorPeer.Host, Port: anchorPeer.Port})
+	}
+
+	return json.MarshalIndent(bundle, "", "  ")
+}
+
+// ImportOrgBundle decodes an org bundle created by ExportOrgBundle, upgrading
+// bundles exported by older versions of this library, and returns the
+// organization it describes.
+func ImportOrgBundle(bundle []byte) (Organization, error) {
+	bundle, err := orgBundleSchema.upgrade(bundle)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	b := orgBundle{}
+	err = json.Unmarshal(bundle, &b)
+	if err != nil {
+		return Organization{}, fmt.Errorf("decoding org bundle: %w", err)
+	}
+
+	if b.Name == "" {
+		return Organization{}, errors.New("org bundle does not contain an organization name")
+	}
+
+	if len(b.MSP) == 0 {
+		return Organization{}, fmt.Errorf("org bundle of %s does not contain an MSP", b.Name)
+	}
+
+	fabricMSPConfig := &mb.FabricMSPConfig{}
+	err = jsonpb.Unmarshal(bytes.NewReader(b.MSP), fabricMSPConfig)
+	if err != nil {
+		return Organization{}, fmt.Errorf("decoding msp config of %s: %w", b.Name, err)
+	}
+
+	msp, err := mspFromProto(fabricMSPConfig, nil)
+	if err != nil {
+		return Organization{}, fmt.Errorf("parsing msp config of %s: %w", b.Name, err)
+	}
+
+	org := Organization{
+		Name:             b.Name,
+		MSP:              msp,
+		OrdererEndpoints: b.OrdererEndpoints,
+	}
+
+	if len(b.Policies) > 0 {
+		org.Policies = map[string]Policy{}
+		for name, policy := range b.Policies {
+			org.Policies[name] = Policy{Type: policy.Type, Rule: policy.Rule}
+		}
+	}
+
+	for _, anchorPeer := range b.AnchorPeers {
+		org.AnchorPeers = append(org.AnchorPeers, Address{Host: anchorPeer.Host, Port: anchorPeer.Port})
+	}
+
+	return org, nil
+}
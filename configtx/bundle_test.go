@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestOrgBundle(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	baseMSP, _ := baseMSP(t)
+	org := Organization{
+		Name:     "Org3",
+		Policies: applicationOrgStandardPolicies(),
+		MSP:      baseMSP,
+		AnchorPeers: []Address{
+			{Host: "127.0.0.1", Port: 7051},
+		},
+	}
+
+	bundle, err := ExportOrgBundle(org)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	imported, err := ImportOrgBundle(bundle)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(imported).To(Equal(org))
+
+	err = c.Application().SetOrganization(imported)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedGroup, err := newApplicationOrgConfigGroup(org, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org3"], expectedGroup)).To(BeTrue())
+}
+
+func TestExportOrgBundleFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	_, err := ExportOrgBundle(Organization{})
+	gt.Expect(err).To(MatchError("organization name is required"))
+}
+
+func TestImportOrgBundleFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		bundle      string
+		expectedErr string
+	}{
+		{
+			name:        "when the bundle is not JSON",
+			bundle:      "bundle",
+			expectedErr: "decoding org bundle: invalid character 'b' looking for beginning of value",
+		},
+		{
+			name:        "when the version is unsupported",
+			bundle:      `{"version": 2, "name": "Org3"}`,
+			expectedErr: "unsupported org bundle version 2",
+		},
+		{
+			name:        "when the name is missing",
+			bundle:      `{"version": 1}`,
+			expectedErr: "org bundle does not contain an organization name",
+		},
+		{
+			name:        "when the MSP is missing",
+			bundle:      `{"version": 1, "name": "Org3"}`,
+			expectedErr: "org bundle of Org3 does not contain an MSP",
+		},
+		{
+			name:        "when the MSP is invalid",
+			bundle:      `{"version": 1, "name": "Org3", "msp": {"root_certs": ["Zm9v"]}}`,
+			expectedErr: "parsing msp config of Org3: parsing root certs: no PEM data found in cert[66 6f 6f]",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := ImportOrgBundle([]byte(tc.bundle))
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
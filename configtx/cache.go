@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// WithConfigurationCache caches the results of ChannelGroup.Configuration
+// and ApplicationGroup.Configuration until the updated config is next
+// mutated, so that repeated reads of a long-lived config transaction do not
+// decode the config again. The cached values are shared between callers and
+// must not be modified.
+func WithConfigurationCache() Option {
+	return func(o *options) {
+		o.configurationCache = true
+	}
+}
+
+// configCache holds the decoded configuration of the updated config. It is
+// referenced by pointer so that copies of a ConfigTx share the cache, like
+// they share the updated config.
+type configCache struct {
+	mutex       sync.Mutex
+	channel     *Channel
+	application *Application
+}
+
+// channelConfiguration returns the cached channel configuration, calling
+// decode to populate the cache if it is empty.
+func (c *configCache) channelConfiguration(decode func() (Channel, error)) (Channel, error) {
+	if c == nil {
+		return decode()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.channel != nil {
+		return *c.channel, nil
+	}
+
+	channel, err := decode()
+	if err != nil {
+		return Channel{}, err
+	}
+	c.channel = &channel
+
+	return channel, nil
+}
+
+// applicationConfiguration returns the cached application configuration,
+// calling decode to populate the cache if it is empty.
+func (c *configCache) applicationConfiguration(decode func() (Application, error)) (Application, error) {
+	if c == nil {
+		return decode()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.application != nil {
+		return *c.application, nil
+	}
+
+	application, err := decode()
+	if err != nil {
+		return Application{}, err
+	}
+	c.application = &application
+
+	return application, nil
+}
+
+// invalidate empties the cache.
+func (c *configCache) invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.channel = nil
+	c.application = nil
+}
+
+// maxCachedConfigs bounds the number of configs held by a ConfigCache.
+// When the bound is reached, the least recently added config is evicted.
+const maxCachedConfigs = 1024
+
+// ConfigCache caches decoded channel configs by channel ID and config
+// sequence, for services that manage many channels and repeatedly fetch
+// the same configs. Configs returned by Config are shared between callers
+// and must not be modified; ConfigTx copies the config before creating the
+// config transaction. The number of cached configs is bounded; the least
+// recently added config is evicted when the cache is full. A ConfigCache is
+// safe for concurrent use.
+type ConfigCache struct {
+	mutex   sync.Mutex
+	max     int
+	added   uint64
+	configs map[configCacheKey]cachedConfig
+}
+
+type configCacheKey struct {
+	channelID string
+	sequence  uint64
+}
+
+// cachedConfig is a cached config and the order in which it was added,
+// used to evict the oldest config when the cache is full.
+type cachedConfig struct {
+	config *cb.Config
+	added  uint64
+}
+
+// NewConfigCache returns an empty config cache.
+func NewConfigCache() *ConfigCache {
+	return &ConfigCache{
+		max:     maxCachedConfigs,
+		configs: map[configCacheKey]cachedConfig{},
+	}
+}
+
+// Config returns the config of the channel at the sequence. If it is not
+// cached, fetch is called to retrieve it, e.g. by fetching and decoding the
+// channel's latest config block, and the result is cached.
+func (c *ConfigCache) Config(channelID string, sequence uint64, fetch func() (*cb.Config, error)) (*cb.Config, error) {
+	key := configCacheKey{channelID: channelID, sequence: sequence}
+
+	c.mutex.Lock()
+	cached, ok := c.configs[key]
+	c.mutex.Unlock()
+	if ok {
+		return cached.config, nil
+	}
+
+	config, err := fetch()
+	if err != nil {
+		return nil, fmt.Errorf("fetching config of channel %s at sequence %d: %w", channelID, sequence, err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("fetching config of channel %s at sequence %d: no config returned", channelID, sequence)
+	}
+	if config.Sequence != sequence {
+		return nil, fmt.Errorf("fetched config of channel %s has sequence %d, expected %d", channelID, config.Sequence, sequence)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if cached, ok := c.configs[key]; ok {
+		return cached.config, nil
+	}
+	c.add(key, config)
+
+	return config, nil
+}
+
+// ConfigTx returns a config transaction for a copy of the config of the
+// channel at the sequence, fetching the config as Config does. The config
+// transaction never references the cached config, so it may be created
+// with any option, including WithoutClone.
+func (c *ConfigCache) ConfigTx(channelID string, sequence uint64, fetch func() (*cb.Config, error), opts ...Option) (ConfigTx, error) {
+	config, err := c.Config(channelID, sequence, fetch)
+	if err != nil {
+		return ConfigTx{}, err
+	}
+
+	return New(proto.Clone(config).(*cb.Config), opts...), nil
+}
+
+// Add caches the config of the channel under the config's sequence. A nil
+// config is ignored.
+func (c *ConfigCache) Add(channelID string, config *cb.Config) {
+	if config == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.add(configCacheKey{channelID: channelID, sequence: config.Sequence}, config)
+}
+
+// add caches the config under the key, evicting the least recently added
+// config if the cache is full. The caller must hold the mutex.
+func (c *ConfigCache) add(key configCacheKey, config *cb.Config) {
+	if _, ok := c.configs[key]; !ok && len(c.configs) >= c.max {
+		var oldest configCacheKey
+		first := true
+		for k, cached := range c.configs {
+			if first || cached.added < c.configs[oldest].added {
+				oldest = k
+				first = false
+			}
+		}
+		delete(c.configs, oldest)
+	}
+
+	c.added++
+	c.configs[key] = cachedConfig{config: config, added: c.added}
+}
+
+// Invalidate removes the config of the channel at the sequence.
+func (c *ConfigCache) Invalidate(channelID string, sequence uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.configs, configCacheKey{channelID: channelID, sequence: sequence})
+}
+
+// InvalidateBefore removes the configs of the channel with a sequence lower
+// than sequence. It is typically called when a channel's config is updated.
+func (c *ConfigCache) InvalidateBefore(channelID string, sequence uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.configs {
+		if key.channelID == channelID && key.sequence < sequence {
+			delete(c.configs, key)
+		}
+	}
+}
+
+// InvalidateChannel removes all configs of the channel.
+func (c *ConfigCache) InvalidateChannel(channelID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.configs {
+		if key.channelID == channelID {
+			delete(c.configs, key)
+		}
+	}
+}
+
+// Len returns the number of cached configs.
+func (c *ConfigCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.configs)
+}
@@ -0,0 +1,258 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigurationCache(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config, WithConfigurationCache())
+
+	channel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	application, err := c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.cache.channel).NotTo(BeNil())
+	gt.Expect(c.cache.application).NotTo(BeNil())
+
+	// copies of the config transaction share the cache
+	copied := c
+	cachedChannel, err := copied.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cachedChannel).To(Equal(channel))
+
+	// mutations invalidate the cache
+	err = c.Application().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.cache.channel).To(BeNil())
+	gt.Expect(c.cache.application).To(BeNil())
+
+	updatedApplication, err := c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updatedApplication.Capabilities).To(ContainElement("V2_0"))
+	gt.Expect(updatedApplication.Capabilities).NotTo(Equal(application.Capabilities))
+
+	updatedChannel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updatedChannel.Application.Capabilities).To(ContainElement("V2_0"))
+
+	// the updated config may be modified directly
+	updatedConfig := c.UpdatedConfig()
+	gt.Expect(c.cache.channel).To(BeNil())
+	delete(updatedConfig.ChannelGroup.Groups[ApplicationGroupKey].Groups, "Org1")
+
+	updatedApplication, err = c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updatedApplication.Organizations).To(HaveLen(len(application.Organizations) - 1))
+}
+
+func TestConfigurationCacheTransformers(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config, WithConfigurationCache(), WithTransformers(func(config *cb.Config) error {
+		config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].ModPolicy = WritersPolicyKey
+		return nil
+	}))
+
+	_, err = c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.cache.application).NotTo(BeNil())
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.cache.application).To(BeNil())
+}
+
+func TestConfigurationWithoutCache(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	gt.Expect(c.cache).To(BeNil())
+
+	_, err = c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestConfigCache(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	cache := NewConfigCache()
+
+	fetches := 0
+	fetch := func(sequence uint64) func() (*cb.Config, error) {
+		return func() (*cb.Config, error) {
+			fetches++
+			return &cb.Config{Sequence: sequence, ChannelGroup: &cb.ConfigGroup{}}, nil
+		}
+	}
+
+	config, err := cache.Config("channel1", 1, fetch(1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(config.Sequence).To(Equal(uint64(1)))
+
+	cached, err := cache.Config("channel1", 1, fetch(1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cached).To(BeIdenticalTo(config))
+	gt.Expect(fetches).To(Equal(1))
+
+	c, err := cache.ConfigTx("channel2", 1, fetch(1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.OriginalConfig().Sequence).To(Equal(uint64(1)))
+	gt.Expect(fetches).To(Equal(2))
+
+	cache.Add("channel1", &cb.Config{Sequence: 2})
+	cache.Add("channel1", &cb.Config{Sequence: 3})
+	gt.Expect(cache.Len()).To(Equal(4))
+
+	cache.Add("channel1", nil)
+	gt.Expect(cache.Len()).To(Equal(4))
+
+	cache.InvalidateBefore("channel1", 3)
+	gt.Expect(cache.Len()).To(Equal(2))
+
+	cache.Invalidate("channel2", 1)
+	gt.Expect(cache.Len()).To(Equal(1))
+
+	cache.InvalidateChannel("channel1")
+	gt.Expect(cache.Len()).To(Equal(0))
+
+	_, err = cache.Config("channel1", 1, fetch(1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fetches).To(Equal(3))
+}
+
+func TestConfigCacheConfigTxCopiesConfig(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	cache := NewConfigCache()
+	cache.Add("testchannel", &cb.Config{Sequence: 1, ChannelGroup: &cb.ConfigGroup{}})
+
+	fetch := func() (*cb.Config, error) { return nil, errors.New("not cached") }
+
+	c, err := cache.ConfigTx("testchannel", 1, fetch, WithoutClone())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c.updated.ChannelGroup.ModPolicy = AdminsPolicyKey
+
+	cached, err := cache.Config("testchannel", 1, fetch)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.OriginalConfig()).NotTo(BeIdenticalTo(cached))
+	gt.Expect(cached.ChannelGroup.ModPolicy).To(BeEmpty())
+}
+
+func TestConfigCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	cache := NewConfigCache()
+	cache.max = 2
+
+	cache.Add("channel1", &cb.Config{Sequence: 1})
+	cache.Add("channel1", &cb.Config{Sequence: 2})
+	cache.Add("channel1", &cb.Config{Sequence: 1})
+	gt.Expect(cache.Len()).To(Equal(2))
+
+	cache.Add("channel2", &cb.Config{Sequence: 1})
+	gt.Expect(cache.Len()).To(Equal(2))
+
+	fetched := false
+	fetch := func() (*cb.Config, error) {
+		fetched = true
+		return &cb.Config{Sequence: 1}, nil
+	}
+
+	_, err := cache.Config("channel1", 1, fetch)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = cache.Config("channel2", 1, fetch)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fetched).To(BeFalse())
+
+	_, err = cache.Config("channel1", 2, func() (*cb.Config, error) {
+		fetched = true
+		return &cb.Config{Sequence: 2}, nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fetched).To(BeTrue())
+}
+
+func TestConfigCacheFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		fetch       func() (*cb.Config, error)
+		expectedErr string
+	}{
+		{
+			name:        "fetch fails",
+			fetch:       func() (*cb.Config, error) { return nil, errors.New("not found") },
+			expectedErr: "fetching config of channel testchannel at sequence 2: not found",
+		},
+		{
+			name:        "no config",
+			fetch:       func() (*cb.Config, error) { return nil, nil },
+			expectedErr: "fetching config of channel testchannel at sequence 2: no config returned",
+		},
+		{
+			name:        "sequence mismatch",
+			fetch:       func() (*cb.Config, error) { return &cb.Config{Sequence: 3}, nil },
+			expectedErr: "fetched config of channel testchannel has sequence 3, expected 2",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			cache := NewConfigCache()
+			_, err := cache.Config("testchannel", 2, tc.fetch)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(cache.Len()).To(Equal(0))
+		})
+	}
+}
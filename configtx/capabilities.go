@@ -42,7 +42,7 @@ func addCapability(configGroup *cb.ConfigGroup, capabilities []string, modPolicy
 
 	err := setValue(configGroup, capabilitiesValue(capabilities), modPolicy)
 	if err != nil {
-		return fmt.Errorf("adding capability: %v", err)
+		return fmt.Errorf("adding capability: %w", err)
 	}
 
 	return nil
@@ -63,7 +63,7 @@ func removeCapability(configGroup *cb.ConfigGroup, capabilities []string, modPol
 
 	err := setValue(configGroup, capabilitiesValue(updatedCapabilities), modPolicy)
 	if err != nil {
-		return fmt.Errorf("removing capability: %v", err)
+		return fmt.Errorf("removing capability: %w", err)
 	}
 
 	return nil
@@ -80,7 +80,7 @@ func getCapabilities(configGroup *cb.ConfigGroup) ([]string, error) {
 
 	err := proto.Unmarshal(capabilitiesValue.Value, capabilitiesProto)
 	if err != nil {
-		return nil, fmt.Errorf("unmarshaling capabilities: %v", err)
+		return nil, fmt.Errorf("unmarshaling capabilities: %w", err)
 	}
 
 	capabilities := []string{}
@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VerifyCertificateChains verifies that the admin certificates of every
+// organization in the updated config chain to the root certificates of the
+// organization's MSP and that the TLS certificates of every etcdraft
+// consenter chain to the TLS root certificates of an orderer organization.
+// The chains are verified against the path length and name constraints of
+// the issuing CA certificates and against the validity periods of the
+// certificates of the chains as of the validity time of the ConfigTx,
+// catching certificates the MSP will refuse once the config update is
+// applied. The error wraps ValidationErrors with
+// a finding for each certificate that does not chain.
+func (c *ConfigTx) VerifyCertificateChains() error {
+	var findings ValidationErrors
+
+	orgs := orgGroupsByPath(c.updated.ChannelGroup)
+
+	var paths []string
+	for path := range orgs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	at := c.options.validityAt()
+
+	var ordererMSPs []MSP
+	for _, path := range paths {
+		msp, err := getMSPConfig(orgs[path], c.options.certificates)
+		if err != nil {
+			return fmt.Errorf("retrieving MSP of %s: %w", path, err)
+		}
+
+		if strings.HasPrefix(path, configPath(ChannelGroupKey, OrdererGroupKey)+"/") {
+			ordererMSPs = append(ordererMSPs, msp)
+		}
+
+		for _, cert := range msp.Admins {
+			err := verifyCertificateChain(cert, msp.RootCerts, msp.IntermediateCerts, at)
+			if err != nil {
+				findings = append(findings, fmt.Errorf("admin certificate %s of %s: %w", cert.Subject, path, err))
+			}
+		}
+	}
+
+	consenters, err := etcdRaftConsenters(c.updated.ChannelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return fmt.Errorf("retrieving consenters: %w", err)
+	}
+
+	for _, consenter := range consenters {
+		for _, field := range []struct {
+			name string
+			cert *x509.Certificate
+		}{
+			{"client", consenter.ClientTLSCert},
+			{"server", consenter.ServerTLSCert},
+		} {
+			if field.cert == nil {
+				continue
+			}
+
+			err := verifyTLSCertificateChain(ordererMSPs, field.cert, at)
+			if err != nil {
+				findings = append(findings, fmt.Errorf("%s TLS certificate of consenter %s:%d: %w", field.name, consenter.Address.Host, consenter.Address.Port, err))
+			}
+		}
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("invalid certificate chains: %w", findings)
+	}
+
+	return nil
+}
+
+// VerifyCertificateChain verifies that cert chains to one of the root
+// certificates of the MSP through its intermediate certificates, honoring
+// the path length and name constraints of the CA certificates.
+func (m *MSP) VerifyCertificateChain(cert *x509.Certificate) error {
+	return verifyCertificateChain(cert, m.RootCerts, m.IntermediateCerts, time.Time{})
+}
+
+// VerifyTLSCertificateChain verifies that cert chains to one of the TLS
+// root certificates of the MSP through its TLS intermediate certificates,
+// honoring the path length and name constraints of the CA certificates.
+func (m *MSP) VerifyTLSCertificateChain(cert *x509.Certificate) error {
+	return verifyCertificateChain(cert, m.TLSRootCerts, m.TLSIntermediateCerts, time.Time{})
+}
+
+// verifyTLSCertificateChain verifies that cert chains to the TLS root
+// certificates of any of the MSPs as of at.
+func verifyTLSCertificateChain(msps []MSP, cert *x509.Certificate, at time.Time) error {
+	if len(msps) == 0 {
+		return errors.New("no orderer organization MSPs to verify against")
+	}
+
+	var err error
+	for _, msp := range msps {
+		err = verifyCertificateChain(cert, msp.TLSRootCerts, msp.TLSIntermediateCerts, at)
+		if err == nil {
+			return nil
+		}
+	}
+
+	if len(msps) > 1 {
+		return fmt.Errorf("not issued by the TLS CAs of any orderer organization: %w", err)
+	}
+
+	return err
+}
+
+// verifyCertificateChain verifies that cert chains to one of the root
+// certificates through the intermediate certificates as of at, or as of the
+// current time if at is zero.
+func verifyCertificateChain(cert *x509.Certificate, rootCerts, intermediateCerts []*x509.Certificate, at time.Time) error {
+	roots := x509.NewCertPool()
+	for _, rootCert := range rootCerts {
+		roots.AddCert(rootCert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, intermediateCert := range intermediateCerts {
+		intermediates.AddCert(intermediateCert)
+	}
+
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		CurrentTime:   at,
+	})
+
+	return err
+}
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestVerifyCertificateChains(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	rootCert, rootPrivKey := generateConstrainedCACert(t, "ca.example.com", nil, nil, -1)
+	intermediateCert, intermediatePrivKey := generateConstrainedCACert(t, "intermediateca.example.com", rootCert, rootPrivKey, 0)
+	adminCert, _ := generateLeafCert(t, "admin.example.com", intermediateCert, intermediatePrivKey)
+
+	tlsRootCert, tlsRootPrivKey := generateConstrainedCACert(t, "tlsca.example.com", nil, nil, -1, "example.com")
+	tlsCert := generateServerTLSCert(t, tlsRootCert, tlsRootPrivKey, []string{"node-1.example.com"}, nil)
+
+	c := constrainedCertsConfigTx(t, rootCert, intermediateCert, adminCert, tlsRootCert, tlsCert)
+
+	err := c.VerifyCertificateChains()
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestVerifyCertificateChainsFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	rootCert, rootPrivKey := generateConstrainedCACert(t, "ca.example.com", nil, nil, 0)
+	intermediateCert, intermediatePrivKey := generateConstrainedCACert(t, "intermediateca.example.com", rootCert, rootPrivKey, 0)
+	adminCert, _ := generateLeafCert(t, "admin.example.com", intermediateCert, intermediatePrivKey)
+
+	tlsRootCert, tlsRootPrivKey := generateConstrainedCACert(t, "tlsca.example.com", nil, nil, -1, "example.com")
+	tlsCert := generateServerTLSCert(t, tlsRootCert, tlsRootPrivKey, []string{"node-1.example.org"}, nil)
+
+	c := constrainedCertsConfigTx(t, rootCert, intermediateCert, adminCert, tlsRootCert, tlsCert)
+
+	err := c.VerifyCertificateChains()
+	gt.Expect(err).To(MatchError(ContainSubstring("admin certificate CN=admin.example.com of /Channel/Orderer/OrdererOrg: x509: too many intermediates for path length constraint")))
+	gt.Expect(err).To(MatchError(ContainSubstring("server TLS certificate of consenter node-1.example.com:7050: x509: a root or intermediate certificate is not authorized to sign for this name")))
+	gt.Expect(err).NotTo(MatchError(ContainSubstring("client TLS certificate")))
+}
+
+func TestVerifyCertificateChainsAsOfValidityTime(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	rootCert, rootPrivKey := generateConstrainedCACert(t, "ca.example.com", nil, nil, -1)
+	intermediateCert, intermediatePrivKey := generateConstrainedCACert(t, "intermediateca.example.com", rootCert, rootPrivKey, 0)
+	adminCert, _ := generateLeafCert(t, "admin.example.com", intermediateCert, intermediatePrivKey)
+
+	tlsRootCert, tlsRootPrivKey := generateConstrainedCACert(t, "tlsca.example.com", nil, nil, -1, "example.com")
+	tlsCert := generateServerTLSCert(t, tlsRootCert, tlsRootPrivKey, []string{"node-1.example.com"}, nil)
+
+	c := constrainedCertsConfigTx(t, rootCert, intermediateCert, adminCert, tlsRootCert, tlsCert)
+	c = New(c.OriginalConfig(), WithValidityTime(time.Now().Add(2*YEAR)))
+
+	err := c.VerifyCertificateChains()
+	gt.Expect(err).To(MatchError(ContainSubstring("admin certificate CN=admin.example.com of /Channel/Orderer/OrdererOrg: x509: certificate has expired or is not yet valid")))
+	gt.Expect(err).To(MatchError(ContainSubstring("server TLS certificate of consenter node-1.example.com:7050: x509: certificate has expired or is not yet valid")))
+}
+
+func TestMSPVerifyCertificateChain(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	rootCert, rootPrivKey := generateConstrainedCACert(t, "ca.example.com", nil, nil, -1)
+	cert, _ := generateLeafCert(t, "user.example.com", rootCert, rootPrivKey)
+	otherRootCert, _ := generateConstrainedCACert(t, "ca.example.com", nil, nil, -1)
+
+	msp := MSP{
+		RootCerts:    []*x509.Certificate{rootCert},
+		TLSRootCerts: []*x509.Certificate{otherRootCert},
+	}
+
+	err := msp.VerifyCertificateChain(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = msp.VerifyTLSCertificateChain(cert)
+	gt.Expect(err).To(MatchError(ContainSubstring("x509: certificate signed by unknown authority")))
+}
+
+// constrainedCertsConfigTx returns a config transaction for an etcdraft
+// orderer whose organization uses the given CA certificates and admin
+// certificate and whose consenters use the given TLS certificate.
+func constrainedCertsConfigTx(t *testing.T, rootCert, intermediateCert, adminCert, tlsRootCert, tlsCert *x509.Certificate) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
+	msp := baseOrdererConf.Organizations[0].MSP
+	msp.RootCerts = []*x509.Certificate{rootCert}
+	msp.IntermediateCerts = []*x509.Certificate{intermediateCert}
+	msp.Admins = []*x509.Certificate{adminCert}
+	msp.TLSRootCerts = []*x509.Certificate{tlsRootCert}
+	msp.TLSIntermediateCerts = nil
+	baseOrdererConf.Organizations[0].MSP = msp
+
+	baseOrdererConf.EtcdRaft.Consenters = baseOrdererConf.EtcdRaft.Consenters[:1]
+	baseOrdererConf.EtcdRaft.Consenters[0].ClientTLSCert = tlsRootCert
+	baseOrdererConf.EtcdRaft.Consenters[0].ServerTLSCert = tlsCert
+
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+}
+
+// generateConstrainedCACert returns a CA certificate with the given path
+// length constraint and permitted DNS domains. The certificate is self
+// signed when parent is nil. A negative maxPathLen leaves the path length
+// unconstrained.
+func generateConstrainedCACert(t *testing.T, commonName string, parent *x509.Certificate, parentPrivKey *ecdsa.PrivateKey, maxPathLen int, permittedDNSDomains ...string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	template := &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(YEAR),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            maxPathLen,
+		MaxPathLenZero:        maxPathLen == 0,
+		PermittedDNSDomains:   permittedDNSDomains,
+	}
+
+	if parent == nil {
+		parent = template
+	}
+
+	return generateCertAndPrivateKey(t, template, parent, parentPrivKey)
+}
+
+// generateLeafCert returns an end-entity certificate signed by the given CA.
+func generateLeafCert(t *testing.T, commonName string, caCert *x509.Certificate, caPrivKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	template := &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(YEAR),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	}
+
+	return generateCertAndPrivateKey(t, template, caCert, caPrivKey)
+}
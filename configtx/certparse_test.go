@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseCertificates(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	certs, err := c.ParseCertificates(2)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(certs).To(HaveKeyWithValue("/Channel/Application/Org1/MSP/root_certs[0]", application.Organizations[0].MSP.RootCerts[0]))
+	gt.Expect(certs).To(HaveKeyWithValue("/Channel/Application/Org2/MSP/tls_root_certs[0]", application.Organizations[1].MSP.TLSRootCerts[0]))
+	gt.Expect(certs).To(HaveKey("/Channel/Application/Org1/MSP/fabric_node_ous/client_ou_identifier"))
+}
+
+func TestParseCertificatesFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for _, orgName := range []string{"Org1", "Org2"} {
+		fabricMSPConfig, err := getFabricMSPConfig(appGroup.Groups[orgName])
+		gt.Expect(err).NotTo(HaveOccurred())
+		fabricMSPConfig.Admins = append(fabricMSPConfig.Admins, []byte("not a certificate"))
+		appGroup.Groups[orgName].Values[MSPKey].Value = marshalOrPanic(&mb.MSPConfig{Config: marshalOrPanic(fabricMSPConfig)})
+	}
+	appGroup.Groups["Org3"] = &cb.ConfigGroup{
+		Values: map[string]*cb.ConfigValue{
+			MSPKey: {Value: []byte("garbage")},
+		},
+	}
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	certs, err := c.ParseCertificates(0)
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(certs).To(HaveKey("/Channel/Application/Org1/MSP/root_certs[0]"))
+
+	var findings ValidationErrors
+	gt.Expect(errors.As(err, &findings)).To(BeTrue())
+	gt.Expect(findings).To(HaveLen(3))
+	gt.Expect(findings[0]).To(MatchError(ContainSubstring("retrieving MSP of /Channel/Application/Org3: ")))
+
+	var certErr CertificateError
+	gt.Expect(errors.As(findings[1], &certErr)).To(BeTrue())
+	gt.Expect(certErr.Path).To(Equal("/Channel/Application/Org1/MSP/admins[1]"))
+	gt.Expect(findings[1]).To(MatchError(ContainSubstring("certificate /Channel/Application/Org1/MSP/admins[1]: no PEM data found in cert")))
+	gt.Expect(errors.As(findings[2], &certErr)).To(BeTrue())
+	gt.Expect(certErr.Path).To(Equal("/Channel/Application/Org2/MSP/admins[1]"))
+}
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 )
@@ -16,15 +18,27 @@ import (
 // This type implements retrieval of the various channel config values.
 type ChannelGroup struct {
 	channelGroup *cb.ConfigGroup
+	tx           *ConfigTx
 }
 
 // Channel returns the channel group from the updated config.
 func (c *ConfigTx) Channel() *ChannelGroup {
-	return &ChannelGroup{channelGroup: c.updated.ChannelGroup}
+	return &ChannelGroup{channelGroup: c.updated.ChannelGroup, tx: c}
 }
 
 // Configuration returns a channel configuration value from a config transaction.
+// It is decoded from the updated config, so it reflects the changes made
+// through the ConfigTx; the channel configuration before the update is
+// decoded from a ConfigTx created from OriginalConfig.
 func (c *ChannelGroup) Configuration() (Channel, error) {
+	if c.tx != nil {
+		return c.tx.cache.channelConfiguration(c.configuration)
+	}
+
+	return c.configuration()
+}
+
+func (c *ChannelGroup) configuration() (Channel, error) {
 	var (
 		config Channel
 		err    error
@@ -40,8 +54,8 @@ func (c *ChannelGroup) Configuration() (Channel, error) {
 	}
 
 	if applicationGroup, ok := c.channelGroup.Groups[ApplicationGroupKey]; ok {
-		a := &ApplicationGroup{applicationGroup: applicationGroup}
-		config.Application, err = a.Configuration()
+		a := &ApplicationGroup{applicationGroup: applicationGroup, tx: c.tx}
+		config.Application, err = a.configuration()
 		if err != nil {
 			return Channel{}, err
 		}
@@ -75,6 +89,11 @@ func (c *ChannelGroup) Configuration() (Channel, error) {
 		return Channel{}, err
 	}
 
+	config.Extensions, err = getExtensions(c.channelGroup, c.tx.valueTypes())
+	if err != nil {
+		return Channel{}, fmt.Errorf("retrieving channel extensions: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -86,7 +105,14 @@ func (c *ChannelGroup) Policies() (map[string]Policy, error) {
 // SetPolicy sets the specified policy in the channel group's config policy map.
 // If the policy already exist in current configuration, its value will be overwritten.
 func (c *ChannelGroup) SetPolicy(modPolicy, policyName string, policy Policy) error {
-	return setPolicy(c.channelGroup, modPolicy, policyName, policy)
+	err := setPolicy(c.channelGroup, modPolicy, policyName, policy)
+	if err != nil {
+		return err
+	}
+
+	c.tx.notify(c.path(), "SetPolicy")
+
+	return nil
 }
 
 // RemovePolicy removes an existing channel level policy.
@@ -97,6 +123,9 @@ func (c *ChannelGroup) RemovePolicy(policyName string) error {
 	}
 
 	removePolicy(c.channelGroup, policyName, policies)
+
+	c.tx.notify(c.path(), "RemovePolicy")
+
 	return nil
 }
 
@@ -105,7 +134,7 @@ func (c *ChannelGroup) RemovePolicy(policyName string) error {
 func (c *ChannelGroup) Capabilities() ([]string, error) {
 	capabilities, err := getCapabilities(c.channelGroup)
 	if err != nil {
-		return nil, fmt.Errorf("retrieving channel capabilities: %v", err)
+		return nil, fmt.Errorf("retrieving channel capabilities: %w", err)
 	}
 
 	return capabilities, nil
@@ -125,6 +154,10 @@ func (c *ChannelGroup) AddCapability(capability string) error {
 		return err
 	}
 
+	c.tx.checkCapabilityLevels()
+
+	c.tx.notify(c.path(), "AddCapability")
+
 	return nil
 }
 
@@ -140,13 +173,233 @@ func (c *ChannelGroup) RemoveCapability(capability string) error {
 		return err
 	}
 
+	c.tx.checkCapabilityLevels()
+
+	c.tx.notify(c.path(), "RemoveCapability")
+
 	return nil
 }
 
+// ChannelValue is a value of the channel group, which may not be modeled
+// by this package.
+type ChannelValue struct {
+	Key       string
+	Version   uint64
+	ModPolicy string
+	// Value is the marshaled value, which can be used to display or
+	// preserve a value whose type is unknown.
+	Value []byte
+	// Known is set for the values modeled by this package: Consortium,
+	// HashingAlgorithm, BlockDataHashingStructure, OrdererAddresses and
+	// Capabilities.
+	Known bool
+}
+
+// Values returns every value of the channel group, including those that are
+// not modeled by this package, sorted by key.
+func (c *ChannelGroup) Values() []ChannelValue {
+	var values []ChannelValue
+	for _, key := range sortedKeys(c.channelGroup.Values) {
+		value := c.channelGroup.Values[key]
+		values = append(values, ChannelValue{
+			Key:       key,
+			Version:   value.Version,
+			ModPolicy: value.ModPolicy,
+			Value:     value.Value,
+			Known:     knownChannelValues[key],
+		})
+	}
+
+	return values
+}
+
+// knownChannelValues are the keys of the channel values modeled by this
+// package.
+var knownChannelValues = map[string]bool{
+	ConsortiumKey:                true,
+	HashingAlgorithmKey:          true,
+	BlockDataHashingStructureKey: true,
+	OrdererAddressesKey:          true,
+	CapabilitiesKey:              true,
+}
+
 // RemoveLegacyOrdererAddresses removes the deprecated top level orderer addresses config key and value
 // from the channel config.
 // In fabric 1.4, top level orderer addresses were migrated to the org level orderer endpoints
 // While top-level orderer addresses are still supported, the organization value is preferred.
 func (c *ChannelGroup) RemoveLegacyOrdererAddresses() {
 	delete(c.channelGroup.Values, OrdererAddressesKey)
+
+	c.tx.notify(c.path(), "RemoveLegacyOrdererAddresses")
+}
+
+// AddLegacyOrdererAddress adds the address, in host:port form, to the
+// deprecated top level orderer addresses of the channel config. It is
+// intended for channels that have not yet migrated to the org level orderer
+// endpoints; an error is returned if the address is already listed.
+func (c *ChannelGroup) AddLegacyOrdererAddress(address string) error {
+	address, err := parseEndpointAddress(address)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := ordererAddresses(c.channelGroup, OrdererAddressesKey)
+	if err != nil {
+		return fmt.Errorf("retrieving orderer addresses: %w", err)
+	}
+
+	for _, existing := range addresses {
+		if sameAddress(existing, address) {
+			return fmt.Errorf("orderer address %s already exists", address)
+		}
+	}
+
+	err = c.setLegacyOrdererAddresses(append(addresses, address))
+	if err != nil {
+		return fmt.Errorf("adding orderer address %s: %w", address, err)
+	}
+
+	c.tx.notify(c.path(), "AddLegacyOrdererAddress")
+
+	c.tx.checkLegacyOrdererAddresses()
+
+	return nil
+}
+
+// RemoveLegacyOrdererAddress removes the address, in host:port form, from
+// the deprecated top level orderer addresses of the channel config. An
+// error is returned if the address is not listed.
+func (c *ChannelGroup) RemoveLegacyOrdererAddress(address string) error {
+	address, err := parseEndpointAddress(address)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := ordererAddresses(c.channelGroup, OrdererAddressesKey)
+	if err != nil {
+		return fmt.Errorf("retrieving orderer addresses: %w", err)
+	}
+
+	remaining, removed := removeAddress(addresses, address)
+	if !removed {
+		return fmt.Errorf("orderer address %s does not exist", address)
+	}
+
+	err = c.setLegacyOrdererAddresses(remaining)
+	if err != nil {
+		return fmt.Errorf("removing orderer address %s: %w", address, err)
+	}
+
+	c.tx.notify(c.path(), "RemoveLegacyOrdererAddress")
+
+	return nil
+}
+
+// setLegacyOrdererAddresses sets the top level orderer addresses, keeping
+// the mod policy of the existing value. New values are modified by the
+// orderer admins, as in configs generated by configtxgen.
+func (c *ChannelGroup) setLegacyOrdererAddresses(addresses []string) error {
+	modPolicy := configPath(ChannelGroupKey, OrdererGroupKey, AdminsPolicyKey)
+	if existing, ok := c.channelGroup.Values[OrdererAddressesKey]; ok && existing.ModPolicy != "" {
+		modPolicy = existing.ModPolicy
+	}
+
+	return setValue(c.channelGroup, &standardConfigValue{
+		key:   OrdererAddressesKey,
+		value: &cb.OrdererAddresses{Addresses: addresses},
+	}, modPolicy)
+}
+
+// RemoveConsortiums removes the Consortiums group and the Consortium value
+// from a channel config that was forked from an ordering system channel, so
+// that it is accepted by orderers that no longer use a system channel.
+// Mod policies in the remaining config that reference policies beneath
+// /Channel/Consortiums are reset to the Admins policy of their group.
+// Versions are left unchanged: like for every other change, the versions
+// of the channel group and the rewritten elements are incremented in the
+// write set by ComputeMarshaledUpdate. The channel must define an
+// Application group.
+func (c *ChannelGroup) RemoveConsortiums() error {
+	if _, ok := c.channelGroup.Groups[ApplicationGroupKey]; !ok {
+		return errors.New("channel config must contain an application group before consortiums can be removed")
+	}
+
+	_, hasConsortiums := c.channelGroup.Groups[ConsortiumsGroupKey]
+	_, hasConsortium := c.channelGroup.Values[ConsortiumKey]
+	if !hasConsortiums && !hasConsortium {
+		return nil
+	}
+
+	delete(c.channelGroup.Groups, ConsortiumsGroupKey)
+	delete(c.channelGroup.Values, ConsortiumKey)
+
+	resetModPolicies(c.channelGroup, configPath(ChannelGroupKey, ConsortiumsGroupKey)+"/", AdminsPolicyKey)
+
+	c.tx.notify(c.path(), "RemoveConsortiums")
+
+	return nil
+}
+
+// SetConsortiumName sets the Consortium value of the channel config to the
+// given consortium name, adding the value if it is missing. If the config
+// contains a Consortiums group, as the config of an ordering system channel
+// does, the consortium must be defined in it.
+func (c *ConfigTx) SetConsortiumName(name string) error {
+	if name == "" {
+		return errors.New("consortium name is required")
+	}
+
+	channelGroup := c.updated.ChannelGroup
+
+	if consortiumsGroup, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		if _, ok := consortiumsGroup.Groups[name]; !ok {
+			return fmt.Errorf("consortium %s does not exist", name)
+		}
+	}
+
+	modPolicy := AdminsPolicyKey
+	if existing, ok := channelGroup.Values[ConsortiumKey]; ok {
+		consortium := &cb.Consortium{}
+		err := unmarshalConfigValueAtKey(channelGroup, ConsortiumKey, consortium)
+		if err == nil && consortium.Name == name {
+			return nil
+		}
+
+		if existing.ModPolicy != "" {
+			modPolicy = existing.ModPolicy
+		}
+	}
+
+	err := setValue(channelGroup, consortiumValue(name), modPolicy)
+	if err != nil {
+		return err
+	}
+
+	c.notify(configPath(ChannelGroupKey), "SetConsortiumName")
+
+	return nil
+}
+
+// resetModPolicies replaces every mod policy in the group tree that starts
+// with prefix with the replacement policy.
+func resetModPolicies(group *cb.ConfigGroup, prefix, replacement string) {
+	if strings.HasPrefix(group.ModPolicy, prefix) {
+		group.ModPolicy = replacement
+	}
+
+	for _, value := range group.Values {
+		if strings.HasPrefix(value.ModPolicy, prefix) {
+			value.ModPolicy = replacement
+		}
+	}
+
+	for _, policy := range group.Policies {
+		if strings.HasPrefix(policy.ModPolicy, prefix) {
+			policy.ModPolicy = replacement
+		}
+	}
+
+	for _, subGroup := range group.Groups {
+		resetModPolicies(subGroup, prefix, replacement)
+	}
 }
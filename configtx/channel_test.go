@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-config/protolator/protoext/commonext"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -344,3 +345,242 @@ func TestRemoveLegacyOrdererAddresses(t *testing.T) {
 	_, exists := c.Channel().channelGroup.Values[OrdererAddressesKey]
 	gt.Expect(exists).To(BeFalse())
 }
+
+func TestChannelValues(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{},
+		},
+	}
+	err := setValue(config.ChannelGroup, capabilitiesValue([]string{"V2_0"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	config.ChannelGroup.Values["CustomValue"] = &cb.ConfigValue{
+		Version:   3,
+		ModPolicy: "/Channel/Orderer/Admins",
+		Value:     []byte("custom"),
+	}
+
+	c := New(config)
+
+	gt.Expect(c.Channel().Values()).To(Equal([]ChannelValue{
+		{
+			Key:       CapabilitiesKey,
+			ModPolicy: AdminsPolicyKey,
+			Value:     config.ChannelGroup.Values[CapabilitiesKey].Value,
+			Known:     true,
+		},
+		{
+			Key:       "CustomValue",
+			Version:   3,
+			ModPolicy: "/Channel/Orderer/Admins",
+			Value:     []byte("custom"),
+		},
+	}))
+}
+
+func TestRemoveConsortiums(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// fork the system channel config into an application channel config
+	appProfile, _, _ := baseApplicationChannelProfile(t)
+	applicationGroup, err := NewApplicationGroup(appProfile.Application)
+	gt.Expect(err).NotTo(HaveOccurred())
+	config.ChannelGroup.Groups[ApplicationGroupKey] = applicationGroup
+	config.ChannelGroup.Values[ConsortiumKey] = &cb.ConfigValue{
+		ModPolicy: "/Channel/Consortiums/Admins",
+		Value:     marshalOrPanic(&cb.Consortium{Name: "Consortium1"}),
+	}
+	config.ChannelGroup.Version = 3
+	config.ChannelGroup.Groups[OrdererGroupKey].Values["BatchSize"].ModPolicy = "/Channel/Consortiums/Admins"
+	config.ChannelGroup.Groups[OrdererGroupKey].Values["BatchSize"].Version = 2
+
+	c := New(config)
+
+	err = c.Channel().RemoveConsortiums()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	updated := c.UpdatedConfig().ChannelGroup
+	gt.Expect(updated.Groups).NotTo(HaveKey(ConsortiumsGroupKey))
+	gt.Expect(updated.Values).NotTo(HaveKey(ConsortiumKey))
+	gt.Expect(updated.Version).To(Equal(uint64(3)))
+	gt.Expect(updated.Groups[OrdererGroupKey].Values["BatchSize"].ModPolicy).To(Equal(AdminsPolicyKey))
+
+	// the original config is not modified
+	gt.Expect(c.OriginalConfig().ChannelGroup.Groups).To(HaveKey(ConsortiumsGroupKey))
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// the channel group and the rewritten value are written with their
+	// versions incremented once, the unchanged orderer group is not
+	writeSet := update.WriteSet
+	gt.Expect(writeSet.Version).To(Equal(uint64(4)))
+	gt.Expect(writeSet.Groups).NotTo(HaveKey(ConsortiumsGroupKey))
+	gt.Expect(writeSet.Values).NotTo(HaveKey(ConsortiumKey))
+	gt.Expect(writeSet.Groups[OrdererGroupKey].Version).To(Equal(config.ChannelGroup.Groups[OrdererGroupKey].Version))
+	gt.Expect(writeSet.Groups[OrdererGroupKey].Values).To(HaveLen(1))
+	gt.Expect(writeSet.Groups[OrdererGroupKey].Values["BatchSize"].Version).To(Equal(uint64(3)))
+	gt.Expect(writeSet.Groups[OrdererGroupKey].Values["BatchSize"].ModPolicy).To(Equal(AdminsPolicyKey))
+	gt.Expect(update.ReadSet.Version).To(Equal(uint64(3)))
+}
+
+func TestRemoveConsortiumsFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	err = c.Channel().RemoveConsortiums()
+	gt.Expect(err).To(MatchError("channel config must contain an application group before consortiums can be removed"))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups).To(HaveKey(ConsortiumsGroupKey))
+}
+
+func TestSetConsortiumName(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	delete(config.ChannelGroup.Values, ConsortiumKey)
+
+	var mutations []Mutation
+	c := New(config, WithObserver(func(m Mutation) {
+		mutations = append(mutations, m)
+	}))
+
+	// the missing value is added
+	err = c.SetConsortiumName("SampleConsortium")
+	gt.Expect(err).NotTo(HaveOccurred())
+	channel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Consortium).To(Equal("SampleConsortium"))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Values[ConsortiumKey].ModPolicy).To(Equal(AdminsPolicyKey))
+
+	// a wrong value is repaired, keeping its mod policy
+	c.UpdatedConfig().ChannelGroup.Values[ConsortiumKey].ModPolicy = "/Channel/Orderer/Admins"
+	err = c.SetConsortiumName("OtherConsortium")
+	gt.Expect(err).NotTo(HaveOccurred())
+	channel, err = c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Consortium).To(Equal("OtherConsortium"))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Values[ConsortiumKey].ModPolicy).To(Equal("/Channel/Orderer/Admins"))
+
+	// setting the current value is not a mutation
+	err = c.SetConsortiumName("OtherConsortium")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mutations).To(Equal([]Mutation{
+		{Path: "/Channel", Operation: "SetConsortiumName"},
+		{Path: "/Channel", Operation: "SetConsortiumName"},
+	}))
+}
+
+func TestSetConsortiumNameFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	err = c.SetConsortiumName("")
+	gt.Expect(err).To(MatchError("consortium name is required"))
+
+	err = c.SetConsortiumName("UnknownConsortium")
+	gt.Expect(err).To(MatchError("consortium UnknownConsortium does not exist"))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Values).NotTo(HaveKey(ConsortiumKey))
+
+	err = c.SetConsortiumName("Consortium1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	channel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Consortium).To(Equal("Consortium1"))
+}
+
+func TestAddRemoveLegacyOrdererAddress(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				OrdererAddressesKey: {
+					ModPolicy: "/Channel/Orderer/Admins",
+					Value: marshalOrPanic(&cb.OrdererAddresses{
+						Addresses: []string{"127.0.0.1:7050"},
+					}),
+				},
+			},
+		},
+	}
+
+	c := New(config)
+
+	err := c.Channel().AddLegacyOrdererAddress("127.0.0.1:8050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	addresses, err := ordererAddresses(c.UpdatedConfig().ChannelGroup, OrdererAddressesKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(addresses).To(Equal([]string{"127.0.0.1:7050", "127.0.0.1:8050"}))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Values[OrdererAddressesKey].ModPolicy).To(Equal("/Channel/Orderer/Admins"))
+
+	err = c.Channel().AddLegacyOrdererAddress("127.0.0.1:8050")
+	gt.Expect(err).To(MatchError("orderer address 127.0.0.1:8050 already exists"))
+
+	err = c.Channel().RemoveLegacyOrdererAddress("127.0.0.1:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	addresses, err = ordererAddresses(c.UpdatedConfig().ChannelGroup, OrdererAddressesKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(addresses).To(Equal([]string{"127.0.0.1:8050"}))
+
+	err = c.Channel().RemoveLegacyOrdererAddress("127.0.0.1:7050")
+	gt.Expect(err).To(MatchError("orderer address 127.0.0.1:7050 does not exist"))
+
+	err = c.Channel().AddLegacyOrdererAddress("orderer.example.com")
+	gt.Expect(err).To(MatchError("invalid endpoint orderer.example.com: address orderer.example.com: missing port in address"))
+}
+
+func TestAddLegacyOrdererAddressToNewValue(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c := New(&cb.Config{ChannelGroup: &cb.ConfigGroup{}})
+
+	err := c.Channel().AddLegacyOrdererAddress("127.0.0.1:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	value := c.UpdatedConfig().ChannelGroup.Values[OrdererAddressesKey]
+	gt.Expect(value.ModPolicy).To(Equal("/Channel/Orderer/Admins"))
+
+	addresses, err := ordererAddresses(c.UpdatedConfig().ChannelGroup, OrdererAddressesKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(addresses).To(Equal([]string{"127.0.0.1:7050"}))
+}
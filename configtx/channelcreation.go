@@ -0,0 +1,375 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+)
+
+// WithSystemChannelConfig checks the channel creation transactions created
+// with NewMarshaledCreateChannelTx against the config of the system
+// channel, so that transactions the orderer would reject fail up front with
+// guidance: the consortium of the channel must exist, the orgs of the
+// channel must be members of the consortium, the admins of the orgs must
+// be able to satisfy the channel creation policy of the consortium, and
+// the channel must not exceed the channel restrictions of the orderer.
+// channels is the number of application channels the ordering service
+// already serves, which the config does not record.
+func WithSystemChannelConfig(config *cb.Config, channels uint64) Option {
+	return func(o *options) {
+		o.systemChannelConfig = config
+		o.systemChannelChannels = channels
+	}
+}
+
+// VerifyCreateChannelTx checks a channel creation transaction, e.g. one
+// created with NewCreateChannelTx by a member organization, against the
+// config of the system channel that defines its consortium, so that the
+// consortium's operators can vet it before countersigning. It checks that
+// the consortium exists, that the organizations of the new channel are
+// members of the consortium and are not redefined by the transaction, and
+// that the signatures of the transaction are valid signatures of
+// identities of those organizations that satisfy the channel creation
+// policy of the consortium. Signatures are verified as of the time set
+// with WithValidityTime, or the current time, and the policy is evaluated
+// with the PolicyEvaluator set with WithPolicyEvaluator, if any. Findings
+// about the content of the transaction are returned as ValidationErrors.
+func VerifyCreateChannelTx(env *cb.Envelope, consortiumConfig *cb.Config, opts ...Option) error {
+	o := newOptions(opts...)
+
+	if consortiumConfig.GetChannelGroup() == nil {
+		return errors.New("consortium config does not contain a channel group")
+	}
+
+	configUpdateEnvelope, update, err := unmarshalCreateChannelTx(env)
+	if err != nil {
+		return err
+	}
+
+	consortiumName, err := createChannelConsortium(update)
+	if err != nil {
+		return err
+	}
+
+	consortiumGroup, _ := configGroupAtPath(consortiumConfig.ChannelGroup, []string{ConsortiumsGroupKey, consortiumName})
+	if consortiumGroup == nil {
+		return fmt.Errorf("consortium %s does not exist in consortium config", consortiumName)
+	}
+
+	applicationGroup := update.WriteSet.Groups[ApplicationGroupKey]
+	if applicationGroup == nil || len(applicationGroup.Groups) == 0 {
+		return errors.New("channel creation transaction does not contain application orgs")
+	}
+
+	var findings ValidationErrors
+
+	channelGroup, nonMembers, err := channelCreationTemplate(consortiumGroup, sortedKeys(applicationGroup.Groups))
+	if err != nil {
+		return fmt.Errorf("retrieving channel creation policy of consortium %s: %w", consortiumName, err)
+	}
+	templateGroup := channelGroup.Groups[ApplicationGroupKey]
+
+	for _, orgName := range sortedKeys(applicationGroup.Groups) {
+		if containsString(nonMembers, orgName) {
+			findings = append(findings, fmt.Errorf("org %s is not a member of consortium %s", orgName, consortiumName))
+			continue
+		}
+
+		if applicationGroup.Groups[orgName].Version != 0 {
+			findings = append(findings, fmt.Errorf("org %s is redefined by the transaction instead of using its definition in consortium %s", orgName, consortiumName))
+		}
+	}
+
+	var signers []Principal
+	for i, signature := range configUpdateEnvelope.Signatures {
+		signer, err := verifyConfigSignature(signature, configUpdateEnvelope.ConfigUpdate, templateGroup, o)
+		if err != nil {
+			findings = append(findings, fmt.Errorf("signature %d: %w", i, err))
+			continue
+		}
+		signers = append(signers, signer)
+	}
+
+	policyPath := configPath(ChannelGroupKey, ApplicationGroupKey, ChannelCreationPolicyKey)
+	satisfied, err := o.evaluator().Evaluate(channelGroup, policyPath, signers)
+	if err != nil {
+		return fmt.Errorf("evaluating channel creation policy of consortium %s: %w", consortiumName, err)
+	}
+	if !satisfied {
+		findings = append(findings, fmt.Errorf("signatures do not satisfy the channel creation policy of consortium %s", consortiumName))
+	}
+
+	return findings.err()
+}
+
+// checkChannelCreation checks that the orderer would accept the creation of
+// the channel given the system channel config set with
+// WithSystemChannelConfig.
+func checkChannelCreation(channelConfig Channel, o options) error {
+	systemChannelGroup := o.systemChannelConfig.GetChannelGroup()
+	consortiumsGroup, _ := configGroupAtPath(systemChannelGroup, []string{ConsortiumsGroupKey})
+	if consortiumsGroup == nil {
+		return errors.New("system channel config does not contain a consortiums group")
+	}
+
+	var findings ValidationErrors
+
+	consortiumName := channelConfig.Consortium
+	consortiumGroup, ok := consortiumsGroup.Groups[consortiumName]
+	if !ok {
+		findings = append(findings, fmt.Errorf("consortium %s does not exist in the system channel: create the channel in one of the consortiums %s or add the consortium to the system channel first",
+			consortiumName, strings.Join(sortedKeys(consortiumsGroup.Groups), ", ")))
+	} else {
+		var orgNames []string
+		for _, org := range channelConfig.Application.Organizations {
+			orgNames = append(orgNames, org.Name)
+		}
+
+		channelGroup, nonMembers, err := channelCreationTemplate(consortiumGroup, orgNames)
+		if err != nil {
+			return fmt.Errorf("retrieving channel creation policy of consortium %s: %w", consortiumName, err)
+		}
+
+		for _, orgName := range nonMembers {
+			findings = append(findings, fmt.Errorf("org %s is not a member of consortium %s: add the org to the consortium in the system channel first", orgName, consortiumName))
+		}
+
+		if len(nonMembers) == 0 {
+			templateGroup := channelGroup.Groups[ApplicationGroupKey]
+
+			var admins []Principal
+			for _, orgName := range sortedKeys(templateGroup.Groups) {
+				msp, err := getMSPConfig(templateGroup.Groups[orgName], o.certificates)
+				if err != nil {
+					return fmt.Errorf("retrieving MSP of org %s of consortium %s: %w", orgName, consortiumName, err)
+				}
+				admins = append(admins, Principal{MSPID: msp.Name, Role: "admin"})
+			}
+
+			policyPath := configPath(ChannelGroupKey, ApplicationGroupKey, ChannelCreationPolicyKey)
+			satisfied, err := o.evaluator().Evaluate(channelGroup, policyPath, admins)
+			if err != nil {
+				return fmt.Errorf("evaluating channel creation policy of consortium %s: %w", consortiumName, err)
+			}
+			if !satisfied {
+				findings = append(findings, fmt.Errorf("the channel creation policy of consortium %s (%s) cannot be satisfied by the admins of the orgs of the channel: include the orgs whose admins the policy requires",
+					consortiumName, policyRuleString(templateGroup.Policies[ChannelCreationPolicyKey].Policy)))
+			}
+		}
+	}
+
+	ordererGroup, _ := configGroupAtPath(systemChannelGroup, []string{OrdererGroupKey})
+	if _, ok := ordererGroup.GetValues()[orderer.ChannelRestrictionsKey]; ok {
+		channelRestrictions := &ob.ChannelRestrictions{}
+		err := unmarshalConfigValueAtKey(ordererGroup, orderer.ChannelRestrictionsKey, channelRestrictions)
+		if err != nil {
+			return err
+		}
+
+		if channelRestrictions.MaxCount > 0 && o.systemChannelChannels >= channelRestrictions.MaxCount {
+			findings = append(findings, fmt.Errorf("the ordering service serves %d channels and its channel restrictions allow at most %d: raise MaxChannels in the orderer config of the system channel first",
+				o.systemChannelChannels, channelRestrictions.MaxCount))
+		}
+	}
+
+	return findings.err()
+}
+
+// channelCreationTemplate returns a channel group with the application
+// group the orderer creates a new channel from: the orgs of the consortium
+// named by orgNames and the channel creation policy of the consortium. It
+// also returns the names of the orgs that are not members of the
+// consortium.
+func channelCreationTemplate(consortiumGroup *cb.ConfigGroup, orgNames []string) (*cb.ConfigGroup, []string, error) {
+	channelGroup := newConfigGroup()
+	templateGroup := newConfigGroup()
+	channelGroup.Groups[ApplicationGroupKey] = templateGroup
+
+	var nonMembers []string
+	for _, orgName := range orgNames {
+		orgGroup, ok := consortiumGroup.Groups[orgName]
+		if !ok {
+			nonMembers = append(nonMembers, orgName)
+			continue
+		}
+		templateGroup.Groups[orgName] = orgGroup
+	}
+
+	creationPolicy := &cb.Policy{}
+	err := unmarshalConfigValueAtKey(consortiumGroup, ChannelCreationPolicyKey, creationPolicy)
+	if err != nil {
+		return nil, nil, err
+	}
+	templateGroup.Policies[ChannelCreationPolicyKey] = &cb.ConfigPolicy{Policy: creationPolicy}
+
+	return channelGroup, nonMembers, nil
+}
+
+// unmarshalCreateChannelTx returns the config update envelope and the
+// config update of a channel creation transaction.
+func unmarshalCreateChannelTx(env *cb.Envelope) (*cb.ConfigUpdateEnvelope, *cb.ConfigUpdate, error) {
+	configUpdateEnvelope, err := ConfigUpdateEnvelopeFromEnvelope(env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, update)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshaling config update: %w", err)
+	}
+
+	if update.ChannelId == "" {
+		return nil, nil, errors.New("config update does not contain a channel ID")
+	}
+
+	if update.WriteSet == nil {
+		return nil, nil, errors.New("config update does not contain a write set")
+	}
+
+	return configUpdateEnvelope, update, nil
+}
+
+// createChannelConsortium returns the name of the consortium the channel
+// creation config update creates the channel in.
+func createChannelConsortium(update *cb.ConfigUpdate) (string, error) {
+	value, ok := update.WriteSet.Values[ConsortiumKey]
+	if !ok {
+		return "", errors.New("config update does not name a consortium, it is not a channel creation transaction")
+	}
+
+	consortium := &cb.Consortium{}
+	err := proto.Unmarshal(value.Value, consortium)
+	if err != nil {
+		return "", fmt.Errorf("unmarshaling consortium: %w", err)
+	}
+
+	if consortium.Name == "" {
+		return "", errors.New("config update does not name a consortium, it is not a channel creation transaction")
+	}
+
+	return consortium.Name, nil
+}
+
+// verifyConfigSignature verifies that the signature of the marshaled
+// config update was created by an identity of an org of the group and
+// returns the principal of the identity.
+func verifyConfigSignature(signature *cb.ConfigSignature, marshaledUpdate []byte, group *cb.ConfigGroup, o options) (Principal, error) {
+	header := &cb.SignatureHeader{}
+	err := proto.Unmarshal(signature.SignatureHeader, header)
+	if err != nil {
+		return Principal{}, fmt.Errorf("unmarshaling signature header: %w", err)
+	}
+
+	creator := &mb.SerializedIdentity{}
+	err = proto.Unmarshal(header.Creator, creator)
+	if err != nil {
+		return Principal{}, fmt.Errorf("unmarshaling creator: %w", err)
+	}
+
+	var msp MSP
+	found := false
+	for _, orgName := range sortedKeys(group.Groups) {
+		orgMSP, err := getMSPConfig(group.Groups[orgName], o.certificates)
+		if err == nil && orgMSP.Name == creator.Mspid {
+			msp = orgMSP
+			found = true
+			break
+		}
+	}
+	if !found {
+		return Principal{}, fmt.Errorf("signer MSP %s is not the MSP of an org of the channel", creator.Mspid)
+	}
+
+	cert, err := parseCertificateFromBytes(creator.IdBytes, nil)
+	if err != nil {
+		return Principal{}, fmt.Errorf("parsing certificate of signer of MSP %s: %w", creator.Mspid, err)
+	}
+
+	err = verifyCertificateChain(cert, msp.RootCerts, msp.IntermediateCerts, o.validityAt())
+	if err != nil {
+		return Principal{}, fmt.Errorf("certificate of signer %s of MSP %s is not valid: %w", cert.Subject.CommonName, creator.Mspid, err)
+	}
+
+	err = verifyECDSASignature(cert, concatenateBytes(signature.SignatureHeader, marshaledUpdate), signature.Signature)
+	if err != nil {
+		return Principal{}, fmt.Errorf("signer %s of MSP %s: %w", cert.Subject.CommonName, creator.Mspid, err)
+	}
+
+	return Principal{MSPID: creator.Mspid, Role: identityRole(cert, msp)}, nil
+}
+
+// verifyECDSASignature verifies the signature of msg, hashed with SHA-256,
+// by the key of the certificate.
+func verifyECDSASignature(cert *x509.Certificate, msg, signature []byte) error {
+	publicKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("verifying signatures of keys of type %T is not supported", cert.PublicKey)
+	}
+
+	sig := ecdsaSignature{}
+	_, err := asn1.Unmarshal(signature, &sig)
+	if err != nil {
+		return fmt.Errorf("unmarshaling signature: %w", err)
+	}
+
+	digest := sha256.Sum256(msg)
+	if !ecdsa.Verify(publicKey, digest[:], sig.R, sig.S) {
+		return errors.New("signature is invalid")
+	}
+
+	return nil
+}
+
+// identityRole returns the most specific role of the identity of the
+// certificate in the MSP: admin for admin certificates, the role of its
+// node OU if node OUs are enabled, or member.
+func identityRole(cert *x509.Certificate, msp MSP) string {
+	for _, admin := range msp.Admins {
+		if admin.Equal(cert) {
+			return "admin"
+		}
+	}
+
+	if !msp.NodeOUs.Enable {
+		return "member"
+	}
+
+	roles := []struct {
+		ou   string
+		role string
+	}{
+		{msp.NodeOUs.AdminOUIdentifier.OrganizationalUnitIdentifier, "admin"},
+		{msp.NodeOUs.ClientOUIdentifier.OrganizationalUnitIdentifier, "client"},
+		{msp.NodeOUs.PeerOUIdentifier.OrganizationalUnitIdentifier, "peer"},
+		{msp.NodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier, "orderer"},
+	}
+	for _, r := range roles {
+		if r.ou == "" {
+			continue
+		}
+		for _, ou := range cert.Subject.OrganizationalUnit {
+			if ou == r.ou {
+				return r.role
+			}
+		}
+	}
+
+	return "member"
+}
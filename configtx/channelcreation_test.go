@@ -0,0 +1,331 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/internal/policydsl"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestVerifyCreateChannelTx(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	consortiumConfig, profile, signers := channelCreationTestSetup(t)
+
+	marshaledUpdate, err := NewMarshaledCreateChannelTx(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signature, err := signers[0].CreateConfigSignature(marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	env, err := NewEnvelope(marshaledUpdate, signature)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = VerifyCreateChannelTx(env, consortiumConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestVerifyCreateChannelTxFindings(t *testing.T) {
+	t.Parallel()
+
+	consortiumConfig, profile, signers := channelCreationTestSetup(t)
+
+	otherMSP, otherPrivKey := baseMSP(t)
+	otherMSP.Name = "Org3MSP"
+	otherSigner := &SigningIdentity{
+		Certificate: otherMSP.RootCerts[0],
+		PrivateKey:  otherPrivKey,
+		MSPID:       otherMSP.Name,
+	}
+
+	tests := []struct {
+		testName      string
+		profile       func() Channel
+		signers       []*SigningIdentity
+		expectedError string
+	}{
+		{
+			testName:      "when the transaction is not signed",
+			profile:       func() Channel { return profile },
+			expectedError: "signatures do not satisfy the channel creation policy of consortium Consortium1",
+		},
+		{
+			testName: "when the signer is not of an org of the channel",
+			profile:  func() Channel { return profile },
+			signers:  []*SigningIdentity{otherSigner},
+			expectedError: "signature 0: signer MSP Org3MSP is not the MSP of an org of the channel; " +
+				"signatures do not satisfy the channel creation policy of consortium Consortium1",
+		},
+		{
+			testName: "when an org is not a member of the consortium",
+			profile: func() Channel {
+				p := profile
+				p.Application.Organizations = append([]Organization{}, profile.Application.Organizations...)
+				p.Application.Organizations = append(p.Application.Organizations, Organization{
+					Name:     "Org3",
+					Policies: orgStandardPolicies(),
+					MSP:      otherMSP,
+				})
+				return p
+			},
+			signers:       signers[:1],
+			expectedError: "org Org3 is not a member of consortium Consortium1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			marshaledUpdate, err := NewMarshaledCreateChannelTx(tt.profile(), "testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			var signatures []*cb.ConfigSignature
+			for _, signer := range tt.signers {
+				signature, err := signer.CreateConfigSignature(marshaledUpdate)
+				gt.Expect(err).NotTo(HaveOccurred())
+				signatures = append(signatures, signature)
+			}
+
+			env, err := NewEnvelope(marshaledUpdate, signatures...)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			err = VerifyCreateChannelTx(env, consortiumConfig)
+			gt.Expect(err).To(MatchError(tt.expectedError))
+		})
+	}
+}
+
+func TestVerifyCreateChannelTxFailures(t *testing.T) {
+	t.Parallel()
+
+	consortiumConfig, profile, _ := channelCreationTestSetup(t)
+
+	unknownConsortium := profile
+	unknownConsortium.Consortium = "Consortium2"
+	marshaledUpdate, err := NewMarshaledCreateChannelTx(unknownConsortium, "testchannel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownConsortiumEnv, err := NewEnvelope(marshaledUpdate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configEnv, err := NewEnvelopeOfType(cb.HeaderType_CONFIG, "testchannel", &cb.ConfigEnvelope{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updateEnv, err := NewEnvelope(protoMarshal(t, &cb.ConfigUpdate{
+		ChannelId: "testchannel",
+		WriteSet:  newConfigGroup(),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName         string
+		env              *cb.Envelope
+		consortiumConfig *cb.Config
+		expectedError    string
+	}{
+		{
+			testName:         "when the envelope is nil",
+			consortiumConfig: consortiumConfig,
+			expectedError:    "envelope is required",
+		},
+		{
+			testName:         "when the consortium config is empty",
+			env:              unknownConsortiumEnv,
+			consortiumConfig: &cb.Config{},
+			expectedError:    "consortium config does not contain a channel group",
+		},
+		{
+			testName:         "when the envelope is not a config update",
+			env:              configEnv,
+			consortiumConfig: consortiumConfig,
+			expectedError:    "envelope has header type CONFIG, not CONFIG_UPDATE",
+		},
+		{
+			testName:         "when the config update does not name a consortium",
+			env:              updateEnv,
+			consortiumConfig: consortiumConfig,
+			expectedError:    "config update does not name a consortium, it is not a channel creation transaction",
+		},
+		{
+			testName:         "when the consortium does not exist",
+			env:              unknownConsortiumEnv,
+			consortiumConfig: consortiumConfig,
+			expectedError:    "consortium Consortium2 does not exist in consortium config",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			err := VerifyCreateChannelTx(tt.env, tt.consortiumConfig)
+			gt.Expect(err).To(MatchError(tt.expectedError))
+		})
+	}
+}
+
+// channelCreationTestSetup returns the config of a system channel with the
+// consortium Consortium1, the profile of a channel of its orgs and signing
+// identities of admins of the orgs.
+func channelCreationTestSetup(t *testing.T) (*cb.Config, Channel, []*SigningIdentity) {
+	gt := NewGomegaWithT(t)
+
+	systemProfile, privKeys, _ := baseSystemChannelProfile(t)
+	orgs := systemProfile.Consortiums[0].Organizations
+	for i := range orgs {
+		orgs[i].MSP.Name = orgs[i].Name + "MSP"
+		orgs[i].Policies[AdminsPolicyKey] = Policy{
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%s.admin')", orgs[i].MSP.Name),
+		}
+	}
+
+	block, err := NewSystemChannelGenesisBlock(systemProfile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	consortiumConfig, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	application, _ := baseApplication(t)
+	application.Organizations = orgs
+	profile := Channel{
+		Consortium:   "Consortium1",
+		Application:  application,
+		Capabilities: []string{"V2_0"},
+	}
+
+	signers := make([]*SigningIdentity, len(orgs))
+	for i, org := range orgs {
+		signers[i] = &SigningIdentity{
+			Certificate: org.MSP.RootCerts[0],
+			PrivateKey:  privKeys[i],
+			MSPID:       org.MSP.Name,
+		}
+	}
+
+	return consortiumConfig, profile, signers
+}
+
+func TestNewMarshaledCreateChannelTxWithSystemChannelConfig(t *testing.T) {
+	t.Parallel()
+
+	systemConfig, profile, _ := channelCreationTestSetup(t)
+
+	// a channel creation policy requiring the admins of an org that is not
+	// in the channel
+	restrictivePolicy := proto.Clone(systemConfig).(*cb.Config)
+	signaturePolicy, err := policydsl.FromString("OR('Org3MSP.admin')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	consortiumGroup, _ := configGroupAtPath(restrictivePolicy.ChannelGroup, []string{ConsortiumsGroupKey, "Consortium1"})
+	consortiumGroup.Values[ChannelCreationPolicyKey].Value = protoMarshal(t, &cb.Policy{
+		Type:  int32(cb.Policy_SIGNATURE),
+		Value: protoMarshal(t, signaturePolicy),
+	})
+
+	c := New(systemConfig)
+	err = c.Orderer().SetMaxChannels(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restrictedChannels := c.UpdatedConfig()
+
+	otherOrg := baseApplicationOrg(t)
+	otherOrg.Name = "Org3"
+
+	tests := []struct {
+		testName     string
+		systemConfig *cb.Config
+		channels     uint64
+		profile      func() Channel
+		expectedErr  string
+	}{
+		{
+			testName:     "when the channel can be created",
+			systemConfig: restrictedChannels,
+			channels:     1,
+			profile:      func() Channel { return profile },
+		},
+		{
+			testName:     "when the consortium does not exist",
+			systemConfig: systemConfig,
+			profile: func() Channel {
+				p := profile
+				p.Consortium = "Consortium2"
+				return p
+			},
+			expectedErr: "channel creation would be rejected by the orderer: consortium Consortium2 does not exist in the system channel: " +
+				"create the channel in one of the consortiums Consortium1 or add the consortium to the system channel first",
+		},
+		{
+			testName:     "when an org is not a member of the consortium",
+			systemConfig: systemConfig,
+			profile: func() Channel {
+				p := profile
+				p.Application.Organizations = append([]Organization{otherOrg}, profile.Application.Organizations...)
+				return p
+			},
+			expectedErr: "channel creation would be rejected by the orderer: org Org3 is not a member of consortium Consortium1: " +
+				"add the org to the consortium in the system channel first",
+		},
+		{
+			testName:     "when the admins of the orgs cannot satisfy the channel creation policy",
+			systemConfig: restrictivePolicy,
+			profile:      func() Channel { return profile },
+			expectedErr: "channel creation would be rejected by the orderer: the channel creation policy of consortium Consortium1 " +
+				"(Signature AND('Org3MSP.admin')) cannot be satisfied by the admins of the orgs of the channel: " +
+				"include the orgs whose admins the policy requires",
+		},
+		{
+			testName:     "when the channel restrictions do not allow another channel",
+			systemConfig: restrictedChannels,
+			channels:     2,
+			profile:      func() Channel { return profile },
+			expectedErr: "channel creation would be rejected by the orderer: the ordering service serves 2 channels and " +
+				"its channel restrictions allow at most 2: raise MaxChannels in the orderer config of the system channel first",
+		},
+		{
+			testName:     "when the system channel config does not contain consortiums",
+			systemConfig: &cb.Config{ChannelGroup: newConfigGroup()},
+			profile:      func() Channel { return profile },
+			expectedErr:  "channel creation would be rejected by the orderer: system channel config does not contain a consortiums group",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := NewMarshaledCreateChannelTx(tt.profile(), "testchannel", WithSystemChannelConfig(tt.systemConfig, tt.channels))
+			if tt.expectedErr == "" {
+				gt.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
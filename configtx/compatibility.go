@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// The defaults applied by configtxgen to an orderer profile.
+// See genesisDefaults in fabric's internal/configtxgen/genesisconfig.
+const (
+	configtxgenDefaultBatchTimeout         = 2 * time.Second
+	configtxgenDefaultMaxMessageCount      = 500
+	configtxgenDefaultAbsoluteMaxBytes     = 10 * 1024 * 1024
+	configtxgenDefaultPreferredMaxBytes    = 2 * 1024 * 1024
+	configtxgenDefaultTickInterval         = "500ms"
+	configtxgenDefaultElectionTick         = 10
+	configtxgenDefaultHeartbeatTick        = 1
+	configtxgenDefaultMaxInflightBlocks    = 5
+	configtxgenDefaultSnapshotIntervalSize = 16 * 1024 * 1024
+
+	configtxgenDefaultSignatureHashFamily            = "SHA2"
+	configtxgenDefaultIdentityIdentifierHashFunction = "SHA256"
+)
+
+// configtxgenDefaults returns a copy of the channel configuration with
+// the defaults configtxgen would apply to an equivalent profile.
+// The input configuration is not modified.
+func configtxgenDefaults(channelConfig Channel) Channel {
+	channelConfig.Orderer = configtxgenOrdererDefaults(channelConfig.Orderer)
+	channelConfig.Application.Organizations = configtxgenOrgDefaults(channelConfig.Application.Organizations)
+
+	consortiums := make([]Consortium, len(channelConfig.Consortiums))
+	for i, consortium := range channelConfig.Consortiums {
+		consortium.Organizations = configtxgenOrgDefaults(consortium.Organizations)
+		consortiums[i] = consortium
+	}
+	channelConfig.Consortiums = consortiums
+
+	return channelConfig
+}
+
+func configtxgenOrdererDefaults(o Orderer) Orderer {
+	if o.OrdererType == "" {
+		o.OrdererType = orderer.ConsensusTypeSolo
+	}
+
+	if o.BatchTimeout == 0 {
+		o.BatchTimeout = configtxgenDefaultBatchTimeout
+	}
+
+	if o.BatchSize.MaxMessageCount == 0 {
+		o.BatchSize.MaxMessageCount = configtxgenDefaultMaxMessageCount
+	}
+
+	if o.BatchSize.AbsoluteMaxBytes == 0 {
+		o.BatchSize.AbsoluteMaxBytes = configtxgenDefaultAbsoluteMaxBytes
+	}
+
+	if o.BatchSize.PreferredMaxBytes == 0 {
+		o.BatchSize.PreferredMaxBytes = configtxgenDefaultPreferredMaxBytes
+	}
+
+	if o.State == "" {
+		o.State = orderer.ConsensusStateNormal
+	}
+
+	if o.OrdererType == orderer.ConsensusTypeEtcdRaft {
+		options := &o.EtcdRaft.Options
+		if options.TickInterval == "" {
+			options.TickInterval = configtxgenDefaultTickInterval
+		}
+
+		if options.ElectionTick == 0 {
+			options.ElectionTick = configtxgenDefaultElectionTick
+		}
+
+		if options.HeartbeatTick == 0 {
+			options.HeartbeatTick = configtxgenDefaultHeartbeatTick
+		}
+
+		if options.MaxInflightBlocks == 0 {
+			options.MaxInflightBlocks = configtxgenDefaultMaxInflightBlocks
+		}
+
+		if options.SnapshotIntervalSize == 0 {
+			options.SnapshotIntervalSize = configtxgenDefaultSnapshotIntervalSize
+		}
+	}
+
+	o.Organizations = configtxgenOrgDefaults(o.Organizations)
+
+	return o
+}
+
+// configtxgenOrgDefaults returns a copy of the organizations with the
+// crypto config configtxgen sets when loading an MSP from disk.
+func configtxgenOrgDefaults(orgs []Organization) []Organization {
+	if orgs == nil {
+		return nil
+	}
+
+	defaulted := make([]Organization, len(orgs))
+	for i, org := range orgs {
+		if org.MSP.CryptoConfig.SignatureHashFamily == "" {
+			org.MSP.CryptoConfig.SignatureHashFamily = configtxgenDefaultSignatureHashFamily
+		}
+
+		if org.MSP.CryptoConfig.IdentityIdentifierHashFunction == "" {
+			org.MSP.CryptoConfig.IdentityIdentifierHashFunction = configtxgenDefaultIdentityIdentifierHashFunction
+		}
+
+		defaulted[i] = org
+	}
+
+	return defaulted
+}
+
+// addConfigtxgenAnchorPeers adds the anchor peers of the application
+// organizations to an application channel group, as configtxgen does
+// when encoding an application channel genesis block.
+func addConfigtxgenAnchorPeers(channelGroup *cb.ConfigGroup, application Application) error {
+	applicationGroup := channelGroup.Groups[ApplicationGroupKey]
+
+	for _, org := range application.Organizations {
+		if len(org.AnchorPeers) == 0 {
+			continue
+		}
+
+		anchorProtos := make([]*pb.AnchorPeer, len(org.AnchorPeers))
+		for i, anchorPeer := range org.AnchorPeers {
+			anchorProtos[i] = &pb.AnchorPeer{
+				Host: anchorPeer.Host,
+				Port: int32(anchorPeer.Port),
+			}
+		}
+
+		err := setValue(applicationGroup.Groups[org.Name], anchorPeersValue(anchorProtos), AdminsPolicyKey)
+		if err != nil {
+			return fmt.Errorf("failed to add anchor peers value for org %s: %w", org.Name, err)
+		}
+	}
+
+	return nil
+}
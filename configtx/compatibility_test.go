@@ -0,0 +1,285 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigtxgenCompatibleApplicationChannelGenesisBlock(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.Orderer.BatchTimeout = 0
+	profile.Orderer.BatchSize = orderer.BatchSize{}
+	profile.Orderer.State = ""
+	profile.Application.Organizations[0].AnchorPeers = []Address{{Host: "peer0.org1.example.com", Port: 7051}}
+	profile.Application.Organizations[0].MSP.CryptoConfig.SignatureHashFamily = ""
+
+	_, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).To(MatchError("creating application channel group: batch timeout must be greater than zero, got '0s'"))
+
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel", WithConfigtxgenCompatibility())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// the caller's profile must not be modified
+	gt.Expect(profile.Orderer.BatchTimeout).To(Equal(time.Duration(0)))
+	gt.Expect(profile.Application.Organizations[0].MSP.CryptoConfig.SignatureHashFamily).To(BeEmpty())
+
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(config)
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(2 * time.Second))
+	gt.Expect(ordererConfig.BatchSize).To(Equal(orderer.BatchSize{
+		MaxMessageCount:   500,
+		AbsoluteMaxBytes:  10 * 1024 * 1024,
+		PreferredMaxBytes: 2 * 1024 * 1024,
+	}))
+	gt.Expect(ordererConfig.State).To(Equal(orderer.ConsensusStateNormal))
+
+	anchorPeers, err := c.Application().Organization("Org1").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(Equal([]Address{{Host: "peer0.org1.example.com", Port: 7051}}))
+
+	msp, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.CryptoConfig.SignatureHashFamily).To(Equal("SHA2"))
+	gt.Expect(msp.CryptoConfig.IdentityIdentifierHashFunction).To(Equal("SHA256"))
+
+	// configtxgen encodes every application org with the same elements
+	// regardless of whether anchor peers are defined
+	org2Group := config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"]
+	gt.Expect(org2Group.Values).To(HaveLen(1))
+	gt.Expect(org2Group.Values).To(HaveKey(MSPKey))
+	gt.Expect(org2Group.ModPolicy).To(Equal(AdminsPolicyKey))
+	gt.Expect(org2Group.Values[MSPKey].ModPolicy).To(Equal(AdminsPolicyKey))
+	mspConfig := &mb.MSPConfig{}
+	err = unmarshalConfigValueAtKey(org2Group, MSPKey, mspConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mspConfig.Type).To(Equal(int32(0)))
+}
+
+func TestConfigtxgenCompatibleEtcdRaftDefaults(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	raftOrderer, _ := baseEtcdRaftOrderer(t)
+	profile.Orderer = raftOrderer
+	profile.Orderer.EtcdRaft.Options = orderer.EtcdRaftOptions{ElectionTick: 20}
+
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel", WithConfigtxgenCompatibility())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(config)
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.EtcdRaft.Options).To(Equal(orderer.EtcdRaftOptions{
+		TickInterval:         "500ms",
+		ElectionTick:         20,
+		HeartbeatTick:        1,
+		MaxInflightBlocks:    5,
+		SnapshotIntervalSize: 16 * 1024 * 1024,
+	}))
+
+	consortium, err := c.Consortium("Consortium1").Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consortium.CryptoConfig.SignatureHashFamily).To(Equal("SHA3"))
+}
+
+func TestConfigtxgenCompatibleCreateChannelTx(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.Consortium = "SampleConsortium"
+	profile.Orderer = Orderer{}
+
+	marshaledUpdate, err := NewMarshaledCreateChannelTx(profile, "testchannel", WithConfigtxgenCompatibility())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// the config update configtxgen's encoder.NewChannelCreateConfigUpdate
+	// computes for the profile: unchanged org groups are only referenced by
+	// version, and the consortium is added to the read and write sets
+	implicitMetaPolicy := func(rule cb.ImplicitMetaPolicy_Rule, subPolicy string) *cb.ConfigPolicy {
+		return &cb.ConfigPolicy{
+			ModPolicy: AdminsPolicyKey,
+			Policy: &cb.Policy{
+				Type:  int32(cb.Policy_IMPLICIT_META),
+				Value: protoMarshal(t, &cb.ImplicitMetaPolicy{Rule: rule, SubPolicy: subPolicy}),
+			},
+		}
+	}
+	expectedUpdate := &cb.ConfigUpdate{
+		ChannelId: "testchannel",
+		ReadSet: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				ConsortiumKey: {},
+			},
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": {},
+						"Org2": {},
+					},
+				},
+			},
+		},
+		WriteSet: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				ConsortiumKey: {Value: protoMarshal(t, &cb.Consortium{Name: "SampleConsortium"})},
+			},
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: {
+					Version:   1,
+					ModPolicy: AdminsPolicyKey,
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": {},
+						"Org2": {},
+					},
+					Values: map[string]*cb.ConfigValue{
+						ACLsKey: {
+							ModPolicy: AdminsPolicyKey,
+							Value: protoMarshal(t, &pb.ACLs{
+								Acls: map[string]*pb.APIResource{"acl1": {PolicyRef: "hi"}},
+							}),
+						},
+						CapabilitiesKey: {
+							ModPolicy: AdminsPolicyKey,
+							Value: protoMarshal(t, &cb.Capabilities{
+								Capabilities: map[string]*cb.Capability{"V1_3": {}},
+							}),
+						},
+					},
+					Policies: map[string]*cb.ConfigPolicy{
+						ReadersPolicyKey: implicitMetaPolicy(cb.ImplicitMetaPolicy_ANY, ReadersPolicyKey),
+						WritersPolicyKey: implicitMetaPolicy(cb.ImplicitMetaPolicy_ANY, WritersPolicyKey),
+						AdminsPolicyKey:  implicitMetaPolicy(cb.ImplicitMetaPolicy_MAJORITY, AdminsPolicyKey),
+					},
+				},
+			},
+		},
+	}
+
+	marshaled, err := marshalDeterministically(update)
+	gt.Expect(err).NotTo(HaveOccurred())
+	expected, err := marshalDeterministically(expectedUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaled).To(Equal(expected))
+}
+
+func TestConfigtxgenGoldenGenesisBlock(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	// testdata/configtxgen_genesis_block.pb is the system channel genesis
+	// block configtxgen generates for the SampleSingleMSPSolo profile of
+	// the Fabric sample config
+	goldenBin, err := ioutil.ReadFile("testdata/configtxgen_genesis_block.pb")
+	gt.Expect(err).NotTo(HaveOccurred())
+	golden := &cb.Block{}
+	err = proto.Unmarshal(goldenBin, golden)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	goldenConfig, err := ConfigFromBlock(golden)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(goldenConfig)
+	profile, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	block, err := NewSystemChannelGenesisBlock(profile, "test", WithConfigtxgenCompatibility())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expected := genesisBlockContents(t, golden)
+	actual := genesisBlockContents(t, block)
+
+	// the profile has no equivalent of the deprecated channel level orderer
+	// addresses configtxgen still generates
+	c = New(actual.configEnvelope.Config)
+	err = c.Channel().AddLegacyOrdererAddress("127.0.0.1:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+	actual.configEnvelope.Config = c.UpdatedConfig()
+
+	// the block metadata is not compared: the fixture predates the orderer
+	// block metadata current configtxgen versions write
+	gt.Expect(proto.Equal(actual.header, expected.header)).To(BeTrue())
+	gt.Expect(proto.Equal(actual.channelHeader, expected.channelHeader)).To(BeTrue())
+	gt.Expect(proto.Equal(actual.signatureHeader, expected.signatureHeader)).To(BeTrue())
+	gt.Expect(proto.Equal(actual.configEnvelope, expected.configEnvelope)).To(BeTrue())
+}
+
+// genesisContents are the unmarshaled parts of a genesis block.
+type genesisContents struct {
+	header          *cb.BlockHeader
+	channelHeader   *cb.ChannelHeader
+	signatureHeader *cb.SignatureHeader
+	configEnvelope  *cb.ConfigEnvelope
+}
+
+// genesisBlockContents unmarshals the genesis block, clearing the fields
+// that differ between generations of the same block: the nonce and the
+// timestamp, and the transaction ID and data hash derived from them.
+func genesisBlockContents(t *testing.T, block *cb.Block) genesisContents {
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(block.Data.Data).To(HaveLen(1))
+	envelope := &cb.Envelope{}
+	err := proto.Unmarshal(block.Data.Data[0], envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(envelope.Signature).To(BeEmpty())
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	contents := genesisContents{
+		header:          proto.Clone(block.Header).(*cb.BlockHeader),
+		channelHeader:   &cb.ChannelHeader{},
+		signatureHeader: &cb.SignatureHeader{},
+		configEnvelope:  &cb.ConfigEnvelope{},
+	}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, contents.channelHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = proto.Unmarshal(payload.Header.SignatureHeader, contents.signatureHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = proto.Unmarshal(payload.Data, contents.configEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(contents.signatureHeader.Nonce).NotTo(BeEmpty())
+	gt.Expect(contents.channelHeader.Timestamp).NotTo(BeNil())
+	gt.Expect(contents.channelHeader.TxId).NotTo(BeEmpty())
+	contents.header.DataHash = nil
+	contents.signatureHeader.Nonce = nil
+	contents.channelHeader.Timestamp = nil
+	contents.channelHeader.TxId = ""
+
+	return contents
+}
@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// EditSet is a named set of logically related edits of a config, e.g.
+// adding an org or rotating the TLS certificate of a consenter, that
+// Compose combines with other edit sets into a single config update.
+type EditSet struct {
+	// Name identifies the edit set in conflicts, e.g. "add Org3".
+	Name string
+	// Edit makes the edits to the updated config of the ConfigTx.
+	Edit func(c *ConfigTx) error
+}
+
+// EditOverlap is a config element changed by more than one edit set.
+type EditOverlap struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Application/Org1/Values/AnchorPeers.
+	Path    string
+	Element ElementType
+	// EditSets are the names of the edit sets that change the element, in
+	// the order they were passed to Compose.
+	EditSets []string
+}
+
+// String returns a description of the overlap, e.g.
+// "value /Channel/Capabilities: changed by bump capability, add Org3".
+func (o EditOverlap) String() string {
+	return fmt.Sprintf("%s %s: changed by %s", o.Element, o.Path, strings.Join(o.EditSets, ", "))
+}
+
+// ComposeConflictError is returned by Compose when edit sets change the
+// same config elements.
+type ComposeConflictError struct {
+	Overlaps []EditOverlap
+}
+
+// Error lists the overlapping changes.
+func (e *ComposeConflictError) Error() string {
+	descriptions := make([]string, len(e.Overlaps))
+	for i, overlap := range e.Overlaps {
+		descriptions[i] = overlap.String()
+	}
+
+	return fmt.Sprintf("%d elements changed by more than one edit set: %s", len(e.Overlaps), strings.Join(descriptions, "; "))
+}
+
+// Compose applies logically separate edit sets, e.g. those of one
+// governance cycle, to the updated config, so that they are submitted as a
+// single config update computed with ComputeMarshaledUpdate. Each edit set
+// is applied independently to a copy of the updated config, so edit sets
+// cannot observe each other's edits. If edit sets change the same config
+// element, even in the same way, or one removes or adds a group another
+// changes, a *ComposeConflictError listing the elements is returned, so
+// that the overlapping edits can be reviewed and combined into one edit
+// set. If an edit set fails or edit sets overlap, the updated config is
+// left unchanged.
+func (c *ConfigTx) Compose(editSets ...EditSet) error {
+	if len(editSets) == 0 {
+		return errors.New("at least one edit set is required")
+	}
+
+	base := c.updated
+	edited := make([]*cb.ConfigGroup, len(editSets))
+	differences := make([][]Difference, len(editSets))
+	names := map[string]bool{}
+
+	for i, editSet := range editSets {
+		if editSet.Name == "" {
+			return fmt.Errorf("edit set %d has no name", i)
+		}
+		if names[editSet.Name] {
+			return fmt.Errorf("edit set name %s is not unique", editSet.Name)
+		}
+		names[editSet.Name] = true
+		if editSet.Edit == nil {
+			return fmt.Errorf("edit set %s has no edit function", editSet.Name)
+		}
+
+		e := ConfigTx{
+			original: base,
+			updated:  proto.Clone(base).(*cb.Config),
+			options:  c.options,
+		}
+		// the edits are reported to the observers when they are composed
+		e.options.observers = nil
+
+		err := editSet.Edit(&e)
+		if err != nil {
+			return fmt.Errorf("applying edit set %s: %w", editSet.Name, err)
+		}
+
+		edited[i] = e.updated.ChannelGroup
+		differences[i] = diffGroups(configPath(ChannelGroupKey), base.ChannelGroup, edited[i])
+	}
+
+	overlaps := editOverlaps(editSets, differences)
+	if len(overlaps) > 0 {
+		return &ComposeConflictError{Overlaps: overlaps}
+	}
+
+	composed := proto.Clone(base.ChannelGroup).(*cb.ConfigGroup)
+	for i, editSet := range editSets {
+		conflicts := mergeGroup(configPath(ChannelGroupKey), base.ChannelGroup, composed, edited[i])
+		if len(conflicts) > 0 {
+			return fmt.Errorf("composing edit set %s: %w", editSet.Name, &MergeConflictError{Conflicts: conflicts})
+		}
+	}
+
+	c.updated.ChannelGroup = composed
+
+	c.notify(configPath(ChannelGroupKey), "Compose")
+
+	return nil
+}
+
+// editOverlaps returns the elements changed by more than one edit set,
+// given the differences each edit set makes. A group added or removed by
+// one edit set overlaps with every change of another edit set beneath it.
+func editOverlaps(editSets []EditSet, differences [][]Difference) []EditOverlap {
+	var overlaps []EditOverlap
+	index := map[string]int{}
+
+	add := func(d Difference, names ...string) {
+		key := string(d.Element) + " " + d.Path
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(overlaps)
+			overlaps = append(overlaps, EditOverlap{Path: d.Path, Element: d.Element})
+			i = len(overlaps) - 1
+		}
+		for _, name := range names {
+			if !containsString(overlaps[i].EditSets, name) {
+				overlaps[i].EditSets = append(overlaps[i].EditSets, name)
+			}
+		}
+	}
+
+	for i := range editSets {
+		for j := i + 1; j < len(editSets); j++ {
+			for _, d1 := range differences[i] {
+				for _, d2 := range differences[j] {
+					switch {
+					case d1.Element == d2.Element && d1.Path == d2.Path:
+						add(d1, editSets[i].Name, editSets[j].Name)
+					case encloses(d1, d2):
+						add(d1, editSets[i].Name, editSets[j].Name)
+					case encloses(d2, d1):
+						add(d2, editSets[i].Name, editSets[j].Name)
+					}
+				}
+			}
+		}
+	}
+
+	// list the edit sets in the order they were passed
+	for i := range overlaps {
+		var names []string
+		for _, editSet := range editSets {
+			if containsString(overlaps[i].EditSets, editSet.Name) {
+				names = append(names, editSet.Name)
+			}
+		}
+		overlaps[i].EditSets = names
+	}
+
+	return overlaps
+}
+
+// encloses reports whether the difference is a group added or removed
+// whose subtree contains the other difference.
+func encloses(group, other Difference) bool {
+	return group.Element == ElementGroup &&
+		group.Change != ChangeModified &&
+		strings.HasPrefix(other.Path, group.Path+"/")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config := undoTestConfig(t)
+	org3 := baseApplicationOrg(t)
+	org3.Name = "Org3"
+
+	addOrg3 := func(c *ConfigTx) error {
+		return c.Application().SetOrganization(org3)
+	}
+	addAnchorPeer := func(c *ConfigTx) error {
+		return c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	}
+	setACLs := func(c *ConfigTx) error {
+		return c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+	}
+
+	var mutations []Mutation
+	c := New(config, WithObserver(func(m Mutation) {
+		mutations = append(mutations, m)
+	}))
+	err := c.Compose(
+		EditSet{Name: "add Org3", Edit: addOrg3},
+		EditSet{Name: "add anchor peer", Edit: addAnchorPeer},
+		EditSet{Name: "set ACLs", Edit: setACLs},
+	)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mutations).To(Equal([]Mutation{{Path: "/Channel", Operation: "Compose"}}))
+
+	// the composed config is the config with all edits applied
+	expected := New(config)
+	for _, edit := range []func(c *ConfigTx) error{addOrg3, addAnchorPeer, setACLs} {
+		gt.Expect(edit(&expected)).To(Succeed())
+	}
+	gt.Expect(proto.Equal(c.UpdatedConfig(), expected.UpdatedConfig())).To(BeTrue())
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestComposeOverlaps(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config := undoTestConfig(t)
+	c := New(config)
+
+	err := c.Compose(
+		EditSet{Name: "set ACLs", Edit: func(c *ConfigTx) error {
+			return c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+		}},
+		EditSet{Name: "remove Org2", Edit: func(c *ConfigTx) error {
+			c.Application().RemoveOrganization("Org2")
+			return nil
+		}},
+		EditSet{Name: "set ACLs again", Edit: func(c *ConfigTx) error {
+			return c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+		}},
+		EditSet{Name: "add Org2 anchor peer", Edit: func(c *ConfigTx) error {
+			return c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2", Port: 7051})
+		}},
+	)
+
+	var conflictErr *ComposeConflictError
+	gt.Expect(errors.As(err, &conflictErr)).To(BeTrue())
+	gt.Expect(conflictErr.Overlaps).To(Equal([]EditOverlap{
+		{Path: "/Channel/Application/Values/ACLs", Element: ElementValue, EditSets: []string{"set ACLs", "set ACLs again"}},
+		{Path: "/Channel/Application/Org2", Element: ElementGroup, EditSets: []string{"remove Org2", "add Org2 anchor peer"}},
+	}))
+	gt.Expect(err).To(MatchError("2 elements changed by more than one edit set: " +
+		"value /Channel/Application/Values/ACLs: changed by set ACLs, set ACLs again; " +
+		"group /Channel/Application/Org2: changed by remove Org2, add Org2 anchor peer"))
+
+	gt.Expect(proto.Equal(c.UpdatedConfig(), config)).To(BeTrue())
+}
+
+func TestComposeFailures(t *testing.T) {
+	t.Parallel()
+
+	noop := func(c *ConfigTx) error { return nil }
+
+	tests := []struct {
+		testName    string
+		editSets    []EditSet
+		expectedErr string
+	}{
+		{
+			testName:    "when there are no edit sets",
+			expectedErr: "at least one edit set is required",
+		},
+		{
+			testName:    "when an edit set has no name",
+			editSets:    []EditSet{{Edit: noop}},
+			expectedErr: "edit set 0 has no name",
+		},
+		{
+			testName:    "when edit set names are not unique",
+			editSets:    []EditSet{{Name: "edit", Edit: noop}, {Name: "edit", Edit: noop}},
+			expectedErr: "edit set name edit is not unique",
+		},
+		{
+			testName:    "when an edit set has no edit function",
+			editSets:    []EditSet{{Name: "edit"}},
+			expectedErr: "edit set edit has no edit function",
+		},
+		{
+			testName: "when an edit set fails",
+			editSets: []EditSet{{Name: "edit", Edit: func(c *ConfigTx) error {
+				return errors.New("boom")
+			}}},
+			expectedErr: "applying edit set edit: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			config := undoTestConfig(t)
+			c := New(config)
+
+			err := c.Compose(tt.editSets...)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(proto.Equal(c.UpdatedConfig(), config)).To(BeTrue())
+		})
+	}
+}
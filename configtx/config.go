@@ -13,6 +13,12 @@ SPDX-License-Identifier: Apache-2.0
 //
 // See https://hyperledger-fabric.readthedocs.io/en/master/configtx.html#anatomy-of-a-configuration
 // for an in-depth description of channel configuration's anatomy.
+//
+// Errors returned by this package wrap the errors that caused them, such as
+// protobuf unmarshaling, x509 parsing or file system errors, so callers can
+// inspect them with errors.Is and errors.As. Error messages describe what
+// was being done followed by the cause, e.g.
+// "unmarshaling config update: <cause>".
 package configtx
 
 import (
@@ -23,9 +29,9 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
-	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	mb "github.com/hyperledger/fabric-protos-go/msp"
@@ -39,6 +45,10 @@ type Channel struct {
 	Consortiums  []Consortium
 	Capabilities []string
 	Policies     map[string]Policy
+	// Extensions are the custom values of the channel group, keyed by
+	// value key. Only the values whose message types are registered with
+	// WithValueTypes are decoded.
+	Extensions map[string]proto.Message
 }
 
 // Policy is an expression used to define rules for access to channels, chaincodes, etc.
@@ -81,16 +91,49 @@ type ConfigTx struct {
 	original *cb.Config
 	// modified state of the config
 	updated *cb.Config
+	// options the config transaction was created with
+	options options
+	// cache of the decoded updated config, if enabled
+	cache *configCache
+	// log of the mutations of the updated config, if enabled
+	audit *auditLog
+	// undo history of the mutations of the updated config, if enabled
+	history *undoHistory
+	// published snapshots of the updated config, if enabled
+	snapshots *snapshotStore
 }
 
 // New creates a new ConfigTx from a Config protobuf.
 // New will panic if given an empty config.
-func New(config *cb.Config) ConfigTx {
-	return ConfigTx{
+func New(config *cb.Config, opts ...Option) ConfigTx {
+	c := ConfigTx{
 		original: config,
+		updated:  config,
+		options:  newOptions(opts...),
+	}
+
+	if !c.options.withoutClone {
 		// Clone the base config for processing updates
-		updated: proto.Clone(config).(*cb.Config),
+		c.updated = proto.Clone(config).(*cb.Config)
+	}
+
+	if c.options.configurationCache {
+		c.cache = &configCache{}
+	}
+
+	if c.options.audit {
+		c.audit = newAuditLog(c.updated)
+	}
+
+	if c.options.undo {
+		c.history = newUndoHistory(c.updated, c.options.undoDepth)
 	}
+
+	if c.options.snapshots {
+		c.snapshots = newSnapshotStore(c.updated, c.options)
+	}
+
+	return c
 }
 
 // OriginalConfig returns the original unedited config.
@@ -98,128 +141,263 @@ func (c *ConfigTx) OriginalConfig() *cb.Config {
 	return c.original
 }
 
-// UpdatedConfig returns the modified config.
+// UpdatedConfig returns the modified config. As the returned config may be
+// modified by the caller, any cached configuration is discarded.
 func (c *ConfigTx) UpdatedConfig() *cb.Config {
+	c.cache.invalidate()
 	return c.updated
 }
 
 // ComputeMarshaledUpdate computes the ConfigUpdate from a base and modified
-// config transaction and returns the marshaled bytes.
+// config transaction and returns the marshaled bytes. Any transformers
+// registered with the config transaction are run on the modified config
+// first, and the changes are checked against the path guard, if any. With
+// WithStrictValidation, the modified config must pass Validate.
 func (c *ConfigTx) ComputeMarshaledUpdate(channelID string) ([]byte, error) {
 	if channelID == "" {
 		return nil, errors.New("channel ID is required")
 	}
 
+	err := c.transform()
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform updated config: %w", err)
+	}
+
+	err = c.options.checkPathGuard(c.original, c.updated)
+	if err != nil {
+		return nil, fmt.Errorf("config update not permitted: %w", err)
+	}
+
+	if c.options.strictValidation {
+		err = c.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("updated config is not valid: %w", err)
+		}
+	}
+
 	update, err := computeConfigUpdate(c.original, c.updated)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compute update: %v", err)
+		return nil, fmt.Errorf("failed to compute update: %w", err)
 	}
 
 	update.ChannelId = channelID
 
 	marshaledUpdate, err := proto.Marshal(update)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling config update: %v", err)
+		return nil, fmt.Errorf("marshaling config update: %w", err)
 	}
 
 	return marshaledUpdate, nil
 }
 
+// ComputeUpdate computes the ConfigUpdate that changes the base config into
+// the updated config for the channel, e.g. to diff configs that were not
+// edited through a ConfigTx, such as configs fetched from two channels or
+// produced by configtxgen. The read set holds the versions of the elements
+// of base the update depends on and the write set the changed elements
+// with incremented versions. Neither config is modified.
+func ComputeUpdate(base, updated *cb.Config, channelID string) (*cb.ConfigUpdate, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	if base == nil || updated == nil {
+		return nil, errors.New("base and updated configs are required")
+	}
+
+	// computeConfigUpdate sets the sequence of the updated config
+	update, err := computeConfigUpdate(base, &cb.Config{ChannelGroup: updated.ChannelGroup, Sequence: updated.Sequence})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute update: %w", err)
+	}
+
+	update.ChannelId = channelID
+
+	return update, nil
+}
+
 // NewEnvelope creates an envelope with the provided marshaled config update
-// and config signatures.
+// and config signatures. The envelope is stamped with the current time and
+// is not bound to a TLS certificate; ConfigTx.NewEnvelope creates an
+// envelope with options such as WithClock and WithTLSCertificate applied.
 func NewEnvelope(marshaledUpdate []byte, signatures ...*cb.ConfigSignature) (*cb.Envelope, error) {
+	return newConfigUpdateEnvelope(marshaledUpdate, signatures, options{})
+}
+
+// NewEnvelope creates an envelope with the provided marshaled config update
+// and config signatures like the NewEnvelope function, applying the options
+// of the ConfigTx, e.g. WithClock and WithTLSCertificate, to its header.
+func (c *ConfigTx) NewEnvelope(marshaledUpdate []byte, signatures ...*cb.ConfigSignature) (*cb.Envelope, error) {
+	return newConfigUpdateEnvelope(marshaledUpdate, signatures, c.options)
+}
+
+// newConfigUpdateEnvelope creates a CONFIG_UPDATE envelope for the channel
+// of the marshaled config update.
+func newConfigUpdateEnvelope(marshaledUpdate []byte, signatures []*cb.ConfigSignature, o options) (*cb.Envelope, error) {
 	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{
 		ConfigUpdate: marshaledUpdate,
 		Signatures:   signatures,
 	}
 
-	c := &cb.ConfigUpdate{}
-	err := proto.Unmarshal(marshaledUpdate, c)
+	update := &cb.ConfigUpdate{}
+	err := proto.Unmarshal(marshaledUpdate, update)
 	if err != nil {
-		return nil, fmt.Errorf("unmarshaling config update: %v", err)
+		return nil, fmt.Errorf("unmarshaling config update: %w", err)
 	}
 
-	envelope, err := newEnvelope(cb.HeaderType_CONFIG_UPDATE, c.ChannelId, configUpdateEnvelope)
-	if err != nil {
-		return nil, err
+	return newEnvelope(cb.HeaderType_CONFIG_UPDATE, update.ChannelId, configUpdateEnvelope, o)
+}
+
+// NewEnvelopeOfType creates an unsigned envelope with a payload header of
+// the given type for the channel and the marshaled message as the payload
+// data, e.g. to wrap a config envelope in a HeaderType_CONFIG transaction.
+// The envelope can be signed with SigningIdentity.SignEnvelope.
+func NewEnvelopeOfType(headerType cb.HeaderType, channelID string, msg proto.Message, opts ...Option) (*cb.Envelope, error) {
+	if msg == nil {
+		return nil, errors.New("message is required")
 	}
 
-	return envelope, nil
+	return newEnvelope(headerType, channelID, msg, newOptions(opts...))
 }
 
 // NewMarshaledCreateChannelTx creates a create channel config update
 // transaction using the provided application channel configuration and returns
-// the marshaled bytes.
-func NewMarshaledCreateChannelTx(channelConfig Channel, channelID string) ([]byte, error) {
+// the marshaled bytes. With WithSystemChannelConfig, the channel creation is
+// checked against the config of the system channel first.
+func NewMarshaledCreateChannelTx(channelConfig Channel, channelID string, opts ...Option) ([]byte, error) {
 	if channelID == "" {
 		return nil, errors.New("profile's channel ID is required")
 	}
 
+	o := newOptions(opts...)
+
+	if o.configtxgenCompatible {
+		channelConfig = configtxgenDefaults(channelConfig)
+	}
+
 	ct, err := defaultConfigTemplate(channelConfig)
 	if err != nil {
-		return nil, fmt.Errorf("creating default config template: %v", err)
+		return nil, fmt.Errorf("creating default config template: %w", err)
 	}
 
 	update, err := newChannelCreateConfigUpdate(channelID, channelConfig, ct)
 	if err != nil {
-		return nil, fmt.Errorf("creating channel create config update: %v", err)
+		return nil, fmt.Errorf("creating channel create config update: %w", err)
+	}
+
+	if o.systemChannelConfig != nil {
+		err = checkChannelCreation(channelConfig, o)
+		if err != nil {
+			return nil, fmt.Errorf("channel creation would be rejected by the orderer: %w", err)
+		}
 	}
 
 	marshaledUpdate, err := proto.Marshal(update)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling config update: %v", err)
+		return nil, fmt.Errorf("marshaling config update: %w", err)
 	}
+
+	o.emitWarnings(channelWarnings(channelConfig, false))
+
 	return marshaledUpdate, nil
 }
 
 // NewSystemChannelGenesisBlock creates a genesis block using the provided
 // consortiums and orderer configuration and returns a block.
-func NewSystemChannelGenesisBlock(channelConfig Channel, channelID string) (*cb.Block, error) {
-	if channelID == "" {
-		return nil, errors.New("system channel ID is required")
-	}
+func NewSystemChannelGenesisBlock(channelConfig Channel, channelID string, opts ...Option) (*cb.Block, error) {
+	o := newOptions(opts...)
 
-	systemChannelGroup, err := newSystemChannelGroup(channelConfig)
+	systemChannelGroup, channelConfig, err := newSystemChannelGenesisGroup(channelConfig, channelID, o)
 	if err != nil {
-		return nil, fmt.Errorf("creating system channel group: %v", err)
+		return nil, err
 	}
 
-	block, err := newGenesisBlock(systemChannelGroup, channelID)
+	block, err := newGenesisBlock(systemChannelGroup, channelID, o)
 	if err != nil {
-		return nil, fmt.Errorf("creating system channel genesis block: %v", err)
+		return nil, fmt.Errorf("creating system channel genesis block: %w", err)
 	}
 
+	o.emitWarnings(channelWarnings(channelConfig, true))
+
 	return block, nil
 }
 
 // NewApplicationChannelGenesisBlock creates a genesis block using the provided
 // application and orderer configuration and returns a block.
-func NewApplicationChannelGenesisBlock(channelConfig Channel, channelID string) (*cb.Block, error) {
+func NewApplicationChannelGenesisBlock(channelConfig Channel, channelID string, opts ...Option) (*cb.Block, error) {
+	o := newOptions(opts...)
+
+	applicationChannelGroup, channelConfig, err := newApplicationChannelGenesisGroup(channelConfig, channelID, o)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := newGenesisBlock(applicationChannelGroup, channelID, o)
+	if err != nil {
+		return nil, fmt.Errorf("creating application channel genesis block: %w", err)
+	}
+
+	o.emitWarnings(channelWarnings(channelConfig, true))
+
+	return block, nil
+}
+
+// newSystemChannelGenesisGroup creates the channel group of a system
+// channel genesis block. It returns the channel configuration the group was
+// created from, which includes the configtxgen defaults if requested.
+func newSystemChannelGenesisGroup(channelConfig Channel, channelID string, o options) (*cb.ConfigGroup, Channel, error) {
 	if channelID == "" {
-		return nil, errors.New("application channel ID is required")
+		return nil, Channel{}, errors.New("system channel ID is required")
 	}
 
-	applicationChannelGroup, err := newApplicationChannelGroup(channelConfig)
+	if o.configtxgenCompatible {
+		channelConfig = configtxgenDefaults(channelConfig)
+	}
+
+	systemChannelGroup, err := newSystemChannelGroup(channelConfig, o.mspConfigs)
 	if err != nil {
-		return nil, fmt.Errorf("creating application channel group: %v", err)
+		return nil, Channel{}, fmt.Errorf("creating system channel group: %w", err)
 	}
 
-	block, err := newGenesisBlock(applicationChannelGroup, channelID)
+	return systemChannelGroup, channelConfig, nil
+}
+
+// newApplicationChannelGenesisGroup creates the channel group of an
+// application channel genesis block. It returns the channel configuration
+// the group was created from, which includes the configtxgen defaults if
+// requested.
+func newApplicationChannelGenesisGroup(channelConfig Channel, channelID string, o options) (*cb.ConfigGroup, Channel, error) {
+	if channelID == "" {
+		return nil, Channel{}, errors.New("application channel ID is required")
+	}
+
+	if o.configtxgenCompatible {
+		channelConfig = configtxgenDefaults(channelConfig)
+	}
+
+	applicationChannelGroup, err := newApplicationChannelGroup(channelConfig, o.mspConfigs)
 	if err != nil {
-		return nil, fmt.Errorf("creating application channel genesis block: %v", err)
+		return nil, Channel{}, fmt.Errorf("creating application channel group: %w", err)
 	}
 
-	return block, nil
+	if o.configtxgenCompatible {
+		err = addConfigtxgenAnchorPeers(applicationChannelGroup, channelConfig.Application)
+		if err != nil {
+			return nil, Channel{}, fmt.Errorf("creating application channel group: %w", err)
+		}
+	}
+
+	return applicationChannelGroup, channelConfig, nil
 }
 
 // newSystemChannelGroup defines the root of the system channel configuration.
-func newSystemChannelGroup(channelConfig Channel) (*cb.ConfigGroup, error) {
-	channelGroup, err := newChannelGroupWithOrderer(channelConfig)
+func newSystemChannelGroup(channelConfig Channel, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
+	channelGroup, err := newChannelGroupWithOrderer(channelConfig, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
 
-	consortiumsGroup, err := newConsortiumsGroup(channelConfig.Consortiums)
+	consortiumsGroup, err := newConsortiumsGroup(channelConfig.Consortiums, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
@@ -232,13 +410,13 @@ func newSystemChannelGroup(channelConfig Channel) (*cb.ConfigGroup, error) {
 
 // newApplicationChannelGroup defines the root of the application
 // channel configuration.
-func newApplicationChannelGroup(channelConfig Channel) (*cb.ConfigGroup, error) {
-	channelGroup, err := newChannelGroupWithOrderer(channelConfig)
+func newApplicationChannelGroup(channelConfig Channel, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
+	channelGroup, err := newChannelGroupWithOrderer(channelConfig, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
 
-	applicationGroup, err := newApplicationGroup(channelConfig.Application)
+	applicationGroup, err := newApplicationGroup(channelConfig.Application, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
@@ -250,12 +428,12 @@ func newApplicationChannelGroup(channelConfig Channel) (*cb.ConfigGroup, error)
 	return channelGroup, nil
 }
 
-func newChannelGroupWithOrderer(channelConfig Channel) (*cb.ConfigGroup, error) {
+func newChannelGroupWithOrderer(channelConfig Channel, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
 	channelGroup := newConfigGroup()
 
 	err := setPolicies(channelGroup, channelConfig.Policies, AdminsPolicyKey)
 	if err != nil {
-		return nil, fmt.Errorf("setting channel policies: %v", err)
+		return nil, fmt.Errorf("setting channel policies: %w", err)
 	}
 
 	err = setValue(channelGroup, hashingAlgorithmValue(), AdminsPolicyKey)
@@ -277,7 +455,12 @@ func newChannelGroupWithOrderer(channelConfig Channel) (*cb.ConfigGroup, error)
 		return nil, err
 	}
 
-	ordererGroup, err := newOrdererGroup(channelConfig.Orderer)
+	err = setExtensions(channelGroup, channelConfig.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	ordererGroup, err := newOrdererGroup(channelConfig.Orderer, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
@@ -288,44 +471,71 @@ func newChannelGroupWithOrderer(channelConfig Channel) (*cb.ConfigGroup, error)
 
 // newGenesisBlock generates a genesis block from the config group and
 // channel ID. The block number is always zero.
-func newGenesisBlock(cg *cb.ConfigGroup, channelID string) (*cb.Block, error) {
-	payloadChannelHeader := channelHeader(cb.HeaderType_CONFIG, msgVersion, channelID, epoch)
-	nonce, err := newNonce()
+func newGenesisBlock(cg *cb.ConfigGroup, channelID string, o options) (*cb.Block, error) {
+	payloadHeader, err := genesisPayloadHeader(channelID, o)
 	if err != nil {
-		return nil, fmt.Errorf("creating nonce: %v", err)
-	}
-	payloadSignatureHeader := &cb.SignatureHeader{Creator: nil, Nonce: nonce}
-	payloadChannelHeader.TxId = computeTxID(payloadSignatureHeader.Nonce, payloadSignatureHeader.Creator)
-	payloadHeader, err := payloadHeader(payloadChannelHeader, payloadSignatureHeader)
-	if err != nil {
-		return nil, fmt.Errorf("construct payload header: %v", err)
+		return nil, err
 	}
-	payloadData, err := proto.Marshal(&cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: cg}})
+	payloadData, err := marshalDeterministically(&cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: cg}})
 	if err != nil {
-		return nil, fmt.Errorf("marshaling payload data: %v", err)
+		return nil, fmt.Errorf("marshaling payload data: %w", err)
 	}
 	payload := &cb.Payload{Header: payloadHeader, Data: payloadData}
 	envelopePayload, err := proto.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling envelope payload: %v", err)
+		return nil, fmt.Errorf("marshaling envelope payload: %w", err)
 	}
 	envelope := &cb.Envelope{Payload: envelopePayload, Signature: nil}
 	blockData, err := proto.Marshal(envelope)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling envelope: %v", err)
+		return nil, fmt.Errorf("marshaling envelope: %w", err)
 	}
 
 	block := newBlock(0, nil)
 	block.Data = &cb.BlockData{Data: [][]byte{blockData}}
 	block.Header.DataHash = blockDataHash(block.Data)
 
+	err = setGenesisBlockMetadata(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// genesisPayloadHeader creates the header of the config transaction of a
+// genesis block.
+func genesisPayloadHeader(channelID string, o options) (*cb.Header, error) {
+	version := int32(msgVersion)
+	if o.configtxgenCompatible {
+		version = configtxgenGenesisMsgVersion
+	}
+
+	payloadChannelHeader := channelHeader(cb.HeaderType_CONFIG, version, channelID, epoch, o.now())
+	nonce, err := newNonce(o.random())
+	if err != nil {
+		return nil, fmt.Errorf("creating nonce: %w", err)
+	}
+	payloadSignatureHeader := &cb.SignatureHeader{Creator: nil, Nonce: nonce}
+	payloadChannelHeader.TxId = computeTxID(payloadSignatureHeader.Nonce, payloadSignatureHeader.Creator)
+	payloadHeader, err := payloadHeader(payloadChannelHeader, payloadSignatureHeader)
+	if err != nil {
+		return nil, fmt.Errorf("construct payload header: %w", err)
+	}
+
+	return payloadHeader, nil
+}
+
+// setGenesisBlockMetadata sets the last config and signatures metadata of
+// a genesis block.
+func setGenesisBlockMetadata(block *cb.Block) error {
 	lastConfigValue, err := proto.Marshal(&cb.LastConfig{Index: 0})
 	if err != nil {
-		return nil, fmt.Errorf("marshaling metadata last config value: %v", err)
+		return fmt.Errorf("marshaling metadata last config value: %w", err)
 	}
 	lastConfigMetadata, err := proto.Marshal(&cb.Metadata{Value: lastConfigValue})
 	if err != nil {
-		return nil, fmt.Errorf("marshaling metadata last config: %v", err)
+		return fmt.Errorf("marshaling metadata last config: %w", err)
 	}
 	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = lastConfigMetadata
 
@@ -333,22 +543,55 @@ func newGenesisBlock(cg *cb.ConfigGroup, channelID string) (*cb.Block, error) {
 		LastConfig: &cb.LastConfig{Index: 0},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("marshaling metadata signature value: %v", err)
+		return fmt.Errorf("marshaling metadata signature value: %w", err)
 	}
 	signatureMetadata, err := proto.Marshal(&cb.Metadata{Value: signatureValue})
 	if err != nil {
-		return nil, fmt.Errorf("marshaling metadata signature: %v", err)
+		return fmt.Errorf("marshaling metadata signature: %w", err)
 	}
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = signatureMetadata
 
-	return block, nil
+	return nil
+}
+
+// ConfigFromBlock extracts the channel config from a config block, e.g. the
+// genesis block of a channel or the latest config block fetched from an
+// orderer.
+func ConfigFromBlock(block *cb.Block) (*cb.Config, error) {
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("block contains no data")
+	}
+
+	envelope := &cb.Envelope{}
+	err := proto.Unmarshal(block.Data.Data[0], envelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling envelope: %w", err)
+	}
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling payload: %w", err)
+	}
+
+	configEnvelope := &cb.ConfigEnvelope{}
+	err = proto.Unmarshal(payload.Data, configEnvelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config envelope: %w", err)
+	}
+
+	if configEnvelope.Config == nil {
+		return nil, errors.New("block does not contain a config")
+	}
+
+	return configEnvelope.Config, nil
 }
 
 // setValue sets the value as ConfigValue in the ConfigGroup.
 func setValue(cg *cb.ConfigGroup, value *standardConfigValue, modPolicy string) error {
 	v, err := proto.Marshal(value.value)
 	if err != nil {
-		return fmt.Errorf("marshaling standard config value '%s': %v", value.key, err)
+		return fmt.Errorf("marshaling standard config value '%s': %w", value.key, err)
 	}
 
 	if cg.Values == nil {
@@ -431,7 +674,7 @@ func newChannelGroup(channelConfig Channel) (*cb.ConfigGroup, error) {
 
 	channelGroup.Groups[ApplicationGroupKey], err = newApplicationGroupTemplate(channelConfig.Application)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create application group: %v", err)
+		return nil, fmt.Errorf("failed to create application group: %w", err)
 	}
 
 	channelGroup.ModPolicy = AdminsPolicyKey
@@ -450,14 +693,14 @@ func newChannelCreateConfigUpdate(channelID string, channelConfig Channel, templ
 
 	updt, err := computeConfigUpdate(&cb.Config{ChannelGroup: templateConfig}, &cb.Config{ChannelGroup: newChannelGroup})
 	if err != nil {
-		return nil, fmt.Errorf("computing update: %v", err)
+		return nil, fmt.Errorf("computing update: %w", err)
 	}
 
 	wsValue, err := proto.Marshal(&cb.Consortium{
 		Name: channelConfig.Consortium,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("marshaling consortium: %v", err)
+		return nil, fmt.Errorf("marshaling consortium: %w", err)
 	}
 
 	// Add the consortium name to create the channel for into the write set as required
@@ -486,18 +729,23 @@ func newEnvelope(
 	txType cb.HeaderType,
 	channelID string,
 	dataMsg proto.Message,
+	o options,
 ) (*cb.Envelope, error) {
-	payloadChannelHeader := channelHeader(txType, msgVersion, channelID, epoch)
+	payloadChannelHeader := channelHeader(txType, msgVersion, channelID, epoch, o.now())
+	if o.tlsCert != nil {
+		tlsCertHash := sha256.Sum256(o.tlsCert.Raw)
+		payloadChannelHeader.TlsCertHash = tlsCertHash[:]
+	}
 	payloadSignatureHeader := &cb.SignatureHeader{}
 
 	data, err := proto.Marshal(dataMsg)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling envelope data: %v", err)
+		return nil, fmt.Errorf("marshaling envelope data: %w", err)
 	}
 
 	payloadHeader, err := payloadHeader(payloadChannelHeader, payloadSignatureHeader)
 	if err != nil {
-		return nil, fmt.Errorf("making payload header: %v", err)
+		return nil, fmt.Errorf("making payload header: %w", err)
 	}
 
 	paylBytes, err := proto.Marshal(
@@ -507,7 +755,7 @@ func newEnvelope(
 		},
 	)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling payload: %v", err)
+		return nil, fmt.Errorf("marshaling payload: %w", err)
 	}
 
 	env := &cb.Envelope{
@@ -517,13 +765,28 @@ func newEnvelope(
 	return env, nil
 }
 
-// channelHeader creates a ChannelHeader.
-func channelHeader(headerType cb.HeaderType, version int32, channelID string, epoch uint64) *cb.ChannelHeader {
+// marshalDeterministically marshals msg with map entries sorted by key so
+// that equal messages are always marshaled to the same bytes.
+func marshalDeterministically(msg proto.Message) ([]byte, error) {
+	buf := proto.NewBuffer([]byte{})
+	buf.SetDeterministic(true)
+
+	err := buf.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// channelHeader creates a ChannelHeader with a timestamp of the given
+// time truncated to seconds.
+func channelHeader(headerType cb.HeaderType, version int32, channelID string, epoch uint64, now time.Time) *cb.ChannelHeader {
 	return &cb.ChannelHeader{
 		Type:    int32(headerType),
 		Version: version,
 		Timestamp: &timestamp.Timestamp{
-			Seconds: ptypes.TimestampNow().GetSeconds(),
+			Seconds: now.Unix(),
 		},
 		ChannelId: channelID,
 		Epoch:     epoch,
@@ -534,12 +797,12 @@ func channelHeader(headerType cb.HeaderType, version int32, channelID string, ep
 func payloadHeader(ch *cb.ChannelHeader, sh *cb.SignatureHeader) (*cb.Header, error) {
 	channelHeader, err := proto.Marshal(ch)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling channel header: %v", err)
+		return nil, fmt.Errorf("marshaling channel header: %w", err)
 	}
 
 	signatureHeader, err := proto.Marshal(sh)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling signature header: %v", err)
+		return nil, fmt.Errorf("marshaling signature header: %w", err)
 	}
 
 	return &cb.Header{
@@ -569,7 +832,7 @@ func unmarshalConfigValueAtKey(group *cb.ConfigGroup, key string, msg proto.Mess
 
 	err := proto.Unmarshal(valueAtKey.Value, msg)
 	if err != nil {
-		return fmt.Errorf("unmarshaling %s: %v", key, err)
+		return fmt.Errorf("unmarshaling %s: %w", key, err)
 	}
 
 	return nil
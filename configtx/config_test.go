@@ -9,9 +9,11 @@ package configtx
 import (
 	"bytes"
 	"crypto/ecdsa"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
@@ -417,7 +419,7 @@ func TestNewCreateChannelTxFailure(t *testing.T) {
 
 			marshaledCreateChannelTx, err := NewMarshaledCreateChannelTx(profile, tt.channelID)
 			gt.Expect(marshaledCreateChannelTx).To(BeNil())
-			gt.Expect(err).To(MatchError(tt.err))
+			gt.Expect(err).To(MatchError(tt.err.Error()))
 		})
 	}
 }
@@ -899,7 +901,7 @@ func TestNewSystemChannelGenesisBlock(t *testing.T) {
 											"BatchTimeout": {
 												"mod_policy": "Admins",
 												"value": {
-													"timeout": "0s"
+													"timeout": "2s"
 												},
 												"version": "0"
 											},
@@ -1138,7 +1140,7 @@ func TestNewSystemChannelGenesisBlockFailure(t *testing.T) {
 
 			block, err := NewSystemChannelGenesisBlock(profile, tt.channelID)
 			gt.Expect(block).To(BeNil())
-			gt.Expect(err).To(MatchError(tt.err))
+			gt.Expect(err).To(MatchError(tt.err.Error()))
 		})
 	}
 }
@@ -1659,7 +1661,7 @@ func TestNewApplicationChannelGenesisBlock(t *testing.T) {
 											"BatchTimeout": {
 												"mod_policy": "Admins",
 												"value": {
-													"timeout": "0s"
+													"timeout": "2s"
 												},
 												"version": "0"
 											},
@@ -1898,7 +1900,7 @@ func TestNewApplicationChannelGenesisBlockFailure(t *testing.T) {
 
 			block, err := NewApplicationChannelGenesisBlock(profile, tt.channelID)
 			gt.Expect(block).To(BeNil())
-			gt.Expect(err).To(MatchError(tt.err))
+			gt.Expect(err).To(MatchError(tt.err.Error()))
 		})
 	}
 }
@@ -1931,6 +1933,220 @@ func TestNewEnvelopeFailures(t *testing.T) {
 	}
 }
 
+func TestNewEnvelopeOfType(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	configEnvelope := &cb.ConfigEnvelope{Config: config}
+	env, err := NewEnvelopeOfType(cb.HeaderType_CONFIG, "testchannel", configEnvelope, WithClock(fixedClock(now)))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(env.Signature).To(BeNil())
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(env.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelHeader := &cb.ChannelHeader{}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelHeader.Type).To(Equal(int32(cb.HeaderType_CONFIG)))
+	gt.Expect(channelHeader.ChannelId).To(Equal("testchannel"))
+	gt.Expect(channelHeader.Timestamp.Seconds).To(Equal(now.Unix()))
+
+	data := &cb.ConfigEnvelope{}
+	err = proto.Unmarshal(payload.Data, data)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(data, configEnvelope)).To(BeTrue())
+
+	cert, privateKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	signingIdentity := SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privateKey,
+		MSPID:       "test-msp",
+	}
+	err = signingIdentity.SignEnvelope(env)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(env.Signature).NotTo(BeEmpty())
+
+	_, err = NewEnvelopeOfType(cb.HeaderType_CONFIG, "testchannel", nil)
+	gt.Expect(err).To(MatchError("message is required"))
+}
+
+func TestNewEnvelopeOfTypeTLSCertificate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	marshaledUpdate, err := proto.Marshal(&cb.ConfigUpdate{ChannelId: "testchannel"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{ConfigUpdate: marshaledUpdate}
+
+	channelHeaderOf := func(env *cb.Envelope) *cb.ChannelHeader {
+		payload := &cb.Payload{}
+		err := proto.Unmarshal(env.Payload, payload)
+		gt.Expect(err).NotTo(HaveOccurred())
+		channelHeader := &cb.ChannelHeader{}
+		err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+		gt.Expect(err).NotTo(HaveOccurred())
+		return channelHeader
+	}
+
+	env, err := NewEnvelopeOfType(cb.HeaderType_CONFIG_UPDATE, "testchannel", configUpdateEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelHeaderOf(env).TlsCertHash).To(BeEmpty())
+
+	tlsCert, privateKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	env, err = NewEnvelopeOfType(cb.HeaderType_CONFIG_UPDATE, "testchannel", configUpdateEnvelope, WithTLSCertificate(tlsCert))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedHash := sha256.Sum256(tlsCert.Raw)
+	gt.Expect(channelHeaderOf(env).TlsCertHash).To(Equal(expectedHash[:]))
+
+	// the binding survives signing
+	signingIdentity := SigningIdentity{
+		Certificate: tlsCert,
+		PrivateKey:  privateKey,
+		MSPID:       "test-msp",
+	}
+	err = signingIdentity.SignEnvelope(env)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelHeaderOf(env).TlsCertHash).To(Equal(expectedHash[:]))
+}
+
+func TestConfigTxNewEnvelope(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	tlsCert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	c := New(config, WithClock(fixedClock(now)), WithTLSCertificate(tlsCert))
+	err = c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	env, err := c.NewEnvelope(marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(env.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelHeader := &cb.ChannelHeader{}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelHeader.Type).To(Equal(int32(cb.HeaderType_CONFIG_UPDATE)))
+	gt.Expect(channelHeader.ChannelId).To(Equal("testchannel"))
+	gt.Expect(channelHeader.Timestamp.Seconds).To(Equal(now.Unix()))
+	expectedHash := sha256.Sum256(tlsCert.Raw)
+	gt.Expect(channelHeader.TlsCertHash).To(Equal(expectedHash[:]))
+}
+
+func TestComputeUpdateBetweenConfigs(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	base := &cb.Config{
+		Sequence: 4,
+		ChannelGroup: &cb.ConfigGroup{
+			Version: 7,
+			Values: map[string]*cb.ConfigValue{
+				"foo": {Version: 3, Value: []byte("value1value")},
+				"bar": {Version: 6, Value: []byte("value2value")},
+			},
+		},
+	}
+	updated := &cb.Config{
+		Sequence: 9,
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				"foo": {Value: []byte("value1value")},
+				"bar": {Value: []byte("updatedValue2Value")},
+			},
+		},
+	}
+
+	update, err := ComputeUpdate(base, updated, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(update, &cb.ConfigUpdate{
+		ChannelId: "testchannel",
+		ReadSet:   &cb.ConfigGroup{Version: 7, Values: map[string]*cb.ConfigValue{}, Policies: map[string]*cb.ConfigPolicy{}, Groups: map[string]*cb.ConfigGroup{}},
+		WriteSet: &cb.ConfigGroup{
+			Version:  7,
+			Values:   map[string]*cb.ConfigValue{"bar": {Version: 7, Value: []byte("updatedValue2Value")}},
+			Policies: map[string]*cb.ConfigPolicy{},
+			Groups:   map[string]*cb.ConfigGroup{},
+		},
+	})).To(BeTrue())
+
+	// the configs are not modified
+	gt.Expect(base.Sequence).To(Equal(uint64(4)))
+	gt.Expect(updated.Sequence).To(Equal(uint64(9)))
+}
+
+func TestComputeUpdateBetweenConfigsFailures(t *testing.T) {
+	t.Parallel()
+
+	config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Version: 1}}
+
+	tests := []struct {
+		name        string
+		base        *cb.Config
+		updated     *cb.Config
+		channelID   string
+		expectedErr string
+	}{
+		{
+			name:        "missing channel ID",
+			base:        config,
+			updated:     config,
+			expectedErr: "channel ID is required",
+		},
+		{
+			name:        "missing config",
+			base:        config,
+			channelID:   "testchannel",
+			expectedErr: "base and updated configs are required",
+		},
+		{
+			name:        "no differences",
+			base:        config,
+			updated:     config,
+			channelID:   "testchannel",
+			expectedErr: "failed to compute update: no differences detected between original and updated config",
+		},
+		{
+			name:        "no channel group",
+			base:        &cb.Config{},
+			updated:     config,
+			channelID:   "testchannel",
+			expectedErr: "failed to compute update: no channel group included for original config",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			update, err := ComputeUpdate(tt.base, tt.updated, tt.channelID)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(update).To(BeNil())
+		})
+	}
+}
+
 func TestComputeMarshaledUpdate(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
@@ -2052,10 +2268,10 @@ func TestChannelConfiguration(t *testing.T) {
 			configMod: func(gt *GomegaWithT) *cb.Config {
 				channelGroup := newConfigGroup()
 
-				applicationGroup, err := newApplicationGroup(baseApplication)
+				applicationGroup, err := NewApplicationGroup(baseApplication)
 				gt.Expect(err).NotTo(HaveOccurred())
 				for _, org := range baseApplication.Organizations {
-					orgGroup, err := newOrgConfigGroup(org)
+					orgGroup, err := newOrgConfigGroup(org, nil)
 					gt.Expect(err).NotTo(HaveOccurred())
 					applicationGroup.Groups[org.Name] = orgGroup
 				}
@@ -2082,7 +2298,7 @@ func TestChannelConfiguration(t *testing.T) {
 					Policies:     policies,
 					Consortium:   "testconsortium",
 				}
-				channelGroup, err := newSystemChannelGroup(channel)
+				channelGroup, err := newSystemChannelGroup(channel, nil)
 				gt.Expect(err).NotTo(HaveOccurred())
 
 				return &cb.Config{
@@ -2122,6 +2338,60 @@ func TestChannelConfiguration(t *testing.T) {
 	}
 }
 
+func TestChannelConfigurationReflectsUpdates(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	channelGroup, err := newApplicationChannelGroup(channel, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	_, err = c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Channel().AddCapability("V3_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.Application().RemoveOrganization("Org2")
+
+	updated, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updated.Capabilities).To(ConsistOf("V2_0", "V3_0"))
+	gt.Expect(updated.Application.Organizations).To(HaveLen(1))
+	gt.Expect(updated.Application.Organizations[0].Name).To(Equal("Org1"))
+
+	o := New(c.OriginalConfig())
+	original, err := o.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(original.Capabilities).To(Equal([]string{"V2_0"}))
+	gt.Expect(original.Application.Organizations).To(HaveLen(2))
+}
+
+func TestNewWithoutClone(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	channelGroup, err := newApplicationChannelGroup(channel, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	config := &cb.Config{ChannelGroup: channelGroup}
+
+	c := New(config, WithoutClone())
+	gt.Expect(c.UpdatedConfig()).To(BeIdenticalTo(config))
+	gt.Expect(c.OriginalConfig()).To(BeIdenticalTo(config))
+
+	err = c.Channel().AddCapability("V3_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	capabilities, err := getCapabilities(config.ChannelGroup)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(capabilities).To(ContainElement("V3_0"))
+
+	c = New(config)
+	gt.Expect(c.UpdatedConfig()).NotTo(BeIdenticalTo(config))
+}
+
 func baseProfile(t *testing.T) Channel {
 	application, _ := baseApplication(t)
 	return Channel{
@@ -2209,13 +2479,13 @@ func baseApplicationChannelGroup(t *testing.T) (*cb.ConfigGroup, []*ecdsa.Privat
 	channelGroup := newConfigGroup()
 
 	application, privKeys := baseApplication(t)
-	applicationGroup, err := newApplicationGroup(application)
+	applicationGroup, err := NewApplicationGroup(application)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	for _, org := range application.Organizations {
-		orgGroup, err := newOrgConfigGroup(org)
+		orgGroup, err := newOrgConfigGroup(org, nil)
 		if err != nil {
 			return nil, nil, err
 		}
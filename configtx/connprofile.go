@@ -0,0 +1,272 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// ConnectionProfileVersion is the version of the connection profile format
+// generated by ConnectionProfile.
+const ConnectionProfileVersion = "1.0.0"
+
+// ConnectionProfileInput is the information of a connection profile that
+// is not part of the channel config.
+type ConnectionProfileInput struct {
+	// Name is the name of the profile. It defaults to
+	// <channel ID>-<organization>.
+	Name string
+	// Organization is the name of the application organization of the
+	// client using the profile.
+	Organization string
+	// Peers are peers of the application organizations, keyed by
+	// organization name, in addition to their anchor peers.
+	Peers map[string][]Address
+	// CertificateAuthorities are the Fabric CAs of the application
+	// organizations, keyed by organization name.
+	CertificateAuthorities map[string][]CertificateAuthority
+}
+
+// CertificateAuthority is a Fabric CA server.
+type CertificateAuthority struct {
+	// Name is the name of the CA in the profile, e.g. ca.org1.example.com.
+	Name string
+	// URL is the URL of the CA server, e.g. https://ca.org1.example.com:7054.
+	URL string
+	// CAName is the name of the CA served by the server, e.g. ca-org1.
+	CAName string
+}
+
+// ConnectionProfile is a Fabric SDK connection profile, also known as a
+// common connection profile, which can be encoded as JSON or YAML.
+type ConnectionProfile struct {
+	Name                   string                              `json:"name" yaml:"name"`
+	Version                string                              `json:"version" yaml:"version"`
+	Client                 ConnectionProfileClient             `json:"client" yaml:"client"`
+	Channels               map[string]ConnectionProfileChannel `json:"channels" yaml:"channels"`
+	Organizations          map[string]ConnectionProfileOrg     `json:"organizations" yaml:"organizations"`
+	Orderers               map[string]ConnectionProfileNode    `json:"orderers,omitempty" yaml:"orderers,omitempty"`
+	Peers                  map[string]ConnectionProfileNode    `json:"peers,omitempty" yaml:"peers,omitempty"`
+	CertificateAuthorities map[string]ConnectionProfileCA      `json:"certificateAuthorities,omitempty" yaml:"certificateAuthorities,omitempty"`
+}
+
+// ConnectionProfileClient is the client section of a connection profile.
+type ConnectionProfileClient struct {
+	Organization string `json:"organization" yaml:"organization"`
+}
+
+// ConnectionProfileChannel lists the orderers and peers of a channel.
+type ConnectionProfileChannel struct {
+	Orderers []string                                `json:"orderers,omitempty" yaml:"orderers,omitempty"`
+	Peers    map[string]ConnectionProfileChannelPeer `json:"peers,omitempty" yaml:"peers,omitempty"`
+}
+
+// ConnectionProfileChannelPeer is the role of a peer in a channel.
+type ConnectionProfileChannelPeer struct {
+	EndorsingPeer  bool `json:"endorsingPeer" yaml:"endorsingPeer"`
+	ChaincodeQuery bool `json:"chaincodeQuery" yaml:"chaincodeQuery"`
+	LedgerQuery    bool `json:"ledgerQuery" yaml:"ledgerQuery"`
+	EventSource    bool `json:"eventSource" yaml:"eventSource"`
+}
+
+// ConnectionProfileOrg is an organization of a connection profile.
+type ConnectionProfileOrg struct {
+	MSPID                  string   `json:"mspid" yaml:"mspid"`
+	Peers                  []string `json:"peers,omitempty" yaml:"peers,omitempty"`
+	CertificateAuthorities []string `json:"certificateAuthorities,omitempty" yaml:"certificateAuthorities,omitempty"`
+}
+
+// ConnectionProfileNode is a peer or orderer of a connection profile.
+type ConnectionProfileNode struct {
+	URL         string               `json:"url" yaml:"url"`
+	TLSCACerts  ConnectionProfilePEM `json:"tlsCACerts" yaml:"tlsCACerts"`
+	GRPCOptions map[string]string    `json:"grpcOptions,omitempty" yaml:"grpcOptions,omitempty"`
+}
+
+// ConnectionProfileCA is a certificate authority of a connection profile.
+type ConnectionProfileCA struct {
+	URL        string               `json:"url" yaml:"url"`
+	CAName     string               `json:"caName,omitempty" yaml:"caName,omitempty"`
+	TLSCACerts ConnectionProfilePEM `json:"tlsCACerts" yaml:"tlsCACerts"`
+}
+
+// ConnectionProfilePEM holds PEM encoded certificates.
+type ConnectionProfilePEM struct {
+	PEM string `json:"pem" yaml:"pem"`
+}
+
+// ConnectionProfile generates the connection profile of the channel for a
+// client of the organization named in the input. It contains every
+// application organization with its anchor peers and the peers and CAs of
+// the input, and the orderer endpoints of every orderer organization, or
+// the legacy orderer addresses if no organization defines endpoints. The
+// TLS CA certificates of the peers and orderers are the TLS root and
+// intermediate certificates of the MSP of their organization; those of the
+// CAs are the root and intermediate certificates of the MSP.
+func (c *ConfigTx) ConnectionProfile(channelID string, input ConnectionProfileInput) (ConnectionProfile, error) {
+	if channelID == "" {
+		return ConnectionProfile{}, errors.New("channel ID is required")
+	}
+
+	applicationGroup, ok := c.updated.ChannelGroup.Groups[ApplicationGroupKey]
+	if !ok {
+		return ConnectionProfile{}, errors.New("config does not contain an application group")
+	}
+
+	if _, ok := applicationGroup.Groups[input.Organization]; !ok {
+		return ConnectionProfile{}, fmt.Errorf("application org %s does not exist", input.Organization)
+	}
+
+	topology, err := c.Topology()
+	if err != nil {
+		return ConnectionProfile{}, err
+	}
+
+	name := input.Name
+	if name == "" {
+		name = channelID + "-" + input.Organization
+	}
+
+	profile := ConnectionProfile{
+		Name:          name,
+		Version:       ConnectionProfileVersion,
+		Client:        ConnectionProfileClient{Organization: input.Organization},
+		Organizations: map[string]ConnectionProfileOrg{},
+		Orderers:      map[string]ConnectionProfileNode{},
+		Peers:         map[string]ConnectionProfileNode{},
+	}
+	channel := ConnectionProfileChannel{
+		Peers: map[string]ConnectionProfileChannelPeer{},
+	}
+
+	for _, org := range topology.ApplicationOrgs {
+		msp, err := getMSPConfig(applicationGroup.Groups[org.Name], c.options.certificates)
+		if err != nil {
+			return ConnectionProfile{}, fmt.Errorf("retrieving MSP of application org %s: %w", org.Name, err)
+		}
+
+		profileOrg := ConnectionProfileOrg{MSPID: org.MSPID}
+		tlsCACerts := pemCertificates(msp.TLSRootCerts, msp.TLSIntermediateCerts)
+
+		for _, peer := range append(org.AnchorPeers, input.Peers[org.Name]...) {
+			peerName := nodeName(profile.Peers, peer.Host, peer.Port)
+			profile.Peers[peerName] = grpcNode(peer.Host, peer.Port, tlsCACerts)
+			profileOrg.Peers = append(profileOrg.Peers, peerName)
+			channel.Peers[peerName] = ConnectionProfileChannelPeer{
+				EndorsingPeer:  true,
+				ChaincodeQuery: true,
+				LedgerQuery:    true,
+				EventSource:    true,
+			}
+		}
+
+		for _, ca := range input.CertificateAuthorities[org.Name] {
+			if profile.CertificateAuthorities == nil {
+				profile.CertificateAuthorities = map[string]ConnectionProfileCA{}
+			}
+			profile.CertificateAuthorities[ca.Name] = ConnectionProfileCA{
+				URL:        ca.URL,
+				CAName:     ca.CAName,
+				TLSCACerts: ConnectionProfilePEM{PEM: pemCertificates(msp.RootCerts, msp.IntermediateCerts)},
+			}
+			profileOrg.CertificateAuthorities = append(profileOrg.CertificateAuthorities, ca.Name)
+		}
+
+		profile.Organizations[org.Name] = profileOrg
+	}
+
+	ordererGroup := c.updated.ChannelGroup.Groups[OrdererGroupKey]
+	var allOrdererTLSCACerts []*x509.Certificate
+	hasEndpoints := false
+	for _, org := range topology.OrdererOrgs {
+		msp, err := getMSPConfig(ordererGroup.Groups[org.Name], c.options.certificates)
+		if err != nil {
+			return ConnectionProfile{}, fmt.Errorf("retrieving MSP of orderer org %s: %w", org.Name, err)
+		}
+		allOrdererTLSCACerts = append(allOrdererTLSCACerts, msp.TLSRootCerts...)
+		allOrdererTLSCACerts = append(allOrdererTLSCACerts, msp.TLSIntermediateCerts...)
+
+		tlsCACerts := pemCertificates(msp.TLSRootCerts, msp.TLSIntermediateCerts)
+		for _, endpoint := range org.OrdererEndpoints {
+			err := addOrderer(&profile, &channel, endpoint, tlsCACerts)
+			if err != nil {
+				return ConnectionProfile{}, fmt.Errorf("invalid endpoint of orderer org %s: %w", org.Name, err)
+			}
+			hasEndpoints = true
+		}
+	}
+
+	if !hasEndpoints {
+		tlsCACerts := pemCertificates(allOrdererTLSCACerts)
+		for _, address := range topology.OrdererAddresses {
+			err := addOrderer(&profile, &channel, address, tlsCACerts)
+			if err != nil {
+				return ConnectionProfile{}, fmt.Errorf("invalid orderer address: %w", err)
+			}
+		}
+	}
+
+	profile.Channels = map[string]ConnectionProfileChannel{channelID: channel}
+
+	return profile, nil
+}
+
+// addOrderer adds the orderer at address, in host:port form, to the
+// profile and channel.
+func addOrderer(profile *ConnectionProfile, channel *ConnectionProfileChannel, address, tlsCACerts string) error {
+	host, port, err := parseAddress(address)
+	if err != nil {
+		return err
+	}
+
+	ordererName := nodeName(profile.Orderers, host, port)
+	profile.Orderers[ordererName] = grpcNode(host, port, tlsCACerts)
+	channel.Orderers = append(channel.Orderers, ordererName)
+
+	return nil
+}
+
+// nodeName returns the name of the node at host and port in a connection
+// profile: its host name, or its address if another node of the profile
+// has the same host name.
+func nodeName(nodes map[string]ConnectionProfileNode, host string, port int) string {
+	if _, ok := nodes[host]; !ok {
+		return host
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// grpcNode returns a node of a connection profile reached over gRPC with
+// TLS.
+func grpcNode(host string, port int, tlsCACerts string) ConnectionProfileNode {
+	return ConnectionProfileNode{
+		URL:        "grpcs://" + net.JoinHostPort(host, strconv.Itoa(port)),
+		TLSCACerts: ConnectionProfilePEM{PEM: tlsCACerts},
+		GRPCOptions: map[string]string{
+			"ssl-target-name-override": host,
+			"hostnameOverride":         host,
+		},
+	}
+}
+
+// pemCertificates returns the concatenated PEM encoding of the
+// certificates.
+func pemCertificates(certLists ...[]*x509.Certificate) string {
+	var pemCerts []byte
+	for _, certs := range certLists {
+		for _, cert := range certs {
+			pemCerts = append(pemCerts, pemEncodeX509Certificate(cert)...)
+		}
+	}
+
+	return string(pemCerts)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConnectionProfile(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseConnectionProfileConfigTx(t)
+
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	profile, err := c.ConnectionProfile("mychannel", ConnectionProfileInput{
+		Organization: "Org1",
+		Peers: map[string][]Address{
+			"Org2": {{Host: "peer0.org2.example.com", Port: 9051}},
+		},
+		CertificateAuthorities: map[string][]CertificateAuthority{
+			"Org1": {{Name: "ca.org1.example.com", URL: "https://ca.org1.example.com:7054", CAName: "ca-org1"}},
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org1MSP, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererMSP, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(profile.Name).To(Equal("mychannel-Org1"))
+	gt.Expect(profile.Version).To(Equal("1.0.0"))
+	gt.Expect(profile.Client).To(Equal(ConnectionProfileClient{Organization: "Org1"}))
+	gt.Expect(profile.Organizations).To(Equal(map[string]ConnectionProfileOrg{
+		"Org1": {
+			MSPID:                  "Org1MSP",
+			Peers:                  []string{"peer0.org1.example.com"},
+			CertificateAuthorities: []string{"ca.org1.example.com"},
+		},
+		"Org2": {
+			MSPID: "Org2MSP",
+			Peers: []string{"peer0.org2.example.com"},
+		},
+	}))
+	gt.Expect(profile.Peers).To(HaveLen(2))
+	gt.Expect(profile.Peers["peer0.org1.example.com"]).To(Equal(ConnectionProfileNode{
+		URL:        "grpcs://peer0.org1.example.com:7051",
+		TLSCACerts: ConnectionProfilePEM{PEM: pemCertificates(org1MSP.TLSRootCerts, org1MSP.TLSIntermediateCerts)},
+		GRPCOptions: map[string]string{
+			"ssl-target-name-override": "peer0.org1.example.com",
+			"hostnameOverride":         "peer0.org1.example.com",
+		},
+	}))
+	gt.Expect(profile.Peers["peer0.org2.example.com"].URL).To(Equal("grpcs://peer0.org2.example.com:9051"))
+	gt.Expect(profile.Orderers).To(Equal(map[string]ConnectionProfileNode{
+		"localhost": {
+			URL:        "grpcs://localhost:123",
+			TLSCACerts: ConnectionProfilePEM{PEM: pemCertificates(ordererMSP.TLSRootCerts, ordererMSP.TLSIntermediateCerts)},
+			GRPCOptions: map[string]string{
+				"ssl-target-name-override": "localhost",
+				"hostnameOverride":         "localhost",
+			},
+		},
+	}))
+	gt.Expect(profile.CertificateAuthorities).To(Equal(map[string]ConnectionProfileCA{
+		"ca.org1.example.com": {
+			URL:        "https://ca.org1.example.com:7054",
+			CAName:     "ca-org1",
+			TLSCACerts: ConnectionProfilePEM{PEM: pemCertificates(org1MSP.RootCerts, org1MSP.IntermediateCerts)},
+		},
+	}))
+	gt.Expect(profile.Channels).To(HaveKey("mychannel"))
+	gt.Expect(profile.Channels["mychannel"].Orderers).To(Equal([]string{"localhost"}))
+	gt.Expect(profile.Channels["mychannel"].Peers).To(HaveLen(2))
+	gt.Expect(profile.Channels["mychannel"].Peers).To(HaveKeyWithValue("peer0.org2.example.com", ConnectionProfileChannelPeer{
+		EndorsingPeer:  true,
+		ChaincodeQuery: true,
+		LedgerQuery:    true,
+		EventSource:    true,
+	}))
+
+	yamlProfile, err := yaml.Marshal(profile)
+	gt.Expect(err).NotTo(HaveOccurred())
+	decoded := ConnectionProfile{}
+	err = yaml.Unmarshal(yamlProfile, &decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded).To(Equal(profile))
+}
+
+func TestConnectionProfileLegacyOrdererAddresses(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseConnectionProfileConfigTx(t)
+	err := c.Orderer().Organization("OrdererOrg").RemoveEndpoint(Address{Host: "localhost", Port: 123})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	profile, err := c.ConnectionProfile("mychannel", ConnectionProfileInput{Name: "profile", Organization: "Org1"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(profile.Name).To(Equal("profile"))
+	gt.Expect(profile.Orderers).To(HaveLen(2))
+	gt.Expect(profile.Orderers["localhost"].URL).To(Equal("grpcs://localhost:123"))
+	gt.Expect(profile.Orderers["other.example.com"].URL).To(Equal("grpcs://other.example.com:7050"))
+	gt.Expect(profile.Channels["mychannel"].Orderers).To(Equal([]string{"localhost", "other.example.com"}))
+}
+
+func TestConnectionProfileFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		channelID   string
+		input       ConnectionProfileInput
+		expectedErr string
+	}{
+		{
+			testName:    "when the channel ID is missing",
+			input:       ConnectionProfileInput{Organization: "Org1"},
+			expectedErr: "channel ID is required",
+		},
+		{
+			testName:    "when the organization does not exist",
+			channelID:   "mychannel",
+			input:       ConnectionProfileInput{Organization: "Org3"},
+			expectedErr: "application org Org3 does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := baseConnectionProfileConfigTx(t)
+
+			_, err := c.ConnectionProfile(tc.channelID, tc.input)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+// baseConnectionProfileConfigTx returns the config of basePlanReplaceOrdererOrg
+// with the application organizations Org1 and Org2.
+func baseConnectionProfileConfigTx(t *testing.T) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	c, _, _ := basePlanReplaceOrdererOrg(t)
+
+	application, _ := baseApplication(t)
+	application.Organizations[0].MSP.Name = "Org1MSP"
+	application.Organizations[1].MSP.Name = "Org2MSP"
+	applicationGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey] = applicationGroup
+
+	return c
+}
@@ -24,6 +24,7 @@ type Consortium struct {
 // ConsortiumsGroup encapsulates the parts of the config that control consortiums.
 type ConsortiumsGroup struct {
 	consortiumsGroup *cb.ConfigGroup
+	tx               *ConfigTx
 }
 
 // ConsortiumGroup encapsulates the parts of the config that control
@@ -32,26 +33,31 @@ type ConsortiumsGroup struct {
 type ConsortiumGroup struct {
 	consortiumGroup *cb.ConfigGroup
 	name            string
+	tx              *ConfigTx
 }
 
 // ConsortiumOrg encapsulates the parts of the config that control a
 // consortium organization's configuration.
 type ConsortiumOrg struct {
-	orgGroup *cb.ConfigGroup
-	name     string
+	orgGroup   *cb.ConfigGroup
+	name       string
+	consortium string
+	tx         *ConfigTx
 }
 
 // MSP returns an OrganizationMSP object that can be used to configure the organization's MSP.
 func (c *ConsortiumOrg) MSP() *OrganizationMSP {
 	return &OrganizationMSP{
 		configGroup: c.orgGroup,
+		path:        c.path(),
+		tx:          c.tx,
 	}
 }
 
 // Consortiums returns the consortiums group from the updated config.
 func (c *ConfigTx) Consortiums() *ConsortiumsGroup {
 	consortiumsGroup := c.updated.ChannelGroup.Groups[ConsortiumsGroupKey]
-	return &ConsortiumsGroup{consortiumsGroup: consortiumsGroup}
+	return &ConsortiumsGroup{consortiumsGroup: consortiumsGroup, tx: c}
 }
 
 // Consortium returns a consortium group from the updated config.
@@ -60,7 +66,7 @@ func (c *ConfigTx) Consortium(name string) *ConsortiumGroup {
 	if !ok {
 		return nil
 	}
-	return &ConsortiumGroup{name: name, consortiumGroup: consortiumGroup}
+	return &ConsortiumGroup{name: name, consortiumGroup: consortiumGroup, tx: c}
 }
 
 // SetConsortium sets the consortium in a channel configuration.
@@ -75,6 +81,8 @@ func (c *ConsortiumsGroup) SetConsortium(consortium Consortium) error {
 		}
 	}
 
+	c.tx.notify(c.path(), "SetConsortium")
+
 	return nil
 }
 
@@ -87,6 +95,8 @@ func (c *ConsortiumsGroup) consortium(name string) *ConsortiumGroup {
 // Removal will panic if the consortiums group does not exist.
 func (c *ConsortiumsGroup) RemoveConsortium(name string) {
 	delete(c.consortiumsGroup.Groups, name)
+
+	c.tx.notify(c.path(), "RemoveConsortium")
 }
 
 // Organization returns the consortium org from the original config.
@@ -95,7 +105,7 @@ func (c *ConsortiumGroup) Organization(name string) *ConsortiumOrg {
 	if !ok {
 		return nil
 	}
-	return &ConsortiumOrg{name: name, orgGroup: orgGroup}
+	return &ConsortiumOrg{name: name, consortium: c.name, orgGroup: orgGroup, tx: c.tx}
 }
 
 // SetOrganization sets the organization config group for the given org key in
@@ -103,13 +113,15 @@ func (c *ConsortiumGroup) Organization(name string) *ConsortiumOrg {
 // If the consortium org already exists in the current configuration, its
 // value will be overwritten.
 func (c *ConsortiumGroup) SetOrganization(org Organization) error {
-	orgGroup, err := newOrgConfigGroup(org)
+	orgGroup, err := newOrgConfigGroup(org, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create consortium org %s: %v", org.Name, err)
+		return fmt.Errorf("failed to create consortium org %s: %w", org.Name, err)
 	}
 
 	c.consortiumGroup.Groups[org.Name] = orgGroup
 
+	c.tx.notify(c.path(), "SetOrganization")
+
 	return nil
 }
 
@@ -117,6 +129,8 @@ func (c *ConsortiumGroup) SetOrganization(org Organization) error {
 // Removal will panic if either the consortiums group or consortium group does not exist.
 func (c *ConsortiumGroup) RemoveOrganization(name string) {
 	delete(c.consortiumGroup.Groups, name)
+
+	c.tx.notify(c.path(), "RemoveOrganization")
 }
 
 // Configuration returns a list of consortium configurations from the updated
@@ -138,7 +152,7 @@ func (c *ConsortiumsGroup) Configuration() ([]Consortium, error) {
 func (c *ConsortiumGroup) Configuration() (Consortium, error) {
 	orgs := []Organization{}
 	for orgName, orgGroup := range c.consortiumGroup.Groups {
-		org, err := getOrganization(orgGroup, orgName)
+		org, err := getOrganization(orgGroup, orgName, c.tx.certificates())
 		if err != nil {
 			return Consortium{}, fmt.Errorf("failed to retrieve organization %s from consortium %s: ", orgName, c.name)
 		}
@@ -153,7 +167,7 @@ func (c *ConsortiumGroup) Configuration() (Consortium, error) {
 // Configuration retrieves an existing org's configuration from a consortium
 // organization config group in the updated config.
 func (c *ConsortiumOrg) Configuration() (Organization, error) {
-	org, err := getOrganization(c.orgGroup, c.name)
+	org, err := getOrganization(c.orgGroup, c.name, c.tx.certificates())
 	if err != nil {
 		return Organization{}, err
 	}
@@ -168,7 +182,7 @@ func (c *ConsortiumOrg) Configuration() (Organization, error) {
 func (c *ConsortiumOrg) SetMSP(updatedMSP MSP) error {
 	currentMSP, err := c.MSP().Configuration()
 	if err != nil {
-		return fmt.Errorf("retrieving msp: %v", err)
+		return fmt.Errorf("retrieving msp: %w", err)
 	}
 
 	if currentMSP.Name != updatedMSP.Name {
@@ -185,13 +199,17 @@ func (c *ConsortiumOrg) SetMSP(updatedMSP MSP) error {
 		return err
 	}
 
+	c.tx.notify(c.path(), "SetMSP")
+
+	c.tx.checkAdminCerts(c.path(), updatedMSP)
+
 	return nil
 }
 
 func (c *ConsortiumOrg) setMSPConfig(updatedMSP MSP) error {
 	mspConfig, err := newMSPConfig(updatedMSP)
 	if err != nil {
-		return fmt.Errorf("new msp config: %v", err)
+		return fmt.Errorf("new msp config: %w", err)
 	}
 
 	err = setValue(c.orgGroup, mspValue(mspConfig), AdminsPolicyKey)
@@ -208,19 +226,21 @@ func (c *ConsortiumOrg) setMSPConfig(updatedMSP MSP) error {
 func (c *ConsortiumGroup) SetChannelCreationPolicy(policy Policy) error {
 	imp, err := implicitMetaFromString(policy.Rule)
 	if err != nil {
-		return fmt.Errorf("invalid implicit meta policy rule '%s': %v", policy.Rule, err)
+		return fmt.Errorf("invalid implicit meta policy rule '%s': %w", policy.Rule, err)
 	}
 
 	implicitMetaPolicy, err := implicitMetaPolicy(imp.SubPolicy, imp.Rule)
 	if err != nil {
-		return fmt.Errorf("failed to make implicit meta policy: %v", err)
+		return fmt.Errorf("failed to make implicit meta policy: %w", err)
 	}
 
 	// update channel creation policy value back to consortium
 	if err = setValue(c.consortiumGroup, channelCreationPolicyValue(implicitMetaPolicy), ordererAdminsPolicyName); err != nil {
-		return fmt.Errorf("failed to update channel creation policy to consortium %s: %v", c.name, err)
+		return fmt.Errorf("failed to update channel creation policy to consortium %s: %w", c.name, err)
 	}
 
+	c.tx.notify(c.path(), "SetChannelCreationPolicy")
+
 	return nil
 }
 
@@ -234,9 +254,11 @@ func (c *ConsortiumOrg) Policies() (map[string]Policy, error) {
 func (c *ConsortiumOrg) SetPolicy(name string, policy Policy) error {
 	err := setPolicy(c.orgGroup, AdminsPolicyKey, name, policy)
 	if err != nil {
-		return fmt.Errorf("failed to set policy '%s' to consortium org '%s': %v", name, c.name, err)
+		return fmt.Errorf("failed to set policy '%s' to consortium org '%s': %w", name, c.name, err)
 	}
 
+	c.tx.notify(c.path(), "SetPolicy")
+
 	return nil
 }
 
@@ -244,12 +266,21 @@ func (c *ConsortiumOrg) SetPolicy(name string, policy Policy) error {
 // Removal will panic if either the consortiums group, consortium group, or consortium org group does not exist.
 func (c *ConsortiumOrg) RemovePolicy(name string) {
 	delete(c.orgGroup.Policies, name)
+
+	c.tx.notify(c.path(), "RemovePolicy")
 }
 
-// newConsortiumsGroup returns the consortiums component of the channel configuration. This element is only defined for
+// NewConsortiumsGroup returns the consortiums component of the channel configuration. This element is only defined for
 // the ordering system channel.
 // It sets the mod_policy for all elements to "/Channel/Orderer/Admins".
-func newConsortiumsGroup(consortiums []Consortium) (*cb.ConfigGroup, error) {
+// It can be used to compose custom channel config trees.
+func NewConsortiumsGroup(consortiums []Consortium) (*cb.ConfigGroup, error) {
+	return newConsortiumsGroup(consortiums, nil)
+}
+
+// newConsortiumsGroup returns the consortiums group, converting the MSPs of
+// the organizations with mspConfigs.
+func newConsortiumsGroup(consortiums []Consortium, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
 	var err error
 
 	consortiumsGroup := newConfigGroup()
@@ -271,7 +302,7 @@ func newConsortiumsGroup(consortiums []Consortium) (*cb.ConfigGroup, error) {
 	}
 
 	for _, consortium := range consortiums {
-		consortiumsGroup.Groups[consortium.Name], err = newConsortiumGroup(consortium)
+		consortiumsGroup.Groups[consortium.Name], err = newConsortiumGroup(consortium, mspConfigs)
 		if err != nil {
 			return nil, err
 		}
@@ -281,16 +312,16 @@ func newConsortiumsGroup(consortiums []Consortium) (*cb.ConfigGroup, error) {
 }
 
 // newConsortiumGroup returns a consortiums component of the channel configuration.
-func newConsortiumGroup(consortium Consortium) (*cb.ConfigGroup, error) {
+func newConsortiumGroup(consortium Consortium, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
 	var err error
 
 	consortiumGroup := newConfigGroup()
 	consortiumGroup.ModPolicy = ordererAdminsPolicyName
 
 	for _, org := range consortium.Organizations {
-		consortiumGroup.Groups[org.Name], err = newOrgConfigGroup(org)
+		consortiumGroup.Groups[org.Name], err = newOrgConfigGroup(org, mspConfigs)
 		if err != nil {
-			return nil, fmt.Errorf("org group '%s': %v", org.Name, err)
+			return nil, fmt.Errorf("org group '%s': %w", org.Name, err)
 		}
 	}
 
@@ -357,7 +388,7 @@ func nOutOf(n int32, policies []*cb.SignaturePolicy) *cb.SignaturePolicy {
 func signaturePolicy(policyName string, sigPolicy *cb.SignaturePolicyEnvelope) (*standardConfigPolicy, error) {
 	signaturePolicy, err := proto.Marshal(sigPolicy)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling signature policy: %v", err)
+		return nil, fmt.Errorf("marshaling signature policy: %w", err)
 	}
 
 	return &standardConfigPolicy{
@@ -376,7 +407,7 @@ func implicitMetaPolicy(subPolicyName string, rule cb.ImplicitMetaPolicy_Rule) (
 		SubPolicy: subPolicyName,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal implicit meta policy: %v", err)
+		return nil, fmt.Errorf("failed to marshal implicit meta policy: %w", err)
 	}
 
 	return &cb.Policy{
@@ -389,7 +420,7 @@ func implicitMetaPolicy(subPolicyName string, rule cb.ImplicitMetaPolicy_Rule) (
 func implicitMetaAnyPolicy(policyName string) (*standardConfigPolicy, error) {
 	implicitMetaPolicy, err := implicitMetaPolicy(policyName, cb.ImplicitMetaPolicy_ANY)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make implicit meta ANY policy: %v", err)
+		return nil, fmt.Errorf("failed to make implicit meta ANY policy: %w", err)
 	}
 
 	return &standardConfigPolicy{
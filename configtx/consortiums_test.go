@@ -13,6 +13,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -28,7 +29,7 @@ func TestNewConsortiumsGroup(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	consortiums, _ := baseConsortiums(t)
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	org1CertBase64, org1CRLBase64 := certCRLBase64(t, consortiums[0].Organizations[0].MSP)
@@ -319,7 +320,7 @@ func TestNewConsortiumsGroupFailure(t *testing.T) {
 	consortiums, _ := baseConsortiums(t)
 	consortiums[0].Organizations[0].Policies = nil
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).To(MatchError("org group 'Org1': no policies defined"))
 	gt.Expect(consortiumsGroup).To(BeNil())
 }
@@ -333,7 +334,7 @@ func TestSetConsortiumOrg(t *testing.T) {
 	org1CertBase64, org1CRLBase64 := certCRLBase64(t, consortiums[0].Organizations[0].MSP)
 	org2CertBase64, org2CRLBase64 := certCRLBase64(t, consortiums[0].Organizations[1].MSP)
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -787,7 +788,7 @@ func TestSetConsortiumOrgFailures(t *testing.T) {
 
 			consortiums, _ := baseConsortiums(t)
 
-			consortiumsGroup, err := newConsortiumsGroup(consortiums)
+			consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			config := &cb.Config{
@@ -812,7 +813,7 @@ func TestRemoveConsortium(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	consortiums, _ := baseConsortiums(t)
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -847,7 +848,7 @@ func TestGetConsortiums(t *testing.T) {
 		Policies:     policies,
 		Consortium:   "testconsortium",
 	}
-	channelGroup, err := newSystemChannelGroup(channel)
+	channelGroup, err := newSystemChannelGroup(channel, nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{ChannelGroup: channelGroup}
@@ -879,7 +880,7 @@ func TestSetConsortium(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	consortiums, _ := baseConsortiums(t)
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1425,7 +1426,7 @@ func TestConsortiumOrg(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	channel, _, _ := baseSystemChannelProfile(t)
-	channelGroup, err := newSystemChannelGroup(channel)
+	channelGroup, err := newSystemChannelGroup(channel, nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1473,7 +1474,7 @@ func TestRemoveConsortiumOrg(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	channel, _, _ := baseSystemChannelProfile(t)
-	channelGroup, err := newSystemChannelGroup(channel)
+	channelGroup, err := newSystemChannelGroup(channel, nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1493,7 +1494,7 @@ func TestSetConsortiumOrgPolicy(t *testing.T) {
 
 	consortiums, _ := baseConsortiums(t)
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1545,7 +1546,7 @@ func TestSetConsortiumOrgPolicyFailures(t *testing.T) {
 
 	consortiums, _ := baseConsortiums(t)
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1587,7 +1588,7 @@ func TestRemoveConsortiumOrgPolicy(t *testing.T) {
 
 	consortiums[0].Organizations[0].Policies["TestPolicy"] = Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement"}
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1634,7 +1635,7 @@ func TestConsortiumOrgPolicies(t *testing.T) {
 
 	consortiums, _ := baseConsortiums(t)
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1679,7 +1680,7 @@ func TestConsortiumMSP(t *testing.T) {
 	consortiums, _ := baseConsortiums(t)
 	expectedMSP := consortiums[0].Organizations[0].MSP
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -2068,7 +2069,7 @@ func TestSetConsortiumMSPFailure(t *testing.T) {
 			},
 			consortiumName: "Consortium1",
 			orgName:        "Org1",
-			expectedErr:    "invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7",
+			expectedErr:    "invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7; intermediate cert not signed by any root certs of this MSP. serial number: {{intermediateSerial}}",
 		},
 	}
 
@@ -2090,9 +2091,11 @@ func TestSetConsortiumMSPFailure(t *testing.T) {
 			consortiumOrg1MSP, err := consortiumOrg1.MSP().Configuration()
 			gt.Expect(err).NotTo(HaveOccurred())
 
+			intermediateSerial := consortiumOrg1MSP.IntermediateCerts[0].SerialNumber.String()
+
 			consortiumOrg1MSP = tc.mspMod(consortiumOrg1MSP)
 			err = consortiumOrg1.SetMSP(consortiumOrg1MSP)
-			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(err).To(MatchError(strings.Replace(tc.expectedErr, "{{intermediateSerial}}", intermediateSerial, -1)))
 		})
 	}
 }
@@ -2124,7 +2127,7 @@ func baseConsortiumChannelGroup(t *testing.T) (*cb.ConfigGroup, []*ecdsa.Private
 	channelGroup := newConfigGroup()
 
 	consortiums, privKeys := baseConsortiums(t)
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -11,6 +11,10 @@ const (
 	msgVersion = 0
 	epoch      = 0
 
+	// configtxgenGenesisMsgVersion is the message version configtxgen
+	// sets in the channel header of genesis blocks.
+	configtxgenGenesisMsgVersion = 1
+
 	// ConsortiumKey is the key for the ConfigValue of a
 	// Consortium.
 	ConsortiumKey = "Consortium"
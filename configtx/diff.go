@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ChangeType describes how a config element differs between two configs.
+type ChangeType string
+
+const (
+	// ChangeAdded is an element that only exists in the second config.
+	ChangeAdded ChangeType = "added"
+	// ChangeRemoved is an element that only exists in the first config.
+	ChangeRemoved ChangeType = "removed"
+	// ChangeModified is an element whose content, version or mod policy
+	// differs between the configs.
+	ChangeModified ChangeType = "modified"
+)
+
+// ElementType is the type of a config element.
+type ElementType string
+
+// The types of config elements.
+const (
+	ElementGroup  ElementType = "group"
+	ElementValue  ElementType = "value"
+	ElementPolicy ElementType = "policy"
+)
+
+// Difference is a config element that differs between two configs.
+type Difference struct {
+	// Path is the config path of the element in the form taken by ValueAt
+	// and SetModPolicyAt, e.g. /Channel/Application/Org1/Values/MSP.
+	Path    string
+	Element ElementType
+	Change  ChangeType
+}
+
+// DiffAt compares the subtree rooted at the group at path, e.g.
+// []string{"Channel", "Application", "Org1"}, in configs a and b and
+// returns the elements that differ, listing the values, policies and
+// sub-groups of each group in lexical order. Elements outside the
+// subtree are not compared. A group is reported as modified when its
+// version or mod policy differs; the differences of its members are
+// reported individually. An error is returned if the group exists in
+// neither config.
+func DiffAt(path []string, a, b *cb.Config) ([]Difference, error) {
+	if len(path) == 0 || path[0] != ChannelGroupKey {
+		return nil, fmt.Errorf("path must start with %s", ChannelGroupKey)
+	}
+	if a == nil || b == nil {
+		return nil, errors.New("both configs are required")
+	}
+
+	groupPath := configPath(path...)
+
+	groupA, _ := configGroupAtPath(a.ChannelGroup, path[1:])
+	groupB, _ := configGroupAtPath(b.ChannelGroup, path[1:])
+
+	switch {
+	case groupA == nil && groupB == nil:
+		return nil, fmt.Errorf("group %s does not exist in either config", groupPath)
+	case groupA == nil:
+		return []Difference{{Path: groupPath, Element: ElementGroup, Change: ChangeAdded}}, nil
+	case groupB == nil:
+		return []Difference{{Path: groupPath, Element: ElementGroup, Change: ChangeRemoved}}, nil
+	}
+
+	return diffGroups(groupPath, groupA, groupB), nil
+}
+
+// diffGroups returns the differences between two versions of the group at
+// groupPath.
+func diffGroups(groupPath string, a, b *cb.ConfigGroup) []Difference {
+	var differences []Difference
+
+	if a.Version != b.Version || a.ModPolicy != b.ModPolicy {
+		differences = append(differences, Difference{Path: groupPath, Element: ElementGroup, Change: ChangeModified})
+	}
+
+	for _, name := range unionKeys(a.Values, b.Values) {
+		valueA, okA := a.Values[name]
+		valueB, okB := b.Values[name]
+		if change, ok := compareElements(okA, okB, valueA, valueB); ok {
+			differences = append(differences, Difference{Path: elementPath(groupPath, ElementValue, name), Element: ElementValue, Change: change})
+		}
+	}
+
+	for _, name := range unionKeys(a.Policies, b.Policies) {
+		policyA, okA := a.Policies[name]
+		policyB, okB := b.Policies[name]
+		if change, ok := compareElements(okA, okB, policyA, policyB); ok {
+			differences = append(differences, Difference{Path: elementPath(groupPath, ElementPolicy, name), Element: ElementPolicy, Change: change})
+		}
+	}
+
+	for _, name := range unionKeys(a.Groups, b.Groups) {
+		subGroupA, okA := a.Groups[name]
+		subGroupB, okB := b.Groups[name]
+		subGroupPath := groupPath + "/" + name
+
+		switch {
+		case !okA:
+			differences = append(differences, Difference{Path: subGroupPath, Element: ElementGroup, Change: ChangeAdded})
+		case !okB:
+			differences = append(differences, Difference{Path: subGroupPath, Element: ElementGroup, Change: ChangeRemoved})
+		default:
+			differences = append(differences, diffGroups(subGroupPath, subGroupA, subGroupB)...)
+		}
+	}
+
+	return differences
+}
+
+// compareElements returns how an element differs between the configs and
+// whether it differs at all.
+func compareElements(inA, inB bool, a, b proto.Message) (ChangeType, bool) {
+	switch {
+	case !inA:
+		return ChangeAdded, true
+	case !inB:
+		return ChangeRemoved, true
+	case !proto.Equal(a, b):
+		return ChangeModified, true
+	default:
+		return "", false
+	}
+}
+
+// unionKeys returns the keys of config groups, values, or policies maps in
+// lexical order.
+func unionKeys(maps ...interface{}) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, m := range maps {
+		for _, key := range sortedKeys(m) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
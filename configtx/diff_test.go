@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiffAt(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	original, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(original)
+
+	org1 := c.Application().Organization("Org1")
+	err = org1.SetPolicy(AdminsPolicyKey, WritersPolicyKey, Policy{Type: SignaturePolicyType, Rule: "OR('Org1MSP.client')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = org1.SetPolicy(AdminsPolicyKey, "Custom", Policy{Type: SignaturePolicyType, Rule: "OR('Org1MSP.peer')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org2").SetPolicy(AdminsPolicyKey, WritersPolicyKey, Policy{Type: SignaturePolicyType, Rule: "OR('Org2MSP.client')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	differences, err := DiffAt([]string{"Channel", "Application", "Org1"}, original, c.UpdatedConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(Equal([]Difference{
+		{Path: "/Channel/Application/Org1/Policies/Custom", Element: ElementPolicy, Change: ChangeAdded},
+		{Path: "/Channel/Application/Org1/Policies/Writers", Element: ElementPolicy, Change: ChangeModified},
+	}))
+
+	modPolicy, err := c.ModPolicyAt(differences[0].Path)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal(AdminsPolicyKey))
+
+	differences, err = DiffAt([]string{"Channel", "Orderer"}, original, c.UpdatedConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(BeEmpty())
+
+	differences, err = DiffAt([]string{"Channel", "Application"}, original, c.UpdatedConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(HaveLen(3))
+	gt.Expect(differences[2].Path).To(Equal("/Channel/Application/Org2/Policies/Writers"))
+}
+
+func TestDiffAtGroupAddedOrRemoved(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	a := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": {},
+					},
+				},
+			},
+		},
+	}
+	b := proto.Clone(a).(*cb.Config)
+	b.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"] = &cb.ConfigGroup{}
+	b.ChannelGroup.Groups[ApplicationGroupKey].Version = 1
+	delete(b.ChannelGroup.Groups[ApplicationGroupKey].Groups, "Org1")
+
+	differences, err := DiffAt([]string{"Channel", "Application"}, a, b)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(Equal([]Difference{
+		{Path: "/Channel/Application", Element: ElementGroup, Change: ChangeModified},
+		{Path: "/Channel/Application/Org1", Element: ElementGroup, Change: ChangeRemoved},
+		{Path: "/Channel/Application/Org2", Element: ElementGroup, Change: ChangeAdded},
+	}))
+
+	differences, err = DiffAt([]string{"Channel", "Application", "Org2"}, a, b)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(differences).To(Equal([]Difference{
+		{Path: "/Channel/Application/Org2", Element: ElementGroup, Change: ChangeAdded},
+	}))
+}
+
+func TestDiffAtFailures(t *testing.T) {
+	t.Parallel()
+
+	config := &cb.Config{ChannelGroup: &cb.ConfigGroup{}}
+
+	tests := []struct {
+		name        string
+		path        []string
+		a, b        *cb.Config
+		expectedErr string
+	}{
+		{
+			name:        "empty path",
+			a:           config,
+			b:           config,
+			expectedErr: "path must start with Channel",
+		},
+		{
+			name:        "path outside of channel group",
+			path:        []string{"Application"},
+			a:           config,
+			b:           config,
+			expectedErr: "path must start with Channel",
+		},
+		{
+			name:        "missing config",
+			path:        []string{"Channel"},
+			a:           config,
+			expectedErr: "both configs are required",
+		},
+		{
+			name:        "group does not exist",
+			path:        []string{"Channel", "Application", "Org1"},
+			a:           config,
+			b:           config,
+			expectedErr: "group /Channel/Application/Org1 does not exist in either config",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			_, err := DiffAt(tc.path, tc.a, tc.b)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
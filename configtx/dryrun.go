@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// DryRunResult is what a change would do to the updated config of a
+// ConfigTx.
+type DryRunResult struct {
+	// Intents are the mutations made by the change, in order.
+	Intents []Mutation
+	// Warnings are the warnings raised while making the change, including
+	// capability levels that the change makes inconsistent.
+	Warnings []Warning
+	// RequiredPolicies are the mod policies that must be satisfied by the
+	// signatures of the config update computed after the change.
+	RequiredPolicies []ProposalPolicy
+}
+
+// DryRun makes the change on a copy of the updated config and checks that
+// a config update could be produced from the result, without modifying
+// the ConfigTx. It fails if the change fails, e.g. because it removes a
+// policy that does not exist, if the changed config does not pass
+// Validate, e.g. because of an invalid MSP, or if a config update cannot
+// be computed, e.g. because it modifies paths outside of the path guard or
+// one of its mod policies cannot be resolved. The observers and warning
+// handler of the ConfigTx are not invoked; the mutations and warnings are
+// recorded in the result instead, which is returned along with the error
+// if the change itself fails.
+func (c *ConfigTx) DryRun(change func(c *ConfigTx) error) (DryRunResult, error) {
+	result := DryRunResult{}
+
+	options := c.options
+	options.observers = []Observer{func(m Mutation) {
+		result.Intents = append(result.Intents, m)
+	}}
+	options.warningHandler = func(w Warning) {
+		result.Warnings = append(result.Warnings, w)
+	}
+
+	dryRun := ConfigTx{
+		original: c.original,
+		updated:  proto.Clone(c.updated).(*cb.Config),
+		options:  options,
+	}
+	if options.configurationCache {
+		dryRun.cache = &configCache{}
+	}
+
+	err := change(&dryRun)
+	if err != nil {
+		return result, fmt.Errorf("applying change: %w", err)
+	}
+
+	err = dryRun.Validate()
+	if err != nil {
+		return DryRunResult{}, fmt.Errorf("validating changed config: %w", err)
+	}
+
+	err = dryRun.transform()
+	if err != nil {
+		return DryRunResult{}, fmt.Errorf("failed to transform updated config: %w", err)
+	}
+
+	err = options.checkPathGuard(c.original, dryRun.updated)
+	if err != nil {
+		return DryRunResult{}, fmt.Errorf("config update not permitted: %w", err)
+	}
+
+	update, err := computeConfigUpdate(c.original, dryRun.updated)
+	if err != nil {
+		return DryRunResult{}, fmt.Errorf("failed to compute update: %w", err)
+	}
+
+	result.RequiredPolicies, err = requiredPolicies(c.original.ChannelGroup, update, options.evaluator())
+	if err != nil {
+		return DryRunResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	for i, mspID := range []string{"Org1MSP", "Org2MSP"} {
+		org := &application.Organizations[i]
+		org.MSP.Name = mspID
+		org.Policies[AdminsPolicyKey] = Policy{Type: SignaturePolicyType, Rule: "OR('" + mspID + ".admin')"}
+	}
+
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var observed []Mutation
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	}, WithObserver(func(m Mutation) { observed = append(observed, m) }))
+
+	result, err := c.DryRun(func(c *ConfigTx) error {
+		for _, org := range []string{"Org1", "Org2"} {
+			err := c.Application().Organization(org).AddAnchorPeer(Address{Host: "peer0." + org, Port: 7051})
+			if err != nil {
+				return err
+			}
+		}
+		return c.Application().SetACLs(map[string]string{"acl1": "Writers"})
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(result.Intents).To(Equal([]Mutation{
+		{Path: "/Channel/Application/Org1", Operation: "AddAnchorPeer"},
+		{Path: "/Channel/Application/Org2", Operation: "AddAnchorPeer"},
+		{Path: "/Channel/Application", Operation: "SetACLs"},
+	}))
+	gt.Expect(result.Warnings).To(BeEmpty())
+	gt.Expect(result.RequiredPolicies).To(HaveLen(3))
+	gt.Expect(result.RequiredPolicies[0].Path).To(Equal("/Channel/Application/Admins"))
+
+	gt.Expect(observed).To(BeEmpty())
+	gt.Expect(proto.Equal(c.UpdatedConfig(), c.OriginalConfig())).To(BeTrue())
+}
+
+func TestDryRunFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName        string
+		change          func(c *ConfigTx) error
+		expectedErr     string
+		expectedIntents []Mutation
+	}{
+		{
+			testName: "when the change fails",
+			change: func(c *ConfigTx) error {
+				err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+				if err != nil {
+					return err
+				}
+				return c.Application().Organization("Org2").RemoveAnchorPeer(Address{Host: "peer0.org2", Port: 7051})
+			},
+			expectedErr:     "applying change: could not find anchor peer peer0.org2:7051 in application org Org2",
+			expectedIntents: []Mutation{{Path: "/Channel/Application/Org1", Operation: "AddAnchorPeer"}},
+		},
+		{
+			testName: "when the changed config is invalid",
+			change: func(c *ConfigTx) error {
+				for _, org := range []string{"Org1", "Org2"} {
+					err := c.Application().Organization(org).AddAnchorPeer(Address{Host: "peer0.example.com", Port: 7051})
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expectedErr: "validating changed config: anchor peer peer0.example.com:7051 of application org Org2 is also an anchor peer of application org Org1",
+		},
+		{
+			testName:    "when the change does not modify the config",
+			change:      func(c *ConfigTx) error { return nil },
+			expectedErr: "failed to compute update: no differences detected between original and updated config",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseApplicationChannelGroup(t)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			result, err := c.DryRun(tc.change)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(result.Intents).To(Equal(tc.expectedIntents))
+			gt.Expect(proto.Equal(c.UpdatedConfig(), c.OriginalConfig())).To(BeTrue())
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// VerifyEndpointCoverage checks that the host of each orderer endpoint of
+// the orderer organizations could be served by a TLS certificate issued by
+// the TLS CAs of its organization. What the CAs issue is judged from the
+// subject alternative names of the etcdraft consenter server TLS
+// certificates they issued: the host must be one of the names or IPs of
+// the certificates, match one of their wildcard names or be in the domain
+// of one of their DNS names. Organizations without consenter certificates
+// issued by their TLS CAs are not checked. The error wraps
+// ValidationErrors with a finding for each endpoint no certificate could
+// serve.
+func (o *OrdererGroup) VerifyEndpointCoverage() error {
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	if cfg.OrdererType != orderer.ConsensusTypeEtcdRaft {
+		return fmt.Errorf("consensus type %s is not etcdraft", cfg.OrdererType)
+	}
+
+	var findings ValidationErrors
+	for _, org := range cfg.Organizations {
+		certs := issuedConsenterCerts(org.MSP, cfg.EtcdRaft.Consenters)
+		if len(certs) == 0 {
+			continue
+		}
+
+		for _, endpoint := range org.OrdererEndpoints {
+			host := endpointHost(endpoint)
+			if !hostCoverable(host, certs) {
+				findings = append(findings, fmt.Errorf("orderer endpoint %s of org %s: host %s is not covered by the consenter TLS certificates issued by the TLS CAs of the org (%s)",
+					endpoint, org.Name, host, strings.Join(certNames(certs), ", ")))
+			}
+		}
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("orderer endpoints not covered by TLS certificates: %w", findings)
+	}
+
+	return nil
+}
+
+// issuedConsenterCerts returns the server TLS certificates of the
+// consenters that were issued by the TLS CAs of the MSP.
+func issuedConsenterCerts(msp MSP, consenters []orderer.Consenter) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for _, consenter := range consenters {
+		cert := consenter.ServerTLSCert
+		if cert == nil {
+			continue
+		}
+
+		// verified as of issuance, so that expired certificates still
+		// show which names the CAs issue certificates for
+		err := verifyCertificateChain(cert, msp.TLSRootCerts, msp.TLSIntermediateCerts, cert.NotBefore)
+		if err == nil {
+			certs = append(certs, cert)
+		}
+	}
+
+	return certs
+}
+
+// endpointHost returns the host of an orderer endpoint of the form
+// host:port.
+func endpointHost(endpoint string) string {
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	return host
+}
+
+// hostCoverable reports whether a certificate for host could be issued
+// like the certificates.
+func hostCoverable(host string, certs []*x509.Certificate) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		for _, cert := range certs {
+			for _, certIP := range cert.IPAddresses {
+				if certIP.Equal(ip) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, cert := range certs {
+		for _, name := range cert.DNSNames {
+			name = strings.ToLower(name)
+			if name == host {
+				return true
+			}
+
+			domain := parentDomain(name)
+			if domain != "" && domain == parentDomain(host) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parentDomain returns the name without its first label, e.g. example.com
+// for orderer1.example.com and *.example.com, or an empty string for a
+// single label.
+func parentDomain(name string) string {
+	i := strings.Index(name, ".")
+	if i < 0 {
+		return ""
+	}
+
+	return name[i+1:]
+}
+
+// certNames returns the DNS names and IPs of the certificates.
+func certNames(certs []*x509.Certificate) []string {
+	var names []string
+	for _, cert := range certs {
+		names = append(names, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			names = append(names, ip.String())
+		}
+	}
+
+	return names
+}
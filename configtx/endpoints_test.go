@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"net"
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestVerifyEndpointCoverage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		endpoints   []string
+		expectedErr string
+	}{
+		{
+			testName: "when the endpoints are covered",
+			endpoints: []string{
+				"node-1.example.com:7050",
+				"NODE-7.example.com:7050",
+				"10.0.0.3:7050",
+			},
+		},
+		{
+			testName: "when endpoints are not covered",
+			endpoints: []string{
+				"node-1.example.com:7050",
+				"orderer.example.org:7050",
+				"10.0.0.9:7050",
+			},
+			expectedErr: "orderer endpoints not covered by TLS certificates: " +
+				"orderer endpoint orderer.example.org:7050 of org OrdererOrg: host orderer.example.org is not covered by the consenter TLS certificates issued by the TLS CAs of the org " +
+				"(node-1.example.com, node-2.example.com, node-3.example.com, 10.0.0.3); " +
+				"orderer endpoint 10.0.0.9:7050 of org OrdererOrg: host 10.0.0.9 is not covered by the consenter TLS certificates issued by the TLS CAs of the org " +
+				"(node-1.example.com, node-2.example.com, node-3.example.com, 10.0.0.3)",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			ordererConf, _ := baseEtcdRaftOrderer(t)
+			caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+			for i, c := range ordererConf.EtcdRaft.Consenters {
+				ordererConf.EtcdRaft.Consenters[i].ServerTLSCert = generateServerTLSCert(t, caCert, caPrivKey, []string{c.Address.Host}, nil)
+			}
+			ordererConf.EtcdRaft.Consenters[2].ServerTLSCert = generateServerTLSCert(t, caCert, caPrivKey, []string{"node-3.example.com"}, []net.IP{net.ParseIP("10.0.0.3")})
+			ordererConf.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{caCert}
+			ordererConf.Organizations[0].OrdererEndpoints = tc.endpoints
+
+			ordererGroup, err := NewOrdererGroup(ordererConf)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			c := New(&cb.Config{
+				ChannelGroup: &cb.ConfigGroup{
+					Groups: map[string]*cb.ConfigGroup{
+						OrdererGroupKey: ordererGroup,
+					},
+				},
+			})
+
+			err = c.Orderer().VerifyEndpointCoverage()
+			if tc.expectedErr == "" {
+				gt.Expect(err).NotTo(HaveOccurred())
+			} else {
+				gt.Expect(err).To(MatchError(tc.expectedErr))
+			}
+		})
+	}
+}
+
+func TestVerifyEndpointCoverageSkipsOrgsWithoutConsenterCerts(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	// the consenter certificates are not issued by the TLS CAs of the org
+	ordererConf, _ := baseEtcdRaftOrderer(t)
+	ordererConf.Organizations[0].OrdererEndpoints = []string{"orderer.example.org:7050"}
+
+	ordererGroup, err := NewOrdererGroup(ordererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+
+	err = c.Orderer().VerifyEndpointCoverage()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	soloOrdererConf := ordererConf
+	soloOrdererConf.OrdererType = orderer.ConsensusTypeSolo
+	err = c.Orderer().SetConfiguration(soloOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Orderer().VerifyEndpointCoverage()
+	gt.Expect(err).To(MatchError("consensus type solo is not etcdraft"))
+}
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainPolicy returns a step-by-step explanation of how the policy name
+// of the group at path, e.g. "/Channel/Application" and "Admins", is
+// evaluated in the updated config: which sub-policies an ImplicitMeta
+// policy delegates to, how many of them must be satisfied, and which
+// organizations and roles must sign to satisfy each signature policy.
+func (c *ConfigTx) ExplainPolicy(path, name string) (string, error) {
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("path %s must be absolute", path)
+	}
+
+	resolved, err := resolvePolicyReference(c.updated.ChannelGroup, strings.TrimSuffix(path, "/"), name)
+	if err != nil {
+		return "", err
+	}
+
+	var explanation strings.Builder
+	explainResolvedPolicy(&explanation, resolved, "", 0)
+
+	return explanation.String(), nil
+}
+
+// explainResolvedPolicy writes the explanation of the resolved policy,
+// numbered with step, followed by the explanations of its sub-policies.
+func explainResolvedPolicy(w *strings.Builder, resolved ResolvedPolicy, step string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if step != "" {
+		step += ". "
+	}
+	noteIndent := indent + strings.Repeat(" ", len(step))
+
+	switch resolved.Policy.Type {
+	case ImplicitMetaPolicyType:
+		rule := strings.Fields(resolved.Policy.Rule)
+		subPolicy := rule[len(rule)-1]
+		groupPath := resolved.Path[:strings.LastIndex(resolved.Path, "/")]
+
+		fmt.Fprintf(w, "%s%s%s is the ImplicitMeta policy %q: it is satisfied when %d of the %s policies of the sub-groups of %s %s satisfied.\n",
+			indent, step, resolved.Path, resolved.Policy.Rule, resolved.Threshold, subPolicy, groupPath, pluralVerb(resolved.Threshold))
+
+		switch {
+		case len(resolved.SubPolicies) == 0:
+			fmt.Fprintf(w, "%sNo sub-group defines the %s policy, so this policy can never be satisfied.\n", noteIndent, subPolicy)
+		case len(resolved.SubPolicies) < resolved.Threshold:
+			fmt.Fprintf(w, "%sToo few sub-groups define the %s policy (%d of %d required), so this policy can never be satisfied.\n", noteIndent, subPolicy, len(resolved.SubPolicies), resolved.Threshold)
+		}
+
+		for i, sub := range resolved.SubPolicies {
+			explainResolvedPolicy(w, sub, fmt.Sprintf("%s%d", strings.TrimSuffix(step, " "), i+1), depth+1)
+		}
+	case SignaturePolicyType:
+		fmt.Fprintf(w, "%s%s%s is the signature policy %q: it is satisfied by %s.\n",
+			indent, step, resolved.Path, resolved.Policy.Rule, signatureRuleExplanation(resolved))
+
+		for _, principal := range resolved.Principals {
+			if principal.Role == "member" {
+				fmt.Fprintf(w, "%s%s is satisfied by any identity issued by %s.\n", noteIndent, principal, principal.MSPID)
+			}
+		}
+	default:
+		fmt.Fprintf(w, "%s%s%s is a %s policy.\n", indent, step, resolved.Path, resolved.Policy.Type)
+	}
+}
+
+// signatureRuleExplanation describes the signatures that satisfy a
+// signature policy. Rules that combine gates are described by their
+// principals only.
+func signatureRuleExplanation(resolved ResolvedPolicy) string {
+	var principals []string
+	for _, principal := range resolved.Principals {
+		principals = append(principals, principal.String())
+	}
+	list := strings.Join(principals, ", ")
+
+	if len(principals) == 1 {
+		return "a signature of " + list
+	}
+
+	rule := resolved.Policy.Rule
+	open := strings.Index(rule, "(")
+	if open < 0 || strings.Contains(rule[open+1:], "(") {
+		return "signatures of " + list + " combined as the rule requires"
+	}
+
+	switch rule[:open] {
+	case "OR":
+		return "a signature of any one of " + list
+	case "AND":
+		return "signatures of all of " + list
+	case "OUTOF":
+		n := strings.TrimSpace(strings.SplitN(rule[open+1:], ",", 2)[0])
+		return "signatures of " + n + " of " + list
+	default:
+		return "signatures of " + list + " combined as the rule requires"
+	}
+}
+
+// pluralVerb returns the form of "to be" that agrees with n.
+func pluralVerb(n int) string {
+	if n == 1 {
+		return "is"
+	}
+
+	return "are"
+}
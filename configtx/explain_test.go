@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestExplainPolicy(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	for i := range profile.Application.Organizations {
+		org := &profile.Application.Organizations[i]
+		org.MSP.Name = org.Name + "MSP"
+		org.Policies = DefaultOrgPoliciesFor(org.MSP.Name)
+	}
+	profile.Application.Organizations[1].Policies = map[string]Policy{
+		ReadersPolicyKey: {Type: SignaturePolicyType, Rule: "OR('Org2MSP.member')"},
+		WritersPolicyKey: {Type: SignaturePolicyType, Rule: "OR('Org2MSP.admin', 'Org2MSP.client')"},
+		AdminsPolicyKey:  {Type: SignaturePolicyType, Rule: "OutOf(2, 'Org2MSP.admin', 'Org2MSP.peer', 'Org2MSP.client')"},
+	}
+	profile.Application.Policies["AllEndorsement"] = Policy{Type: ImplicitMetaPolicyType, Rule: "ALL Endorsement"}
+	profile.Orderer.Organizations[0].MSP.Name = "OrdererMSP"
+	profile.Orderer.Organizations[0].Policies = defaultOrdererOrgPoliciesFor("OrdererMSP")
+
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	tests := []struct {
+		path        string
+		name        string
+		explanation string
+	}{
+		{
+			path: "/Channel",
+			name: AdminsPolicyKey,
+			explanation: `/Channel/Admins is the ImplicitMeta policy "MAJORITY Admins": it is satisfied when 2 of the Admins policies of the sub-groups of /Channel are satisfied.
+  1. /Channel/Application/Admins is the ImplicitMeta policy "MAJORITY Admins": it is satisfied when 2 of the Admins policies of the sub-groups of /Channel/Application are satisfied.
+    1.1. /Channel/Application/Org1/Admins is the signature policy "AND('Org1MSP.admin')": it is satisfied by a signature of Org1MSP.admin.
+    1.2. /Channel/Application/Org2/Admins is the signature policy "OUTOF(2, 'Org2MSP.admin', 'Org2MSP.peer', 'Org2MSP.client')": it is satisfied by signatures of 2 of Org2MSP.admin, Org2MSP.peer, Org2MSP.client.
+  2. /Channel/Orderer/Admins is the ImplicitMeta policy "MAJORITY Admins": it is satisfied when 1 of the Admins policies of the sub-groups of /Channel/Orderer is satisfied.
+    2.1. /Channel/Orderer/OrdererOrg/Admins is the signature policy "AND('OrdererMSP.admin')": it is satisfied by a signature of OrdererMSP.admin.
+`,
+		},
+		{
+			path: "/Channel/Application/",
+			name: WritersPolicyKey,
+			explanation: `/Channel/Application/Writers is the ImplicitMeta policy "ANY Writers": it is satisfied when 1 of the Writers policies of the sub-groups of /Channel/Application is satisfied.
+  1. /Channel/Application/Org1/Writers is the signature policy "OR('Org1MSP.admin', 'Org1MSP.client')": it is satisfied by a signature of any one of Org1MSP.admin, Org1MSP.client.
+  2. /Channel/Application/Org2/Writers is the signature policy "OR('Org2MSP.admin', 'Org2MSP.client')": it is satisfied by a signature of any one of Org2MSP.admin, Org2MSP.client.
+`,
+		},
+		{
+			path: "/Channel/Application/Org2",
+			name: ReadersPolicyKey,
+			explanation: `/Channel/Application/Org2/Readers is the signature policy "AND('Org2MSP.member')": it is satisfied by a signature of Org2MSP.member.
+Org2MSP.member is satisfied by any identity issued by Org2MSP.
+`,
+		},
+		{
+			path: "/Channel/Application",
+			name: "AllEndorsement",
+			explanation: `/Channel/Application/AllEndorsement is the ImplicitMeta policy "ALL Endorsement": it is satisfied when 2 of the Endorsement policies of the sub-groups of /Channel/Application are satisfied.
+Too few sub-groups define the Endorsement policy (1 of 2 required), so this policy can never be satisfied.
+  1. /Channel/Application/Org1/Endorsement is the signature policy "AND('Org1MSP.peer')": it is satisfied by a signature of Org1MSP.peer.
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		explanation, err := c.ExplainPolicy(tc.path, tc.name)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(explanation).To(Equal(tc.explanation))
+	}
+}
+
+func TestExplainPolicyFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	_, err = c.ExplainPolicy("Channel", AdminsPolicyKey)
+	gt.Expect(err).To(MatchError("path Channel must be absolute"))
+
+	_, err = c.ExplainPolicy("/Channel/Application/Org3", AdminsPolicyKey)
+	gt.Expect(err).To(MatchError("policy /Channel/Application/Org3/Admins does not exist"))
+
+	_, err = c.ExplainPolicy("/Channel/Application", "Missing")
+	gt.Expect(err).To(MatchError("policy /Channel/Application/Missing does not exist"))
+}
@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// WithValueTypes registers the message types of custom config values, e.g.
+// vendor-specific values of the channel and application groups, by mapping
+// their keys to constructors for their messages. The custom values with
+// registered keys are decoded into the Extensions of the channel and
+// application configurations and converged by Apply, so that they
+// round-trip like the values defined by Fabric. Keys of values defined by
+// Fabric are ignored.
+func WithValueTypes(types map[string]func() proto.Message) Option {
+	return func(o *options) {
+		if o.valueTypes == nil {
+			o.valueTypes = map[string]func() proto.Message{}
+		}
+		for key, newMessage := range types {
+			if _, ok := standardValueTypes[key]; !ok {
+				o.valueTypes[key] = newMessage
+			}
+		}
+	}
+}
+
+// valueTypes returns the registered message types of custom config
+// values. It is safe to call on a nil ConfigTx.
+func (c *ConfigTx) valueTypes() map[string]func() proto.Message {
+	if c == nil {
+		return nil
+	}
+
+	return c.options.valueTypes
+}
+
+// SetExtension sets the custom config value with the key in the channel
+// group of the updated config. If the value already exists, it will be
+// overwritten.
+func (c *ChannelGroup) SetExtension(key string, msg proto.Message) error {
+	err := setExtension(c.channelGroup, key, msg)
+	if err != nil {
+		return err
+	}
+
+	c.tx.notify(c.path(), "SetExtension")
+
+	return nil
+}
+
+// RemoveExtension removes the custom config value with the key from the
+// channel group of the updated config.
+func (c *ChannelGroup) RemoveExtension(key string) error {
+	err := removeExtension(c.channelGroup, key)
+	if err != nil {
+		return err
+	}
+
+	c.tx.notify(c.path(), "RemoveExtension")
+
+	return nil
+}
+
+// SetExtension sets the custom config value with the key in the
+// application group of the updated config. If the value already exists, it
+// will be overwritten.
+func (a *ApplicationGroup) SetExtension(key string, msg proto.Message) error {
+	err := setExtension(a.applicationGroup, key, msg)
+	if err != nil {
+		return err
+	}
+
+	a.tx.notify(a.path(), "SetExtension")
+
+	return nil
+}
+
+// RemoveExtension removes the custom config value with the key from the
+// application group of the updated config.
+func (a *ApplicationGroup) RemoveExtension(key string) error {
+	err := removeExtension(a.applicationGroup, key)
+	if err != nil {
+		return err
+	}
+
+	a.tx.notify(a.path(), "RemoveExtension")
+
+	return nil
+}
+
+// setExtension sets the custom config value with the key in the group.
+func setExtension(group *cb.ConfigGroup, key string, msg proto.Message) error {
+	if _, ok := standardValueTypes[key]; ok {
+		return fmt.Errorf("extension %s conflicts with a standard config value", key)
+	}
+
+	if msg == nil {
+		return fmt.Errorf("extension %s has no value", key)
+	}
+
+	return setValue(group, &standardConfigValue{key: key, value: msg}, AdminsPolicyKey)
+}
+
+// removeExtension removes the custom config value with the key from the
+// group.
+func removeExtension(group *cb.ConfigGroup, key string) error {
+	if _, ok := standardValueTypes[key]; ok {
+		return fmt.Errorf("extension %s conflicts with a standard config value", key)
+	}
+
+	delete(group.Values, key)
+
+	return nil
+}
+
+// setExtensions sets the custom config values in the group.
+func setExtensions(group *cb.ConfigGroup, extensions map[string]proto.Message) error {
+	for _, key := range sortedExtensionKeys(extensions) {
+		err := setExtension(group, key, extensions[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getExtensions decodes the custom config values of the group whose keys
+// are registered in types. It returns nil if the group has none.
+func getExtensions(group *cb.ConfigGroup, types map[string]func() proto.Message) (map[string]proto.Message, error) {
+	var extensions map[string]proto.Message
+
+	for key, newMessage := range types {
+		value, ok := group.Values[key]
+		if !ok {
+			continue
+		}
+
+		msg := newMessage()
+		err := proto.Unmarshal(value.Value, msg)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling extension %s: %w", key, err)
+		}
+
+		if extensions == nil {
+			extensions = map[string]proto.Message{}
+		}
+		extensions[key] = msg
+	}
+
+	return extensions, nil
+}
+
+// applyExtensions sets the desired custom config values of the group that
+// differ and removes the registered ones that are not desired.
+func applyExtensions(
+	group *cb.ConfigGroup,
+	desired map[string]proto.Message,
+	types map[string]func() proto.Message,
+	set func(key string, msg proto.Message) error,
+	remove func(key string) error,
+) error {
+	for _, key := range sortedExtensionKeys(desired) {
+		msg := desired[key]
+		if value, ok := group.Values[key]; ok && msg != nil {
+			existing := proto.Clone(msg)
+			existing.Reset()
+			if proto.Unmarshal(value.Value, existing) == nil && proto.Equal(existing, msg) {
+				continue
+			}
+		}
+
+		err := set(key, msg)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(group.Values) {
+		if _, ok := types[key]; !ok {
+			continue
+		}
+		if _, ok := desired[key]; ok {
+			continue
+		}
+
+		err := remove(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedExtensionKeys returns the keys of the extensions in lexical order.
+func sortedExtensionKeys(extensions map[string]proto.Message) []string {
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
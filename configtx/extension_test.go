@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestExtensions(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	valueTypes := WithValueTypes(map[string]func() proto.Message{
+		"VendorChannelConfig":     func() proto.Message { return &wrappers.StringValue{} },
+		"VendorApplicationConfig": func() proto.Message { return &wrappers.UInt64Value{} },
+		// standard keys cannot be registered
+		CapabilitiesKey: func() proto.Message { return &wrappers.StringValue{} },
+	})
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.Extensions = map[string]proto.Message{"VendorChannelConfig": &wrappers.StringValue{Value: "vendor"}}
+	profile.Application.Extensions = map[string]proto.Message{"VendorApplicationConfig": &wrappers.UInt64Value{Value: 42}}
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// without registered types, the custom values are not decoded
+	c := New(config)
+	channel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Extensions).To(BeNil())
+	gt.Expect(channel.Application.Extensions).To(BeNil())
+
+	c = New(config, valueTypes)
+	channel, err = c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Extensions).To(HaveLen(1))
+	gt.Expect(proto.Equal(channel.Extensions["VendorChannelConfig"], &wrappers.StringValue{Value: "vendor"})).To(BeTrue())
+	gt.Expect(channel.Application.Extensions).To(HaveLen(1))
+	gt.Expect(proto.Equal(channel.Application.Extensions["VendorApplicationConfig"], &wrappers.UInt64Value{Value: 42})).To(BeTrue())
+
+	// applying the decoded configuration leaves the custom values untouched
+	err = c.Apply(channel)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).To(MatchError(ContainSubstring("no differences detected")))
+
+	channel.Extensions["VendorChannelConfig"] = &wrappers.StringValue{Value: "updated"}
+	channel.Application.Extensions = nil
+	err = c.Apply(channel)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	updated, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(updated.Extensions["VendorChannelConfig"], &wrappers.StringValue{Value: "updated"})).To(BeTrue())
+	gt.Expect(updated.Application.Extensions).To(BeNil())
+
+	err = c.Application().SetExtension("VendorApplicationConfig", &wrappers.UInt64Value{Value: 7})
+	gt.Expect(err).NotTo(HaveOccurred())
+	application, err := c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(application.Extensions["VendorApplicationConfig"], &wrappers.UInt64Value{Value: 7})).To(BeTrue())
+
+	err = c.Channel().RemoveExtension("VendorChannelConfig")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.Channel().channelGroup.Values).NotTo(HaveKey("VendorChannelConfig"))
+}
+
+func TestExtensionsFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				"VendorChannelConfig": {Value: []byte("invalid")},
+			},
+		},
+	}, WithValueTypes(map[string]func() proto.Message{
+		"VendorChannelConfig": func() proto.Message { return &wrappers.StringValue{} },
+	}))
+
+	_, err := c.Channel().Configuration()
+	gt.Expect(err).To(MatchError(HavePrefix("retrieving channel extensions: unmarshaling extension VendorChannelConfig: ")))
+
+	err = c.Channel().SetExtension(CapabilitiesKey, &wrappers.StringValue{})
+	gt.Expect(err).To(MatchError("extension Capabilities conflicts with a standard config value"))
+
+	err = c.Channel().RemoveExtension(ConsortiumKey)
+	gt.Expect(err).To(MatchError("extension Consortium conflicts with a standard config value"))
+
+	err = c.Channel().SetExtension("VendorChannelConfig", nil)
+	gt.Expect(err).To(MatchError("extension VendorChannelConfig has no value"))
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.Application.Extensions = map[string]proto.Message{ACLsKey: &wrappers.StringValue{}}
+	_, err = NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).To(MatchError(ContainSubstring("extension ACLs conflicts with a standard config value")))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ConfigFingerprint returns a hex encoded SHA-256 hash of the content of
+// the channel config, e.g. to record the fingerprint of an approved config
+// and detect when the live config of the channel drifts from it. The hash
+// is computed over a canonical form of the config: map entries are sorted,
+// the standard values and the signature and implicit meta policies are
+// re-marshaled deterministically, and the sequence and the versions of the
+// elements are ignored, as they record the history of the config rather
+// than its content. Configs with the same content, e.g. a config edited
+// with a ConfigTx and the config committed by the resulting update, have
+// the same fingerprint.
+func ConfigFingerprint(config *cb.Config) string {
+	channelGroup := newConfigGroup()
+	if config.GetChannelGroup() != nil {
+		channelGroup = proto.Clone(config.ChannelGroup).(*cb.ConfigGroup)
+	}
+
+	canonicalizeGroup(channelGroup)
+
+	data, err := marshalDeterministically(channelGroup)
+	if err != nil {
+		// the canonical group holds the messages of a valid config,
+		// so it always marshals
+		panic(err)
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// canonicalizeGroup clears the versions of the group and its members and
+// re-marshals the values and policies it can decode.
+func canonicalizeGroup(group *cb.ConfigGroup) {
+	group.Version = 0
+
+	for key, value := range group.Values {
+		value.Version = 0
+		if newMessage, ok := standardValueTypes[key]; ok {
+			value.Value = remarshal(value.Value, newMessage())
+		}
+	}
+
+	for _, policy := range group.Policies {
+		policy.Version = 0
+		if policy.Policy == nil {
+			continue
+		}
+
+		switch cb.Policy_PolicyType(policy.Policy.Type) {
+		case cb.Policy_SIGNATURE:
+			policy.Policy.Value = remarshal(policy.Policy.Value, &cb.SignaturePolicyEnvelope{})
+		case cb.Policy_IMPLICIT_META:
+			policy.Policy.Value = remarshal(policy.Policy.Value, &cb.ImplicitMetaPolicy{})
+		}
+	}
+
+	for _, subGroup := range group.Groups {
+		canonicalizeGroup(subGroup)
+	}
+}
+
+// remarshal unmarshals data into msg and marshals it deterministically,
+// or returns data unchanged if it is not a valid msg.
+func remarshal(data []byte, msg proto.Message) []byte {
+	err := proto.Unmarshal(data, msg)
+	if err != nil {
+		return data
+	}
+
+	canonical, err := marshalDeterministically(msg)
+	if err != nil {
+		return data
+	}
+
+	return canonical
+}
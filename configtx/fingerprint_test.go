@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigFingerprint(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	fingerprint := ConfigFingerprint(config)
+	gt.Expect(fingerprint).To(MatchRegexp("^[0-9a-f]{64}$"))
+	gt.Expect(ConfigFingerprint(proto.Clone(config).(*cb.Config))).To(Equal(fingerprint))
+
+	// the sequence and versions do not affect the fingerprint
+	committed := proto.Clone(config).(*cb.Config)
+	committed.Sequence = 5
+	committed.ChannelGroup.Version = 2
+	committed.ChannelGroup.Groups[ApplicationGroupKey].Values[ACLsKey].Version = 3
+	gt.Expect(ConfigFingerprint(committed)).To(Equal(fingerprint))
+
+	// neither does the order of the map entries in the marshaled values
+	acls := &pb.ACLs{Acls: map[string]*pb.APIResource{
+		"acl1": {PolicyRef: "hi"},
+		"acl2": {PolicyRef: "Writers"},
+	}}
+	ordered := proto.Clone(config).(*cb.Config)
+	ordered.ChannelGroup.Groups[ApplicationGroupKey].Values[ACLsKey].Value = protoMarshal(t, acls)
+	unordered := proto.Clone(config).(*cb.Config)
+	unordered.ChannelGroup.Groups[ApplicationGroupKey].Values[ACLsKey].Value = concatenateBytes(
+		protoMarshal(t, &pb.ACLs{Acls: map[string]*pb.APIResource{"acl2": acls.Acls["acl2"]}}),
+		protoMarshal(t, &pb.ACLs{Acls: map[string]*pb.APIResource{"acl1": acls.Acls["acl1"]}}),
+	)
+	gt.Expect(ConfigFingerprint(unordered)).To(Equal(ConfigFingerprint(ordered)))
+
+	// changes of the content do
+	gt.Expect(ConfigFingerprint(ordered)).NotTo(Equal(fingerprint))
+
+	changed := proto.Clone(config).(*cb.Config)
+	changed.ChannelGroup.Groups[ApplicationGroupKey].ModPolicy = ReadersPolicyKey
+	gt.Expect(ConfigFingerprint(changed)).NotTo(Equal(fingerprint))
+
+	gt.Expect(ConfigFingerprint(nil)).To(Equal(ConfigFingerprint(&cb.Config{})))
+}
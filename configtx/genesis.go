@@ -0,0 +1,291 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Field numbers of the messages the genesis block is encoded from.
+const (
+	blockHeaderField     = 1
+	blockDataField       = 2
+	blockMetadataField   = 3
+	blockDataDataField   = 1
+	envelopePayloadField = 1
+	payloadHeaderField   = 1
+	payloadDataField     = 2
+)
+
+// WriteGenesisBlock writes a genesis block using the provided channel
+// configuration to w and returns the size of the block in bytes. A system
+// channel genesis block is written if the configuration defines consortiums,
+// otherwise an application channel genesis block is written.
+//
+// The block is identical to the marshaled output of
+// NewSystemChannelGenesisBlock or NewApplicationChannelGenesisBlock, but the
+// channel config is marshaled only once and the block is streamed to w
+// instead of being assembled in memory, which avoids holding several copies
+// of very large configs.
+func WriteGenesisBlock(w io.Writer, channelConfig Channel, channelID string, opts ...Option) (int64, error) {
+	o := newOptions(opts...)
+
+	var (
+		channelGroup *cb.ConfigGroup
+		channelType  string
+		err          error
+	)
+
+	if len(channelConfig.Consortiums) > 0 {
+		channelType = "system"
+		channelGroup, channelConfig, err = newSystemChannelGenesisGroup(channelConfig, channelID, o)
+	} else {
+		channelType = "application"
+		channelGroup, channelConfig, err = newApplicationChannelGenesisGroup(channelConfig, channelID, o)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := writeGenesisBlock(w, channelGroup, channelID, o)
+	if err != nil {
+		return n, fmt.Errorf("writing %s channel genesis block: %w", channelType, err)
+	}
+
+	o.emitWarnings(channelWarnings(channelConfig, true))
+
+	return n, nil
+}
+
+// GenerateGenesisBlocks creates the genesis blocks of many channels at once,
+// e.g. to provision a test network, and returns them by channel ID. As with
+// WriteGenesisBlock, a system channel genesis block is created for a
+// configuration that defines consortiums and an application channel genesis
+// block otherwise.
+//
+// The channel groups are built concurrently and an MSP shared by several
+// channels is converted once. The blocks are then assembled in the order of
+// the channel IDs, so that the nonces drawn from WithRandomness and the
+// timestamps of WithClock, as well as the warnings passed to the warning
+// handler, do not depend on scheduling. If any channel fails, the error of
+// the first of them in that order is returned.
+func GenerateGenesisBlocks(configs map[string]Channel, opts ...Option) (map[string]*cb.Block, error) {
+	o := newOptions(opts...)
+	o.mspConfigs = &mspConfigCache{}
+
+	channelIDs := make([]string, 0, len(configs))
+	for channelID := range configs {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	type channelGroup struct {
+		group   *cb.ConfigGroup
+		config  Channel
+		genesis func(*cb.ConfigGroup, string, options) (*cb.Block, error)
+		err     error
+	}
+
+	groups := make([]channelGroup, len(channelIDs))
+	var wg sync.WaitGroup
+	for i := range channelIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			channelID := channelIDs[i]
+			channelConfig := configs[channelID]
+			g := &groups[i]
+			if len(channelConfig.Consortiums) > 0 {
+				g.group, g.config, g.err = newSystemChannelGenesisGroup(channelConfig, channelID, o)
+			} else {
+				g.group, g.config, g.err = newApplicationChannelGenesisGroup(channelConfig, channelID, o)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	blocks := make(map[string]*cb.Block, len(channelIDs))
+	for i, channelID := range channelIDs {
+		g := groups[i]
+		if g.err != nil {
+			return nil, fmt.Errorf("channel %s: %w", channelID, g.err)
+		}
+
+		block, err := newGenesisBlock(g.group, channelID, o)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: creating genesis block: %w", channelID, err)
+		}
+		blocks[channelID] = block
+	}
+
+	for i := range channelIDs {
+		o.emitWarnings(channelWarnings(groups[i].config, true))
+	}
+
+	return blocks, nil
+}
+
+// mspConfigCache shares the marshaled configs of MSPs between the
+// organizations of channels built concurrently, so that an MSP used by
+// several channels is converted once. A nil cache converts every MSP.
+type mspConfigCache struct {
+	mutex   sync.Mutex
+	entries map[string][]mspConfigEntry
+}
+
+// mspConfigEntry is a converted MSP and its marshaled config.
+type mspConfigEntry struct {
+	msp  MSP
+	conf []byte
+}
+
+// marshaledMSPConfig returns the marshaled FabricMSPConfig of the MSP. The
+// returned config is shared and must not be modified.
+func (c *mspConfigCache) marshaledMSPConfig(msp MSP) ([]byte, error) {
+	if c == nil {
+		return marshalMSPConfig(msp)
+	}
+
+	if conf, ok := c.lookup(msp); ok {
+		return conf, nil
+	}
+
+	conf, err := marshalMSPConfig(msp)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string][]mspConfigEntry{}
+	}
+	c.entries[msp.Name] = append(c.entries[msp.Name], mspConfigEntry{msp: msp, conf: conf})
+
+	return conf, nil
+}
+
+// lookup returns the marshaled config of an MSP equal to msp, if any.
+// Organizations built from the same MSP share its certificates, so the
+// comparison rarely has to descend into them.
+func (c *mspConfigCache) lookup(msp MSP) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, entry := range c.entries[msp.Name] {
+		if reflect.DeepEqual(entry.msp, msp) {
+			return entry.conf, true
+		}
+	}
+
+	return nil, false
+}
+
+// marshalMSPConfig converts the MSP to a FabricMSPConfig and marshals it.
+func marshalMSPConfig(msp MSP) ([]byte, error) {
+	fabricMSPConfig, err := msp.toProto()
+	if err != nil {
+		return nil, fmt.Errorf("converting fabric msp config to proto: %w", err)
+	}
+
+	conf, err := proto.Marshal(fabricMSPConfig)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling msp config: %w", err)
+	}
+
+	return conf, nil
+}
+
+// writeGenesisBlock writes the protobuf encoding of the genesis block of
+// the config group to w. Only the config envelope is marshaled in full;
+// the envelope, payload and block that wrap it are written as field
+// prefixes around it.
+func writeGenesisBlock(w io.Writer, cg *cb.ConfigGroup, channelID string, o options) (int64, error) {
+	payloadHeader, err := genesisPayloadHeader(channelID, o)
+	if err != nil {
+		return 0, err
+	}
+	marshaledPayloadHeader, err := proto.Marshal(payloadHeader)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling payload header: %w", err)
+	}
+	payloadData, err := marshalDeterministically(&cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: cg}})
+	if err != nil {
+		return 0, fmt.Errorf("marshaling payload data: %w", err)
+	}
+
+	payloadPrefix := append(lengthDelimitedField(payloadHeaderField, marshaledPayloadHeader), fieldPrefix(payloadDataField, len(payloadData))...)
+	payloadLen := len(payloadPrefix) + len(payloadData)
+	envelopePrefix := fieldPrefix(envelopePayloadField, payloadLen)
+	envelopeLen := len(envelopePrefix) + payloadLen
+
+	block := newBlock(0, nil)
+	hasher := sha256.New()
+	hasher.Write(envelopePrefix)
+	hasher.Write(payloadPrefix)
+	hasher.Write(payloadData)
+	block.Header.DataHash = hasher.Sum(nil)
+
+	err = setGenesisBlockMetadata(block)
+	if err != nil {
+		return 0, err
+	}
+
+	marshaledBlockHeader, err := proto.Marshal(block.Header)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling block header: %w", err)
+	}
+	marshaledBlockMetadata, err := proto.Marshal(block.Metadata)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling block metadata: %w", err)
+	}
+
+	blockDataPrefix := fieldPrefix(blockDataDataField, envelopeLen)
+	blockDataLen := len(blockDataPrefix) + envelopeLen
+
+	var n int64
+	for _, chunk := range [][]byte{
+		lengthDelimitedField(blockHeaderField, marshaledBlockHeader),
+		fieldPrefix(blockDataField, blockDataLen),
+		blockDataPrefix,
+		envelopePrefix,
+		payloadPrefix,
+		payloadData,
+		lengthDelimitedField(blockMetadataField, marshaledBlockMetadata),
+	} {
+		written, err := w.Write(chunk)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// fieldPrefix returns the key and length of a length-delimited protobuf
+// field.
+func fieldPrefix(field, length int) []byte {
+	prefix := proto.EncodeVarint(uint64(field<<3 | proto.WireBytes))
+	return append(prefix, proto.EncodeVarint(uint64(length))...)
+}
+
+// lengthDelimitedField returns the encoding of a length-delimited protobuf
+// field.
+func lengthDelimitedField(field int, value []byte) []byte {
+	return append(fieldPrefix(field, len(value)), value...)
+}
@@ -0,0 +1,317 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestWriteGenesisBlock(t *testing.T) {
+	t.Parallel()
+
+	applicationProfile, _, _ := baseApplicationChannelProfile(t)
+	systemProfile, _, _ := baseSystemChannelProfile(t)
+
+	tests := []struct {
+		name      string
+		profile   Channel
+		newBlock  func(Channel, string, ...Option) (*cb.Block, error)
+		channelID string
+	}{
+		{
+			name:      "application channel",
+			profile:   applicationProfile,
+			newBlock:  NewApplicationChannelGenesisBlock,
+			channelID: "testchannel",
+		},
+		{
+			name:      "system channel",
+			profile:   systemProfile,
+			newBlock:  NewSystemChannelGenesisBlock,
+			channelID: "testsystemchannel",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			buf := &bytes.Buffer{}
+			n, err := WriteGenesisBlock(buf, tc.profile, tc.channelID)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(n).To(Equal(int64(buf.Len())))
+
+			block := &cb.Block{}
+			err = proto.Unmarshal(buf.Bytes(), block)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			// the written block is encoded exactly as proto.Marshal would
+			marshaledBlock, err := proto.Marshal(block)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(marshaledBlock).To(Equal(buf.Bytes()))
+
+			gt.Expect(block.Header.Number).To(Equal(uint64(0)))
+			gt.Expect(block.Header.DataHash).To(Equal(blockDataHash(block.Data)))
+
+			expectedBlock, err := tc.newBlock(tc.profile, tc.channelID)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(block.Metadata, expectedBlock.Metadata)).To(BeTrue())
+
+			config, err := ConfigFromBlock(block)
+			gt.Expect(err).NotTo(HaveOccurred())
+			expectedConfig, err := ConfigFromBlock(expectedBlock)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(config, expectedConfig)).To(BeTrue())
+
+			envelope := &cb.Envelope{}
+			err = proto.Unmarshal(block.Data.Data[0], envelope)
+			gt.Expect(err).NotTo(HaveOccurred())
+			payload := &cb.Payload{}
+			err = proto.Unmarshal(envelope.Payload, payload)
+			gt.Expect(err).NotTo(HaveOccurred())
+			channelHeader := &cb.ChannelHeader{}
+			err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(channelHeader.ChannelId).To(Equal(tc.channelID))
+			gt.Expect(channelHeader.Type).To(Equal(int32(cb.HeaderType_CONFIG)))
+		})
+	}
+}
+
+func TestWriteGenesisBlockFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+
+	_, err := WriteGenesisBlock(&bytes.Buffer{}, profile, "")
+	gt.Expect(err).To(MatchError("application channel ID is required"))
+
+	n, err := WriteGenesisBlock(&failingWriter{limit: 10}, profile, "testchannel")
+	gt.Expect(err).To(MatchError("writing application channel genesis block: write failed"))
+	gt.Expect(n).To(BeNumerically("<=", 10))
+}
+
+// failingWriter fails once more than limit bytes have been written.
+type failingWriter struct {
+	limit   int
+	written int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.limit {
+		n := w.limit - w.written
+		w.written = w.limit
+		return n, errors.New("write failed")
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+func TestGenesisBlockRandomness(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	nonce := bytes.Repeat([]byte{7}, 24)
+
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel", WithRandomness(bytes.NewReader(nonce)))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	buf := &bytes.Buffer{}
+	_, err = WriteGenesisBlock(buf, profile, "testchannel", WithRandomness(bytes.NewReader(nonce)))
+	gt.Expect(err).NotTo(HaveOccurred())
+	writtenBlock := &cb.Block{}
+	err = proto.Unmarshal(buf.Bytes(), writtenBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for _, block := range []*cb.Block{block, writtenBlock} {
+		envelope := &cb.Envelope{}
+		err = proto.Unmarshal(block.Data.Data[0], envelope)
+		gt.Expect(err).NotTo(HaveOccurred())
+		payload := &cb.Payload{}
+		err = proto.Unmarshal(envelope.Payload, payload)
+		gt.Expect(err).NotTo(HaveOccurred())
+		signatureHeader := &cb.SignatureHeader{}
+		err = proto.Unmarshal(payload.Header.SignatureHeader, signatureHeader)
+		gt.Expect(err).NotTo(HaveOccurred())
+		channelHeader := &cb.ChannelHeader{}
+		err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		gt.Expect(signatureHeader.Nonce).To(Equal(nonce))
+		gt.Expect(channelHeader.TxId).To(Equal(computeTxID(nonce, nil)))
+	}
+
+	_, err = NewApplicationChannelGenesisBlock(profile, "testchannel", WithRandomness(bytes.NewReader(nil)))
+	gt.Expect(err).To(MatchError("creating application channel genesis block: creating nonce: failed to get random bytes: EOF"))
+}
+
+func TestGenesisBlockClock(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	nonce := bytes.Repeat([]byte{7}, 24)
+
+	opts := func() []Option {
+		return []Option{WithClock(fixedClock(now)), WithRandomness(bytes.NewReader(nonce))}
+	}
+
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel", opts()...)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(block.Data.Data[0], envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelHeader := &cb.ChannelHeader{}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelHeader.Timestamp.Seconds).To(Equal(now.Unix()))
+
+	// blocks built with the same clock and randomness are identical
+	marshaledBlock, err := proto.Marshal(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	sameBlock, err := NewApplicationChannelGenesisBlock(profile, "testchannel", opts()...)
+	gt.Expect(err).NotTo(HaveOccurred())
+	marshaledSameBlock, err := proto.Marshal(sameBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaledSameBlock).To(Equal(marshaledBlock))
+
+	buf := &bytes.Buffer{}
+	_, err = WriteGenesisBlock(buf, profile, "testchannel", opts()...)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(buf.Bytes()).To(Equal(marshaledBlock))
+}
+
+func TestClockFunc(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	o := newOptions(WithClock(ClockFunc(func() time.Time { return now })))
+	gt.Expect(o.now()).To(Equal(now))
+}
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestGenerateGenesisBlocks(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	applicationProfile, _, _ := baseApplicationChannelProfile(t)
+	systemProfile, _, _ := baseSystemChannelProfile(t)
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	nonce := bytes.Repeat([]byte{7}, 24)
+
+	configs := map[string]Channel{
+		"channel1":      applicationProfile,
+		"channel2":      applicationProfile,
+		"channel3":      applicationProfile,
+		"systemchannel": systemProfile,
+	}
+
+	var warnings []Warning
+	blocks, err := GenerateGenesisBlocks(configs,
+		WithClock(fixedClock(now)),
+		WithRandomness(bytes.NewReader(bytes.Repeat(nonce, len(configs)))),
+		WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }),
+	)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(blocks).To(HaveLen(len(configs)))
+
+	for channelID, profile := range configs {
+		newBlock := NewApplicationChannelGenesisBlock
+		if channelID == "systemchannel" {
+			newBlock = NewSystemChannelGenesisBlock
+		}
+		expectedBlock, err := newBlock(profile, channelID, WithClock(fixedClock(now)), WithRandomness(bytes.NewReader(nonce)))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(blocks[channelID], expectedBlock)).To(BeTrue(), "block of %s", channelID)
+	}
+
+	var expectedWarnings []Warning
+	for _, channelID := range []string{"channel1", "channel2", "channel3", "systemchannel"} {
+		expectedWarnings = append(expectedWarnings, channelWarnings(configs[channelID], true)...)
+	}
+	gt.Expect(warnings).To(Equal(expectedWarnings))
+}
+
+func TestGenerateGenesisBlocksFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	invalidProfile := profile
+	invalidProfile.Capabilities = nil
+
+	blocks, err := GenerateGenesisBlocks(map[string]Channel{
+		"channel1": profile,
+		"channel2": invalidProfile,
+		"channel3": invalidProfile,
+	})
+	gt.Expect(err).To(MatchError("channel channel2: creating application channel group: capabilities is not defined in channel config"))
+	gt.Expect(blocks).To(BeNil())
+
+	_, err = GenerateGenesisBlocks(map[string]Channel{"": profile})
+	gt.Expect(err).To(MatchError("channel : application channel ID is required"))
+}
+
+func TestMSPConfigCache(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	msp := profile.Application.Organizations[0].MSP
+	otherMSP := msp
+	otherMSP.Admins = nil
+
+	c := &mspConfigCache{}
+
+	conf, err := c.marshaledMSPConfig(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+	expectedConf, err := marshalMSPConfig(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(conf).To(Equal(expectedConf))
+
+	sameConf, err := c.marshaledMSPConfig(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(&sameConf[0]).To(BeIdenticalTo(&conf[0]))
+
+	// an MSP with the same name but different content is converted again
+	otherConf, err := c.marshaledMSPConfig(otherMSP)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(otherConf).NotTo(Equal(conf))
+	gt.Expect(c.entries[msp.Name]).To(HaveLen(2))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// WithPathGuard restricts the config paths that a ConfigTx may modify to
+// those matching one of the patterns, e.g. so that a self-service portal
+// shared by several organizations only lets each of them edit its own
+// subtree. A pattern is a config path whose elements may be * to match
+// any single element, and whose last element may be ** to match the path
+// and everything below it, e.g. /Channel/Application/Org1/** or
+// /Channel/Application/*/Values/AnchorPeers. Computing a config update fails if
+// a group, value or policy outside of the permitted paths was added,
+// removed or modified, whether through the typed API, a transformer or
+// the raw updated config.
+func WithPathGuard(patterns ...string) Option {
+	return func(o *options) {
+		o.pathGuard = append(o.pathGuard, patterns...)
+	}
+}
+
+// checkPathGuard returns the elements that differ between the original
+// and updated config but are not permitted by the path guard as
+// ValidationErrors. It returns nil when no path guard is configured.
+func (o options) checkPathGuard(original, updated *cb.Config) error {
+	if len(o.pathGuard) == 0 {
+		return nil
+	}
+
+	patterns := make([][]string, 0, len(o.pathGuard))
+	for _, pattern := range o.pathGuard {
+		elements, err := parsePathPattern(pattern)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, elements)
+	}
+
+	var findings ValidationErrors
+	channelPath := configPath(ChannelGroupKey)
+	for _, difference := range diffGroups(channelPath, original.GetChannelGroup(), updated.GetChannelGroup()) {
+		if !matchesPathPatterns(patterns, difference.Path) {
+			findings = append(findings, fmt.Errorf("%s %s was %s outside of the permitted paths", difference.Element, difference.Path, difference.Change))
+		}
+	}
+
+	return findings.err()
+}
+
+// parsePathPattern splits a path guard pattern into its elements.
+func parsePathPattern(pattern string) ([]string, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("invalid path guard pattern '%s': must be an absolute config path", pattern)
+	}
+
+	elements := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	for i, element := range elements {
+		if element == "" {
+			return nil, fmt.Errorf("invalid path guard pattern '%s': empty path element", pattern)
+		}
+		if element == "**" && i != len(elements)-1 {
+			return nil, fmt.Errorf("invalid path guard pattern '%s': ** must be the last path element", pattern)
+		}
+	}
+
+	return elements, nil
+}
+
+// matchesPathPatterns reports whether the config path matches one of the
+// parsed patterns.
+func matchesPathPatterns(patterns [][]string, path string) bool {
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+
+	for _, pattern := range patterns {
+		if matchesPathPattern(pattern, elements) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPathPattern reports whether the path elements match the pattern
+// elements.
+func matchesPathPattern(pattern, elements []string) bool {
+	for i, patternElement := range pattern {
+		if patternElement == "**" {
+			return true
+		}
+		if i >= len(elements) || (patternElement != "*" && patternElement != elements[i]) {
+			return false
+		}
+	}
+
+	return len(pattern) == len(elements)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestPathGuard(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{ChannelGroup: channelGroup}, WithPathGuard("/Channel/Application/Org1/**"))
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org1").RemovePolicy(EndorsementPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.SplitUpdate("testchannel", 1<<20)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.OrgUpdate("testchannel", "/Channel/Application/Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.DryRun(func(c *ConfigTx) error {
+		return c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer1.org1.example.com", Port: 7051})
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c = New(&cb.Config{ChannelGroup: channelGroup}, WithPathGuard("/Channel/Application/Org1/**"))
+
+	err = c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2.example.com", Port: 9051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Policies[AdminsPolicyKey].ModPolicy = "Writers"
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).To(MatchError("config update not permitted: " +
+		"policy /Channel/Application/Policies/Admins was modified outside of the permitted paths; " +
+		"value /Channel/Application/Org2/Values/AnchorPeers was added outside of the permitted paths"))
+
+	_, err = c.DryRun(func(c *ConfigTx) error { return nil })
+	gt.Expect(err).To(MatchError(HavePrefix("config update not permitted: ")))
+	_, err = c.SplitUpdate("testchannel", 1<<20)
+	gt.Expect(err).To(MatchError(HavePrefix("config update not permitted: ")))
+	_, err = c.OrgUpdate("testchannel", "/Channel/Application/Org2")
+	gt.Expect(err).To(MatchError(HavePrefix("config update not permitted: ")))
+
+	// changes outside of the organization of an org update are checked too
+	c = New(&cb.Config{ChannelGroup: channelGroup}, WithPathGuard("/Channel/Application/Org1/**"))
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Channel().AddCapability("V3_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.OrgUpdate("testchannel", "/Channel/Application/Org1")
+	gt.Expect(err).To(MatchError(HavePrefix("config update not permitted: ")))
+	_, err = c.SplitUpdate("testchannel", 1<<20)
+	gt.Expect(err).To(MatchError(HavePrefix("config update not permitted: ")))
+}
+
+func TestPathGuardPatterns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{pattern: "/Channel/Application/Org1/**", path: "/Channel/Application/Org1", matches: true},
+		{pattern: "/Channel/Application/Org1/**", path: "/Channel/Application/Org1/Values/MSP", matches: true},
+		{pattern: "/Channel/Application/Org1/**", path: "/Channel/Application/Org10", matches: false},
+		{pattern: "/Channel/Application/Org1/**", path: "/Channel/Application", matches: false},
+		{pattern: "/Channel/Application/*/Values/AnchorPeers", path: "/Channel/Application/Org2/Values/AnchorPeers", matches: true},
+		{pattern: "/Channel/Application/*/Values/AnchorPeers", path: "/Channel/Application/Org2/Values/MSP", matches: false},
+		{pattern: "/Channel/Application/*", path: "/Channel/Application/Org2/Values/MSP", matches: false},
+		{pattern: "/Channel/Orderer/Values/BatchSize", path: "/Channel/Orderer/Values/BatchSize", matches: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			pattern, err := parsePathPattern(tc.pattern)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(matchesPathPatterns([][]string{pattern}, tc.path)).To(Equal(tc.matches))
+		})
+	}
+}
+
+func TestPathGuardInvalidPatterns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern     string
+		expectedErr string
+	}{
+		{
+			pattern:     "Channel/Application",
+			expectedErr: "invalid path guard pattern 'Channel/Application': must be an absolute config path",
+		},
+		{
+			pattern:     "/Channel//Application",
+			expectedErr: "invalid path guard pattern '/Channel//Application': empty path element",
+		},
+		{
+			pattern:     "/Channel/**/MSP",
+			expectedErr: "invalid path guard pattern '/Channel/**/MSP': ** must be the last path element",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.pattern, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := New(&cb.Config{ChannelGroup: newConfigGroup()}, WithPathGuard(tc.pattern))
+			err := c.Channel().AddCapability("V2_0")
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			_, err = c.ComputeMarshaledUpdate("testchannel")
+			gt.Expect(err).To(MatchError("config update not permitted: " + tc.expectedErr))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Group identifies a config group that contains organizations.
+type Group struct {
+	// path is the path of the group relative to the channel group.
+	path []string
+}
+
+// The groups that contain organizations.
+var (
+	// GroupApplication contains the application organizations.
+	GroupApplication = Group{path: []string{ApplicationGroupKey}}
+	// GroupOrderer contains the orderer organizations.
+	GroupOrderer = Group{path: []string{OrdererGroupKey}}
+)
+
+// GroupConsortium returns the group of the consortium with the name, which
+// contains the organizations of the consortium.
+func GroupConsortium(name string) Group {
+	return Group{path: []string{ConsortiumsGroupKey, name}}
+}
+
+// orgValueKeys returns the keys of the values an organization of the group
+// can hold.
+func (g Group) orgValueKeys() map[string]bool {
+	switch g.path[0] {
+	case ApplicationGroupKey:
+		return map[string]bool{MSPKey: true, AnchorPeersKey: true}
+	case OrdererGroupKey:
+		return map[string]bool{MSPKey: true, EndpointsKey: true}
+	default:
+		return map[string]bool{MSPKey: true}
+	}
+}
+
+// ImportOrgFromConfig copies the organization named orgName from src, e.g.
+// the fetched config of another channel the organization is a member of,
+// into the dest group of the updated config, replacing the organization if
+// it already exists. The MSP, policies and mod policies are copied as they
+// are encoded in src, so that the organization is defined identically in
+// both channels. Values that organizations of dest cannot hold, e.g. the
+// anchor peers of an application organization imported into the orderer,
+// are left out.
+//
+// The organization is looked up in the group of src at the same path as
+// dest first, then in the application, orderer and consortium groups.
+func (c *ConfigTx) ImportOrgFromConfig(src *cb.Config, orgName string, dest Group) error {
+	if src == nil || src.ChannelGroup == nil {
+		return errors.New("source config is required")
+	}
+	if len(dest.path) == 0 {
+		return errors.New("destination group is required")
+	}
+
+	srcOrg, err := findOrgGroup(src.ChannelGroup, orgName, dest)
+	if err != nil {
+		return err
+	}
+
+	destGroup, err := configGroupAtPath(c.updated.ChannelGroup, dest.path)
+	if err != nil {
+		return err
+	}
+
+	orgGroup := proto.Clone(srcOrg).(*cb.ConfigGroup)
+	valueKeys := dest.orgValueKeys()
+	for key := range orgGroup.Values {
+		if !valueKeys[key] {
+			delete(orgGroup.Values, key)
+		}
+	}
+	// versions are assigned when the update is computed
+	resetVersions(orgGroup)
+
+	destGroup.Groups[orgName] = orgGroup
+
+	c.notify(configPath(append([]string{ChannelGroupKey}, dest.path...)...), "ImportOrgFromConfig")
+
+	return nil
+}
+
+// findOrgGroup returns the group of the organization named orgName in the
+// channel group, looking in the group at the path of dest first.
+func findOrgGroup(channelGroup *cb.ConfigGroup, orgName string, dest Group) (*cb.ConfigGroup, error) {
+	candidates := [][]string{dest.path, GroupApplication.path, GroupOrderer.path}
+	if consortiums, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		for _, name := range sortedKeys(consortiums.Groups) {
+			candidates = append(candidates, GroupConsortium(name).path)
+		}
+	}
+
+	for _, path := range candidates {
+		group, _ := configGroupAtPath(channelGroup, path)
+		if orgGroup, ok := group.GetGroups()[orgName]; ok {
+			return orgGroup, nil
+		}
+	}
+
+	return nil, fmt.Errorf("org %s does not exist in source config", orgName)
+}
+
+// resetVersions sets the versions of the group and of its members to zero.
+func resetVersions(group *cb.ConfigGroup) {
+	group.Version = 0
+
+	for _, value := range group.Values {
+		value.Version = 0
+	}
+
+	for _, policy := range group.Policies {
+		policy.Version = 0
+	}
+
+	for _, subGroup := range group.Groups {
+		resetVersions(subGroup)
+	}
+}
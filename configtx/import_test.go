@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestImportOrgFromConfig(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	src, dest := baseImportOrgConfigs(t)
+	srcOrg := src.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"]
+
+	c := New(dest)
+	err := c.ImportOrgFromConfig(src, "Org2", GroupApplication)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	imported := c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"]
+	gt.Expect(sameGroupContent(imported, srcOrg)).To(BeTrue())
+	gt.Expect(imported.Version).To(Equal(uint64(0)))
+	gt.Expect(imported.Values[MSPKey].Version).To(Equal(uint64(0)))
+
+	anchorPeers, err := c.Application().Organization("Org2").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(Equal([]Address{{Host: "peer0.org2", Port: 7051}}))
+
+	// the imported org is not shared with the source config
+	imported.ModPolicy = "Writers"
+	gt.Expect(srcOrg.ModPolicy).NotTo(Equal("Writers"))
+}
+
+func TestImportOrgFromConfigIntoOrderer(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	src, dest := baseImportOrgConfigs(t)
+
+	c := New(dest)
+	err := c.ImportOrgFromConfig(src, "Org2", GroupOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	imported := c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Groups["Org2"]
+	gt.Expect(imported.Values).To(HaveKey(MSPKey))
+	gt.Expect(imported.Values).NotTo(HaveKey(AnchorPeersKey))
+	gt.Expect(proto.Equal(imported.Values[MSPKey], src.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"].Values[MSPKey])).To(BeTrue())
+}
+
+func TestImportOrgFromConfigFailures(t *testing.T) {
+	t.Parallel()
+
+	src, dest := baseImportOrgConfigs(t)
+
+	tests := []struct {
+		name        string
+		src         *cb.Config
+		orgName     string
+		dest        Group
+		expectedErr string
+	}{
+		{
+			name:        "when the source config is missing",
+			orgName:     "Org2",
+			dest:        GroupApplication,
+			expectedErr: "source config is required",
+		},
+		{
+			name:        "when the destination group is missing",
+			src:         src,
+			orgName:     "Org2",
+			expectedErr: "destination group is required",
+		},
+		{
+			name:        "when the org does not exist",
+			src:         src,
+			orgName:     "Org3",
+			dest:        GroupApplication,
+			expectedErr: "org Org3 does not exist in source config",
+		},
+		{
+			name:        "when the destination group does not exist",
+			src:         src,
+			orgName:     "Org2",
+			dest:        GroupConsortium("SampleConsortium"),
+			expectedErr: "config group /Channel/Consortiums does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			c := New(proto.Clone(dest).(*cb.Config))
+			err := c.ImportOrgFromConfig(tc.src, tc.orgName, tc.dest)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+// baseImportOrgConfigs returns the config of an application channel with
+// the orgs Org1 and Org2, whose Org2 has anchor peers, and the config of
+// an application channel with Org1 only.
+func baseImportOrgConfigs(t *testing.T) (*cb.Config, *cb.Config) {
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "srcchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	err = c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	src := c.UpdatedConfig()
+
+	profile.Application.Organizations = profile.Application.Organizations[:1]
+	block, err = NewApplicationChannelGenesisBlock(profile, "destchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	dest, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return src, dest
+}
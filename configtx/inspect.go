@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// UpdateDescription is a human readable description of a config update.
+type UpdateDescription struct {
+	ChannelID string
+	Changes   []UpdateChange
+}
+
+// UpdateChange is a config element changed by a config update.
+type UpdateChange struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Application/Org1/MSP.
+	Path    string
+	Element ElementType
+	Change  ChangeType
+	// Version and ModPolicy are those of the element after the update. They
+	// are not set for removed elements.
+	Version   uint64
+	ModPolicy string
+	// Content describes the content of added and modified values and
+	// policies: the JSON encoding of values defined by Fabric, the MSP ID
+	// of MSPs and the rule of policies.
+	Content string
+}
+
+// String returns the changes of the update, one per line.
+func (d UpdateDescription) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "config update for channel %s\n", d.ChannelID)
+	for _, change := range d.Changes {
+		fmt.Fprintf(&b, "%s %s %s", change.Change, change.Element, change.Path)
+		if change.Change != ChangeRemoved {
+			fmt.Fprintf(&b, " %s", versionString(change.Version, change.ModPolicy))
+		}
+		if change.Content != "" {
+			fmt.Fprintf(&b, ": %s", change.Content)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// DescribeConfigUpdate describes the changes of a config update, which may
+// have been produced by any tool, so that it can be reviewed before it is
+// signed. An element of the write set is reported as added if it is not in
+// the read set and as modified if its version differs from the read set.
+// Removed elements are not part of a config update; they are only reported
+// if the config the update applies to is given as original, which may be
+// nil.
+func DescribeConfigUpdate(update *cb.ConfigUpdate, original *cb.Config) (UpdateDescription, error) {
+	if update == nil || update.WriteSet == nil {
+		return UpdateDescription{}, errors.New("config update does not contain a write set")
+	}
+
+	var originalGroup *cb.ConfigGroup
+	if original != nil {
+		originalGroup = original.ChannelGroup
+	}
+
+	d := UpdateDescription{ChannelID: update.ChannelId}
+	err := d.describeGroup(configPath(ChannelGroupKey), update.ReadSet, update.WriteSet, originalGroup)
+	if err != nil {
+		return UpdateDescription{}, err
+	}
+
+	return d, nil
+}
+
+// describeGroup adds the changes of the write set of the group at path.
+// The read set and original group are nil if they do not contain the
+// group.
+func (d *UpdateDescription) describeGroup(path string, readSet, writeSet, original *cb.ConfigGroup) error {
+	modified := readSet == nil || readSet.Version != writeSet.Version
+	if modified {
+		change := ChangeModified
+		if readSet == nil {
+			change = ChangeAdded
+		}
+		d.Changes = append(d.Changes, UpdateChange{
+			Path:      path,
+			Element:   ElementGroup,
+			Change:    change,
+			Version:   writeSet.Version,
+			ModPolicy: writeSet.ModPolicy,
+		})
+	}
+
+	for _, name := range sortedKeys(writeSet.Values) {
+		value := writeSet.Values[name]
+		read, ok := readSet.GetValues()[name]
+		if ok && read.Version == value.Version {
+			continue
+		}
+
+		content, err := describeValue(name, value.Value)
+		if err != nil {
+			return fmt.Errorf("decoding value %s/%s: %w", path, name, err)
+		}
+
+		d.Changes = append(d.Changes, UpdateChange{
+			Path:      path + "/" + name,
+			Element:   ElementValue,
+			Change:    changeType(ok),
+			Version:   value.Version,
+			ModPolicy: value.ModPolicy,
+			Content:   content,
+		})
+	}
+
+	for _, name := range sortedKeys(writeSet.Policies) {
+		policy := writeSet.Policies[name]
+		read, ok := readSet.GetPolicies()[name]
+		if ok && read.Version == policy.Version {
+			continue
+		}
+
+		d.Changes = append(d.Changes, UpdateChange{
+			Path:      path + "/" + name,
+			Element:   ElementPolicy,
+			Change:    changeType(ok),
+			Version:   policy.Version,
+			ModPolicy: policy.ModPolicy,
+			Content:   policyRuleString(policy.Policy),
+		})
+	}
+
+	// a group lists all of its members in the write set when its version
+	// changes, so members of the original group missing from it are removed
+	if modified && original != nil {
+		for _, name := range sortedKeys(original.Values) {
+			if _, ok := writeSet.Values[name]; !ok {
+				d.Changes = append(d.Changes, UpdateChange{Path: path + "/" + name, Element: ElementValue, Change: ChangeRemoved})
+			}
+		}
+		for _, name := range sortedKeys(original.Policies) {
+			if _, ok := writeSet.Policies[name]; !ok {
+				d.Changes = append(d.Changes, UpdateChange{Path: path + "/" + name, Element: ElementPolicy, Change: ChangeRemoved})
+			}
+		}
+		for _, name := range sortedKeys(original.Groups) {
+			if _, ok := writeSet.Groups[name]; !ok {
+				d.Changes = append(d.Changes, UpdateChange{Path: path + "/" + name, Element: ElementGroup, Change: ChangeRemoved})
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(writeSet.Groups) {
+		err := d.describeGroup(path+"/"+name, readSet.GetGroups()[name], writeSet.Groups[name], original.GetGroups()[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// changeType returns the change of an element of the write set depending
+// on whether it is in the read set.
+func changeType(inReadSet bool) ChangeType {
+	if inReadSet {
+		return ChangeModified
+	}
+
+	return ChangeAdded
+}
+
+// describeValue describes the content of the config value with the key.
+func describeValue(key string, value []byte) (string, error) {
+	newMessage, ok := standardValueTypes[key]
+	if !ok {
+		return fmt.Sprintf("<%d bytes>", len(value)), nil
+	}
+
+	msg := newMessage()
+	err := proto.Unmarshal(value, msg)
+	if err != nil {
+		return "", err
+	}
+
+	if mspConfig, ok := msg.(*mb.MSPConfig); ok {
+		fabricMSPConfig := &mb.FabricMSPConfig{}
+		err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig)
+		if err != nil {
+			return "", fmt.Errorf("unmarshaling fabric msp config: %w", err)
+		}
+
+		return fmt.Sprintf("MSP %s", fabricMSPConfig.Name), nil
+	}
+
+	buf := &bytes.Buffer{}
+	err = (&jsonpb.Marshaler{}).Marshal(buf, msg)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
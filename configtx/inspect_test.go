@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestDescribeConfigUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.Application().RemoveOrganization("Org2")
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	d, err := DescribeConfigUpdate(update, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(d.ChannelID).To(Equal("testchannel"))
+	gt.Expect(d.Changes).To(Equal([]UpdateChange{
+		{
+			Path:      "/Channel/Application",
+			Element:   ElementGroup,
+			Change:    ChangeModified,
+			Version:   1,
+			ModPolicy: AdminsPolicyKey,
+		},
+		{
+			Path:      "/Channel/Application/Org1",
+			Element:   ElementGroup,
+			Change:    ChangeModified,
+			Version:   1,
+			ModPolicy: AdminsPolicyKey,
+		},
+		{
+			Path:      "/Channel/Application/Org1/AnchorPeers",
+			Element:   ElementValue,
+			Change:    ChangeAdded,
+			ModPolicy: AdminsPolicyKey,
+			Content:   `{"anchorPeers":[{"host":"peer0.org1","port":7051}]}`,
+		},
+	}))
+
+	d, err = DescribeConfigUpdate(update, c.OriginalConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(d.Changes).To(ContainElement(UpdateChange{
+		Path:    "/Channel/Application/Org2",
+		Element: ElementGroup,
+		Change:  ChangeRemoved,
+	}))
+	gt.Expect(d.String()).To(Equal(`config update for channel testchannel
+modified group /Channel/Application [version: 1, mod_policy: "Admins"]
+removed group /Channel/Application/Org2
+modified group /Channel/Application/Org1 [version: 1, mod_policy: "Admins"]
+added value /Channel/Application/Org1/AnchorPeers [version: 0, mod_policy: "Admins"]: {"anchorPeers":[{"host":"peer0.org1","port":7051}]}
+`))
+}
+
+func TestDescribeConfigUpdateValues(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	msp, _ := baseMSP(t)
+	mspConfig, err := newMSPConfig(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	update := &cb.ConfigUpdate{
+		ChannelId: "testchannel",
+		ReadSet:   &cb.ConfigGroup{},
+		WriteSet: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				MSPKey:   {Value: marshalOrPanic(mspConfig)},
+				"Custom": {Value: []byte("custom")},
+			},
+		},
+	}
+
+	d, err := DescribeConfigUpdate(update, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(d.Changes).To(HaveLen(2))
+	gt.Expect(d.Changes[0].Content).To(Equal("<6 bytes>"))
+	gt.Expect(d.Changes[1].Content).To(Equal("MSP MSPID"))
+}
+
+func TestDescribeConfigUpdateFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		update      *cb.ConfigUpdate
+		expectedErr string
+	}{
+		{
+			testName:    "when the update is nil",
+			update:      nil,
+			expectedErr: "config update does not contain a write set",
+		},
+		{
+			testName:    "when the update does not contain a write set",
+			update:      &cb.ConfigUpdate{},
+			expectedErr: "config update does not contain a write set",
+		},
+		{
+			testName: "when a standard value cannot be decoded",
+			update: &cb.ConfigUpdate{
+				WriteSet: &cb.ConfigGroup{
+					Values: map[string]*cb.ConfigValue{
+						AnchorPeersKey: {Value: []byte("garbage")},
+					},
+				},
+			},
+			expectedErr: "decoding value /Channel/AnchorPeers: ",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := DescribeConfigUpdate(tc.update, nil)
+			gt.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"sync"
+)
+
+// maxInternedCertificates bounds the number of certificates held by the
+// certificate interner so that long-running processes reading many
+// distinct configs do not retain certificates indefinitely.
+const maxInternedCertificates = 4096
+
+// certificateInterner shares parsed certificates between every occurrence
+// of the same DER encoded certificate. Configs of large networks repeat the
+// same CA certificates across application, orderer and consortium orgs and
+// across the original and updated configs of a config transaction;
+// interning parses each of them once and keeps a single copy in memory.
+// Each ConfigTx created by New or Plan and each ConfigReader has its own
+// interner, so certificates are never shared between them. Copies of a
+// ConfigTx value share its interner, like they share its configs. A nil
+// interner parses every certificate.
+type certificateInterner struct {
+	mutex sync.Mutex
+	max   int
+	certs map[[sha256.Size]byte]*x509.Certificate
+}
+
+func newCertificateInterner(max int) *certificateInterner {
+	return &certificateInterner{
+		max:   max,
+		certs: map[[sha256.Size]byte]*x509.Certificate{},
+	}
+}
+
+// parse returns the interned certificate for der, parsing and interning it
+// if it has not been seen before.
+func (i *certificateInterner) parse(der []byte) (*x509.Certificate, error) {
+	if i == nil {
+		return x509.ParseCertificate(der)
+	}
+
+	key := sha256.Sum256(der)
+
+	i.mutex.Lock()
+	cert, ok := i.certs[key]
+	i.mutex.Unlock()
+	if ok && bytes.Equal(cert.Raw, der) {
+		return cert, nil
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if interned, ok := i.certs[key]; ok && bytes.Equal(interned.Raw, der) {
+		return interned, nil
+	}
+
+	if len(i.certs) >= i.max {
+		i.certs = map[[sha256.Size]byte]*x509.Certificate{}
+	}
+	i.certs[key] = cert
+
+	return cert, nil
+}
+
+// certificates returns the certificate interner of the ConfigTx, or nil if
+// there is none.
+func (c *ConfigTx) certificates() *certificateInterner {
+	if c == nil {
+		return nil
+	}
+
+	return c.options.certificates
+}
+
+// len returns the number of interned certificates.
+func (i *certificateInterner) len() int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return len(i.certs)
+}
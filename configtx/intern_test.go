@@ -0,0 +1,191 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCertificateInterner(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	cert1, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	cert2, _ := generateCACertAndPrivateKey(t, "org2.example.com")
+
+	interner := newCertificateInterner(2)
+
+	parsed1, err := interner.parse(append([]byte{}, cert1.Raw...))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed1).To(Equal(cert1))
+
+	parsed1Again, err := interner.parse(append([]byte{}, cert1.Raw...))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed1Again).To(BeIdenticalTo(parsed1))
+
+	parsed2, err := interner.parse(cert2.Raw)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed2).NotTo(BeIdenticalTo(parsed1))
+	gt.Expect(interner.len()).To(Equal(2))
+
+	// the interner is emptied once full
+	cert3, _ := generateCACertAndPrivateKey(t, "org3.example.com")
+	_, err = interner.parse(cert3.Raw)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(interner.len()).To(Equal(1))
+
+	_, err = interner.parse([]byte("not a certificate"))
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(interner.len()).To(Equal(1))
+}
+
+func TestMSPCertificatesAreShared(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	consortiumMSP, err := c.Consortium("Consortium1").Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	originalConsortiumMSP, err := getMSPConfig(c.OriginalConfig().ChannelGroup.Groups[ConsortiumsGroupKey].Groups["Consortium1"].Groups["Org1"], c.options.certificates)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(consortiumMSP.RootCerts[0]).To(BeIdenticalTo(originalConsortiumMSP.RootCerts[0]))
+	gt.Expect(consortiumMSP.RootCerts[0]).To(BeIdenticalTo(consortiumMSP.Admins[0]))
+
+	// certificates are not shared with other config transactions, so
+	// modifying them cannot affect other config transactions
+	other := New(config)
+	otherMSP, err := other.Consortium("Consortium1").Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(otherMSP.RootCerts[0]).To(Equal(consortiumMSP.RootCerts[0]))
+	gt.Expect(otherMSP.RootCerts[0]).NotTo(BeIdenticalTo(consortiumMSP.RootCerts[0]))
+
+	// copies of a config transaction share its certificates
+	copied := c
+	copiedMSP, err := copied.Consortium("Consortium1").Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(copiedMSP.RootCerts[0]).To(BeIdenticalTo(consortiumMSP.RootCerts[0]))
+}
+
+func BenchmarkCertificateParsing(b *testing.B) {
+	der := benchmarkCertificate(b)
+
+	// the number of copies of a CA certificate in the MSPs of an org that
+	// is a member of a hundred consortiums
+	const copies = 100
+
+	b.Run("parsed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			certs := make([]*x509.Certificate, copies)
+			for j := range certs {
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					b.Fatal(err)
+				}
+				certs[j] = cert
+			}
+		}
+	})
+
+	b.Run("interned", func(b *testing.B) {
+		interner := newCertificateInterner(maxInternedCertificates)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			certs := make([]*x509.Certificate, copies)
+			for j := range certs {
+				cert, err := interner.parse(der)
+				if err != nil {
+					b.Fatal(err)
+				}
+				certs[j] = cert
+			}
+		}
+	})
+}
+
+func BenchmarkMSPConfiguration(b *testing.B) {
+	der := benchmarkCertificate(b)
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	msp := MSP{
+		Name:         "MSPID",
+		RootCerts:    []*x509.Certificate{cert},
+		Admins:       []*x509.Certificate{cert},
+		TLSRootCerts: []*x509.Certificate{cert},
+	}
+	mspConfig, err := newMSPConfig(msp)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	group := newConfigGroup()
+	err = setValue(group, mspValue(mspConfig), AdminsPolicyKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	interner := newCertificateInterner(maxInternedCertificates)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := getMSPConfig(group, interner)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkCertificate returns a DER encoded self-signed CA certificate.
+func benchmarkCertificate(b *testing.B) []byte {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName:   "ca.org1.example.com",
+			Organization: []string{"org1.example.com"},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return der
+}
@@ -0,0 +1,248 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"gopkg.in/yaml.v2"
+)
+
+// Organization types of an inventory.
+const (
+	InventoryApplicationOrg = "application"
+	InventoryOrdererOrg     = "orderer"
+	InventoryConsortiumOrg  = "consortium"
+)
+
+// inventoryFile is the name of the inventory written by ExportInventory.
+const inventoryFile = "inventory.yaml"
+
+// Inventory describes the members of a network as recorded in a channel
+// config in a structured form consumable by deployment automation.
+type Inventory struct {
+	Organizations []InventoryOrganization `json:"organizations" yaml:"organizations"`
+	Consenters    []InventoryConsenter    `json:"consenters,omitempty" yaml:"consenters,omitempty"`
+}
+
+// InventoryOrganization is an organization of an inventory.
+type InventoryOrganization struct {
+	Name  string `json:"name" yaml:"name"`
+	MSPID string `json:"mspID" yaml:"mspID"`
+	// Type is one of application, orderer or consortium.
+	Type string `json:"type" yaml:"type"`
+	// Consortium is the consortium of a consortium org.
+	Consortium string `json:"consortium,omitempty" yaml:"consortium,omitempty"`
+	// MSPDir is the directory the MSP of the organization is written to by
+	// ExportInventory, relative to the export directory.
+	MSPDir           string              `json:"mspDir" yaml:"mspDir"`
+	OrdererEndpoints []string            `json:"ordererEndpoints,omitempty" yaml:"ordererEndpoints,omitempty"`
+	AnchorPeers      []InventoryEndpoint `json:"anchorPeers,omitempty" yaml:"anchorPeers,omitempty"`
+}
+
+// InventoryEndpoint is the host and port of a node.
+type InventoryEndpoint struct {
+	Host string `json:"host" yaml:"host"`
+	Port int    `json:"port" yaml:"port"`
+}
+
+// InventoryConsenter is an etcdraft consenter of an inventory. The TLS
+// certificate files are relative to the export directory.
+type InventoryConsenter struct {
+	Host          string `json:"host" yaml:"host"`
+	Port          int    `json:"port" yaml:"port"`
+	ClientTLSCert string `json:"clientTLSCert,omitempty" yaml:"clientTLSCert,omitempty"`
+	ServerTLSCert string `json:"serverTLSCert,omitempty" yaml:"serverTLSCert,omitempty"`
+}
+
+// Inventory returns the organizations, endpoints, anchor peers and
+// consenters of the updated config.
+func (c *ConfigTx) Inventory() (Inventory, error) {
+	inventory, _, err := c.inventory()
+	return inventory, err
+}
+
+// ExportInventory writes the inventory of the updated config to
+// inventory.yaml in dir, along with the MSP directory of each organization
+// and the TLS certificates of each consenter at the paths referenced by
+// the inventory. An error is returned if the name of an organization or
+// consortium, or the host of a consenter, cannot be used as a file name,
+// e.g. because it contains a path separator.
+func (c *ConfigTx) ExportInventory(dir string) error {
+	inventory, orgs, err := c.inventory()
+	if err != nil {
+		return err
+	}
+
+	for i, org := range orgs {
+		err := WriteMSPDir(org.MSP, filepath.Join(dir, inventory.Organizations[i].MSPDir))
+		if err != nil {
+			return fmt.Errorf("writing MSP of %s: %w", org.Name, err)
+		}
+	}
+
+	consenters, err := etcdRaftConsenters(c.updated.ChannelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return fmt.Errorf("retrieving consenters: %w", err)
+	}
+
+	for i, consenter := range consenters {
+		for _, cert := range []struct {
+			file string
+			cert *x509.Certificate
+		}{
+			{inventory.Consenters[i].ClientTLSCert, consenter.ClientTLSCert},
+			{inventory.Consenters[i].ServerTLSCert, consenter.ServerTLSCert},
+		} {
+			if cert.cert == nil {
+				continue
+			}
+
+			err := writeFileAll(filepath.Join(dir, cert.file), pemEncodeX509Certificate(cert.cert))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	raw, err := yaml.Marshal(inventory)
+	if err != nil {
+		return fmt.Errorf("encoding inventory: %w", err)
+	}
+
+	return writeFileAll(filepath.Join(dir, inventoryFile), raw)
+}
+
+// inventory returns the inventory of the updated config together with the
+// organizations in the order of the inventory's organizations.
+func (c *ConfigTx) inventory() (Inventory, []Organization, error) {
+	inventory := Inventory{}
+	var orgs []Organization
+
+	addOrg := func(org Organization, orgType, consortium, mspDir string) {
+		inventoryOrg := InventoryOrganization{
+			Name:             org.Name,
+			MSPID:            org.MSP.Name,
+			Type:             orgType,
+			Consortium:       consortium,
+			MSPDir:           mspDir,
+			OrdererEndpoints: org.OrdererEndpoints,
+		}
+
+		for _, anchorPeer := range org.AnchorPeers {
+			inventoryOrg.AnchorPeers = append(inventoryOrg.AnchorPeers, InventoryEndpoint{
+				Host: anchorPeer.Host,
+				Port: anchorPeer.Port,
+			})
+		}
+
+		inventory.Organizations = append(inventory.Organizations, inventoryOrg)
+		orgs = append(orgs, org)
+	}
+
+	channelGroup := c.updated.ChannelGroup
+
+	if applicationGroup, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		for _, name := range sortedGroupNames(applicationGroup) {
+			if err := checkInventoryFileName(name); err != nil {
+				return Inventory{}, nil, fmt.Errorf("application org: %w", err)
+			}
+			org, err := getOrganization(applicationGroup.Groups[name], name, c.options.certificates)
+			if err != nil {
+				return Inventory{}, nil, fmt.Errorf("retrieving application org %s: %w", name, err)
+			}
+			addOrg(org, InventoryApplicationOrg, "", filepath.Join("organizations", "application", name, "msp"))
+		}
+	}
+
+	if ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]; ok {
+		o := c.Orderer()
+		for _, name := range sortedGroupNames(ordererGroup) {
+			if err := checkInventoryFileName(name); err != nil {
+				return Inventory{}, nil, fmt.Errorf("orderer org: %w", err)
+			}
+			org, err := o.Organization(name).Configuration()
+			if err != nil {
+				return Inventory{}, nil, fmt.Errorf("retrieving orderer org %s: %w", name, err)
+			}
+			addOrg(org, InventoryOrdererOrg, "", filepath.Join("organizations", "orderer", name, "msp"))
+		}
+	}
+
+	if consortiumsGroup, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		for _, consortiumName := range sortedGroupNames(consortiumsGroup) {
+			if err := checkInventoryFileName(consortiumName); err != nil {
+				return Inventory{}, nil, fmt.Errorf("consortium: %w", err)
+			}
+			consortiumGroup := consortiumsGroup.Groups[consortiumName]
+			for _, name := range sortedGroupNames(consortiumGroup) {
+				if err := checkInventoryFileName(name); err != nil {
+					return Inventory{}, nil, fmt.Errorf("org of consortium %s: %w", consortiumName, err)
+				}
+				org, err := getOrganization(consortiumGroup.Groups[name], name, c.options.certificates)
+				if err != nil {
+					return Inventory{}, nil, fmt.Errorf("retrieving org %s of consortium %s: %w", name, consortiumName, err)
+				}
+				addOrg(org, InventoryConsortiumOrg, consortiumName, filepath.Join("organizations", "consortiums", consortiumName, name, "msp"))
+			}
+		}
+	}
+
+	consenters, err := etcdRaftConsenters(channelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return Inventory{}, nil, fmt.Errorf("retrieving consenters: %w", err)
+	}
+
+	for _, consenter := range consenters {
+		if err := checkInventoryFileName(consenter.Address.Host); err != nil {
+			return Inventory{}, nil, fmt.Errorf("consenter host: %w", err)
+		}
+		consenterDir := filepath.Join("consenters", consenter.Address.Host+"-"+strconv.Itoa(consenter.Address.Port))
+		inventoryConsenter := InventoryConsenter{
+			Host: consenter.Address.Host,
+			Port: consenter.Address.Port,
+		}
+		if consenter.ClientTLSCert != nil {
+			inventoryConsenter.ClientTLSCert = filepath.Join(consenterDir, "client.pem")
+		}
+		if consenter.ServerTLSCert != nil {
+			inventoryConsenter.ServerTLSCert = filepath.Join(consenterDir, "server.pem")
+		}
+		inventory.Consenters = append(inventory.Consenters, inventoryConsenter)
+	}
+
+	return inventory, orgs, nil
+}
+
+// checkInventoryFileName checks that a name taken from the config can be
+// used as a file name of the export, so that exporting a config with names
+// like ../../x cannot write outside the export directory.
+func checkInventoryFileName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("name '%s' cannot be used as a file name", name)
+	}
+
+	return nil
+}
+
+// sortedGroupNames returns the names of the sub-groups of group in lexical
+// order.
+func sortedGroupNames(group *cb.ConfigGroup) []string {
+	var names []string
+	for name := range group.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestInventory(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c := inventoryConfigTx(t)
+
+	inventory, err := c.Inventory()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(inventory).To(Equal(Inventory{
+		Organizations: []InventoryOrganization{
+			{
+				Name:        "Org1",
+				MSPID:       "MSPID",
+				Type:        InventoryApplicationOrg,
+				MSPDir:      "organizations/application/Org1/msp",
+				AnchorPeers: []InventoryEndpoint{{Host: "peer0.org1.example.com", Port: 7051}},
+			},
+			{
+				Name:   "Org2",
+				MSPID:  "MSPID",
+				Type:   InventoryApplicationOrg,
+				MSPDir: "organizations/application/Org2/msp",
+			},
+			{
+				Name:             "OrdererOrg",
+				MSPID:            "MSPID",
+				Type:             InventoryOrdererOrg,
+				MSPDir:           "organizations/orderer/OrdererOrg/msp",
+				OrdererEndpoints: []string{"localhost:123"},
+			},
+			{
+				Name:       "Org1",
+				MSPID:      "MSPID",
+				Type:       InventoryConsortiumOrg,
+				Consortium: "Consortium1",
+				MSPDir:     "organizations/consortiums/Consortium1/Org1/msp",
+			},
+			{
+				Name:       "Org2",
+				MSPID:      "MSPID",
+				Type:       InventoryConsortiumOrg,
+				Consortium: "Consortium1",
+				MSPDir:     "organizations/consortiums/Consortium1/Org2/msp",
+			},
+		},
+		Consenters: []InventoryConsenter{
+			{Host: "node-1.example.com", Port: 7050, ClientTLSCert: "consenters/node-1.example.com-7050/client.pem", ServerTLSCert: "consenters/node-1.example.com-7050/server.pem"},
+			{Host: "node-2.example.com", Port: 7050, ClientTLSCert: "consenters/node-2.example.com-7050/client.pem", ServerTLSCert: "consenters/node-2.example.com-7050/server.pem"},
+			{Host: "node-3.example.com", Port: 7050, ClientTLSCert: "consenters/node-3.example.com-7050/client.pem", ServerTLSCert: "consenters/node-3.example.com-7050/server.pem"},
+		},
+	}))
+}
+
+func TestExportInventory(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "inventory")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	c := inventoryConfigTx(t)
+
+	err = c.ExportInventory(dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "inventory.yaml"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	inventory := Inventory{}
+	err = yaml.Unmarshal(raw, &inventory)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedInventory, err := c.Inventory()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(inventory).To(Equal(expectedInventory))
+
+	expectedMSP, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err := LoadMSPDir("MSPID", filepath.Join(dir, inventory.Organizations[0].MSPDir))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.RootCerts).To(Equal(expectedMSP.RootCerts))
+	gt.Expect(msp.Admins).To(Equal(expectedMSP.Admins))
+
+	ordererConf, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	serverCert, err := ioutil.ReadFile(filepath.Join(dir, inventory.Consenters[0].ServerTLSCert))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(serverCert).To(Equal(pemEncodeX509Certificate(ordererConf.EtcdRaft.Consenters[0].ServerTLSCert)))
+}
+
+func TestExportInventoryUnsafeNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName      string
+		rename        func(channelGroup *cb.ConfigGroup)
+		expectedError string
+	}{
+		{
+			testName: "when an application org name is a relative path",
+			rename: func(channelGroup *cb.ConfigGroup) {
+				applicationGroup := channelGroup.Groups[ApplicationGroupKey]
+				applicationGroup.Groups["../../x"] = applicationGroup.Groups["Org1"]
+				delete(applicationGroup.Groups, "Org1")
+			},
+			expectedError: "application org: name '../../x' cannot be used as a file name",
+		},
+		{
+			testName: "when a consortium name is the parent directory",
+			rename: func(channelGroup *cb.ConfigGroup) {
+				consortiumsGroup := channelGroup.Groups[ConsortiumsGroupKey]
+				consortiumsGroup.Groups[".."] = consortiumsGroup.Groups["Consortium1"]
+				delete(consortiumsGroup.Groups, "Consortium1")
+			},
+			expectedError: "consortium: name '..' cannot be used as a file name",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			dir, err := ioutil.TempDir("", "inventory")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			c := inventoryConfigTx(t)
+			tt.rename(c.UpdatedConfig().ChannelGroup)
+
+			err = c.ExportInventory(dir)
+			gt.Expect(err).To(MatchError(tt.expectedError))
+
+			files, err := ioutil.ReadDir(dir)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(files).To(BeEmpty())
+		})
+	}
+}
+
+func inventoryConfigTx(t *testing.T) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	applicationGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConf, _ := baseEtcdRaftOrderer(t)
+	ordererGroup, err := NewOrdererGroup(ordererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	consortiums, _ := baseConsortiums(t)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: applicationGroup,
+				OrdererGroupKey:     ordererGroup,
+				ConsortiumsGroupKey: consortiumsGroup,
+			},
+		},
+	})
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return c
+}
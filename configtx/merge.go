@@ -0,0 +1,271 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Conflict is a config element that was changed differently by both
+// updates of a merge.
+type Conflict struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Orderer/BatchSize.
+	Path    string
+	Element ElementType
+	// Ours and Theirs are the changes made to the element by the updated
+	// config of the ConfigTx being merged into and by the other one.
+	Ours   ChangeType
+	Theirs ChangeType
+}
+
+// String returns a description of the conflict, e.g.
+// "value /Channel/Orderer/BatchSize: modified by both updates".
+func (c Conflict) String() string {
+	if c.Ours == c.Theirs {
+		return fmt.Sprintf("%s %s: %s by both updates", c.Element, c.Path, c.Ours)
+	}
+
+	return fmt.Sprintf("%s %s: %s by ours, %s by theirs", c.Element, c.Path, c.Ours, c.Theirs)
+}
+
+// MergeConflictError is returned by Merge when the updates conflict.
+type MergeConflictError struct {
+	Conflicts []Conflict
+}
+
+// Error lists the conflicts.
+func (e *MergeConflictError) Error() string {
+	descriptions := make([]string, len(e.Conflicts))
+	for i, conflict := range e.Conflicts {
+		descriptions[i] = conflict.String()
+	}
+
+	return fmt.Sprintf("%d conflicting changes: %s", len(e.Conflicts), strings.Join(descriptions, "; "))
+}
+
+// Merge merges the changes made by other to its updated config into the
+// updated config, so that updates prepared independently against the same
+// channel config can be submitted together. Both ConfigTxs must have the
+// same original config. An element changed by only one of the updates
+// takes the content it has in that update, and an element changed the same
+// way by both keeps it; an element changed differently by both is a
+// conflict. If there are conflicts, a *MergeConflictError listing them in
+// the order of the config tree is returned and the updated config is left
+// unchanged. Versions are ignored when comparing elements, as they are
+// assigned when the update is computed. Merge is not supported if either
+// ConfigTx was created with WithoutClone.
+func (c *ConfigTx) Merge(other ConfigTx) error {
+	if other.original == nil || other.updated == nil {
+		return errors.New("other config is required")
+	}
+	if c.options.withoutClone || other.options.withoutClone {
+		return errWithoutClone("Merge")
+	}
+	if !proto.Equal(c.original, other.original) {
+		return errors.New("configs do not have the same original config")
+	}
+
+	merged := proto.Clone(c.updated.ChannelGroup).(*cb.ConfigGroup)
+	conflicts := mergeGroup(configPath(ChannelGroupKey), c.original.ChannelGroup, merged, other.updated.ChannelGroup)
+	if len(conflicts) > 0 {
+		return &MergeConflictError{Conflicts: conflicts}
+	}
+
+	c.updated.ChannelGroup = merged
+
+	c.notify(configPath(ChannelGroupKey), "Merge")
+
+	return nil
+}
+
+// Rebase re-applies the pending edits of the updated config onto newBase,
+// e.g. the current config of the channel after it changed while the update
+// was prepared, and makes newBase the original config, so that the update
+// is computed against the versions the orderer expects. The edits are
+// merged with the changes from the original config to newBase like Merge.
+// If an edit no longer applies because newBase changed the same element
+// differently, a *MergeConflictError is returned whose conflicts describe
+// the pending edits as ours and the changes of newBase as theirs, and the
+// ConfigTx is left unchanged. Rebase is not supported with WithoutClone.
+func (c *ConfigTx) Rebase(newBase *cb.Config) error {
+	if newBase == nil || newBase.ChannelGroup == nil {
+		return errors.New("new base config is required")
+	}
+	if c.options.withoutClone {
+		return errWithoutClone("Rebase")
+	}
+
+	merged := proto.Clone(c.updated.ChannelGroup).(*cb.ConfigGroup)
+	conflicts := mergeGroup(configPath(ChannelGroupKey), c.original.ChannelGroup, merged, newBase.ChannelGroup)
+	if len(conflicts) > 0 {
+		return &MergeConflictError{Conflicts: conflicts}
+	}
+
+	c.original = newBase
+	c.updated = proto.Clone(newBase).(*cb.Config)
+	c.updated.ChannelGroup = merged
+
+	c.notify(configPath(ChannelGroupKey), "Rebase")
+
+	return nil
+}
+
+// mergeGroup merges the changes made to the group at groupPath from base
+// to theirs into ours and returns the conflicting changes.
+func mergeGroup(groupPath string, base, ours, theirs *cb.ConfigGroup) []Conflict {
+	var conflicts []Conflict
+
+	if theirs.ModPolicy != base.ModPolicy && ours.ModPolicy != theirs.ModPolicy {
+		if ours.ModPolicy == base.ModPolicy {
+			ours.ModPolicy = theirs.ModPolicy
+		} else {
+			conflicts = append(conflicts, Conflict{Path: groupPath, Element: ElementGroup, Ours: ChangeModified, Theirs: ChangeModified})
+		}
+	}
+
+	if ours.Values == nil {
+		ours.Values = map[string]*cb.ConfigValue{}
+	}
+	for _, name := range unionKeys(base.Values, ours.Values, theirs.Values) {
+		b, o, t := base.Values[name], ours.Values[name], theirs.Values[name]
+
+		switch {
+		case sameValue(b, t) || sameValue(o, t):
+		case sameValue(b, o) && t == nil:
+			delete(ours.Values, name)
+		case sameValue(b, o):
+			ours.Values[name] = proto.Clone(t).(*cb.ConfigValue)
+		default:
+			conflicts = append(conflicts, newConflict(groupPath+"/"+name, ElementValue, b != nil, o != nil, t != nil))
+		}
+	}
+
+	if ours.Policies == nil {
+		ours.Policies = map[string]*cb.ConfigPolicy{}
+	}
+	for _, name := range unionKeys(base.Policies, ours.Policies, theirs.Policies) {
+		b, o, t := base.Policies[name], ours.Policies[name], theirs.Policies[name]
+
+		switch {
+		case samePolicy(b, t) || samePolicy(o, t):
+		case samePolicy(b, o) && t == nil:
+			delete(ours.Policies, name)
+		case samePolicy(b, o):
+			ours.Policies[name] = proto.Clone(t).(*cb.ConfigPolicy)
+		default:
+			conflicts = append(conflicts, newConflict(groupPath+"/"+name, ElementPolicy, b != nil, o != nil, t != nil))
+		}
+	}
+
+	if ours.Groups == nil {
+		ours.Groups = map[string]*cb.ConfigGroup{}
+	}
+	for _, name := range unionKeys(base.Groups, ours.Groups, theirs.Groups) {
+		b, o, t := base.Groups[name], ours.Groups[name], theirs.Groups[name]
+		subGroupPath := groupPath + "/" + name
+
+		switch {
+		case sameGroupContent(b, t) || sameGroupContent(o, t):
+		case sameGroupContent(b, o) && t == nil:
+			delete(ours.Groups, name)
+		case sameGroupContent(b, o):
+			ours.Groups[name] = proto.Clone(t).(*cb.ConfigGroup)
+		case o != nil && t != nil:
+			// both updates modified or added the group, so their changes
+			// are merged element by element
+			if b == nil {
+				b = newConfigGroup()
+			}
+			conflicts = append(conflicts, mergeGroup(subGroupPath, b, o, t)...)
+		default:
+			conflicts = append(conflicts, newConflict(subGroupPath, ElementGroup, b != nil, o != nil, t != nil))
+		}
+	}
+
+	return conflicts
+}
+
+// newConflict returns the conflict of an element based on whether it
+// exists in the base config and in each of the updated configs.
+func newConflict(path string, element ElementType, inBase, inOurs, inTheirs bool) Conflict {
+	change := func(inUpdated bool) ChangeType {
+		switch {
+		case !inBase:
+			return ChangeAdded
+		case !inUpdated:
+			return ChangeRemoved
+		default:
+			return ChangeModified
+		}
+	}
+
+	return Conflict{Path: path, Element: element, Ours: change(inOurs), Theirs: change(inTheirs)}
+}
+
+// sameGroupContent reports whether the groups have the same mod policy,
+// values, policies and sub-groups, ignoring their versions. Two nil groups
+// have the same content.
+func sameGroupContent(g1, g2 *cb.ConfigGroup) bool {
+	if g1 == nil || g2 == nil {
+		return g1 == g2
+	}
+
+	if g1.ModPolicy != g2.ModPolicy ||
+		len(g1.Values) != len(g2.Values) ||
+		len(g1.Policies) != len(g2.Policies) ||
+		len(g1.Groups) != len(g2.Groups) {
+		return false
+	}
+
+	for name, value := range g1.Values {
+		if !sameValue(value, g2.Values[name]) {
+			return false
+		}
+	}
+
+	for name, policy := range g1.Policies {
+		if !samePolicy(policy, g2.Policies[name]) {
+			return false
+		}
+	}
+
+	for name, sub1 := range g1.Groups {
+		sub2, ok := g2.Groups[name]
+		if !ok || !sameGroupContent(sub1, sub2) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameValue reports whether the values have the same mod policy and
+// content, ignoring their versions. Two nil values are the same.
+func sameValue(v1, v2 *cb.ConfigValue) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == v2
+	}
+
+	return v1.ModPolicy == v2.ModPolicy && bytes.Equal(v1.Value, v2.Value)
+}
+
+// samePolicy reports whether the policies have the same mod policy and
+// content, ignoring their versions. Two nil policies are the same.
+func samePolicy(p1, p2 *cb.ConfigPolicy) bool {
+	if p1 == nil || p2 == nil {
+		return p1 == p2
+	}
+
+	return p1.ModPolicy == p2.ModPolicy && proto.Equal(p1.Policy, p2.Policy)
+}
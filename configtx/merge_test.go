@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	base := baseOrgUpdateConfigTx(t)
+	original := base.OriginalConfig()
+	ours := New(proto.Clone(original).(*cb.Config))
+	theirs := New(proto.Clone(original).(*cb.Config))
+
+	err := ours.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ours.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = theirs.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = theirs.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = theirs.Application().RemovePolicy(ReadersPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = ours.Merge(theirs)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org1AnchorPeers, err := ours.Application().Organization("Org1").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org1AnchorPeers).To(Equal([]Address{{Host: "peer0.org1.example.com", Port: 7051}}))
+	org2AnchorPeers, err := ours.Application().Organization("Org2").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org2AnchorPeers).To(Equal([]Address{{Host: "peer0.org2.example.com", Port: 7051}}))
+	acls, err := ours.Application().ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(HaveKeyWithValue("acl2", "Admins"))
+	policies, err := ours.Application().Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).NotTo(HaveKey(ReadersPolicyKey))
+
+	_, err = ours.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestMergeConflicts(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	base := baseOrgUpdateConfigTx(t)
+	original := base.OriginalConfig()
+	ours := New(proto.Clone(original).(*cb.Config))
+	theirs := New(proto.Clone(original).(*cb.Config))
+
+	err := ours.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	ours.Application().RemoveOrganization("Org2")
+
+	err = theirs.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer1.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = theirs.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = theirs.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	updated := proto.Clone(ours.UpdatedConfig())
+
+	err = ours.Merge(theirs)
+	gt.Expect(err).To(MatchError("2 conflicting changes: " +
+		"value /Channel/Application/Org1/AnchorPeers: added by both updates; " +
+		"group /Channel/Application/Org2: removed by ours, modified by theirs"))
+
+	mergeErr, ok := err.(*MergeConflictError)
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(mergeErr.Conflicts).To(Equal([]Conflict{
+		{Path: "/Channel/Application/Org1/AnchorPeers", Element: ElementValue, Ours: ChangeAdded, Theirs: ChangeAdded},
+		{Path: "/Channel/Application/Org2", Element: ElementGroup, Ours: ChangeRemoved, Theirs: ChangeModified},
+	}))
+
+	// the non-conflicting ACL change is not merged either
+	gt.Expect(proto.Equal(ours.UpdatedConfig(), updated)).To(BeTrue())
+}
+
+func TestMergeFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	ours := baseOrgUpdateConfigTx(t)
+	theirs := baseOrgUpdateConfigTx(t)
+
+	err := ours.Merge(theirs)
+	gt.Expect(err).To(MatchError("configs do not have the same original config"))
+
+	err = ours.Merge(ConfigTx{})
+	gt.Expect(err).To(MatchError("other config is required"))
+
+	withoutClone := New(ours.OriginalConfig(), WithoutClone())
+	err = withoutClone.Merge(ours)
+	gt.Expect(err).To(MatchError("Merge is not supported without a copy of the original config, see WithoutClone"))
+	err = ours.Merge(withoutClone)
+	gt.Expect(err).To(MatchError("Merge is not supported without a copy of the original config, see WithoutClone"))
+	err = withoutClone.Rebase(ours.OriginalConfig())
+	gt.Expect(err).To(MatchError("Rebase is not supported without a copy of the original config, see WithoutClone"))
+}
+
+func TestRebase(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// the channel config changed while the update was prepared
+	channelUpdate := New(proto.Clone(c.OriginalConfig()).(*cb.Config))
+	err = channelUpdate.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	newBase := channelUpdate.UpdatedConfig()
+	newBase.Sequence = 1
+	newBase.ChannelGroup.Groups[ApplicationGroupKey].Version = 1
+	newBase.ChannelGroup.Groups[ApplicationGroupKey].Values[ACLsKey].Version = 1
+
+	err = c.Rebase(newBase)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.OriginalConfig()).To(Equal(newBase))
+
+	acls, err := c.Application().ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(HaveKeyWithValue("acl2", "Admins"))
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(update.ReadSet.Groups[ApplicationGroupKey].Version).To(Equal(uint64(1)))
+	gt.Expect(update.WriteSet.Groups[ApplicationGroupKey].Values).NotTo(HaveKey(ACLsKey))
+	gt.Expect(update.WriteSet.Groups[ApplicationGroupKey].Groups["Org1"].Values).To(HaveKey(AnchorPeersKey))
+	gt.Expect(c.UpdatedConfig().Sequence).To(Equal(uint64(2)))
+}
+
+func TestRebaseConflicts(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	err := c.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	original := c.OriginalConfig()
+
+	channelUpdate := New(proto.Clone(original).(*cb.Config))
+	err = channelUpdate.Application().SetACLs(map[string]string{"acl2": "Readers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelUpdate.Application().RemoveOrganization("Org2")
+
+	err = c.Rebase(channelUpdate.UpdatedConfig())
+	gt.Expect(err).To(MatchError("1 conflicting changes: value /Channel/Application/ACLs: modified by both updates"))
+	gt.Expect(c.OriginalConfig()).To(Equal(original))
+
+	err = c.Rebase(nil)
+	gt.Expect(err).To(MatchError("new base config is required"))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+)
+
+// VerifyConsensusMigration checks a change of the consensus type between
+// the original and updated config against the migration rules of the
+// orderer, so that partially converted updates are rejected before they
+// are submitted. The consensus type may only change while the channel is
+// in maintenance mode, without changing the consensus state in the same
+// update, and only from kafka to etcdraft. The update that migrates to
+// etcdraft must also remove the kafka brokers and set etcdraft metadata
+// with consenters and valid options. All findings are returned as
+// ValidationErrors; a config whose consensus type does not change has
+// none.
+func (c *ConfigTx) VerifyConsensusMigration() error {
+	originalOrderer, ok := c.original.GetChannelGroup().GetGroups()[OrdererGroupKey]
+	if !ok {
+		return nil
+	}
+	updatedOrderer, ok := c.updated.ChannelGroup.Groups[OrdererGroupKey]
+	if !ok {
+		return nil
+	}
+
+	original := &ob.ConsensusType{}
+	err := unmarshalConfigValueAtKey(originalOrderer, orderer.ConsensusTypeKey, original)
+	if err != nil {
+		return fmt.Errorf("retrieving original consensus type: %w", err)
+	}
+	updated := &ob.ConsensusType{}
+	err = unmarshalConfigValueAtKey(updatedOrderer, orderer.ConsensusTypeKey, updated)
+	if err != nil {
+		return fmt.Errorf("retrieving updated consensus type: %w", err)
+	}
+
+	if original.Type == updated.Type {
+		return nil
+	}
+
+	var findings ValidationErrors
+
+	if original.State != ob.ConsensusType_STATE_MAINTENANCE {
+		findings = append(findings, fmt.Errorf("consensus type cannot be changed from %s to %s outside of maintenance mode", original.Type, updated.Type))
+	}
+	if updated.State != ob.ConsensusType_STATE_MAINTENANCE {
+		findings = append(findings, errors.New("consensus type and consensus state cannot be changed in the same update"))
+	}
+
+	if original.Type != orderer.ConsensusTypeKafka || updated.Type != orderer.ConsensusTypeEtcdRaft {
+		findings = append(findings, fmt.Errorf("unsupported consensus type migration from %s to %s", original.Type, updated.Type))
+		return findings.err()
+	}
+
+	if _, ok := updatedOrderer.Values[orderer.KafkaBrokersKey]; ok {
+		findings = append(findings, errors.New("kafka brokers must be removed when migrating to etcdraft"))
+	}
+
+	findings = findings.append(verifyEtcdRaftMetadata(updated.Metadata))
+
+	return findings.err()
+}
+
+// verifyEtcdRaftMetadata checks that the etcdraft metadata of a migration
+// would be accepted by the etcdraft consenter.
+func verifyEtcdRaftMetadata(metadata []byte) error {
+	etcdRaft, err := unmarshalEtcdRaftMetadata(metadata, nil)
+	if err != nil {
+		return fmt.Errorf("invalid etcdraft metadata: %w", err)
+	}
+
+	var findings ValidationErrors
+
+	if len(etcdRaft.Consenters) == 0 {
+		findings = append(findings, errors.New("etcdraft metadata does not contain consenters"))
+	}
+
+	options := etcdRaft.Options
+	tickInterval, err := time.ParseDuration(options.TickInterval)
+	if err != nil || tickInterval <= 0 {
+		findings = append(findings, fmt.Errorf("invalid etcdraft tick interval '%s'", options.TickInterval))
+	}
+	if options.HeartbeatTick == 0 {
+		findings = append(findings, errors.New("etcdraft heartbeat tick must be greater than zero"))
+	}
+	if options.ElectionTick <= options.HeartbeatTick {
+		findings = append(findings, fmt.Errorf("etcdraft election tick %d must be greater than heartbeat tick %d", options.ElectionTick, options.HeartbeatTick))
+	}
+	if options.MaxInflightBlocks == 0 {
+		findings = append(findings, errors.New("etcdraft max inflight blocks must be greater than zero"))
+	}
+
+	return findings.err()
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestMigrateToEtcdRaft(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, etcdRaft := baseConsensusMigration(t)
+
+	err := c.Orderer().MigrateToEtcdRaft(etcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.OrdererType).To(Equal(orderer.ConsensusTypeEtcdRaft))
+	gt.Expect(ordererConfig.State).To(Equal(orderer.ConsensusStateMaintenance))
+	gt.Expect(ordererConfig.EtcdRaft).To(Equal(etcdRaft))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Values).NotTo(HaveKey(orderer.KafkaBrokersKey))
+
+	err = c.VerifyConsensusMigration()
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestMigrateToEtcdRaftFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		state       orderer.ConsensusState
+		ordererType string
+		expectedErr string
+	}{
+		{
+			testName:    "when the orderer is not in maintenance mode",
+			state:       orderer.ConsensusStateNormal,
+			ordererType: orderer.ConsensusTypeKafka,
+			expectedErr: "consensus type can only be migrated in maintenance mode",
+		},
+		{
+			testName:    "when the consensus type is not kafka",
+			state:       orderer.ConsensusStateMaintenance,
+			ordererType: orderer.ConsensusTypeSolo,
+			expectedErr: "unsupported consensus type migration from solo to etcdraft",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			ordererConf, _ := baseKafkaOrderer(t)
+			ordererConf.OrdererType = tc.ordererType
+			ordererConf.State = tc.state
+			ordererGroup, err := NewOrdererGroup(ordererConf)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			channelGroup := newConfigGroup()
+			channelGroup.Groups[OrdererGroupKey] = ordererGroup
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+			err = c.Orderer().MigrateToEtcdRaft(etcdRaftOrderer.EtcdRaft)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestVerifyConsensusMigrationFindings(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, etcdRaft := baseConsensusMigration(t)
+
+	etcdRaft.Options.TickInterval = "never"
+	etcdRaft.Options.ElectionTick = 1
+	err := c.Orderer().SetEtcdRaftConsensusType(etcdRaft, orderer.ConsensusStateNormal)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.VerifyConsensusMigration()
+	gt.Expect(err).To(MatchError("consensus type and consensus state cannot be changed in the same update; " +
+		"kafka brokers must be removed when migrating to etcdraft; " +
+		"invalid etcdraft tick interval 'never'; " +
+		"etcdraft election tick 1 must be greater than heartbeat tick 1"))
+
+	var findings ValidationErrors
+	gt.Expect(errors.As(c.Validate(), &findings)).To(BeTrue())
+	gt.Expect(findings).To(ContainElement(MatchError("kafka brokers must be removed when migrating to etcdraft")))
+}
+
+func TestVerifyConsensusMigrationOutsideMaintenanceMode(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	soloOrderer, _ := baseSoloOrderer(t)
+	ordererGroup, err := NewOrdererGroup(soloOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	err = c.Orderer().SetEtcdRaftConsensusType(etcdRaftOrderer.EtcdRaft, orderer.ConsensusStateMaintenance)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.VerifyConsensusMigration()
+	gt.Expect(err).To(MatchError("consensus type cannot be changed from solo to etcdraft outside of maintenance mode; " +
+		"unsupported consensus type migration from solo to etcdraft"))
+}
+
+func TestVerifyConsensusMigrationUnchangedType(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, _ := baseConsensusMigration(t)
+	err := c.Orderer().SetConsensusState(orderer.ConsensusStateNormal)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.VerifyConsensusMigration()
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestVerifyConsensusMigrationInvalidMetadata(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, _ := baseConsensusMigration(t)
+	ordererGroup := c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey]
+	err := setValue(ordererGroup, consensusTypeValue(orderer.ConsensusTypeEtcdRaft, []byte("invalid"), int32(ob.ConsensusType_STATE_MAINTENANCE)), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	delete(ordererGroup.Values, orderer.KafkaBrokersKey)
+
+	err = c.VerifyConsensusMigration()
+	gt.Expect(err).To(MatchError(HavePrefix("invalid etcdraft metadata: unmarshaling etcd raft metadata: ")))
+}
+
+// baseConsensusMigration returns a config with a kafka orderer in
+// maintenance mode and valid etcdraft metadata to migrate it to.
+func baseConsensusMigration(t *testing.T) (ConfigTx, orderer.EtcdRaft) {
+	gt := NewGomegaWithT(t)
+
+	kafkaOrderer, _ := baseKafkaOrderer(t)
+	kafkaOrderer.State = orderer.ConsensusStateMaintenance
+	ordererGroup, err := NewOrdererGroup(kafkaOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaft := etcdRaftOrderer.EtcdRaft
+	etcdRaft.Options = orderer.EtcdRaftOptions{
+		TickInterval:         "500ms",
+		ElectionTick:         10,
+		HeartbeatTick:        1,
+		MaxInflightBlocks:    5,
+		SnapshotIntervalSize: 16 * 1024 * 1024,
+	}
+
+	return c, etcdRaft
+}
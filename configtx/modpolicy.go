@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ModPolicyEntry is the mod policy of a config element.
+type ModPolicyEntry struct {
+	// Path is the config path of the element in the form taken by
+	// SetModPolicyAt, e.g. /Channel/Orderer/Values/BatchSize.
+	Path      string
+	Element   ElementType
+	ModPolicy string
+}
+
+// ModPolicies lists the mod policies of the group at path, e.g.
+// []string{"Channel", "Orderer"}, and of its values and policies in the
+// updated config. The group comes first, followed by its values and
+// policies in lexical order.
+func (c *ConfigTx) ModPolicies(path []string) ([]ModPolicyEntry, error) {
+	if len(path) == 0 || path[0] != ChannelGroupKey {
+		return nil, fmt.Errorf("path must start with %s", ChannelGroupKey)
+	}
+
+	group, err := configGroupAtPath(c.updated.ChannelGroup, path[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	groupPath := configPath(path...)
+	entries := []ModPolicyEntry{{Path: groupPath, Element: ElementGroup, ModPolicy: group.ModPolicy}}
+
+	for _, name := range sortedKeys(group.Values) {
+		entries = append(entries, ModPolicyEntry{Path: elementPath(groupPath, ElementValue, name), Element: ElementValue, ModPolicy: group.Values[name].ModPolicy})
+	}
+	for _, name := range sortedKeys(group.Policies) {
+		entries = append(entries, ModPolicyEntry{Path: elementPath(groupPath, ElementPolicy, name), Element: ElementPolicy, ModPolicy: group.Policies[name].ModPolicy})
+	}
+
+	return entries, nil
+}
+
+// ModPolicy returns the mod policy of the element of the given type at
+// path in the updated config. The path of a value or policy ends with its
+// name, e.g. []string{"Channel", "Orderer", "BatchSize"}.
+func (c *ConfigTx) ModPolicy(element ElementType, path []string) (string, error) {
+	modPolicy, _, err := c.modPolicyAt(element, path)
+	if err != nil {
+		return "", err
+	}
+
+	return *modPolicy, nil
+}
+
+// SetModPolicy sets the mod policy of the element of the given type at
+// path in the updated config, e.g. to tighten who can change the batch
+// size of the orderer. A warning is emitted if the mod policy does not
+// resolve to a policy of the config, as the element could then no longer
+// be modified.
+func (c *ConfigTx) SetModPolicy(element ElementType, path []string, modPolicy string) error {
+	if modPolicy == "" {
+		return errors.New("mod policy is required")
+	}
+
+	current, basePath, err := c.modPolicyAt(element, path)
+	if err != nil {
+		return err
+	}
+
+	*current = modPolicy
+
+	c.notify(basePath, "SetModPolicy")
+
+	elementPath := configPath(path...)
+	_, err = c.options.evaluator().Resolve(c.updated.ChannelGroup, absolutePolicyPath(basePath, modPolicy))
+	if err != nil {
+		c.warn(Warning{
+			Path:    elementPath,
+			Message: fmt.Sprintf("mod policy %s cannot be resolved: %v", modPolicy, err),
+		})
+	}
+
+	return nil
+}
+
+// ModPolicyAt returns the mod policy of the element at path in the updated
+// config. See SetModPolicyAt for the form of the path.
+func (c *ConfigTx) ModPolicyAt(path string) (string, error) {
+	element, elements, err := parseConfigPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	return c.ModPolicy(element, append([]string{ChannelGroupKey}, elements...))
+}
+
+// SetModPolicyAt sets the mod policy of the element at path in the updated
+// config, e.g. to delegate modification of the application group to a
+// custom policy. The path addresses a group by its path, e.g.
+// /Channel/Application, a value like a value path, e.g.
+// /Channel/Orderer/Values/BatchSize, and a policy by the path of its group
+// and its name, e.g. /Channel/Application/Policies/Admins.
+func (c *ConfigTx) SetModPolicyAt(path string, modPolicy string) error {
+	element, elements, err := parseConfigPath(path)
+	if err != nil {
+		return err
+	}
+
+	return c.SetModPolicy(element, append([]string{ChannelGroupKey}, elements...), modPolicy)
+}
+
+// modPolicyAt returns the mod policy field of the element of the given
+// type at path, and the path of the group its mod policy is resolved
+// against: the group itself for groups and the enclosing group for values
+// and policies.
+func (c *ConfigTx) modPolicyAt(element ElementType, path []string) (*string, string, error) {
+	if len(path) == 0 || path[0] != ChannelGroupKey {
+		return nil, "", fmt.Errorf("path must start with %s", ChannelGroupKey)
+	}
+
+	if element == ElementGroup {
+		group, err := configGroupAtPath(c.updated.ChannelGroup, path[1:])
+		if err != nil {
+			return nil, "", err
+		}
+
+		return &group.ModPolicy, configPath(path...), nil
+	}
+
+	if len(path) < 2 {
+		return nil, "", fmt.Errorf("path of %s must contain its group and name", element)
+	}
+
+	group, err := configGroupAtPath(c.updated.ChannelGroup, path[1:len(path)-1])
+	if err != nil {
+		return nil, "", err
+	}
+
+	groupPath := configPath(path[:len(path)-1]...)
+	name := path[len(path)-1]
+
+	switch element {
+	case ElementValue:
+		value, ok := group.Values[name]
+		if !ok {
+			return nil, "", fmt.Errorf("value %s does not exist", configPath(path...))
+		}
+
+		return &value.ModPolicy, groupPath, nil
+	case ElementPolicy:
+		policy, ok := group.Policies[name]
+		if !ok {
+			return nil, "", fmt.Errorf("policy %s does not exist", configPath(path...))
+		}
+
+		return &policy.ModPolicy, groupPath, nil
+	default:
+		return nil, "", fmt.Errorf("unknown element type '%s'", element)
+	}
+}
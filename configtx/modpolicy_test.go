@@ -0,0 +1,302 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestModPolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	entries, err := c.ModPolicies([]string{"Channel", "Orderer"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(entries).To(Equal([]ModPolicyEntry{
+		{Path: "/Channel/Orderer", Element: ElementGroup, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Values/BatchSize", Element: ElementValue, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Values/BatchTimeout", Element: ElementValue, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Values/Capabilities", Element: ElementValue, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Values/ChannelRestrictions", Element: ElementValue, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Values/ConsensusType", Element: ElementValue, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Policies/Admins", Element: ElementPolicy, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Policies/BlockValidation", Element: ElementPolicy, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Policies/Readers", Element: ElementPolicy, ModPolicy: "Admins"},
+		{Path: "/Channel/Orderer/Policies/Writers", Element: ElementPolicy, ModPolicy: "Admins"},
+	}))
+}
+
+func TestSetModPolicy(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var warnings []Warning
+	var mutations []Mutation
+	c := New(&cb.Config{ChannelGroup: channelGroup},
+		WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }),
+		WithObserver(func(m Mutation) { mutations = append(mutations, m) }),
+	)
+
+	batchSizePath := []string{"Channel", "Orderer", "BatchSize"}
+	err = c.SetModPolicy(ElementValue, batchSizePath, "/Channel/Orderer/BlockValidation")
+	gt.Expect(err).NotTo(HaveOccurred())
+	modPolicy, err := c.ModPolicy(ElementValue, batchSizePath)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal("/Channel/Orderer/BlockValidation"))
+
+	err = c.SetModPolicy(ElementPolicy, []string{"Channel", "Orderer", "Writers"}, "Writers")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Policies["Writers"].ModPolicy).To(Equal("Writers"))
+
+	err = c.SetModPolicy(ElementGroup, []string{"Channel", "Orderer"}, "Writers")
+	gt.Expect(err).NotTo(HaveOccurred())
+	modPolicy, err = c.ModPolicy(ElementGroup, []string{"Channel", "Orderer"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal("Writers"))
+
+	gt.Expect(warnings).To(BeEmpty())
+	gt.Expect(mutations).To(Equal([]Mutation{
+		{Path: "/Channel/Orderer", Operation: "SetModPolicy"},
+		{Path: "/Channel/Orderer", Operation: "SetModPolicy"},
+		{Path: "/Channel/Orderer", Operation: "SetModPolicy"},
+	}))
+
+	err = c.SetModPolicy(ElementValue, batchSizePath, "Missing")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).To(Equal([]Warning{{
+		Path:    "/Channel/Orderer/BatchSize",
+		Message: "mod policy Missing cannot be resolved: policy /Channel/Orderer/Missing does not exist",
+	}}))
+}
+
+func TestModPolicyFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		element     ElementType
+		path        []string
+		modPolicy   string
+		expectedErr string
+	}{
+		{
+			testName:    "when the path does not start with the channel group",
+			element:     ElementGroup,
+			path:        []string{"Orderer"},
+			modPolicy:   "Admins",
+			expectedErr: "path must start with Channel",
+		},
+		{
+			testName:    "when the group does not exist",
+			element:     ElementGroup,
+			path:        []string{"Channel", "Application"},
+			modPolicy:   "Admins",
+			expectedErr: "config group /Channel/Application does not exist",
+		},
+		{
+			testName:    "when the value does not exist",
+			element:     ElementValue,
+			path:        []string{"Channel", "Orderer", "KafkaBrokers"},
+			modPolicy:   "Admins",
+			expectedErr: "value /Channel/Orderer/KafkaBrokers does not exist",
+		},
+		{
+			testName:    "when the policy does not exist",
+			element:     ElementPolicy,
+			path:        []string{"Channel", "Orderer", "Endorsement"},
+			modPolicy:   "Admins",
+			expectedErr: "policy /Channel/Orderer/Endorsement does not exist",
+		},
+		{
+			testName:    "when the path of a value does not contain its name",
+			element:     ElementValue,
+			path:        []string{"Channel"},
+			modPolicy:   "Admins",
+			expectedErr: "path of value must contain its group and name",
+		},
+		{
+			testName:    "when the element type is unknown",
+			element:     "acl",
+			path:        []string{"Channel", "Orderer", "BatchSize"},
+			modPolicy:   "Admins",
+			expectedErr: "unknown element type 'acl'",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			_, err = c.ModPolicy(tc.element, tc.path)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+
+			err = c.SetModPolicy(tc.element, tc.path, tc.modPolicy)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+
+	t.Run("when the mod policy is empty", func(t *testing.T) {
+		t.Parallel()
+		gt := NewGomegaWithT(t)
+
+		c := New(&cb.Config{ChannelGroup: newConfigGroup()})
+
+		err := c.SetModPolicy(ElementGroup, []string{"Channel"}, "")
+		gt.Expect(err).To(MatchError("mod policy is required"))
+	})
+}
+
+func TestSetModPolicyAt(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.SetModPolicyAt("/Channel/Orderer", "Writers")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.SetModPolicyAt("/Channel/Orderer/Values/BatchSize", "/Channel/Orderer/BlockValidation")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.SetModPolicyAt("/Channel/Orderer/Policies/Writers", "Writers")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererGroup := c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey]
+	gt.Expect(ordererGroup.ModPolicy).To(Equal("Writers"))
+	gt.Expect(ordererGroup.Values["BatchSize"].ModPolicy).To(Equal("/Channel/Orderer/BlockValidation"))
+	gt.Expect(ordererGroup.Policies[WritersPolicyKey].ModPolicy).To(Equal("Writers"))
+
+	modPolicy, err := c.ModPolicyAt("/Channel/Orderer/Values/BatchSize")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal("/Channel/Orderer/BlockValidation"))
+
+	modPolicy, err = c.ModPolicyAt("/Channel/Orderer/Policies/Admins")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal("Admins"))
+}
+
+func TestSetModPolicyAtUndo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName     string
+		path         string
+		expectedPath string
+	}{
+		{
+			testName:     "when the mod policy of a value is set",
+			path:         "/Channel/Orderer/Values/BatchSize",
+			expectedPath: "/Channel/Orderer",
+		},
+		{
+			testName:     "when the mod policy of a policy is set",
+			path:         "/Channel/Orderer/Policies/Writers",
+			expectedPath: "/Channel/Orderer",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			var mutations []Mutation
+			c := New(&cb.Config{ChannelGroup: channelGroup}, EnableUndo(0), WithObserver(func(m Mutation) {
+				mutations = append(mutations, m)
+			}))
+			original := proto.Clone(c.UpdatedConfig()).(*cb.Config)
+
+			err = c.SetModPolicyAt(tc.path, "Writers")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(mutations).To(Equal([]Mutation{{Path: tc.expectedPath, Operation: "SetModPolicy"}}))
+			updated := proto.Clone(c.UpdatedConfig()).(*cb.Config)
+
+			gt.Expect(c.CanUndo()).To(BeTrue())
+			gt.Expect(c.Undo()).To(Succeed())
+			gt.Expect(proto.Equal(c.UpdatedConfig(), original)).To(BeTrue())
+
+			gt.Expect(c.Redo()).To(Succeed())
+			gt.Expect(proto.Equal(c.UpdatedConfig(), updated)).To(BeTrue())
+		})
+	}
+}
+
+func TestSetModPolicyAtFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		path        string
+		expectedErr string
+	}{
+		{
+			testName:    "when the path is relative",
+			path:        "Channel/Orderer",
+			expectedErr: "invalid config path 'Channel/Orderer': must be an absolute config path",
+		},
+		{
+			testName:    "when the path contains an empty element",
+			path:        "/Channel//Orderer",
+			expectedErr: "invalid config path '/Channel//Orderer': empty path element",
+		},
+		{
+			testName:    "when the group does not exist",
+			path:        "/Channel/Application",
+			expectedErr: "config group /Channel/Application does not exist",
+		},
+		{
+			testName:    "when the value does not exist",
+			path:        "/Channel/Orderer/Values/KafkaBrokers",
+			expectedErr: "value /Channel/Orderer/KafkaBrokers does not exist",
+		},
+		{
+			testName:    "when the policy does not exist",
+			path:        "/Channel/Orderer/Policies/Endorsement",
+			expectedErr: "policy /Channel/Orderer/Endorsement does not exist",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			_, err = c.ModPolicyAt(tc.path)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+
+			err = c.SetModPolicyAt(tc.path, "Admins")
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
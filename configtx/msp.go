@@ -78,16 +78,19 @@ const YEAR = 365 * 24 * time.Hour
 // OrganizationMSP encapsulates the configuration functions used to modify an organization MSP.
 type OrganizationMSP struct {
 	configGroup *cb.ConfigGroup
+	// path is the config path of the organization group
+	path string
+	tx   *ConfigTx
 }
 
 // Configuration returns the MSP value for a organization in the updated config.
 func (m *OrganizationMSP) Configuration() (MSP, error) {
-	return getMSPConfig(m.configGroup)
+	return getMSPConfig(m.configGroup, m.tx.certificates())
 }
 
 // AddAdminCert adds an administator identity to the organization MSP.
 func (m *OrganizationMSP) AddAdminCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -100,12 +103,21 @@ func (m *OrganizationMSP) AddAdminCert(cert *x509.Certificate) error {
 
 	msp.Admins = append(msp.Admins, cert)
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddAdminCert")
+
+	m.tx.checkAdminCerts(m.path, msp)
+
+	return nil
 }
 
 // RemoveAdminCert removes an administator identity from the organization MSP.
 func (m *OrganizationMSP) RemoveAdminCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -120,12 +132,19 @@ func (m *OrganizationMSP) RemoveAdminCert(cert *x509.Certificate) error {
 
 	msp.Admins = certs
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "RemoveAdminCert")
+
+	return nil
 }
 
 // AddRootCert adds a root certificate trusted by the organization MSP.
 func (m *OrganizationMSP) AddRootCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -143,12 +162,19 @@ func (m *OrganizationMSP) AddRootCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddRootCert")
+
+	return nil
 }
 
 // RemoveRootCert removes a trusted root certificate from the organization MSP.
 func (m *OrganizationMSP) RemoveRootCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -168,12 +194,19 @@ func (m *OrganizationMSP) RemoveRootCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "RemoveRootCert")
+
+	return nil
 }
 
 // AddIntermediateCert adds an intermediate certificate trusted by the organization MSP.
 func (m *OrganizationMSP) AddIntermediateCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -191,12 +224,19 @@ func (m *OrganizationMSP) AddIntermediateCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddIntermediateCert")
+
+	return nil
 }
 
 // RemoveIntermediateCert removes a trusted intermediate certificate from the organization MSP.
 func (m *OrganizationMSP) RemoveIntermediateCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -216,12 +256,19 @@ func (m *OrganizationMSP) RemoveIntermediateCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "RemoveIntermediateCert")
+
+	return nil
 }
 
 // AddOUIdentifier adds a custom organizational unit identifier to the organization MSP.
 func (m *OrganizationMSP) AddOUIdentifier(ou membership.OUIdentifier) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -234,12 +281,19 @@ func (m *OrganizationMSP) AddOUIdentifier(ou membership.OUIdentifier) error {
 
 	msp.OrganizationalUnitIdentifiers = append(msp.OrganizationalUnitIdentifiers, ou)
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddOUIdentifier")
+
+	return nil
 }
 
 // RemoveOUIdentifier removes an existing organizational unit identifier from the organization MSP.
 func (m *OrganizationMSP) RemoveOUIdentifier(ou membership.OUIdentifier) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -254,24 +308,38 @@ func (m *OrganizationMSP) RemoveOUIdentifier(ou membership.OUIdentifier) error {
 
 	msp.OrganizationalUnitIdentifiers = ous
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "RemoveOUIdentifier")
+
+	return nil
 }
 
 // SetCryptoConfig sets the configuration for the cryptographic algorithms for the organization MSP.
 func (m *OrganizationMSP) SetCryptoConfig(cryptoConfig membership.CryptoConfig) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.CryptoConfig = cryptoConfig
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "SetCryptoConfig")
+
+	return nil
 }
 
 // AddTLSRootCert adds a TLS root certificate trusted by the organization MSP.
 func (m *OrganizationMSP) AddTLSRootCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -289,12 +357,19 @@ func (m *OrganizationMSP) AddTLSRootCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddTLSRootCert")
+
+	return nil
 }
 
 // RemoveTLSRootCert removes a trusted TLS root certificate from the organization MSP.
 func (m *OrganizationMSP) RemoveTLSRootCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -314,12 +389,19 @@ func (m *OrganizationMSP) RemoveTLSRootCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "RemoveTLSRootCert")
+
+	return nil
 }
 
 // AddTLSIntermediateCert adds a TLS intermediate cert trusted by the organization MSP.
 func (m *OrganizationMSP) AddTLSIntermediateCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -337,12 +419,19 @@ func (m *OrganizationMSP) AddTLSIntermediateCert(cert *x509.Certificate) error {
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddTLSIntermediateCert")
+
+	return nil
 }
 
 // RemoveTLSIntermediateCert removes a trusted TLS intermediate cert from the organization MSP.
 func (m *OrganizationMSP) RemoveTLSIntermediateCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -362,86 +451,139 @@ func (m *OrganizationMSP) RemoveTLSIntermediateCert(cert *x509.Certificate) erro
 		return err
 	}
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "RemoveTLSIntermediateCert")
+
+	return nil
 }
 
 // SetClientOUIdentifier sets the NodeOUs client ou identifier for the organization MSP.
 func (m *OrganizationMSP) SetClientOUIdentifier(clientOU membership.OUIdentifier) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.NodeOUs.ClientOUIdentifier = clientOU
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "SetClientOUIdentifier")
+
+	return nil
 }
 
 // SetPeerOUIdentifier sets the NodeOUs peer ou identifier for the organization MSP.
 func (m *OrganizationMSP) SetPeerOUIdentifier(peerOU membership.OUIdentifier) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.NodeOUs.PeerOUIdentifier = peerOU
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "SetPeerOUIdentifier")
+
+	return nil
 }
 
 // SetAdminOUIdentifier sets the NodeOUs admin ou identifier for the organization MSP.
 func (m *OrganizationMSP) SetAdminOUIdentifier(adminOU membership.OUIdentifier) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.NodeOUs.AdminOUIdentifier = adminOU
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "SetAdminOUIdentifier")
+
+	m.tx.checkAdminCerts(m.path, msp)
+
+	return nil
 }
 
 // SetOrdererOUIdentifier sets the NodeOUs orderer ou identifier for the organization MSP.
 func (m *OrganizationMSP) SetOrdererOUIdentifier(ordererOU membership.OUIdentifier) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.NodeOUs.OrdererOUIdentifier = ordererOU
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "SetOrdererOUIdentifier")
+
+	return nil
 }
 
 // SetEnableNodeOUs sets the NodeOUs recognition, if NodeOUs recognition is enabled then an msp identity
 // that does not contain exactly one of the fabric Node OU Identifiers will be considered invalid.
 func (m *OrganizationMSP) SetEnableNodeOUs(isEnabled bool) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.NodeOUs.Enable = isEnabled
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "SetEnableNodeOUs")
+
+	m.tx.checkAdminCerts(m.path, msp)
+
+	return nil
 }
 
 // AddCRL adds a CRL to the identity revocation list for the organization MSP.
 func (m *OrganizationMSP) AddCRL(crl *pkix.CertificateList) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
 
 	msp.RevocationList = append(msp.RevocationList, crl)
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddCRL")
+
+	return nil
 }
 
 // AddCRLFromSigningIdentity creates a CRL from the provided signing identity and associated certs and then adds the CRL to
 // the identity revocation list for the organization MSP.
 func (m *OrganizationMSP) AddCRLFromSigningIdentity(signingIdentity *SigningIdentity, certs ...*x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
+	msp, err := getMSPConfig(m.configGroup, m.tx.certificates())
 	if err != nil {
 		return err
 	}
@@ -452,7 +594,14 @@ func (m *OrganizationMSP) AddCRLFromSigningIdentity(signingIdentity *SigningIden
 	}
 	msp.RevocationList = append(msp.RevocationList, crl)
 
-	return msp.setConfig(m.configGroup)
+	err = msp.setConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	m.tx.notify(m.path, "AddCRLFromSigningIdentity")
+
+	return nil
 }
 
 // CreateMSPCRL creates a CRL that revokes the provided certificates
@@ -529,7 +678,7 @@ func (m *MSP) isCACert(signingCert *x509.Certificate) error {
 func (m *MSP) setConfig(configGroup *cb.ConfigGroup) error {
 	mspConfig, err := newMSPConfig(*m)
 	if err != nil {
-		return fmt.Errorf("new msp config: %v", err)
+		return fmt.Errorf("new msp config: %w", err)
 	}
 
 	err = setValue(configGroup, mspValue(mspConfig), AdminsPolicyKey)
@@ -542,37 +691,55 @@ func (m *MSP) setConfig(configGroup *cb.ConfigGroup) error {
 
 // getMSPConfig parses the MSP value in a config group returns
 // the configuration as an MSP type.
-func getMSPConfig(configGroup *cb.ConfigGroup) (MSP, error) {
+func getMSPConfig(configGroup *cb.ConfigGroup, interner *certificateInterner) (MSP, error) {
+	fabricMSPConfig, err := getFabricMSPConfig(configGroup)
+	if err != nil {
+		return MSP{}, err
+	}
+
+	return mspFromProto(fabricMSPConfig, interner)
+}
+
+// getFabricMSPConfig unmarshals the MSP value in a config group without
+// parsing its certificates.
+func getFabricMSPConfig(configGroup *cb.ConfigGroup) (*mb.FabricMSPConfig, error) {
 	mspValueProto := &mb.MSPConfig{}
 
 	err := unmarshalConfigValueAtKey(configGroup, MSPKey, mspValueProto)
 	if err != nil {
-		return MSP{}, err
+		return nil, err
 	}
 
 	fabricMSPConfig := &mb.FabricMSPConfig{}
 
 	err = proto.Unmarshal(mspValueProto.Config, fabricMSPConfig)
 	if err != nil {
-		return MSP{}, fmt.Errorf("unmarshaling fabric msp config: %v", err)
+		return nil, fmt.Errorf("unmarshaling fabric msp config: %w", err)
 	}
 
+	return fabricMSPConfig, nil
+}
+
+// mspFromProto converts an mb.FabricMSPConfig proto to an MSP
+// configuration. It parses the pem encoded x509 certificates, interning
+// them with the interner, and CRLs.
+func mspFromProto(fabricMSPConfig *mb.FabricMSPConfig, interner *certificateInterner) (MSP, error) {
 	// ROOT CERTS
-	rootCerts, err := parseCertificateListFromBytes(fabricMSPConfig.RootCerts)
+	rootCerts, err := parseCertificateListFromBytes(fabricMSPConfig.RootCerts, interner)
 	if err != nil {
-		return MSP{}, fmt.Errorf("parsing root certs: %v", err)
+		return MSP{}, fmt.Errorf("parsing root certs: %w", err)
 	}
 
 	// INTERMEDIATE CERTS
-	intermediateCerts, err := parseCertificateListFromBytes(fabricMSPConfig.IntermediateCerts)
+	intermediateCerts, err := parseCertificateListFromBytes(fabricMSPConfig.IntermediateCerts, interner)
 	if err != nil {
-		return MSP{}, fmt.Errorf("parsing intermediate certs: %v", err)
+		return MSP{}, fmt.Errorf("parsing intermediate certs: %w", err)
 	}
 
 	// ADMIN CERTS
-	adminCerts, err := parseCertificateListFromBytes(fabricMSPConfig.Admins)
+	adminCerts, err := parseCertificateListFromBytes(fabricMSPConfig.Admins, interner)
 	if err != nil {
-		return MSP{}, fmt.Errorf("parsing admin certs: %v", err)
+		return MSP{}, fmt.Errorf("parsing admin certs: %w", err)
 	}
 
 	// REVOCATION LIST
@@ -582,63 +749,63 @@ func getMSPConfig(configGroup *cb.ConfigGroup) (MSP, error) {
 	}
 
 	// OU IDENTIFIERS
-	ouIdentifiers, err := parseOUIdentifiers(fabricMSPConfig.OrganizationalUnitIdentifiers)
+	ouIdentifiers, err := parseOUIdentifiers(fabricMSPConfig.OrganizationalUnitIdentifiers, interner)
 	if err != nil {
-		return MSP{}, fmt.Errorf("parsing ou identifiers: %v", err)
+		return MSP{}, fmt.Errorf("parsing ou identifiers: %w", err)
 	}
 
 	// TLS ROOT CERTS
-	tlsRootCerts, err := parseCertificateListFromBytes(fabricMSPConfig.TlsRootCerts)
+	tlsRootCerts, err := parseCertificateListFromBytes(fabricMSPConfig.TlsRootCerts, interner)
 	if err != nil {
-		return MSP{}, fmt.Errorf("parsing tls root certs: %v", err)
+		return MSP{}, fmt.Errorf("parsing tls root certs: %w", err)
 	}
 
 	// TLS INTERMEDIATE CERTS
-	tlsIntermediateCerts, err := parseCertificateListFromBytes(fabricMSPConfig.TlsIntermediateCerts)
+	tlsIntermediateCerts, err := parseCertificateListFromBytes(fabricMSPConfig.TlsIntermediateCerts, interner)
 	if err != nil {
-		return MSP{}, fmt.Errorf("parsing tls intermediate certs: %v", err)
+		return MSP{}, fmt.Errorf("parsing tls intermediate certs: %w", err)
 	}
 
 	// NODE OUS
 	nodeOUs := membership.NodeOUs{}
 	if fabricMSPConfig.FabricNodeOus != nil {
-		clientOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.ClientOuIdentifier.Certificate)
+		clientOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.GetClientOuIdentifier().GetCertificate(), interner)
 		if err != nil {
-			return MSP{}, fmt.Errorf("parsing client ou identifier cert: %v", err)
+			return MSP{}, fmt.Errorf("parsing client ou identifier cert: %w", err)
 		}
 
-		peerOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.PeerOuIdentifier.Certificate)
+		peerOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.GetPeerOuIdentifier().GetCertificate(), interner)
 		if err != nil {
-			return MSP{}, fmt.Errorf("parsing peer ou identifier cert: %v", err)
+			return MSP{}, fmt.Errorf("parsing peer ou identifier cert: %w", err)
 		}
 
-		adminOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.AdminOuIdentifier.Certificate)
+		adminOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.GetAdminOuIdentifier().GetCertificate(), interner)
 		if err != nil {
-			return MSP{}, fmt.Errorf("parsing admin ou identifier cert: %v", err)
+			return MSP{}, fmt.Errorf("parsing admin ou identifier cert: %w", err)
 		}
 
-		ordererOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.OrdererOuIdentifier.Certificate)
+		ordererOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.GetOrdererOuIdentifier().GetCertificate(), interner)
 		if err != nil {
-			return MSP{}, fmt.Errorf("parsing orderer ou identifier cert: %v", err)
+			return MSP{}, fmt.Errorf("parsing orderer ou identifier cert: %w", err)
 		}
 
 		nodeOUs = membership.NodeOUs{
 			Enable: fabricMSPConfig.FabricNodeOus.Enable,
 			ClientOUIdentifier: membership.OUIdentifier{
 				Certificate:                  clientOUIdentifierCert,
-				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.ClientOuIdentifier.OrganizationalUnitIdentifier,
+				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.GetClientOuIdentifier().GetOrganizationalUnitIdentifier(),
 			},
 			PeerOUIdentifier: membership.OUIdentifier{
 				Certificate:                  peerOUIdentifierCert,
-				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.PeerOuIdentifier.OrganizationalUnitIdentifier,
+				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.GetPeerOuIdentifier().GetOrganizationalUnitIdentifier(),
 			},
 			AdminOUIdentifier: membership.OUIdentifier{
 				Certificate:                  adminOUIdentifierCert,
-				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.AdminOuIdentifier.OrganizationalUnitIdentifier,
+				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.GetAdminOuIdentifier().GetOrganizationalUnitIdentifier(),
 			},
 			OrdererOUIdentifier: membership.OUIdentifier{
 				Certificate:                  ordererOUIdentifierCert,
-				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.OrdererOuIdentifier.OrganizationalUnitIdentifier,
+				OrganizationalUnitIdentifier: fabricMSPConfig.FabricNodeOus.GetOrdererOuIdentifier().GetOrganizationalUnitIdentifier(),
 			},
 		}
 	}
@@ -651,8 +818,8 @@ func getMSPConfig(configGroup *cb.ConfigGroup) (MSP, error) {
 		RevocationList:                revocationList,
 		OrganizationalUnitIdentifiers: ouIdentifiers,
 		CryptoConfig: membership.CryptoConfig{
-			SignatureHashFamily:            fabricMSPConfig.CryptoConfig.SignatureHashFamily,
-			IdentityIdentifierHashFunction: fabricMSPConfig.CryptoConfig.IdentityIdentifierHashFunction,
+			SignatureHashFamily:            fabricMSPConfig.GetCryptoConfig().GetSignatureHashFamily(),
+			IdentityIdentifierHashFunction: fabricMSPConfig.GetCryptoConfig().GetIdentityIdentifierHashFunction(),
 		},
 		TLSRootCerts:         tlsRootCerts,
 		TLSIntermediateCerts: tlsIntermediateCerts,
@@ -660,11 +827,11 @@ func getMSPConfig(configGroup *cb.ConfigGroup) (MSP, error) {
 	}, nil
 }
 
-func parseCertificateListFromBytes(certs [][]byte) ([]*x509.Certificate, error) {
+func parseCertificateListFromBytes(certs [][]byte, interner *certificateInterner) ([]*x509.Certificate, error) {
 	certificateList := []*x509.Certificate{}
 
 	for _, cert := range certs {
-		certificate, err := parseCertificateFromBytes(cert)
+		certificate, err := parseCertificateFromBytes(cert, interner)
 		if err != nil {
 			return certificateList, err
 		}
@@ -675,13 +842,13 @@ func parseCertificateListFromBytes(certs [][]byte) ([]*x509.Certificate, error)
 	return certificateList, nil
 }
 
-func parseCertificateFromBytes(cert []byte) (*x509.Certificate, error) {
+func parseCertificateFromBytes(cert []byte, interner *certificateInterner) (*x509.Certificate, error) {
 	pemBlock, _ := pem.Decode(cert)
 	if pemBlock == nil {
 		return &x509.Certificate{}, fmt.Errorf("no PEM data found in cert[% x]", cert)
 	}
 
-	certificate, err := x509.ParseCertificate(pemBlock.Bytes)
+	certificate, err := interner.parse(pemBlock.Bytes)
 	if err != nil {
 		return &x509.Certificate{}, err
 	}
@@ -700,7 +867,7 @@ func parseCRL(crls [][]byte) ([]*pkix.CertificateList, error) {
 
 		certificateList, err := x509.ParseCRL(pemBlock.Bytes)
 		if err != nil {
-			return certificateLists, fmt.Errorf("parsing crl: %v", err)
+			return certificateLists, fmt.Errorf("parsing crl: %w", err)
 		}
 
 		certificateLists = append(certificateLists, certificateList)
@@ -721,17 +888,17 @@ func parsePrivateKeyFromBytes(priv []byte) (crypto.PrivateKey, error) {
 
 	privateKey, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
 	if err != nil {
-		return nil, fmt.Errorf("failed parsing PKCS#8 private key: %v", err)
+		return nil, fmt.Errorf("failed parsing PKCS#8 private key: %w", err)
 	}
 
 	return privateKey, nil
 }
 
-func parseOUIdentifiers(identifiers []*mb.FabricOUIdentifier) ([]membership.OUIdentifier, error) {
+func parseOUIdentifiers(identifiers []*mb.FabricOUIdentifier, interner *certificateInterner) ([]membership.OUIdentifier, error) {
 	fabricIdentifiers := []membership.OUIdentifier{}
 
 	for _, identifier := range identifiers {
-		cert, err := parseCertificateFromBytes(identifier.Certificate)
+		cert, err := parseCertificateFromBytes(identifier.Certificate, interner)
 		if err != nil {
 			return fabricIdentifiers, err
 		}
@@ -752,7 +919,7 @@ func parseOUIdentifiers(identifiers []*mb.FabricOUIdentifier) ([]membership.OUId
 func (m *MSP) toProto() (*mb.FabricMSPConfig, error) {
 	revocationList, err := buildPemEncodedRevocationList(m.RevocationList)
 	if err != nil {
-		return nil, fmt.Errorf("building pem encoded revocation list: %v", err)
+		return nil, fmt.Errorf("building pem encoded revocation list: %w", err)
 	}
 
 	ouIdentifiers := buildOUIdentifiers(m.OrganizationalUnitIdentifiers)
@@ -854,7 +1021,7 @@ func pemEncodeX509Certificate(cert *x509.Certificate) []byte {
 func pemEncodePKCS8PrivateKey(priv crypto.PrivateKey) ([]byte, error) {
 	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling PKCS#8 private key: %v", err)
+		return nil, fmt.Errorf("marshaling PKCS#8 private key: %w", err)
 	}
 
 	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}), nil
@@ -869,7 +1036,7 @@ func newMSPConfig(updatedMSP MSP) (*mb.MSPConfig, error) {
 
 	conf, err := proto.Marshal(fabricMSPConfig)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling msp config: %v", err)
+		return nil, fmt.Errorf("marshaling msp config: %w", err)
 	}
 
 	mspConfig := &mb.MSPConfig{
@@ -879,15 +1046,17 @@ func newMSPConfig(updatedMSP MSP) (*mb.MSPConfig, error) {
 	return mspConfig, nil
 }
 
+// validateCACerts checks the CA certificates of the MSP and returns
+// ValidationErrors with a finding for each invalid certificate.
 func (m *MSP) validateCACerts() error {
-	err := validateCACerts(m.RootCerts)
-	if err != nil {
-		return fmt.Errorf("invalid root cert: %v", err)
+	var findings ValidationErrors
+
+	for _, err := range validateCACerts(m.RootCerts) {
+		findings = append(findings, fmt.Errorf("invalid root cert: %w", err))
 	}
 
-	err = validateCACerts(m.IntermediateCerts)
-	if err != nil {
-		return fmt.Errorf("invalid intermediate cert: %v", err)
+	for _, err := range validateCACerts(m.IntermediateCerts) {
+		findings = append(findings, fmt.Errorf("invalid intermediate cert: %w", err))
 	}
 	//TODO: follow the workaround that msp code use to incorporate cert.Verify()
 	for _, ic := range m.IntermediateCerts {
@@ -900,33 +1069,75 @@ func (m *MSP) validateCACerts() error {
 			}
 		}
 		if !validIntermediateCert {
-			return fmt.Errorf("intermediate cert not signed by any root certs of this MSP. serial number: %d", ic.SerialNumber)
+			findings = append(findings, fmt.Errorf("intermediate cert not signed by any root certs of this MSP. serial number: %d", ic.SerialNumber))
 		}
 	}
 
-	err = validateCACerts(m.TLSRootCerts)
-	if err != nil {
-		return fmt.Errorf("invalid tls root cert: %v", err)
+	for _, err := range validateCACerts(m.TLSRootCerts) {
+		findings = append(findings, fmt.Errorf("invalid tls root cert: %w", err))
 	}
 
-	err = validateCACerts(m.TLSIntermediateCerts)
-	if err != nil {
-		return fmt.Errorf("invalid tls intermediate cert: %v", err)
+	for _, err := range validateCACerts(m.TLSIntermediateCerts) {
+		findings = append(findings, fmt.Errorf("invalid tls intermediate cert: %w", err))
+	}
+
+	return findings.err()
+}
+
+// validateValidityPeriods checks the validity periods of the CA
+// certificates of the MSP as of at and returns ValidationErrors with a
+// finding for each certificate that is not valid at that time.
+func (m *MSP) validateValidityPeriods(at time.Time) error {
+	var findings ValidationErrors
+
+	for _, certs := range []struct {
+		name  string
+		certs []*x509.Certificate
+	}{
+		{"root cert", m.RootCerts},
+		{"intermediate cert", m.IntermediateCerts},
+		{"tls root cert", m.TLSRootCerts},
+		{"tls intermediate cert", m.TLSIntermediateCerts},
+	} {
+		for _, cert := range certs.certs {
+			err := checkValidityPeriod(cert, at)
+			if err != nil {
+				findings = append(findings, fmt.Errorf("invalid %s: %w", certs.name, err))
+			}
+		}
+	}
+
+	return findings.err()
+}
+
+// checkValidityPeriod returns an error if the certificate is not valid at
+// at.
+func checkValidityPeriod(cert *x509.Certificate, at time.Time) error {
+	if at.Before(cert.NotBefore) {
+		return fmt.Errorf("not valid before %s. serial number: %d", cert.NotBefore.UTC().Format(time.RFC3339), cert.SerialNumber)
+	}
+
+	if at.After(cert.NotAfter) {
+		return fmt.Errorf("expired at %s. serial number: %d", cert.NotAfter.UTC().Format(time.RFC3339), cert.SerialNumber)
 	}
 
 	return nil
 }
 
-func validateCACerts(caCerts []*x509.Certificate) error {
+// validateCACerts returns an error for each certificate that is not a CA
+// certificate.
+func validateCACerts(caCerts []*x509.Certificate) []error {
+	var errs []error
 	for _, caCert := range caCerts {
 		if (caCert.KeyUsage & x509.KeyUsageCertSign) == 0 {
-			return fmt.Errorf("KeyUsage must be x509.KeyUsageCertSign. serial number: %d", caCert.SerialNumber)
+			errs = append(errs, fmt.Errorf("KeyUsage must be x509.KeyUsageCertSign. serial number: %d", caCert.SerialNumber))
+			continue
 		}
 
 		if !caCert.IsCA {
-			return fmt.Errorf("must be a CA certificate. serial number: %d", caCert.SerialNumber)
+			errs = append(errs, fmt.Errorf("must be a CA certificate. serial number: %d", caCert.SerialNumber))
 		}
 	}
 
-	return nil
+	return errs
 }
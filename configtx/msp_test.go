@@ -140,15 +140,15 @@ func TestMSPConfigurationFailures(t *testing.T) {
 			gt := NewGomegaWithT(t)
 
 			consortiums, _ := baseConsortiums(t)
-			consortiumsGroup, err := newConsortiumsGroup(consortiums)
+			consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			orderer, _ := baseSoloOrderer(t)
-			ordererGroup, err := newOrdererGroup(orderer)
+			ordererGroup, err := NewOrdererGroup(orderer)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			application, _ := baseApplication(t)
-			applicationGroup, err := newApplicationGroup(application)
+			applicationGroup, err := NewApplicationGroup(application)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			config := &cb.Config{
@@ -333,11 +333,11 @@ func TestParseCertificateFromBytesFailure(t *testing.T) {
 -----END CERTIFICATE-----
 `
 
-	_, err := parseCertificateFromBytes([]byte(errCert))
+	_, err := parseCertificateFromBytes([]byte(errCert), nil)
 	gt.Expect(err).NotTo(BeNil())
 	gt.Expect(err.Error()).To(ContainSubstring("no PEM data found in cert["))
 
-	_, err = parseCertificateFromBytes(nil)
+	_, err = parseCertificateFromBytes(nil, nil)
 	gt.Expect(err).To(MatchError("no PEM data found in cert[]"))
 }
 
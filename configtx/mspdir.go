@@ -0,0 +1,419 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	"gopkg.in/yaml.v2"
+)
+
+// Names of the files and directories of a local MSP directory, as
+// created by cryptogen or the Fabric CA client.
+const (
+	mspCACertsDir              = "cacerts"
+	mspIntermediateCertsDir    = "intermediatecerts"
+	mspAdminCertsDir           = "admincerts"
+	mspTLSCACertsDir           = "tlscacerts"
+	mspTLSIntermediateCertsDir = "tlsintermediatecerts"
+	mspCRLsDir                 = "crls"
+	mspSignCertsDir            = "signcerts"
+	mspKeyStoreDir             = "keystore"
+	mspConfigFile              = "config.yaml"
+)
+
+// mspDirConfig is the config.yaml file of a local MSP directory.
+type mspDirConfig struct {
+	OrganizationalUnitIdentifiers []*mspDirOUIdentifier `yaml:"OrganizationalUnitIdentifiers,omitempty"`
+	NodeOUs                       *mspDirNodeOUs        `yaml:"NodeOUs,omitempty"`
+}
+
+type mspDirOUIdentifier struct {
+	Certificate                  string `yaml:"Certificate,omitempty"`
+	OrganizationalUnitIdentifier string `yaml:"OrganizationalUnitIdentifier,omitempty"`
+}
+
+type mspDirNodeOUs struct {
+	Enable              bool                `yaml:"Enable,omitempty"`
+	ClientOUIdentifier  *mspDirOUIdentifier `yaml:"ClientOUIdentifier,omitempty"`
+	PeerOUIdentifier    *mspDirOUIdentifier `yaml:"PeerOUIdentifier,omitempty"`
+	AdminOUIdentifier   *mspDirOUIdentifier `yaml:"AdminOUIdentifier,omitempty"`
+	OrdererOUIdentifier *mspDirOUIdentifier `yaml:"OrdererOUIdentifier,omitempty"`
+}
+
+// LoadMSPDir loads the verifying MSP configuration for mspID from a local
+// MSP directory laid out as created by cryptogen or the Fabric CA client.
+// The crypto config defaults to SHA2 signatures with SHA256 identity
+// identifiers.
+func LoadMSPDir(mspID, dir string) (MSP, error) {
+	rootCerts, err := readCertsDir(filepath.Join(dir, mspCACertsDir))
+	if err != nil {
+		return MSP{}, err
+	}
+
+	if len(rootCerts) == 0 {
+		return MSP{}, fmt.Errorf("no root certificates found in %s", filepath.Join(dir, mspCACertsDir))
+	}
+
+	intermediateCerts, err := readCertsDir(filepath.Join(dir, mspIntermediateCertsDir))
+	if err != nil {
+		return MSP{}, err
+	}
+
+	admins, err := readCertsDir(filepath.Join(dir, mspAdminCertsDir))
+	if err != nil {
+		return MSP{}, err
+	}
+
+	tlsRootCerts, err := readCertsDir(filepath.Join(dir, mspTLSCACertsDir))
+	if err != nil {
+		return MSP{}, err
+	}
+
+	tlsIntermediateCerts, err := readCertsDir(filepath.Join(dir, mspTLSIntermediateCertsDir))
+	if err != nil {
+		return MSP{}, err
+	}
+
+	crls, err := readCRLsDir(filepath.Join(dir, mspCRLsDir))
+	if err != nil {
+		return MSP{}, err
+	}
+
+	msp := MSP{
+		Name:                 mspID,
+		RootCerts:            rootCerts,
+		IntermediateCerts:    intermediateCerts,
+		Admins:               admins,
+		RevocationList:       crls,
+		TLSRootCerts:         tlsRootCerts,
+		TLSIntermediateCerts: tlsIntermediateCerts,
+		CryptoConfig: membership.CryptoConfig{
+			SignatureHashFamily:            configtxgenDefaultSignatureHashFamily,
+			IdentityIdentifierHashFunction: configtxgenDefaultIdentityIdentifierHashFunction,
+		},
+	}
+
+	err = loadMSPDirConfig(&msp, dir)
+	if err != nil {
+		return MSP{}, err
+	}
+
+	return msp, nil
+}
+
+// LoadSigningIdentity loads the signing identity for mspID from the
+// signcerts and keystore directories of a local MSP directory.
+func LoadSigningIdentity(mspID, dir string) (*SigningIdentity, error) {
+	certs, err := readCertsDir(filepath.Join(dir, mspSignCertsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(certs) != 1 {
+		return nil, fmt.Errorf("expected exactly one certificate in %s, found %d", filepath.Join(dir, mspSignCertsDir), len(certs))
+	}
+
+	keyFiles, err := readPEMFiles(filepath.Join(dir, mspKeyStoreDir))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keyFiles) != 1 {
+		return nil, fmt.Errorf("expected exactly one private key in %s, found %d", filepath.Join(dir, mspKeyStoreDir), len(keyFiles))
+	}
+
+	privateKey, err := parsePrivateKeyFromBytes(keyFiles[0])
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key in %s: %w", filepath.Join(dir, mspKeyStoreDir), err)
+	}
+
+	return &SigningIdentity{
+		Certificate: certs[0],
+		PrivateKey:  privateKey,
+		MSPID:       mspID,
+	}, nil
+}
+
+// WriteMSPDir writes the verifying MSP configuration to dir laid out as a
+// local MSP directory, so it can be read by LoadMSPDir or by tools that
+// consume MSP directories. OU identifier certificates that are not root or
+// intermediate certificates of the MSP are written to an oucerts directory
+// and referenced from config.yaml.
+func WriteMSPDir(msp MSP, dir string) error {
+	for _, certs := range []struct {
+		dir   string
+		certs []*x509.Certificate
+	}{
+		{mspCACertsDir, msp.RootCerts},
+		{mspIntermediateCertsDir, msp.IntermediateCerts},
+		{mspAdminCertsDir, msp.Admins},
+		{mspTLSCACertsDir, msp.TLSRootCerts},
+		{mspTLSIntermediateCertsDir, msp.TLSIntermediateCerts},
+	} {
+		for i, cert := range certs.certs {
+			err := writeFileAll(filepath.Join(dir, certs.dir, fmt.Sprintf("cert%d.pem", i)), pemEncodeX509Certificate(cert))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	crls, err := buildPemEncodedRevocationList(msp.RevocationList)
+	if err != nil {
+		return fmt.Errorf("encoding CRLs: %w", err)
+	}
+
+	for i, crl := range crls {
+		err := writeFileAll(filepath.Join(dir, mspCRLsDir, fmt.Sprintf("crl%d.pem", i)), crl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeMSPDirConfig(msp, dir)
+}
+
+// writeMSPDirConfig writes the OU identifiers and node OUs of the MSP to the
+// config.yaml file of the MSP directory. No file is written if the MSP
+// defines neither.
+func writeMSPDirConfig(msp MSP, dir string) error {
+	config := &mspDirConfig{}
+	ouCerts := 0
+
+	ouIdentifier := func(identifier membership.OUIdentifier) (*mspDirOUIdentifier, error) {
+		certFile := ""
+		for _, c := range []struct {
+			dir   string
+			certs []*x509.Certificate
+		}{
+			{mspCACertsDir, msp.RootCerts},
+			{mspIntermediateCertsDir, msp.IntermediateCerts},
+		} {
+			for i, cert := range c.certs {
+				if certFile == "" && identifier.Certificate != nil && cert.Equal(identifier.Certificate) {
+					certFile = filepath.Join(c.dir, fmt.Sprintf("cert%d.pem", i))
+				}
+			}
+		}
+
+		if certFile == "" && identifier.Certificate != nil {
+			certFile = filepath.Join("oucerts", fmt.Sprintf("cert%d.pem", ouCerts))
+			ouCerts++
+
+			err := writeFileAll(filepath.Join(dir, certFile), pemEncodeX509Certificate(identifier.Certificate))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &mspDirOUIdentifier{
+			Certificate:                  certFile,
+			OrganizationalUnitIdentifier: identifier.OrganizationalUnitIdentifier,
+		}, nil
+	}
+
+	for _, identifier := range msp.OrganizationalUnitIdentifiers {
+		ou, err := ouIdentifier(identifier)
+		if err != nil {
+			return err
+		}
+		config.OrganizationalUnitIdentifiers = append(config.OrganizationalUnitIdentifiers, ou)
+	}
+
+	if msp.NodeOUs.Enable {
+		config.NodeOUs = &mspDirNodeOUs{Enable: true}
+		for _, nodeOU := range []struct {
+			identifier membership.OUIdentifier
+			target     **mspDirOUIdentifier
+		}{
+			{msp.NodeOUs.ClientOUIdentifier, &config.NodeOUs.ClientOUIdentifier},
+			{msp.NodeOUs.PeerOUIdentifier, &config.NodeOUs.PeerOUIdentifier},
+			{msp.NodeOUs.AdminOUIdentifier, &config.NodeOUs.AdminOUIdentifier},
+			{msp.NodeOUs.OrdererOUIdentifier, &config.NodeOUs.OrdererOUIdentifier},
+		} {
+			ou, err := ouIdentifier(nodeOU.identifier)
+			if err != nil {
+				return err
+			}
+			*nodeOU.target = ou
+		}
+	}
+
+	if config.OrganizationalUnitIdentifiers == nil && config.NodeOUs == nil {
+		return nil
+	}
+
+	raw, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", mspConfigFile, err)
+	}
+
+	return writeFileAll(filepath.Join(dir, mspConfigFile), raw)
+}
+
+// writeFileAll writes the contents to file, creating its directory if
+// needed.
+func writeFileAll(file string, contents []byte) error {
+	err := os.MkdirAll(filepath.Dir(file), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory %s: %w", filepath.Dir(file), err)
+	}
+
+	err = ioutil.WriteFile(file, contents, 0o644)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// loadMSPDirConfig sets the OU identifiers and node OUs defined in the
+// config.yaml file of the MSP directory, if one exists.
+func loadMSPDirConfig(msp *MSP, dir string) error {
+	configFile := filepath.Join(dir, mspConfigFile)
+
+	raw, err := ioutil.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", configFile, err)
+	}
+
+	config := &mspDirConfig{}
+	err = yaml.Unmarshal(raw, config)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", configFile, err)
+	}
+
+	for _, identifier := range config.OrganizationalUnitIdentifiers {
+		ouIdentifier, err := readOUIdentifier(dir, identifier)
+		if err != nil {
+			return err
+		}
+		msp.OrganizationalUnitIdentifiers = append(msp.OrganizationalUnitIdentifiers, ouIdentifier)
+	}
+
+	if config.NodeOUs == nil {
+		return nil
+	}
+
+	msp.NodeOUs.Enable = config.NodeOUs.Enable
+
+	for _, nodeOU := range []struct {
+		name       string
+		identifier *mspDirOUIdentifier
+		target     *membership.OUIdentifier
+	}{
+		{"ClientOUIdentifier", config.NodeOUs.ClientOUIdentifier, &msp.NodeOUs.ClientOUIdentifier},
+		{"PeerOUIdentifier", config.NodeOUs.PeerOUIdentifier, &msp.NodeOUs.PeerOUIdentifier},
+		{"AdminOUIdentifier", config.NodeOUs.AdminOUIdentifier, &msp.NodeOUs.AdminOUIdentifier},
+		{"OrdererOUIdentifier", config.NodeOUs.OrdererOUIdentifier, &msp.NodeOUs.OrdererOUIdentifier},
+	} {
+		if nodeOU.identifier == nil || nodeOU.identifier.Certificate == "" {
+			return fmt.Errorf("certificate for NodeOUs %s is required in %s", nodeOU.name, configFile)
+		}
+
+		*nodeOU.target, err = readOUIdentifier(dir, nodeOU.identifier)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readOUIdentifier(dir string, identifier *mspDirOUIdentifier) (membership.OUIdentifier, error) {
+	certFile := filepath.Join(dir, identifier.Certificate)
+
+	raw, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return membership.OUIdentifier{}, fmt.Errorf("reading %s: %w", certFile, err)
+	}
+
+	cert, err := parseCertificateFromBytes(raw, nil)
+	if err != nil {
+		return membership.OUIdentifier{}, fmt.Errorf("parsing certificate %s: %w", certFile, err)
+	}
+
+	return membership.OUIdentifier{
+		Certificate:                  cert,
+		OrganizationalUnitIdentifier: identifier.OrganizationalUnitIdentifier,
+	}, nil
+}
+
+// readCertsDir parses every PEM file in dir as a certificate. A missing
+// directory is treated as empty.
+func readCertsDir(dir string) ([]*x509.Certificate, error) {
+	files, err := readPEMFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := parseCertificateListFromBytes(files, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing certificates in %s: %w", dir, err)
+	}
+
+	return certs, nil
+}
+
+// readCRLsDir parses every PEM file in dir as a CRL. A missing directory is
+// treated as empty.
+func readCRLsDir(dir string) ([]*pkix.CertificateList, error) {
+	files, err := readPEMFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	crls, err := parseCRL(files)
+	if err != nil {
+		return nil, fmt.Errorf("parsing CRLs in %s: %w", dir, err)
+	}
+
+	return crls, nil
+}
+
+// readPEMFiles returns the contents of the regular files in dir that
+// contain PEM data, in lexical order of their names.
+func readPEMFiles(dir string) ([][]byte, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %s: %w", dir, err)
+	}
+
+	var files [][]byte
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+
+		raw, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filepath.Join(dir, info.Name()), err)
+		}
+
+		if block, _ := pem.Decode(raw); block == nil {
+			continue
+		}
+
+		files = append(files, raw)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	. "github.com/onsi/gomega"
+)
+
+const nodeOUsConfigYAML = `NodeOUs:
+  Enable: true
+  ClientOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: client
+  PeerOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: peer
+  AdminOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: admin
+  OrdererOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: orderer
+`
+
+func TestLoadMSPDir(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	tlsCACert, _ := generateCACertAndPrivateKey(t, "tls.org1.example.com")
+	writeMSPDir(t, dir, caCert, tlsCACert, nodeOUsConfigYAML)
+
+	msp, err := LoadMSPDir("Org1MSP", dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Name).To(Equal("Org1MSP"))
+	gt.Expect(msp.RootCerts).To(Equal([]*x509.Certificate{caCert}))
+	gt.Expect(msp.TLSRootCerts).To(Equal([]*x509.Certificate{tlsCACert}))
+	gt.Expect(msp.IntermediateCerts).To(BeEmpty())
+	gt.Expect(msp.Admins).To(BeEmpty())
+	gt.Expect(msp.CryptoConfig).To(Equal(membership.CryptoConfig{
+		SignatureHashFamily:            "SHA2",
+		IdentityIdentifierHashFunction: "SHA256",
+	}))
+	gt.Expect(msp.NodeOUs.Enable).To(BeTrue())
+	gt.Expect(msp.NodeOUs.AdminOUIdentifier).To(Equal(membership.OUIdentifier{
+		Certificate:                  caCert,
+		OrganizationalUnitIdentifier: "admin",
+	}))
+	gt.Expect(msp.NodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier).To(Equal("orderer"))
+
+	adminCert, adminPrivKey := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", caCert, caPrivKey)
+	writeSigningIdentity(t, dir, adminCert, adminPrivKey)
+
+	signingIdentity, err := LoadSigningIdentity("Org1MSP", dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signingIdentity.MSPID).To(Equal("Org1MSP"))
+	gt.Expect(signingIdentity.Certificate).To(Equal(adminCert))
+	gt.Expect(signingIdentity.PrivateKey).To(Equal(adminPrivKey))
+}
+
+func TestLoadMSPDirFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		configYAML  string
+		removeCA    bool
+		expectedErr string
+	}{
+		{
+			testName:    "when there are no root certificates",
+			removeCA:    true,
+			expectedErr: "no root certificates found in {{dir}}/cacerts",
+		},
+		{
+			testName:    "when config.yaml cannot be parsed",
+			configYAML:  "NodeOUs: [",
+			expectedErr: "parsing {{dir}}/config.yaml: yaml: line 1: did not find expected node content",
+		},
+		{
+			testName:    "when a node OU certificate is missing",
+			configYAML:  "NodeOUs:\n  Enable: true\n",
+			expectedErr: "certificate for NodeOUs ClientOUIdentifier is required in {{dir}}/config.yaml",
+		},
+		{
+			testName:    "when an OU certificate cannot be read",
+			configYAML:  "OrganizationalUnitIdentifiers:\n- Certificate: cacerts/missing.pem\n  OrganizationalUnitIdentifier: COP\n",
+			expectedErr: "reading {{dir}}/cacerts/missing.pem: open {{dir}}/cacerts/missing.pem: no such file or directory",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			dir, err := ioutil.TempDir("", "msp")
+			gt.Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+
+			caCert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+			writeMSPDir(t, dir, caCert, caCert, tc.configYAML)
+			if tc.removeCA {
+				gt.Expect(os.RemoveAll(filepath.Join(dir, "cacerts"))).To(Succeed())
+			}
+
+			_, err = LoadMSPDir("Org1MSP", dir)
+			gt.Expect(err).To(MatchError(replaceDir(tc.expectedErr, dir)))
+		})
+	}
+}
+
+func TestLoadSigningIdentityFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	_, err = LoadSigningIdentity("Org1MSP", dir)
+	gt.Expect(err).To(MatchError(replaceDir("expected exactly one certificate in {{dir}}/signcerts, found 0", dir)))
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	adminCert, _ := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", caCert, caPrivKey)
+	writeFile(t, filepath.Join(dir, "signcerts", "cert.pem"), pemEncodeX509Certificate(adminCert))
+
+	_, err = LoadSigningIdentity("Org1MSP", dir)
+	gt.Expect(err).To(MatchError(replaceDir("expected exactly one private key in {{dir}}/keystore, found 0", dir)))
+}
+
+func TestWriteMSPDir(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	msp, _ := baseMSP(t)
+	msp.CryptoConfig = membership.CryptoConfig{
+		SignatureHashFamily:            "SHA2",
+		IdentityIdentifierHashFunction: "SHA256",
+	}
+	msp.NodeOUs.Enable = true
+	ouCert, _ := generateCACertAndPrivateKey(t, "ou.org1.example.com")
+	msp.OrganizationalUnitIdentifiers[0].Certificate = ouCert
+
+	err = WriteMSPDir(msp, dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(filepath.Join(dir, "oucerts", "cert0.pem")).To(BeAnExistingFile())
+
+	loadedMSP, err := LoadMSPDir(msp.Name, dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(loadedMSP).To(Equal(msp))
+}
+
+func TestWriteMSPDirWithoutOUs(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	caCert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	err = WriteMSPDir(MSP{Name: "Org1MSP", RootCerts: []*x509.Certificate{caCert}}, dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(filepath.Join(dir, "config.yaml")).NotTo(BeAnExistingFile())
+
+	loadedMSP, err := LoadMSPDir("Org1MSP", dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(loadedMSP.RootCerts).To(Equal([]*x509.Certificate{caCert}))
+	gt.Expect(loadedMSP.NodeOUs.Enable).To(BeFalse())
+}
+
+func writeMSPDir(t *testing.T, dir string, caCert, tlsCACert *x509.Certificate, configYAML string) {
+	writeFile(t, filepath.Join(dir, "cacerts", "ca.pem"), pemEncodeX509Certificate(caCert))
+	writeFile(t, filepath.Join(dir, "tlscacerts", "tlsca.pem"), pemEncodeX509Certificate(tlsCACert))
+	if configYAML != "" {
+		writeFile(t, filepath.Join(dir, "config.yaml"), []byte(configYAML))
+	}
+}
+
+func writeSigningIdentity(t *testing.T, dir string, cert *x509.Certificate, privKey *ecdsa.PrivateKey) {
+	writeFile(t, filepath.Join(dir, "signcerts", "cert.pem"), pemEncodeX509Certificate(cert))
+	privKeyPEM, err := pemEncodePKCS8PrivateKey(privKey)
+	if err != nil {
+		t.Fatalf("encoding private key: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "keystore", "priv_sk"), privKeyPEM)
+}
+
+func writeFile(t *testing.T, file string, contents []byte) {
+	err := os.MkdirAll(filepath.Dir(file), 0755)
+	if err != nil {
+		t.Fatalf("creating directory for %s: %v", file, err)
+	}
+
+	err = ioutil.WriteFile(file, contents, 0644)
+	if err != nil {
+		t.Fatalf("writing %s: %v", file, err)
+	}
+}
+
+func replaceDir(s, dir string) string {
+	return strings.Replace(s, "{{dir}}", dir, -1)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+// Mutation describes a single change made to the updated config through
+// the typed API.
+type Mutation struct {
+	// Path is the config path of the group that was modified,
+	// e.g. /Channel/Application/Org1.
+	Path string
+	// Operation is the name of the method that modified the group,
+	// e.g. AddAnchorPeer.
+	Operation string
+}
+
+// Observer is notified after each successful mutation of the updated config.
+type Observer func(Mutation)
+
+// WithObserver registers an observer that is notified after each successful
+// mutation of the updated config. Observers are notified in the order they
+// are registered.
+func WithObserver(observer Observer) Option {
+	return func(o *options) {
+		o.observers = append(o.observers, observer)
+	}
+}
+
+// notify passes a mutation to the registered observers. It is safe to call
+// on a nil ConfigTx.
+func (c *ConfigTx) notify(path, operation string) {
+	if c == nil {
+		return
+	}
+
+	c.history.record(c.updated.ChannelGroup, path, operation)
+	c.observe(path, operation)
+}
+
+// observe passes a mutation to the audit log, the published snapshots and
+// the registered observers without recording it in the undo history, e.g. for Undo and Redo.
+func (c *ConfigTx) observe(path, operation string) {
+	c.cache.invalidate()
+	c.audit.record(c.updated.ChannelGroup, path, operation, c.options.now())
+	c.snapshots.publish(c.updated, c.options)
+
+	for _, observer := range c.options.observers {
+		observer(Mutation{Path: path, Operation: operation})
+	}
+}
+
+func (c *ChannelGroup) path() string {
+	return configPath(ChannelGroupKey)
+}
+
+func (a *ApplicationGroup) path() string {
+	return configPath(ChannelGroupKey, ApplicationGroupKey)
+}
+
+func (a *ApplicationOrg) path() string {
+	return configPath(ChannelGroupKey, ApplicationGroupKey, a.name)
+}
+
+func (o *OrdererGroup) path() string {
+	return configPath(ChannelGroupKey, OrdererGroupKey)
+}
+
+func (o *OrdererOrg) path() string {
+	return configPath(ChannelGroupKey, OrdererGroupKey, o.name)
+}
+
+func (b *BatchSizeValue) path() string {
+	return configPath(ChannelGroupKey, OrdererGroupKey)
+}
+
+func (e *EtcdRaftOptionsValue) path() string {
+	return configPath(ChannelGroupKey, OrdererGroupKey)
+}
+
+func (c *ConsortiumsGroup) path() string {
+	return configPath(ChannelGroupKey, ConsortiumsGroupKey)
+}
+
+func (c *ConsortiumGroup) path() string {
+	return configPath(ChannelGroupKey, ConsortiumsGroupKey, c.name)
+}
+
+func (c *ConsortiumOrg) path() string {
+	return configPath(ChannelGroupKey, ConsortiumsGroupKey, c.consortium, c.name)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestObserverApplicationChannel(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var mutations []Mutation
+	c := New(config, WithObserver(func(m Mutation) {
+		mutations = append(mutations, m)
+	}))
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().Organization("Org1").MSP().SetEnableNodeOUs(true)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Orderer().BatchSize().SetMaxMessageCount(100)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c.Channel().RemoveLegacyOrdererAddresses()
+
+	// failed mutations are not observed
+	err = c.Application().Organization("Org1").SetPolicy(AdminsPolicyKey, "Invalid", Policy{Type: "Unknown"})
+	gt.Expect(err).To(HaveOccurred())
+
+	gt.Expect(mutations).To(Equal([]Mutation{
+		{Path: "/Channel/Application/Org1", Operation: "AddAnchorPeer"},
+		{Path: "/Channel/Application/Org1", Operation: "SetEnableNodeOUs"},
+		{Path: "/Channel/Orderer", Operation: "SetMaxMessageCount"},
+		{Path: "/Channel", Operation: "RemoveLegacyOrdererAddresses"},
+	}))
+}
+
+func TestObserverSystemChannel(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var first, second []Mutation
+	c := New(config,
+		WithObserver(func(m Mutation) { first = append(first, m) }),
+		WithObserver(func(m Mutation) { second = append(second, m) }),
+	)
+
+	err = c.Consortium("Consortium1").Organization("Org1").SetPolicy("Endorsement", Policy{
+		Type: SignaturePolicyType,
+		Rule: "OR('Org1MSP.peer')",
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c.Consortiums().RemoveConsortium("Consortium1")
+
+	expected := []Mutation{
+		{Path: "/Channel/Consortiums/Consortium1/Org1", Operation: "SetPolicy"},
+		{Path: "/Channel/Consortiums", Operation: "RemoveConsortium"},
+	}
+	gt.Expect(first).To(Equal(expected))
+	gt.Expect(second).To(Equal(expected))
+}
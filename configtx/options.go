@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Option configures optional behavior when building channel artifacts.
+type Option func(*options)
+
+type options struct {
+	configtxgenCompatible bool
+	warningHandler        WarningHandler
+	transformers          []Transformer
+	observers             []Observer
+	configurationCache    bool
+	randomness            io.Reader
+	clock                 Clock
+	tlsCert               *x509.Certificate
+	pathGuard             []string
+	policyEvaluator       PolicyEvaluator
+	mspConfigs            *mspConfigCache
+	audit                 bool
+	validityTime          time.Time
+	strictValidation      bool
+	withoutClone          bool
+	valueTypes            map[string]func() proto.Message
+	undo                  bool
+	undoDepth             int
+	snapshots             bool
+	systemChannelConfig   *cb.Config
+	systemChannelChannels uint64
+	// certificates interns the certificates decoded by the ConfigTx
+	certificates *certificateInterner
+}
+
+// WithConfigtxgenCompatibility builds artifacts that are structurally
+// identical to the output of configtxgen for equivalent input. Unset
+// orderer values are filled in with the configtxgen defaults, MSPs
+// default to the SHA2 crypto config, and application organization anchor
+// peers are included in application channel genesis blocks. Genesis
+// blocks carry the channel header version configtxgen sets. It applies to
+// genesis blocks and to channel creation transactions.
+func WithConfigtxgenCompatibility() Option {
+	return func(o *options) {
+		o.configtxgenCompatible = true
+	}
+}
+
+// WithRandomness uses r instead of crypto/rand as the source of randomness
+// for the nonces of genesis blocks, e.g. to build reproducible blocks in
+// tests or to use a DRBG mandated by the environment.
+func WithRandomness(r io.Reader) Option {
+	return func(o *options) {
+		o.randomness = r
+	}
+}
+
+// random returns the configured source of randomness.
+func (o options) random() io.Reader {
+	if o.randomness == nil {
+		return rand.Reader
+	}
+
+	return o.randomness
+}
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc is an adapter to use a function as a Clock.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// WithClock uses clock instead of the system clock for the timestamps of
+// genesis block and envelope headers, e.g. to build reproducible blocks.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// now returns the current time of the configured clock.
+func (o options) now() time.Time {
+	if o.clock == nil {
+		return time.Now()
+	}
+
+	return o.clock.Now()
+}
+
+// WithValidityTime checks the validity periods of certificates as of t
+// instead of the current time of the clock, e.g. to find the certificates
+// that will have expired in 90 days or to validate a historical config as
+// of its creation date.
+func WithValidityTime(t time.Time) Option {
+	return func(o *options) {
+		o.validityTime = t
+	}
+}
+
+// validityAt returns the time as of which the validity periods of
+// certificates are checked.
+func (o options) validityAt() time.Time {
+	if o.validityTime.IsZero() {
+		return o.now()
+	}
+
+	return o.validityTime
+}
+
+// WithoutClone makes New use the config as the updated config instead of
+// a copy of it, avoiding the copy of large configs when the ConfigTx is
+// only used to read the config. As the original config is the same config,
+// it reflects the changes made through the ConfigTx and no update can be
+// computed from them. For the same reason, Reset, ResetGroup, Merge and
+// Rebase return an error.
+func WithoutClone() Option {
+	return func(o *options) {
+		o.withoutClone = true
+	}
+}
+
+// WithTLSCertificate binds envelopes to the client TLS certificate used to
+// submit them by setting the SHA-256 hash of the certificate in the channel
+// header, as required by orderers that enforce mutual TLS binding.
+func WithTLSCertificate(cert *x509.Certificate) Option {
+	return func(o *options) {
+		o.tlsCert = cert
+	}
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		certificates: newCertificateInterner(maxInternedCertificates),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
@@ -7,12 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
-	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"math"
+	"net"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -33,6 +34,7 @@ type Orderer struct {
 	// Options: `Solo`, `Kafka` or `Raft`
 	OrdererType string
 	// BatchTimeout is the wait time between transactions.
+	// It must be greater than zero.
 	BatchTimeout  time.Duration
 	BatchSize     orderer.BatchSize
 	Kafka         orderer.Kafka
@@ -52,6 +54,7 @@ type Orderer struct {
 type OrdererGroup struct {
 	channelGroup *cb.ConfigGroup
 	ordererGroup *cb.ConfigGroup
+	tx           *ConfigTx
 }
 
 // OrdererOrg encapsulates the parts of the config that control
@@ -59,30 +62,35 @@ type OrdererGroup struct {
 type OrdererOrg struct {
 	orgGroup *cb.ConfigGroup
 	name     string
+	tx       *ConfigTx
 }
 
 // MSP returns an OrganizationMSP object that can be used to configure the organization's MSP.
 func (o *OrdererOrg) MSP() *OrganizationMSP {
 	return &OrganizationMSP{
 		configGroup: o.orgGroup,
+		path:        o.path(),
+		tx:          o.tx,
 	}
 }
 
 // EtcdRaftOptionsValue encapsulates the configuration functions used to modify an etcdraft configuration's options.
 type EtcdRaftOptionsValue struct {
 	value *cb.ConfigValue
+	tx    *ConfigTx
 }
 
 // BatchSizeValue encapsulates the configuration functions used to modify an orderer configuration's batch size values.
 type BatchSizeValue struct {
 	value *cb.ConfigValue
+	tx    *ConfigTx
 }
 
 // Orderer returns the orderer group from the updated config.
 func (c *ConfigTx) Orderer() *OrdererGroup {
 	channelGroup := c.updated.ChannelGroup
 	ordererGroup := channelGroup.Groups[OrdererGroupKey]
-	return &OrdererGroup{channelGroup: channelGroup, ordererGroup: ordererGroup}
+	return &OrdererGroup{channelGroup: channelGroup, ordererGroup: ordererGroup, tx: c}
 }
 
 // Organization returns the orderer org from the updated config.
@@ -91,7 +99,7 @@ func (o *OrdererGroup) Organization(name string) *OrdererOrg {
 	if !ok {
 		return nil
 	}
-	return &OrdererOrg{name: name, orgGroup: orgGroup}
+	return &OrdererOrg{name: name, orgGroup: orgGroup, tx: o.tx}
 }
 
 // Configuration returns the existing orderer configuration values from the updated
@@ -122,14 +130,14 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 		kafkaBrokersProto := &ob.KafkaBrokers{}
 		err := proto.Unmarshal(kafkaBrokersValue.Value, kafkaBrokersProto)
 		if err != nil {
-			return Orderer{}, fmt.Errorf("unmarshaling kafka brokers: %v", err)
+			return Orderer{}, fmt.Errorf("unmarshaling kafka brokers: %w", err)
 		}
 
 		kafkaBrokers.Brokers = kafkaBrokersProto.Brokers
 	case orderer.ConsensusTypeEtcdRaft:
-		etcdRaft, err = unmarshalEtcdRaftMetadata(consensusTypeProto.Metadata)
+		etcdRaft, err = unmarshalEtcdRaftMetadata(consensusTypeProto.Metadata, o.tx.certificates())
 		if err != nil {
-			return Orderer{}, fmt.Errorf("unmarshaling etcd raft metadata: %v", err)
+			return Orderer{}, fmt.Errorf("unmarshaling etcd raft metadata: %w", err)
 		}
 	default:
 		return Orderer{}, fmt.Errorf("config contains unknown consensus type '%s'", consensusTypeProto.Type)
@@ -158,7 +166,7 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 	for orgName := range o.ordererGroup.Groups {
 		orgConfig, err := o.Organization(orgName).Configuration()
 		if err != nil {
-			return Orderer{}, fmt.Errorf("retrieving orderer org %s: %v", orgName, err)
+			return Orderer{}, fmt.Errorf("retrieving orderer org %s: %w", orgName, err)
 		}
 
 		ordererOrgs = append(ordererOrgs, orgConfig)
@@ -174,13 +182,13 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 	// CAPABILITIES
 	capabilities, err := getCapabilities(o.ordererGroup)
 	if err != nil {
-		return Orderer{}, fmt.Errorf("retrieving orderer capabilities: %v", err)
+		return Orderer{}, fmt.Errorf("retrieving orderer capabilities: %w", err)
 	}
 
 	// POLICIES
 	policies, err := o.Policies()
 	if err != nil {
-		return Orderer{}, fmt.Errorf("retrieving orderer policies: %v", err)
+		return Orderer{}, fmt.Errorf("retrieving orderer policies: %w", err)
 	}
 
 	return Orderer{
@@ -205,6 +213,7 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 func (o *OrdererGroup) BatchSize() *BatchSizeValue {
 	return &BatchSizeValue{
 		value: o.ordererGroup.Values[orderer.BatchSizeKey],
+		tx:    o.tx,
 	}
 }
 
@@ -218,8 +227,13 @@ func (b *BatchSizeValue) SetMaxMessageCount(maxMessageCount uint32) error {
 
 	batchSize.MaxMessageCount = maxMessageCount
 	b.value.Value, err = proto.Marshal(batchSize)
+	if err != nil {
+		return err
+	}
 
-	return err
+	b.tx.notify(b.path(), "SetMaxMessageCount")
+
+	return nil
 }
 
 // SetAbsoluteMaxBytes sets an orderer configuration's batch size max block size.
@@ -232,8 +246,13 @@ func (b *BatchSizeValue) SetAbsoluteMaxBytes(maxBytes uint32) error {
 
 	batchSize.AbsoluteMaxBytes = maxBytes
 	b.value.Value, err = proto.Marshal(batchSize)
+	if err != nil {
+		return err
+	}
 
-	return err
+	b.tx.notify(b.path(), "SetAbsoluteMaxBytes")
+
+	return nil
 }
 
 // SetPreferredMaxBytes sets an orderer configuration's batch size preferred size of blocks.
@@ -246,28 +265,95 @@ func (b *BatchSizeValue) SetPreferredMaxBytes(maxBytes uint32) error {
 
 	batchSize.PreferredMaxBytes = maxBytes
 	b.value.Value, err = proto.Marshal(batchSize)
+	if err != nil {
+		return err
+	}
 
-	return err
+	b.tx.notify(b.path(), "SetPreferredMaxBytes")
+
+	return nil
 }
 
-// SetBatchTimeout sets the wait time between transactions.
+// SetBatchTimeout sets the wait time between transactions. The timeout must
+// be greater than zero.
 func (o *OrdererGroup) SetBatchTimeout(timeout time.Duration) error {
-	return setValue(o.ordererGroup, batchTimeoutValue(timeout.String()), AdminsPolicyKey)
+	err := validateBatchTimeout(timeout)
+	if err != nil {
+		return err
+	}
+
+	err = setValue(o.ordererGroup, batchTimeoutValue(timeout.String()), AdminsPolicyKey)
+	if err != nil {
+		return err
+	}
+
+	o.tx.notify(o.path(), "SetBatchTimeout")
+
+	return nil
 }
 
 // SetMaxChannels sets the maximum count of channels an orderer supports.
 func (o *OrdererGroup) SetMaxChannels(max int) error {
-	return setValue(o.ordererGroup, channelRestrictionsValue(uint64(max)), AdminsPolicyKey)
+	err := setValue(o.ordererGroup, channelRestrictionsValue(uint64(max)), AdminsPolicyKey)
+	if err != nil {
+		return err
+	}
+
+	o.tx.notify(o.path(), "SetMaxChannels")
+
+	return nil
 }
 
 // SetEtcdRaftConsensusType sets the orderer consensus type to etcdraft, sets etcdraft metadata, and consensus state.
 func (o *OrdererGroup) SetEtcdRaftConsensusType(consensusMetadata orderer.EtcdRaft, consensusState orderer.ConsensusState) error {
 	consensusMetadataBytes, err := marshalEtcdRaftMetadata(consensusMetadata)
 	if err != nil {
-		return fmt.Errorf("marshaling etcdraft metadata: %v", err)
+		return fmt.Errorf("marshaling etcdraft metadata: %w", err)
+	}
+
+	err = setValue(o.ordererGroup, consensusTypeValue(orderer.ConsensusTypeEtcdRaft, consensusMetadataBytes, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
+	if err != nil {
+		return err
+	}
+
+	o.tx.notify(o.path(), "SetEtcdRaftConsensusType")
+
+	return nil
+}
+
+// MigrateToEtcdRaft migrates the consensus type of a kafka orderer in
+// maintenance mode to etcdraft with the given metadata, and removes the
+// kafka brokers in the same update as required by the orderer.
+func (o *OrdererGroup) MigrateToEtcdRaft(consensusMetadata orderer.EtcdRaft) error {
+	consensusTypeProto := &ob.ConsensusType{}
+	err := unmarshalConfigValueAtKey(o.ordererGroup, orderer.ConsensusTypeKey, consensusTypeProto)
+	if err != nil {
+		return err
+	}
+
+	if consensusTypeProto.Type != orderer.ConsensusTypeKafka {
+		return fmt.Errorf("unsupported consensus type migration from %s to %s", consensusTypeProto.Type, orderer.ConsensusTypeEtcdRaft)
+	}
+
+	if consensusTypeProto.State != ob.ConsensusType_STATE_MAINTENANCE {
+		return errors.New("consensus type can only be migrated in maintenance mode")
+	}
+
+	consensusMetadataBytes, err := marshalEtcdRaftMetadata(consensusMetadata)
+	if err != nil {
+		return fmt.Errorf("marshaling etcdraft metadata: %w", err)
+	}
+
+	err = setValue(o.ordererGroup, consensusTypeValue(orderer.ConsensusTypeEtcdRaft, consensusMetadataBytes, int32(consensusTypeProto.State)), AdminsPolicyKey)
+	if err != nil {
+		return err
 	}
 
-	return setValue(o.ordererGroup, consensusTypeValue(orderer.ConsensusTypeEtcdRaft, consensusMetadataBytes, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
+	delete(o.ordererGroup.Values, orderer.KafkaBrokersKey)
+
+	o.tx.notify(o.path(), "MigrateToEtcdRaft")
+
+	return nil
 }
 
 // SetConsensusState sets the consensus state.
@@ -278,13 +364,21 @@ func (o *OrdererGroup) SetConsensusState(consensusState orderer.ConsensusState)
 		return err
 	}
 
-	return setValue(o.ordererGroup, consensusTypeValue(consensusTypeProto.Type, consensusTypeProto.Metadata, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
+	err = setValue(o.ordererGroup, consensusTypeValue(consensusTypeProto.Type, consensusTypeProto.Metadata, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
+	if err != nil {
+		return err
+	}
+
+	o.tx.notify(o.path(), "SetConsensusState")
+
+	return nil
 }
 
 // EtcdRaftOptions returns an EtcdRaftOptionsValue that can be used to configure an etcdraft configuration's options.
 func (o *OrdererGroup) EtcdRaftOptions() *EtcdRaftOptionsValue {
 	return &EtcdRaftOptionsValue{
 		value: o.ordererGroup.Values[orderer.ConsensusTypeKey],
+		tx:    o.tx,
 	}
 }
 
@@ -294,13 +388,13 @@ func (e *EtcdRaftOptionsValue) etcdRaftConfig(consensusTypeProto *ob.ConsensusTy
 		return orderer.EtcdRaft{}, err
 	}
 
-	return unmarshalEtcdRaftMetadata(consensusTypeProto.Metadata)
+	return unmarshalEtcdRaftMetadata(consensusTypeProto.Metadata, e.tx.certificates())
 }
 
 func (e *EtcdRaftOptionsValue) setEtcdRaftConfig(consensusTypeProto *ob.ConsensusType, etcdRaft orderer.EtcdRaft) error {
 	consensusMetadata, err := marshalEtcdRaftMetadata(etcdRaft)
 	if err != nil {
-		return fmt.Errorf("marshaling etcdraft metadata: %v", err)
+		return fmt.Errorf("marshaling etcdraft metadata: %w", err)
 	}
 
 	consensusTypeProto.Metadata = consensusMetadata
@@ -318,7 +412,15 @@ func (e *EtcdRaftOptionsValue) SetTickInterval(interval string) error {
 	}
 
 	etcdRaft.Options.TickInterval = interval
-	return e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+
+	err = e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+	if err != nil {
+		return err
+	}
+
+	e.tx.notify(e.path(), "SetTickInterval")
+
+	return nil
 }
 
 // SetElectionInterval sets the Etcdraft's election interval.
@@ -330,7 +432,15 @@ func (e *EtcdRaftOptionsValue) SetElectionInterval(interval uint32) error {
 	}
 
 	etcdRaft.Options.ElectionTick = interval
-	return e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+
+	err = e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+	if err != nil {
+		return err
+	}
+
+	e.tx.notify(e.path(), "SetElectionInterval")
+
+	return nil
 }
 
 // SetHeartbeatTick sets the Etcdraft's heartbeat tick interval.
@@ -342,7 +452,15 @@ func (e *EtcdRaftOptionsValue) SetHeartbeatTick(tick uint32) error {
 	}
 
 	etcdRaft.Options.HeartbeatTick = tick
-	return e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+
+	err = e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+	if err != nil {
+		return err
+	}
+
+	e.tx.notify(e.path(), "SetHeartbeatTick")
+
+	return nil
 }
 
 // SetMaxInflightBlocks sets the Etcdraft's max inflight blocks.
@@ -354,7 +472,15 @@ func (e *EtcdRaftOptionsValue) SetMaxInflightBlocks(maxBlks uint32) error {
 	}
 
 	etcdRaft.Options.MaxInflightBlocks = maxBlks
-	return e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+
+	err = e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+	if err != nil {
+		return err
+	}
+
+	e.tx.notify(e.path(), "SetMaxInflightBlocks")
+
+	return nil
 }
 
 // SetSnapshotIntervalSize sets the Etcdraft's snapshot interval size.
@@ -366,13 +492,21 @@ func (e *EtcdRaftOptionsValue) SetSnapshotIntervalSize(intervalSize uint32) erro
 	}
 
 	etcdRaft.Options.SnapshotIntervalSize = intervalSize
-	return e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+
+	err = e.setEtcdRaftConfig(consensusTypeProto, etcdRaft)
+	if err != nil {
+		return err
+	}
+
+	e.tx.notify(e.path(), "SetSnapshotIntervalSize")
+
+	return nil
 }
 
 // Configuration retrieves an existing org's configuration from an
 // orderer organization config group in the updated config.
 func (o *OrdererOrg) Configuration() (Organization, error) {
-	org, err := getOrganization(o.orgGroup, o.name)
+	org, err := getOrganization(o.orgGroup, o.name, o.tx.certificates())
 	if err != nil {
 		return Organization{}, err
 	}
@@ -403,13 +537,15 @@ func (o *OrdererOrg) Configuration() (Organization, error) {
 // org key in an existing Orderer configuration's Groups map.
 // If the orderer org already exists in the current configuration, its value will be overwritten.
 func (o *OrdererGroup) SetOrganization(org Organization) error {
-	orgGroup, err := newOrdererOrgConfigGroup(org)
+	orgGroup, err := newOrdererOrgConfigGroup(org, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create orderer org %s: %v", org.Name, err)
+		return fmt.Errorf("failed to create orderer org %s: %w", org.Name, err)
 	}
 
 	o.ordererGroup.Groups[org.Name] = orgGroup
 
+	o.tx.notify(o.path(), "SetOrganization")
+
 	return nil
 }
 
@@ -417,6 +553,8 @@ func (o *OrdererGroup) SetOrganization(org Organization) error {
 // Removal will panic if the orderer group does not exist.
 func (o *OrdererGroup) RemoveOrganization(name string) {
 	delete(o.ordererGroup.Groups, name)
+
+	o.tx.notify(o.path(), "RemoveOrganization")
 }
 
 // SetConfiguration modifies an updated config's Orderer configuration
@@ -428,6 +566,10 @@ func (o *OrdererGroup) SetConfiguration(ord Orderer) error {
 		return err
 	}
 
+	o.tx.warn(consensusTypeWarnings(configPath(ChannelGroupKey, OrdererGroupKey), ord.OrdererType)...)
+
+	o.tx.notify(o.path(), "SetConfiguration")
+
 	return nil
 }
 
@@ -448,11 +590,16 @@ func (o *OrdererGroup) AddConsenter(consenter orderer.Consenter) error {
 		}
 	}
 
+	err = verifyConsenterHostname(consenter)
+	if err != nil {
+		o.tx.warn(Warning{Path: o.path(), Message: err.Error()})
+	}
+
 	cfg.EtcdRaft.Consenters = append(cfg.EtcdRaft.Consenters, consenter)
 
 	consensusMetadata, err := marshalEtcdRaftMetadata(cfg.EtcdRaft)
 	if err != nil {
-		return fmt.Errorf("marshaling etcdraft metadata: %v", err)
+		return fmt.Errorf("marshaling etcdraft metadata: %w", err)
 	}
 
 	consensusState, ok := ob.ConsensusType_State_value[string(cfg.State)]
@@ -465,6 +612,8 @@ func (o *OrdererGroup) AddConsenter(consenter orderer.Consenter) error {
 		return err
 	}
 
+	o.tx.notify(o.path(), "AddConsenter")
+
 	return nil
 }
 
@@ -491,7 +640,7 @@ func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) error {
 
 	consensusMetadata, err := marshalEtcdRaftMetadata(cfg.EtcdRaft)
 	if err != nil {
-		return fmt.Errorf("marshaling etcdraft metadata: %v", err)
+		return fmt.Errorf("marshaling etcdraft metadata: %w", err)
 	}
 
 	consensusState, ok := ob.ConsensusType_State_value[string(cfg.State)]
@@ -504,6 +653,36 @@ func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) error {
 		return err
 	}
 
+	o.tx.notify(o.path(), "RemoveConsenter")
+
+	return nil
+}
+
+// VerifyConsenterHostnames verifies that the server TLS certificate of each
+// etcdraft consenter is valid for the consenter's host, i.e. that the host
+// is one of the certificate's DNS or IP subject alternative names. This
+// catches certificates issued for the wrong hostname before a cluster
+// change is submitted. The error wraps ValidationErrors with a finding for
+// each invalid certificate.
+func (o *OrdererGroup) VerifyConsenterHostnames() error {
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	if cfg.OrdererType != orderer.ConsensusTypeEtcdRaft {
+		return fmt.Errorf("consensus type %s is not etcdraft", cfg.OrdererType)
+	}
+
+	var findings ValidationErrors
+	for _, c := range cfg.EtcdRaft.Consenters {
+		findings = findings.append(verifyConsenterHostname(c))
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("invalid consenter server TLS certificates: %w", findings)
+	}
+
 	return nil
 }
 
@@ -512,7 +691,7 @@ func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) error {
 func (o *OrdererGroup) Capabilities() ([]string, error) {
 	capabilities, err := getCapabilities(o.ordererGroup)
 	if err != nil {
-		return nil, fmt.Errorf("retrieving orderer capabilities: %v", err)
+		return nil, fmt.Errorf("retrieving orderer capabilities: %w", err)
 	}
 
 	return capabilities, nil
@@ -532,6 +711,10 @@ func (o *OrdererGroup) AddCapability(capability string) error {
 		return err
 	}
 
+	o.tx.checkCapabilityLevels()
+
+	o.tx.notify(o.path(), "AddCapability")
+
 	return nil
 }
 
@@ -547,26 +730,33 @@ func (o *OrdererGroup) RemoveCapability(capability string) error {
 		return err
 	}
 
+	o.tx.notify(o.path(), "RemoveCapability")
+
 	return nil
 }
 
 // SetEndpoint adds an orderer's endpoint to an existing channel config transaction.
 // If the same endpoint already exist in current configuration, this will be a no-op.
+// The host is normalized like that of ApplicationOrg.AddAnchorPeer.
 func (o *OrdererOrg) SetEndpoint(endpoint Address) error {
 	ordererAddrProto := &cb.OrdererAddresses{}
 
 	if ordererAddrConfigValue, ok := o.orgGroup.Values[EndpointsKey]; ok {
 		err := proto.Unmarshal(ordererAddrConfigValue.Value, ordererAddrProto)
 		if err != nil {
-			return fmt.Errorf("failed unmarshaling endpoints for orderer org %s: %v", o.name, err)
+			return fmt.Errorf("failed unmarshaling endpoints for orderer org %s: %w", o.name, err)
 		}
 	}
 
-	endpointToAdd := fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+	host, err := normalizeHost(endpoint.Host)
+	if err != nil {
+		return err
+	}
+	endpointToAdd := fmt.Sprintf("%s:%d", host, endpoint.Port)
 
 	existingOrdererEndpoints := ordererAddrProto.Addresses
 	for _, e := range existingOrdererEndpoints {
-		if e == endpointToAdd {
+		if sameAddress(e, endpointToAdd) {
 			return nil
 		}
 	}
@@ -574,11 +764,13 @@ func (o *OrdererOrg) SetEndpoint(endpoint Address) error {
 	existingOrdererEndpoints = append(existingOrdererEndpoints, endpointToAdd)
 
 	// Add orderer endpoints config value back to orderer org
-	err := setValue(o.orgGroup, endpointsValue(existingOrdererEndpoints), AdminsPolicyKey)
+	err = setValue(o.orgGroup, endpointsValue(existingOrdererEndpoints), AdminsPolicyKey)
 	if err != nil {
-		return fmt.Errorf("failed to add endpoint %v to orderer org %s: %v", endpoint, o.name, err)
+		return fmt.Errorf("failed to add endpoint %v to orderer org %s: %w", endpoint, o.name, err)
 	}
 
+	o.tx.notify(o.path(), "SetEndpoint")
+
 	return nil
 }
 
@@ -590,7 +782,7 @@ func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
 	if ordererAddrConfigValue, ok := o.orgGroup.Values[EndpointsKey]; ok {
 		err := proto.Unmarshal(ordererAddrConfigValue.Value, ordererAddrProto)
 		if err != nil {
-			return fmt.Errorf("failed unmarshaling endpoints for orderer org %s: %v", o.name, err)
+			return fmt.Errorf("failed unmarshaling endpoints for orderer org %s: %w", o.name, err)
 		}
 	}
 
@@ -598,7 +790,7 @@ func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
 
 	existingEndpoints := ordererAddrProto.Addresses[:0]
 	for _, e := range ordererAddrProto.Addresses {
-		if e != endpointToRemove {
+		if !sameAddress(e, endpointToRemove) {
 			existingEndpoints = append(existingEndpoints, e)
 		}
 	}
@@ -606,9 +798,82 @@ func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
 	// Add orderer endpoints config value back to orderer org
 	err := setValue(o.orgGroup, endpointsValue(existingEndpoints), AdminsPolicyKey)
 	if err != nil {
-		return fmt.Errorf("failed to remove endpoint %v from orderer org %s: %v", endpoint, o.name, err)
+		return fmt.Errorf("failed to remove endpoint %v from orderer org %s: %w", endpoint, o.name, err)
+	}
+
+	o.tx.notify(o.path(), "RemoveEndpoint")
+
+	return nil
+}
+
+// AddOrdererEndpoint adds the endpoint address, in host:port form, to the
+// orderer endpoints of the orderer org. Unlike OrdererOrg.SetEndpoint, an
+// error is returned if the org already lists the address. The host is
+// normalized like that of ApplicationOrg.AddAnchorPeer.
+func (o *OrdererGroup) AddOrdererEndpoint(orgName, address string) error {
+	address, err := parseEndpointAddress(address)
+	if err != nil {
+		return err
+	}
+
+	orgGroup, ok := o.ordererGroup.Groups[orgName]
+	if !ok {
+		return fmt.Errorf("orderer org %s does not exist", orgName)
+	}
+
+	addresses, err := ordererAddresses(orgGroup, EndpointsKey)
+	if err != nil {
+		return fmt.Errorf("retrieving endpoints of orderer org %s: %w", orgName, err)
+	}
+
+	for _, existing := range addresses {
+		if sameAddress(existing, address) {
+			return fmt.Errorf("endpoint %s already exists in orderer org %s", address, orgName)
+		}
+	}
+
+	err = setValue(orgGroup, endpointsValue(append(addresses, address)), AdminsPolicyKey)
+	if err != nil {
+		return fmt.Errorf("adding endpoint %s to orderer org %s: %w", address, orgName, err)
+	}
+
+	o.tx.notify(configPath(ChannelGroupKey, OrdererGroupKey, orgName), "AddOrdererEndpoint")
+
+	return nil
+}
+
+// RemoveOrdererEndpoint removes the endpoint address, in host:port form,
+// from the orderer endpoints of the orderer org. Unlike
+// OrdererOrg.RemoveEndpoint, an error is returned if the org does not list
+// the address.
+func (o *OrdererGroup) RemoveOrdererEndpoint(orgName, address string) error {
+	address, err := parseEndpointAddress(address)
+	if err != nil {
+		return err
+	}
+
+	orgGroup, ok := o.ordererGroup.Groups[orgName]
+	if !ok {
+		return fmt.Errorf("orderer org %s does not exist", orgName)
 	}
 
+	addresses, err := ordererAddresses(orgGroup, EndpointsKey)
+	if err != nil {
+		return fmt.Errorf("retrieving endpoints of orderer org %s: %w", orgName, err)
+	}
+
+	remaining, removed := removeAddress(addresses, address)
+	if !removed {
+		return fmt.Errorf("endpoint %s does not exist in orderer org %s", address, orgName)
+	}
+
+	err = setValue(orgGroup, endpointsValue(remaining), AdminsPolicyKey)
+	if err != nil {
+		return fmt.Errorf("removing endpoint %s from orderer org %s: %w", address, orgName, err)
+	}
+
+	o.tx.notify(configPath(ChannelGroupKey, OrdererGroupKey, orgName), "RemoveOrdererEndpoint")
+
 	return nil
 }
 
@@ -617,9 +882,13 @@ func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
 func (o *OrdererGroup) SetPolicy(modPolicy, policyName string, policy Policy) error {
 	err := setPolicy(o.ordererGroup, modPolicy, policyName, policy)
 	if err != nil {
-		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
+		return fmt.Errorf("failed to set policy '%s': %w", policyName, err)
 	}
 
+	o.tx.checkImplicitMetaPolicy(configPath(ChannelGroupKey, OrdererGroupKey), o.ordererGroup, policyName, policy)
+
+	o.tx.notify(o.path(), "SetPolicy")
+
 	return nil
 }
 
@@ -635,6 +904,9 @@ func (o *OrdererGroup) RemovePolicy(policyName string) error {
 	}
 
 	removePolicy(o.ordererGroup, policyName, policies)
+
+	o.tx.notify(o.path(), "RemovePolicy")
+
 	return nil
 }
 
@@ -649,7 +921,7 @@ func (o *OrdererGroup) Policies() (map[string]Policy, error) {
 func (o *OrdererOrg) SetMSP(updatedMSP MSP) error {
 	currentMSP, err := o.MSP().Configuration()
 	if err != nil {
-		return fmt.Errorf("retrieving msp: %v", err)
+		return fmt.Errorf("retrieving msp: %w", err)
 	}
 
 	if currentMSP.Name != updatedMSP.Name {
@@ -666,13 +938,26 @@ func (o *OrdererOrg) SetMSP(updatedMSP MSP) error {
 		return err
 	}
 
+	o.tx.notify(o.path(), "SetMSP")
+
+	o.tx.checkAdminCerts(o.path(), updatedMSP)
+
 	return nil
 }
 
 // SetPolicy sets the specified policy in the orderer org group's config policy map.
 // If the policy already exist in current configuration, its value will be overwritten.
 func (o *OrdererOrg) SetPolicy(modPolicy, policyName string, policy Policy) error {
-	return setPolicy(o.orgGroup, modPolicy, policyName, policy)
+	err := setPolicy(o.orgGroup, modPolicy, policyName, policy)
+	if err != nil {
+		return err
+	}
+
+	o.tx.checkImplicitMetaPolicy(configPath(ChannelGroupKey, OrdererGroupKey, o.name), o.orgGroup, policyName, policy)
+
+	o.tx.notify(o.path(), "SetPolicy")
+
+	return nil
 }
 
 // RemovePolicy removes an existing policy from an orderer organization.
@@ -683,6 +968,9 @@ func (o *OrdererOrg) RemovePolicy(policyName string) error {
 	}
 
 	removePolicy(o.orgGroup, policyName, policies)
+
+	o.tx.notify(o.path(), "RemovePolicy")
+
 	return nil
 }
 
@@ -696,14 +984,23 @@ func (o *OrdererOrg) Policies() (map[string]Policy, error) {
 // In fabric 2.0, kafka was deprecated as a consensus type.
 func (o *OrdererGroup) RemoveLegacyKafkaBrokers() {
 	delete(o.ordererGroup.Values, orderer.KafkaBrokersKey)
+
+	o.tx.notify(o.path(), "RemoveLegacyKafkaBrokers")
 }
 
-// newOrdererGroup returns the orderer component of the channel configuration.
+// NewOrdererGroup returns the orderer component of the channel configuration.
 // It defines parameters of the ordering service about how large blocks should be,
 // how frequently they should be emitted, etc. as well as the organizations of the ordering network.
 // It sets the mod_policy of all elements to "Admins".
-// This group is always present in any channel configuration.
-func newOrdererGroup(orderer Orderer) (*cb.ConfigGroup, error) {
+// This group is always present in any channel configuration. It can be used to
+// compose custom channel config trees.
+func NewOrdererGroup(orderer Orderer) (*cb.ConfigGroup, error) {
+	return newOrdererGroup(orderer, nil)
+}
+
+// newOrdererGroup returns the orderer group, converting the MSPs of the
+// organizations with mspConfigs.
+func newOrdererGroup(orderer Orderer, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
 	ordererGroup := newConfigGroup()
 	ordererGroup.ModPolicy = AdminsPolicyKey
 
@@ -724,15 +1021,32 @@ func newOrdererGroup(orderer Orderer) (*cb.ConfigGroup, error) {
 			return nil, fmt.Errorf("orderer endpoints are not defined for org %s", org.Name)
 		}
 
-		ordererGroup.Groups[org.Name], err = newOrdererOrgConfigGroup(org)
+		ordererGroup.Groups[org.Name], err = newOrdererOrgConfigGroup(org, mspConfigs)
 		if err != nil {
-			return nil, fmt.Errorf("org group '%s': %v", org.Name, err)
+			return nil, fmt.Errorf("org group '%s': %w", org.Name, err)
 		}
 	}
 
 	return ordererGroup, nil
 }
 
+// verifyConsenterHostname checks that the server TLS certificate of the
+// consenter is valid for its host.
+func verifyConsenterHostname(consenter orderer.Consenter) error {
+	address := fmt.Sprintf("%s:%d", consenter.Address.Host, consenter.Address.Port)
+
+	if consenter.ServerTLSCert == nil {
+		return fmt.Errorf("consenter %s has no server TLS certificate", address)
+	}
+
+	err := consenter.ServerTLSCert.VerifyHostname(consenter.Address.Host)
+	if err != nil {
+		return fmt.Errorf("server TLS certificate of consenter %s is not valid for host %s: %w", address, consenter.Address.Host, err)
+	}
+
+	return nil
+}
+
 // addOrdererValues adds configuration specified in Orderer to an orderer
 // *cb.ConfigGroup's Values map.
 func addOrdererValues(ordererGroup *cb.ConfigGroup, o Orderer) error {
@@ -745,6 +1059,11 @@ func addOrdererValues(ordererGroup *cb.ConfigGroup, o Orderer) error {
 		return err
 	}
 
+	err = validateBatchTimeout(o.BatchTimeout)
+	if err != nil {
+		return err
+	}
+
 	err = setValue(ordererGroup, batchTimeoutValue(o.BatchTimeout.String()), AdminsPolicyKey)
 	if err != nil {
 		return err
@@ -773,7 +1092,7 @@ func addOrdererValues(ordererGroup *cb.ConfigGroup, o Orderer) error {
 		}
 	case orderer.ConsensusTypeEtcdRaft:
 		if consensusMetadata, err = marshalEtcdRaftMetadata(o.EtcdRaft); err != nil {
-			return fmt.Errorf("marshaling etcdraft metadata for orderer type '%s': %v", orderer.ConsensusTypeEtcdRaft, err)
+			return fmt.Errorf("marshaling etcdraft metadata for orderer type '%s': %w", orderer.ConsensusTypeEtcdRaft, err)
 		}
 	default:
 		return fmt.Errorf("unknown orderer type '%s'", o.OrdererType)
@@ -792,6 +1111,16 @@ func addOrdererValues(ordererGroup *cb.ConfigGroup, o Orderer) error {
 	return nil
 }
 
+// validateBatchTimeout ensures the batch timeout is positive. The orderer
+// rejects batch timeouts that are zero or negative.
+func validateBatchTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return fmt.Errorf("batch timeout must be greater than zero, got '%s'", timeout)
+	}
+
+	return nil
+}
+
 // setOrdererPolicies adds *cb.ConfigPolicies to the passed Orderer *cb.ConfigGroup's Policies map.
 // It checks that the BlockValidation policy is defined alongside the standard policy checks.
 func setOrdererPolicies(cg *cb.ConfigGroup, policyMap map[string]Policy, modPolicy string) error {
@@ -829,6 +1158,64 @@ func batchTimeoutValue(timeout string) *standardConfigValue {
 	}
 }
 
+// ordererAddresses returns the addresses of the OrdererAddresses value at
+// key, or no addresses if the group has no such value.
+func ordererAddresses(group *cb.ConfigGroup, key string) ([]string, error) {
+	if _, ok := group.Values[key]; !ok {
+		return nil, nil
+	}
+
+	addresses := &cb.OrdererAddresses{}
+	err := unmarshalConfigValueAtKey(group, key, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	return addresses.Addresses, nil
+}
+
+// removeAddress returns the addresses without address, compared with
+// normalized hosts, and whether it was present.
+func removeAddress(addresses []string, address string) ([]string, bool) {
+	var remaining []string
+	removed := false
+	for _, existing := range addresses {
+		if sameAddress(existing, address) {
+			removed = true
+			continue
+		}
+		remaining = append(remaining, existing)
+	}
+
+	return remaining, removed
+}
+
+// parseEndpointAddress checks that address has the host:port form of an
+// orderer endpoint and returns it in canonical form, with a normalized
+// host.
+func parseEndpointAddress(address string) (string, error) {
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %s: %w", address, err)
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("invalid endpoint %s: missing host", address)
+	}
+
+	host, err = normalizeHost(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %s: %w", address, err)
+	}
+
+	port, err := strconv.Atoi(portString)
+	if err != nil || port < 1 || port > 65535 {
+		return "", fmt.Errorf("invalid endpoint %s: invalid port %s", address, portString)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
 // endpointsValue returns the config definition for the orderer addresses at an org scoped level.
 // It is a value for the /Channel/Orderer/<OrgName> group.
 func endpointsValue(addresses []string) *standardConfigValue {
@@ -924,18 +1311,18 @@ func marshalEtcdRaftMetadata(md orderer.EtcdRaft) ([]byte, error) {
 
 	data, err := proto.Marshal(configMetadata)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling config metadata: %v", err)
+		return nil, fmt.Errorf("marshaling config metadata: %w", err)
 	}
 
 	return data, nil
 }
 
 // unmarshalEtcdRaftMetadata deserializes etcd RAFT metadata.
-func unmarshalEtcdRaftMetadata(mdBytes []byte) (orderer.EtcdRaft, error) {
+func unmarshalEtcdRaftMetadata(mdBytes []byte, interner *certificateInterner) (orderer.EtcdRaft, error) {
 	etcdRaftMetadata := &eb.ConfigMetadata{}
 	err := proto.Unmarshal(mdBytes, etcdRaftMetadata)
 	if err != nil {
-		return orderer.EtcdRaft{}, fmt.Errorf("unmarshaling etcd raft metadata: %v", err)
+		return orderer.EtcdRaft{}, fmt.Errorf("unmarshaling etcd raft metadata: %w", err)
 	}
 
 	consenters := []orderer.Consenter{}
@@ -945,17 +1332,17 @@ func unmarshalEtcdRaftMetadata(mdBytes []byte) (orderer.EtcdRaft, error) {
 		if clientTLSCertBlock == nil {
 			return orderer.EtcdRaft{}, fmt.Errorf("no PEM data found in client TLS cert[% x]", c.ClientTlsCert)
 		}
-		clientTLSCert, err := x509.ParseCertificate(clientTLSCertBlock.Bytes)
+		clientTLSCert, err := interner.parse(clientTLSCertBlock.Bytes)
 		if err != nil {
-			return orderer.EtcdRaft{}, fmt.Errorf("unable to parse client tls cert: %v", err)
+			return orderer.EtcdRaft{}, fmt.Errorf("unable to parse client tls cert: %w", err)
 		}
 		serverTLSCertBlock, _ := pem.Decode(c.ServerTlsCert)
 		if serverTLSCertBlock == nil {
 			return orderer.EtcdRaft{}, fmt.Errorf("no PEM data found in server TLS cert[% x]", c.ServerTlsCert)
 		}
-		serverTLSCert, err := x509.ParseCertificate(serverTLSCertBlock.Bytes)
+		serverTLSCert, err := interner.parse(serverTLSCertBlock.Bytes)
 		if err != nil {
-			return orderer.EtcdRaft{}, fmt.Errorf("unable to parse server tls cert: %v", err)
+			return orderer.EtcdRaft{}, fmt.Errorf("unable to parse server tls cert: %w", err)
 		}
 
 		consenter := orderer.Consenter{
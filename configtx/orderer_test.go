@@ -10,9 +10,12 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"fmt"
 	"math/big"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -221,7 +224,7 @@ func TestNewOrdererGroup(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -446,7 +449,7 @@ func TestNewOrdererGroup(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -697,7 +700,7 @@ func TestNewOrdererGroup(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -753,7 +756,7 @@ func TestNewOrdererGroup(t *testing.T) {
 
 			ordererConf, _ := baseOrdererOfType(t, tt.ordererType)
 
-			ordererGroup, err := newOrdererGroup(ordererConf)
+			ordererGroup, err := NewOrdererGroup(ordererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 			expectedConfigJSON := tt.expectedConfigJSONGen(ordererConf)
 
@@ -787,6 +790,20 @@ func TestNewOrdererGroupFailure(t *testing.T) {
 			},
 			err: "unknown orderer type 'ConsensusTypeGreen'",
 		},
+		{
+			testName: "When batch timeout is zero",
+			ordererMod: func(o *Orderer) {
+				o.BatchTimeout = 0
+			},
+			err: "batch timeout must be greater than zero, got '0s'",
+		},
+		{
+			testName: "When batch timeout is negative",
+			ordererMod: func(o *Orderer) {
+				o.BatchTimeout = -time.Second
+			},
+			err: "batch timeout must be greater than zero, got '-1s'",
+		},
 		{
 			testName: "When adding policies to orderer org group",
 			ordererMod: func(o *Orderer) {
@@ -867,7 +884,7 @@ func TestNewOrdererGroupFailure(t *testing.T) {
 			ordererConf, _ := baseSoloOrderer(t)
 			tt.ordererMod(&ordererConf)
 
-			ordererGroup, err := newOrdererGroup(ordererConf)
+			ordererGroup, err := NewOrdererGroup(ordererConf)
 			gt.Expect(err).To(MatchError(tt.err))
 			gt.Expect(ordererGroup).To(BeNil())
 		})
@@ -882,7 +899,7 @@ func TestSetOrdererConfiguration(t *testing.T) {
 	baseOrdererConf, _ := baseSoloOrderer(t)
 	certBase64, crlBase64 := certCRLBase64(t, baseOrdererConf.Organizations[0].MSP)
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	imp, err := implicitMetaFromString(baseOrdererConf.Policies[AdminsPolicyKey].Rule)
@@ -1120,7 +1137,7 @@ func TestSetOrdererConfiguration(t *testing.T) {
 					"BatchTimeout": {
 						"mod_policy": "Admins",
 						"value": {
-							"timeout": "0s"
+							"timeout": "2s"
 						},
 						"version": "0"
 					},
@@ -1223,7 +1240,7 @@ func TestOrdererConfiguration(t *testing.T) {
 
 			baseOrdererConf, _ := baseOrdererOfType(t, tt.ordererType)
 
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			config := &cb.Config{
@@ -1251,7 +1268,7 @@ func TestOrdererConfigurationNoOrdererEndpoints(t *testing.T) {
 
 	baseOrdererConf, _ := baseOrdererOfType(t, orderer.ConsensusTypeSolo)
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1327,7 +1344,7 @@ func TestOrdererConfigurationFailure(t *testing.T) {
 			gt := NewGomegaWithT(t)
 
 			baseOrdererConfig, _ := baseOrdererOfType(t, tt.ordererType)
-			ordererGroup, err := newOrdererGroup(baseOrdererConfig)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConfig)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			config := &cb.Config{
@@ -1357,7 +1374,7 @@ func TestSetOrdererOrg(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	orderer, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(orderer)
+	ordererGroup, err := NewOrdererGroup(orderer)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1520,7 +1537,7 @@ func TestSetOrdererOrgFailures(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	orderer, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(orderer)
+	ordererGroup, err := NewOrdererGroup(orderer)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1773,7 +1790,7 @@ func TestOrdererCapabilities(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -1803,7 +1820,7 @@ func TestAddOrdererCapability(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -2002,7 +2019,7 @@ func TestAddOrdererCapability(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -2078,7 +2095,7 @@ func TestAddConsenter(t *testing.T) {
 
 			baseOrdererConf, _ := baseEtcdRaftOrderer(t)
 			baseOrdererConf = tt.baseOrderer(baseOrdererConf)
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			config := &cb.Config{
@@ -2279,7 +2296,7 @@ func TestAddConsenter(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -2442,7 +2459,7 @@ func TestAddConsenterFailures(t *testing.T) {
 			baseOrdererConf, _ := baseEtcdRaftOrderer(t)
 			ord := tt.orderer(baseOrdererConf)
 
-			ordererGroup, err := newOrdererGroup(ord)
+			ordererGroup, err := NewOrdererGroup(ord)
 			gt.Expect(err).NotTo(HaveOccurred())
 			tt.ordererGroup(ordererGroup, ord)
 
@@ -2473,13 +2490,123 @@ func TestAddConsenterFailures(t *testing.T) {
 	}
 }
 
+func TestVerifyConsenterHostnames(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	for i, c := range baseOrdererConf.EtcdRaft.Consenters {
+		baseOrdererConf.EtcdRaft.Consenters[i].ServerTLSCert = generateServerTLSCert(t, caCert, caPrivKey, []string{c.Address.Host}, nil)
+	}
+	baseOrdererConf.EtcdRaft.Consenters[2].Address.Host = "10.0.0.3"
+	baseOrdererConf.EtcdRaft.Consenters[2].ServerTLSCert = generateServerTLSCert(t, caCert, caPrivKey, []string{"node-3.example.com"}, []net.IP{net.ParseIP("10.0.0.3")})
+
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+
+	err = c.Orderer().VerifyConsenterHostnames()
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestVerifyConsenterHostnamesFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	for i, c := range baseOrdererConf.EtcdRaft.Consenters {
+		baseOrdererConf.EtcdRaft.Consenters[i].ServerTLSCert = generateServerTLSCert(t, caCert, caPrivKey, []string{c.Address.Host}, nil)
+	}
+	baseOrdererConf.EtcdRaft.Consenters[1].ServerTLSCert = baseOrdererConf.EtcdRaft.Consenters[0].ServerTLSCert
+
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+
+	err = c.Orderer().VerifyConsenterHostnames()
+	gt.Expect(err).To(MatchError("invalid consenter server TLS certificates: " +
+		"server TLS certificate of consenter node-2.example.com:7050 is not valid for host node-2.example.com: " +
+		"x509: certificate is valid for node-1.example.com, not node-2.example.com"))
+
+	soloOrdererConf := baseOrdererConf
+	soloOrdererConf.OrdererType = orderer.ConsensusTypeSolo
+	err = c.Orderer().SetConfiguration(soloOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Orderer().VerifyConsenterHostnames()
+	gt.Expect(err).To(MatchError("consensus type solo is not etcdraft"))
+}
+
+func TestAddConsenterHostnameWarning(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var warnings []Warning
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	}, WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	consenter := orderer.Consenter{
+		Address: orderer.EtcdAddress{
+			Host: "node-4.example.com",
+			Port: 7050,
+		},
+		ClientTLSCert: baseOrdererConf.EtcdRaft.Consenters[0].ClientTLSCert,
+		ServerTLSCert: generateServerTLSCert(t, caCert, caPrivKey, []string{"node-4.example.com"}, nil),
+	}
+
+	err = c.Orderer().AddConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).To(BeEmpty())
+
+	consenter.Address.Host = "node-5.example.com"
+	err = c.Orderer().AddConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).To(Equal([]Warning{
+		{
+			Path:    "/Channel/Orderer",
+			Message: "server TLS certificate of consenter node-5.example.com:7050 is not valid for host node-5.example.com: x509: certificate is valid for node-4.example.com, not node-5.example.com",
+		},
+	}))
+}
+
 func TestRemoveConsenter(t *testing.T) {
 	t.Parallel()
 
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -2680,7 +2807,7 @@ func TestRemoveConsenter(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -2816,7 +2943,7 @@ func TestRemoveConsenterFailures(t *testing.T) {
 			baseOrdererConf, _ := baseEtcdRaftOrderer(t)
 			ord := tt.orderer(baseOrdererConf)
 
-			ordererGroup, err := newOrdererGroup(ord)
+			ordererGroup, err := NewOrdererGroup(ord)
 			gt.Expect(err).NotTo(HaveOccurred())
 			tt.ordererGroup(ordererGroup, ord)
 
@@ -2878,7 +3005,7 @@ func TestAddOrdererCapabilityFailures(t *testing.T) {
 			gt := NewGomegaWithT(t)
 
 			baseOrdererConf, _ := baseSoloOrderer(t)
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 			tt.ordererGroup(ordererGroup)
 
@@ -2904,7 +3031,7 @@ func TestRemoveOrdererCapability(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3103,7 +3230,7 @@ func TestRemoveOrdererCapability(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -3182,7 +3309,7 @@ func TestRemoveOrdererCapabilityFailures(t *testing.T) {
 			gt := NewGomegaWithT(t)
 
 			baseOrdererConf, _ := baseSoloOrderer(t)
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 			tt.ordererGroup(ordererGroup)
 
@@ -3207,7 +3334,7 @@ func TestOrdererOrg(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	channel, _, _ := baseSystemChannelProfile(t)
-	channelGroup, err := newSystemChannelGroup(channel)
+	channelGroup, err := newSystemChannelGroup(channel, nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3253,7 +3380,7 @@ func TestRemoveOrdererOrg(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	channel, _, _ := baseSystemChannelProfile(t)
-	channelGroup, err := newSystemChannelGroup(channel)
+	channelGroup, err := newSystemChannelGroup(channel, nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3273,7 +3400,7 @@ func TestSetOrdererPolicy(t *testing.T) {
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3324,7 +3451,7 @@ func TestSetOrdererPolicyFailures(t *testing.T) {
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3349,7 +3476,7 @@ func TestRemoveOrdererPolicy(t *testing.T) {
 	baseOrdererConf, _ := baseSoloOrderer(t)
 	baseOrdererConf.Policies["TestPolicy"] = baseOrdererConf.Policies[AdminsPolicyKey]
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3397,7 +3524,7 @@ func TestRemoveOrdererPolicyFailures(t *testing.T) {
 	baseOrdererConf, _ := baseSoloOrderer(t)
 	baseOrdererConf.Policies["TestPolicy"] = baseOrdererConf.Policies[AdminsPolicyKey]
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3451,7 +3578,7 @@ func TestSetOrdererOrgPolicy(t *testing.T) {
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3503,7 +3630,7 @@ func TestSetOrdererOrgPolicyFailures(t *testing.T) {
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3528,7 +3655,7 @@ func TestRemoveOrdererOrgPolicy(t *testing.T) {
 	baseOrdererConf, _ := baseSoloOrderer(t)
 	baseOrdererConf.Organizations[0].Policies["TestPolicy"] = baseOrdererConf.Organizations[0].Policies[AdminsPolicyKey]
 
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3576,7 +3703,7 @@ func TestOrdererMSP(t *testing.T) {
 	soloOrderer, _ := baseSoloOrderer(t)
 	expectedMSP := soloOrderer.Organizations[0].MSP
 
-	ordererGroup, err := newOrdererGroup(soloOrderer)
+	ordererGroup, err := NewOrdererGroup(soloOrderer)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -3826,7 +3953,7 @@ func TestUpdateOrdererMSP(t *testing.T) {
 					"BatchTimeout": {
 						"mod_policy": "Admins",
 						"value": {
-							"timeout": "0s"
+							"timeout": "2s"
 						},
 						"version": "0"
 					},
@@ -3902,7 +4029,7 @@ func TestUpdateOrdererMSPFailure(t *testing.T) {
 				return msp
 			},
 			orgName:     "OrdererOrg",
-			expectedErr: "invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7",
+			expectedErr: "invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7; intermediate cert not signed by any root certs of this MSP. serial number: {{intermediateSerial}}",
 		},
 	}
 
@@ -3923,9 +4050,11 @@ func TestUpdateOrdererMSPFailure(t *testing.T) {
 			ordererMSP, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
 			gt.Expect(err).NotTo(HaveOccurred())
 
+			intermediateSerial := ordererMSP.IntermediateCerts[0].SerialNumber.String()
+
 			ordererMSP = tc.mspMod(ordererMSP)
 			err = c.Orderer().Organization(tc.orgName).SetMSP(ordererMSP)
-			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(err).To(MatchError(strings.Replace(tc.expectedErr, "{{intermediateSerial}}", intermediateSerial, -1)))
 		})
 	}
 }
@@ -3967,7 +4096,7 @@ func TestRemoveLegacyKafkaBrokers(t *testing.T) {
 		orderer.BatchTimeoutKey: {
 			ModPolicy: AdminsPolicyKey,
 			Value: marshalOrPanic(&ob.BatchTimeout{
-				Timeout: "0s",
+				Timeout: "2s",
 			}),
 		},
 		orderer.BatchSizeKey: {
@@ -3989,7 +4118,7 @@ func TestSetBatchSizeValues(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -4190,7 +4319,7 @@ func TestSetBatchSizeValues(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -4271,7 +4400,7 @@ func TestSetMaxMessageCountFailures(t *testing.T) {
 
 	gt := NewGomegaWithT(t)
 	baseOrdererConf, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	ordererGroup.Values[orderer.BatchSizeKey] = &cb.ConfigValue{Value: []byte("{")}
@@ -4294,7 +4423,7 @@ func TestSetAbsoluteMaxBytesFailures(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	ordererGroup.Values[orderer.BatchSizeKey] = &cb.ConfigValue{Value: []byte("{")}
@@ -4317,7 +4446,7 @@ func TestSetPreferredMaxBytesFailures(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseSoloOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	ordererGroup.Values[orderer.BatchSizeKey] = &cb.ConfigValue{Value: []byte("{")}
@@ -4340,7 +4469,7 @@ func TestSetBatchTimeout(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -4611,13 +4740,39 @@ func TestSetBatchTimeout(t *testing.T) {
 	gt.Expect(buf.String()).To(Equal(expectedConfigGroupJSON))
 }
 
+func TestSetBatchTimeoutFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSoloOrderer(t)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	}
+
+	c := New(config)
+
+	err = c.Orderer().SetBatchTimeout(0)
+	gt.Expect(err).To(MatchError("batch timeout must be greater than zero, got '0s'"))
+
+	err = c.Orderer().SetBatchTimeout(-5 * time.Millisecond)
+	gt.Expect(err).To(MatchError("batch timeout must be greater than zero, got '-5ms'"))
+}
+
 func TestSetMaxChannels(t *testing.T) {
 	t.Parallel()
 
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -4818,7 +4973,7 @@ func TestSetMaxChannels(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -4917,7 +5072,7 @@ func TestSetConsensusType(t *testing.T) {
 				baseOrdererConf, _ = baseKafkaOrderer(t)
 			}
 
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			config := &cb.Config{
@@ -5115,7 +5270,7 @@ func TestSetConsensusType(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -5208,7 +5363,7 @@ func TestSetConsensusTypeFailures(t *testing.T) {
 			gt := NewGomegaWithT(t)
 
 			baseOrdererConf, _ := baseSoloOrderer(t)
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			delete(ordererGroup.Values, orderer.ConsensusTypeKey)
@@ -5234,7 +5389,7 @@ func TestSetConsensusState(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -5435,7 +5590,7 @@ func TestSetConsensusState(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -5522,7 +5677,7 @@ func TestSetConsensusStateFailures(t *testing.T) {
 			gt := NewGomegaWithT(t)
 
 			baseOrdererConf, _ := baseSoloOrderer(t)
-			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 			gt.Expect(err).NotTo(HaveOccurred())
 
 			delete(ordererGroup.Values, orderer.ConsensusTypeKey)
@@ -5548,7 +5703,7 @@ func TestSetEtcdRaftOptions(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
 	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
-	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -5749,7 +5904,7 @@ func TestSetEtcdRaftOptions(t *testing.T) {
 		"BatchTimeout": {
 			"mod_policy": "Admins",
 			"value": {
-				"timeout": "0s"
+				"timeout": "2s"
 			},
 			"version": "0"
 		},
@@ -5858,6 +6013,7 @@ func baseSoloOrderer(t *testing.T) (Orderer, []*ecdsa.PrivateKey) {
 			},
 		},
 		Capabilities: []string{"V1_3"},
+		BatchTimeout: 2 * time.Second,
 		BatchSize: orderer.BatchSize{
 			MaxMessageCount:   100,
 			AbsoluteMaxBytes:  100,
@@ -5877,6 +6033,25 @@ func baseKafkaOrderer(t *testing.T) (Orderer, []*ecdsa.PrivateKey) {
 	return soloOrderer, privKeys
 }
 
+// generateServerTLSCert returns a server TLS certificate signed by the
+// given CA for the given DNS and IP subject alternative names.
+func generateServerTLSCert(t *testing.T, caCert *x509.Certificate, caPrivKey *ecdsa.PrivateKey, dnsNames []string, ipAddresses []net.IP) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		Subject: pkix.Name{
+			CommonName: "orderer",
+		},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:    dnsNames,
+		IPAddresses: ipAddresses,
+	}
+	cert, _ := generateCertAndPrivateKey(t, template, caCert, caPrivKey)
+	return cert
+}
+
 func baseEtcdRaftOrderer(t *testing.T) (Orderer, []*ecdsa.PrivateKey) {
 	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
 	cert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
@@ -5922,7 +6097,7 @@ func baseOrdererChannelGroup(t *testing.T, ordererType string) (*cb.ConfigGroup,
 	channelGroup := newConfigGroup()
 
 	ordererConf, privKeys := baseOrdererOfType(t, ordererType)
-	ordererGroup, err := newOrdererGroup(ordererConf)
+	ordererGroup, err := NewOrdererGroup(ordererConf)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -5940,3 +6115,159 @@ func marshalOrPanic(pb proto.Message) []byte {
 
 	return data
 }
+
+func TestAddRemoveOrdererEndpoint(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"OrdererOrg": {
+							Values: map[string]*cb.ConfigValue{
+								EndpointsKey: {
+									ModPolicy: AdminsPolicyKey,
+									Value: marshalOrPanic(&cb.OrdererAddresses{
+										Addresses: []string{"127.0.0.1:7050"},
+									}),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	c := New(config)
+
+	err := c.Orderer().AddOrdererEndpoint("OrdererOrg", "orderer2.example.com:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().AddOrdererEndpoint("OrdererOrg", "[::1]:8050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orgGroup := c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"]
+	endpoints, err := ordererAddresses(orgGroup, EndpointsKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(endpoints).To(Equal([]string{"127.0.0.1:7050", "orderer2.example.com:7050", "[::1]:8050"}))
+
+	err = c.Orderer().RemoveOrdererEndpoint("OrdererOrg", "127.0.0.1:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	endpoints, err = ordererAddresses(orgGroup, EndpointsKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(endpoints).To(Equal([]string{"orderer2.example.com:7050", "[::1]:8050"}))
+}
+
+func TestAddRemoveOrdererEndpointFailures(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func() *cb.Config {
+		return &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					OrdererGroupKey: {
+						Groups: map[string]*cb.ConfigGroup{
+							"OrdererOrg": {
+								Values: map[string]*cb.ConfigValue{
+									EndpointsKey: {
+										ModPolicy: AdminsPolicyKey,
+										Value: marshalOrPanic(&cb.OrdererAddresses{
+											Addresses: []string{"127.0.0.1:7050"},
+										}),
+									},
+								},
+							},
+							"BadOrg": {
+								Values: map[string]*cb.ConfigValue{
+									EndpointsKey: {Value: []byte("fire time")},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		remove      bool
+		org         string
+		address     string
+		expectedErr string
+	}{
+		{
+			name:        "missing port",
+			org:         "OrdererOrg",
+			address:     "127.0.0.1",
+			expectedErr: "invalid endpoint 127.0.0.1: address 127.0.0.1: missing port in address",
+		},
+		{
+			name:        "missing host",
+			org:         "OrdererOrg",
+			address:     ":7050",
+			expectedErr: "invalid endpoint :7050: missing host",
+		},
+		{
+			name:        "invalid port",
+			org:         "OrdererOrg",
+			address:     "127.0.0.1:70500",
+			expectedErr: "invalid endpoint 127.0.0.1:70500: invalid port 70500",
+		},
+		{
+			name:        "org does not exist",
+			org:         "MissingOrg",
+			address:     "127.0.0.1:8050",
+			expectedErr: "orderer org MissingOrg does not exist",
+		},
+		{
+			name:        "duplicate endpoint",
+			org:         "OrdererOrg",
+			address:     "127.0.0.1:7050",
+			expectedErr: "endpoint 127.0.0.1:7050 already exists in orderer org OrdererOrg",
+		},
+		{
+			name:        "invalid endpoints value",
+			org:         "BadOrg",
+			address:     "127.0.0.1:7050",
+			expectedErr: "retrieving endpoints of orderer org BadOrg: unmarshaling Endpoints: proto: can't skip unknown wire type 6",
+		},
+		{
+			name:        "remove endpoint that does not exist",
+			remove:      true,
+			org:         "OrdererOrg",
+			address:     "127.0.0.1:8050",
+			expectedErr: "endpoint 127.0.0.1:8050 does not exist in orderer org OrdererOrg",
+		},
+		{
+			name:        "remove invalid endpoint",
+			remove:      true,
+			org:         "OrdererOrg",
+			address:     "127.0.0.1:port",
+			expectedErr: "invalid endpoint 127.0.0.1:port: invalid port port",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			c := New(newConfig())
+
+			var err error
+			if tc.remove {
+				err = c.Orderer().RemoveOrdererEndpoint(tc.org, tc.address)
+			} else {
+				err = c.Orderer().AddOrdererEndpoint(tc.org, tc.address)
+			}
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
@@ -9,7 +9,6 @@ package configtx
 import (
 	"fmt"
 
-	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	mb "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
@@ -18,7 +17,7 @@ import (
 // newOrgConfigGroup returns an config group for an organization.
 // It defines the crypto material for the organization (its MSP).
 // It sets the mod_policy of all elements to "Admins".
-func newOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
+func newOrgConfigGroup(org Organization, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
 	orgGroup := newConfigGroup()
 	orgGroup.ModPolicy = AdminsPolicyKey
 
@@ -26,14 +25,9 @@ func newOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
 		return nil, err
 	}
 
-	fabricMSPConfig, err := org.MSP.toProto()
+	conf, err := mspConfigs.marshaledMSPConfig(org.MSP)
 	if err != nil {
-		return nil, fmt.Errorf("converting fabric msp config to proto: %v", err)
-	}
-
-	conf, err := proto.Marshal(fabricMSPConfig)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling msp config: %v", err)
+		return nil, err
 	}
 
 	// mspConfig defaults type to FABRIC which implements an X.509 based provider
@@ -49,8 +43,8 @@ func newOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
 	return orgGroup, nil
 }
 
-func newOrdererOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
-	orgGroup, err := newOrgConfigGroup(org)
+func newOrdererOrgConfigGroup(org Organization, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
+	orgGroup, err := newOrgConfigGroup(org, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
@@ -66,8 +60,8 @@ func newOrdererOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
 	return orgGroup, nil
 }
 
-func newApplicationOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
-	orgGroup, err := newOrgConfigGroup(org)
+func newApplicationOrgConfigGroup(org Organization, mspConfigs *mspConfigCache) (*cb.ConfigGroup, error) {
+	orgGroup, err := newOrgConfigGroup(org, mspConfigs)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +81,27 @@ func newApplicationOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
 	if len(anchorProtos) > 0 {
 		err := setValue(orgGroup, anchorPeersValue(anchorProtos), AdminsPolicyKey)
 		if err != nil {
-			return nil, fmt.Errorf("failed to add anchor peers value: %v", err)
+			return nil, fmt.Errorf("failed to add anchor peers value: %w", err)
+		}
+	}
+
+	return orgGroup, nil
+}
+
+// NewOrgGroup returns the config group of an organization with its MSP and
+// policies, and with its anchor peers and orderer endpoints when they are
+// set. It sets the mod_policy of all elements to "Admins". It can be used to
+// compose custom channel config trees.
+func NewOrgGroup(org Organization) (*cb.ConfigGroup, error) {
+	orgGroup, err := newApplicationOrgConfigGroup(org, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(org.OrdererEndpoints) > 0 {
+		err := setValue(orgGroup, endpointsValue(org.OrdererEndpoints), AdminsPolicyKey)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -95,13 +109,13 @@ func newApplicationOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
 }
 
 // getOrganization returns a basic Organization struct from org config group.
-func getOrganization(orgGroup *cb.ConfigGroup, orgName string) (Organization, error) {
+func getOrganization(orgGroup *cb.ConfigGroup, orgName string, interner *certificateInterner) (Organization, error) {
 	policies, err := getPolicies(orgGroup.Policies)
 	if err != nil {
 		return Organization{}, err
 	}
 
-	msp, err := getMSPConfig(orgGroup)
+	msp, err := getMSPConfig(orgGroup, interner)
 	if err != nil {
 		return Organization{}, err
 	}
@@ -130,3 +144,66 @@ func getOrganization(orgGroup *cb.ConfigGroup, orgName string) (Organization, er
 		AnchorPeers: anchorPeers,
 	}, nil
 }
+
+// HasApplicationOrg reports whether the updated config contains an
+// application organization with the given name.
+func (c *ConfigTx) HasApplicationOrg(name string) bool {
+	return hasSubGroup(c.updated.ChannelGroup, ApplicationGroupKey, name)
+}
+
+// HasOrdererOrg reports whether the updated config contains an orderer
+// organization with the given name.
+func (c *ConfigTx) HasOrdererOrg(name string) bool {
+	return hasSubGroup(c.updated.ChannelGroup, OrdererGroupKey, name)
+}
+
+// MSPForOrg returns the MSP configuration of the organization with the
+// given name in the updated config. Application organizations are searched
+// first, then orderer organizations and then the organizations of each
+// consortium in lexical order of consortium name.
+func (c *ConfigTx) MSPForOrg(name string) (MSP, error) {
+	channelGroup := c.updated.ChannelGroup
+
+	var orgGroup *cb.ConfigGroup
+	var path string
+	for _, key := range []string{ApplicationGroupKey, OrdererGroupKey} {
+		if hasSubGroup(channelGroup, key, name) {
+			orgGroup = channelGroup.Groups[key].Groups[name]
+			path = configPath(ChannelGroupKey, key, name)
+			break
+		}
+	}
+
+	if consortiumsGroup, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok && orgGroup == nil {
+		for _, consortiumName := range sortedGroupNames(consortiumsGroup) {
+			if hasSubGroup(consortiumsGroup, consortiumName, name) {
+				orgGroup = consortiumsGroup.Groups[consortiumName].Groups[name]
+				path = configPath(ChannelGroupKey, ConsortiumsGroupKey, consortiumName, name)
+				break
+			}
+		}
+	}
+
+	if orgGroup == nil {
+		return MSP{}, fmt.Errorf("organization %s does not exist", name)
+	}
+
+	msp, err := getMSPConfig(orgGroup, c.options.certificates)
+	if err != nil {
+		return MSP{}, fmt.Errorf("retrieving MSP of %s: %w", path, err)
+	}
+
+	return msp, nil
+}
+
+// hasSubGroup reports whether the group key of the parent group contains
+// a group with the given name.
+func hasSubGroup(parent *cb.ConfigGroup, key, name string) bool {
+	group, ok := parent.Groups[key]
+	if !ok {
+		return false
+	}
+
+	_, ok = group.Groups[name]
+	return ok
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-config/protolator/protoext/ordererext"
+	cb "github.com/hyperledger/fabric-protos-go/common"
 	. "github.com/onsi/gomega"
 )
 
@@ -22,10 +23,10 @@ func TestOrganization(t *testing.T) {
 
 	expectedOrg := baseApplicationOrg(t)
 	expectedOrg.AnchorPeers = nil
-	orgGroup, err := newOrgConfigGroup(expectedOrg)
+	orgGroup, err := newOrgConfigGroup(expectedOrg, nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 
-	org, err := getOrganization(orgGroup, "Org1")
+	org, err := getOrganization(orgGroup, "Org1", nil)
 	gt.Expect(err).NotTo(HaveOccurred())
 	gt.Expect(expectedOrg).To(Equal(org))
 }
@@ -39,7 +40,7 @@ func TestNewOrgConfigGroup(t *testing.T) {
 
 		baseSystemChannelProfile, _, _ := baseSystemChannelProfile(t)
 		org := baseSystemChannelProfile.Orderer.Organizations[0]
-		configGroup, err := newOrdererOrgConfigGroup(org)
+		configGroup, err := newOrdererOrgConfigGroup(org, nil)
 		gt.Expect(err).NotTo(HaveOccurred())
 
 		certBase64, crlBase64 := certCRLBase64(t, org.MSP)
@@ -186,7 +187,7 @@ func TestNewOrgConfigGroupFailure(t *testing.T) {
 	baseOrg := baseSystemChannelProfile.Orderer.Organizations[0]
 	baseOrg.Policies = nil
 
-	configGroup, err := newOrgConfigGroup(baseOrg)
+	configGroup, err := newOrgConfigGroup(baseOrg, nil)
 	gt.Expect(configGroup).To(BeNil())
 	gt.Expect(err).To(MatchError("no policies defined"))
 }
@@ -202,3 +203,98 @@ func baseApplicationOrg(t *testing.T) Organization {
 		},
 	}
 }
+
+func TestOrgQueries(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	gt.Expect(c.HasApplicationOrg("Org1")).To(BeTrue())
+	gt.Expect(c.HasApplicationOrg("OrdererOrg")).To(BeFalse())
+	gt.Expect(c.HasOrdererOrg("OrdererOrg")).To(BeTrue())
+	gt.Expect(c.HasOrdererOrg("Org1")).To(BeFalse())
+
+	msp, err := c.MSPForOrg("Org2")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Name).To(Equal(profile.Application.Organizations[1].MSP.Name))
+
+	msp, err = c.MSPForOrg("OrdererOrg")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Name).To(Equal(profile.Orderer.Organizations[0].MSP.Name))
+
+	_, err = c.MSPForOrg("Org3")
+	gt.Expect(err).To(MatchError("organization Org3 does not exist"))
+
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey].Value = []byte("invalid")
+	_, err = c.MSPForOrg("Org1")
+	gt.Expect(err).To(MatchError(HavePrefix("retrieving MSP of /Channel/Application/Org1: unmarshaling MSP: ")))
+}
+
+func TestOrgQueriesConsortiums(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	gt.Expect(c.HasApplicationOrg("Org1")).To(BeFalse())
+
+	msp, err := c.MSPForOrg("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Name).To(Equal(profile.Consortiums[0].Organizations[0].MSP.Name))
+}
+
+func TestNewOrgGroup(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	org := application.Organizations[0]
+	org.AnchorPeers = []Address{{Host: "peer0.org1.example.com", Port: 7051}}
+	org.OrdererEndpoints = []string{"orderer.org1.example.com:7050"}
+
+	orgGroup, err := NewOrgGroup(org)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orgGroup.ModPolicy).To(Equal(AdminsPolicyKey))
+	gt.Expect(orgGroup.Values).To(HaveKey(MSPKey))
+	gt.Expect(orgGroup.Values).To(HaveKey(AnchorPeersKey))
+	gt.Expect(orgGroup.Values).To(HaveKey(EndpointsKey))
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						org.Name: orgGroup,
+					},
+				},
+			},
+		},
+	})
+
+	orgConfig, err := c.Application().Organization(org.Name).Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orgConfig.AnchorPeers).To(Equal(org.AnchorPeers))
+	gt.Expect(orgConfig.MSP).To(Equal(org.MSP))
+
+	withoutExtras := application.Organizations[1]
+	orgGroup, err = NewOrgGroup(withoutExtras)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orgGroup.Values).NotTo(HaveKey(AnchorPeersKey))
+	gt.Expect(orgGroup.Values).NotTo(HaveKey(EndpointsKey))
+}
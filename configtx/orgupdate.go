@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// OrgUpdate is the update of a single organization extracted from the
+// pending update by OrgUpdate.
+type OrgUpdate struct {
+	// Update is the marshaled config update, which only changes the
+	// organization.
+	Update []byte
+	// ModPolicies are the resolved mod policies of the elements modified by
+	// the update, sorted by path. Each of them must be satisfied by the
+	// signatures of the update.
+	ModPolicies []ResolvedPolicy
+	// OrgSigned is set if every signer of the mod policies belongs to the
+	// MSP of the organization, so that the update can be signed by the
+	// organization alone.
+	OrgSigned bool
+}
+
+// OrgUpdate computes the update of the organization at orgPath, e.g.
+// /Channel/Application/Org2, from the original config to the updated
+// config, leaving out every other change, so that an organization can
+// update its own MSP or anchor peers without collecting the signatures
+// required by the rest of the pending update. The organization must exist
+// in both configs, as adding or removing an organization modifies its
+// parent group. The mod policies that authorize the update are resolved
+// against the original config with the policy evaluator of the ConfigTx,
+// as the orderer evaluates them against the current config of the channel.
+// Like ComputeMarshaledUpdate, it fails if the changes of the ConfigTx are
+// not permitted by its path guard.
+func (c *ConfigTx) OrgUpdate(channelID, orgPath string) (OrgUpdate, error) {
+	if channelID == "" {
+		return OrgUpdate{}, errors.New("channel ID is required")
+	}
+
+	err := c.transform()
+	if err != nil {
+		return OrgUpdate{}, fmt.Errorf("failed to transform updated config: %w", err)
+	}
+
+	err = c.options.checkPathGuard(c.original, c.updated)
+	if err != nil {
+		return OrgUpdate{}, fmt.Errorf("config update not permitted: %w", err)
+	}
+
+	originalOrg, ok := orgGroupsByPath(c.original.ChannelGroup)[orgPath]
+	if !ok {
+		return OrgUpdate{}, fmt.Errorf("org %s does not exist in the original config", orgPath)
+	}
+	updatedOrg, ok := orgGroupsByPath(c.updated.ChannelGroup)[orgPath]
+	if !ok {
+		return OrgUpdate{}, fmt.Errorf("org %s does not exist in the updated config", orgPath)
+	}
+
+	msp, err := getMSPConfig(originalOrg, c.options.certificates)
+	if err != nil {
+		return OrgUpdate{}, fmt.Errorf("retrieving MSP of %s: %w", orgPath, err)
+	}
+
+	elements := strings.Split(strings.TrimPrefix(orgPath, "/"), "/")
+	parentPath, name := elements[1:len(elements)-1], elements[len(elements)-1]
+
+	intermediate := proto.Clone(c.original).(*cb.Config)
+	parent, _ := configGroupAtPath(intermediate.ChannelGroup, parentPath)
+	parent.Groups[name] = proto.Clone(updatedOrg).(*cb.ConfigGroup)
+
+	update, err := computeConfigUpdate(c.original, intermediate)
+	if err != nil {
+		return OrgUpdate{}, fmt.Errorf("failed to compute update of %s: %w", orgPath, err)
+	}
+	update.ChannelId = channelID
+
+	marshaled, err := proto.Marshal(update)
+	if err != nil {
+		return OrgUpdate{}, fmt.Errorf("marshaling config update: %w", err)
+	}
+
+	orgWriteSet, _ := configGroupAtPath(update.WriteSet, elements[1:])
+	modPolicies := map[string]bool{}
+	collectModPolicies(modPolicies, orgPath, originalOrg, orgWriteSet)
+
+	var paths []string
+	for path := range modPolicies {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	evaluator := c.options.evaluator()
+
+	result := OrgUpdate{Update: marshaled, OrgSigned: true}
+	for _, path := range paths {
+		policy, err := evaluator.Resolve(c.original.ChannelGroup, path)
+		if err != nil {
+			return OrgUpdate{}, fmt.Errorf("resolving mod policy %s: %w", path, err)
+		}
+		result.ModPolicies = append(result.ModPolicies, policy)
+
+		signers := policy.Signers()
+		if len(signers) == 0 {
+			result.OrgSigned = false
+		}
+		for _, signer := range signers {
+			if signer.MSPID != msp.Name {
+				result.OrgSigned = false
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// collectModPolicies adds the absolute paths of the mod policies of the
+// elements of the group at groupPath that are modified by its write set to
+// modPolicies. As in the orderer's validation of an update, the mod policy
+// of a modified element is the one in the original config, and added
+// elements are authorized by the mod policy of their group, whose version
+// is incremented when its members change.
+func collectModPolicies(modPolicies map[string]bool, groupPath string, original, writeSet *cb.ConfigGroup) {
+	add := func(basePath, modPolicy string) {
+		path := modPolicy
+		if !strings.HasPrefix(modPolicy, "/") {
+			path = basePath + "/" + modPolicy
+		}
+		modPolicies[path] = true
+	}
+
+	// groups resolve their mod policy against themselves
+	if writeSet.Version > original.Version {
+		add(groupPath, original.ModPolicy)
+	}
+
+	for name, value := range writeSet.Values {
+		if originalValue, ok := original.Values[name]; ok && value.Version > originalValue.Version {
+			add(groupPath, originalValue.ModPolicy)
+		}
+	}
+
+	for name, policy := range writeSet.Policies {
+		if originalPolicy, ok := original.Policies[name]; ok && policy.Version > originalPolicy.Version {
+			add(groupPath, originalPolicy.ModPolicy)
+		}
+	}
+
+	for name, group := range writeSet.Groups {
+		if originalGroup, ok := original.Groups[name]; ok {
+			collectModPolicies(modPolicies, groupPath+"/"+name, originalGroup, group)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestOrgUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().SetACLs(map[string]string{"acl1": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orgUpdate, err := c.OrgUpdate("testchannel", "/Channel/Application/Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orgUpdate.OrgSigned).To(BeTrue())
+	gt.Expect(orgUpdate.ModPolicies).To(HaveLen(1))
+	gt.Expect(orgUpdate.ModPolicies[0].Path).To(Equal("/Channel/Application/Org1/Admins"))
+	gt.Expect(orgUpdate.ModPolicies[0].Signers()).To(Equal([]Principal{{MSPID: "Org1MSP", Role: "admin"}}))
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(orgUpdate.Update, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(update.ChannelId).To(Equal("testchannel"))
+
+	applicationWriteSet := update.WriteSet.Groups[ApplicationGroupKey]
+	gt.Expect(applicationWriteSet.Values).To(BeEmpty())
+	gt.Expect(applicationWriteSet.Groups).To(HaveLen(1))
+	gt.Expect(applicationWriteSet.Groups["Org1"].Version).To(Equal(uint64(1)))
+	gt.Expect(applicationWriteSet.Groups["Org1"].Values).To(HaveKey(AnchorPeersKey))
+
+	// the pending update is unchanged
+	anchorPeers, err := c.Application().Organization("Org2").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(HaveLen(1))
+}
+
+func TestOrgUpdateRequiringOtherSigners(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+
+	orgGroup := c.OriginalConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+	orgGroup.Policies[EndorsementPolicyKey].ModPolicy = "/Channel/Application/Admins"
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Policies[EndorsementPolicyKey].ModPolicy = "/Channel/Application/Admins"
+
+	err := c.Application().Organization("Org1").SetPolicy("/Channel/Application/Admins", EndorsementPolicyKey, Policy{
+		Type: SignaturePolicyType,
+		Rule: "OR('Org1MSP.member')",
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orgUpdate, err := c.OrgUpdate("testchannel", "/Channel/Application/Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orgUpdate.OrgSigned).To(BeFalse())
+	gt.Expect(orgUpdate.ModPolicies).To(HaveLen(1))
+	gt.Expect(orgUpdate.ModPolicies[0].Path).To(Equal("/Channel/Application/Admins"))
+	gt.Expect(orgUpdate.ModPolicies[0].Signers()).To(Equal([]Principal{
+		{MSPID: "Org1MSP", Role: "admin"},
+		{MSPID: "Org2MSP", Role: "admin"},
+	}))
+}
+
+func TestOrgUpdateFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		channelID   string
+		orgPath     string
+		expectedErr string
+	}{
+		{
+			testName:    "missing channel ID",
+			orgPath:     "/Channel/Application/Org1",
+			expectedErr: "channel ID is required",
+		},
+		{
+			testName:    "missing org",
+			channelID:   "testchannel",
+			orgPath:     "/Channel/Application/Org3",
+			expectedErr: "org /Channel/Application/Org3 does not exist in the original config",
+		},
+		{
+			testName:    "unchanged org",
+			channelID:   "testchannel",
+			orgPath:     "/Channel/Application/Org2",
+			expectedErr: "failed to compute update of /Channel/Application/Org2: no differences detected between original and updated config",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := baseOrgUpdateConfigTx(t)
+			err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			_, err = c.OrgUpdate(tc.channelID, tc.orgPath)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+// baseOrgUpdateConfigTx returns a ConfigTx of a channel with the
+// application orgs Org1 and Org2, whose policies are signature policies of
+// their MSPs Org1MSP and Org2MSP.
+func baseOrgUpdateConfigTx(t *testing.T) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	for i, mspID := range []string{"Org1MSP", "Org2MSP"} {
+		application.Organizations[i].MSP.Name = mspID
+		application.Organizations[i].Policies = DefaultOrgPoliciesFor(mspID)
+	}
+
+	applicationGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[ApplicationGroupKey] = applicationGroup
+
+	return New(&cb.Config{ChannelGroup: channelGroup})
+}
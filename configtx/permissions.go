@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Permission is an operation on a channel together with the resolved
+// policy that authorizes it.
+type Permission struct {
+	// Operation describes the operation, e.g. "modify channel config",
+	// "update /Channel/Application/Org1", "invoke peer/Propose" or
+	// "create channel in consortium SampleConsortium".
+	Operation string
+	// Policy is the resolved policy that must be satisfied to perform the
+	// operation.
+	Policy ResolvedPolicy
+	// Err is set when the policy cannot be resolved, e.g. because it
+	// references a policy that does not exist. Such an operation can never
+	// be authorized.
+	Err error
+}
+
+// PermissionsReport lists the policies that authorize the key operations
+// on a channel: modifying the channel config, updating each of its groups
+// and organizations, invoking each ACL resource and creating channels in
+// each consortium.
+type PermissionsReport struct {
+	Permissions []Permission
+}
+
+// Operations returns the operations whose policies name the principal,
+// either directly or through the member role of its MSP. The principal's
+// signature counts toward satisfying the policies of these operations,
+// although policies that require several signatures also need the
+// signatures of others.
+func (r PermissionsReport) Operations(principal Principal) []string {
+	var operations []string
+
+	for _, permission := range r.Permissions {
+		if permission.Err != nil {
+			continue
+		}
+
+		for _, signer := range permission.Policy.Signers() {
+			if signer.MSPID == principal.MSPID && (signer.Role == principal.Role || signer.Role == "member") {
+				operations = append(operations, permission.Operation)
+				break
+			}
+		}
+	}
+
+	return operations
+}
+
+// PermissionsReport resolves the policies that authorize the key
+// operations on the channel in the updated config down to the principals
+// that must sign, so that auditors can see which organizations and roles
+// can perform each operation.
+func (c *ConfigTx) PermissionsReport() (PermissionsReport, error) {
+	var report PermissionsReport
+
+	channelGroup := c.updated.ChannelGroup
+	channelPath := configPath(ChannelGroupKey)
+
+	if channelGroup.ModPolicy != "" {
+		report.add(channelGroup, "modify channel config", channelPath, channelGroup.ModPolicy)
+	}
+
+	groupPaths := map[string]*cb.ConfigGroup{}
+	for _, key := range []string{ApplicationGroupKey, OrdererGroupKey, ConsortiumsGroupKey} {
+		if group, ok := channelGroup.Groups[key]; ok {
+			groupPaths[configPath(ChannelGroupKey, key)] = group
+		}
+	}
+	for path, orgGroup := range orgGroupsByPath(channelGroup) {
+		groupPaths[path] = orgGroup
+	}
+
+	var paths []string
+	for path := range groupPaths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if modPolicy := groupPaths[path].ModPolicy; modPolicy != "" {
+			report.add(channelGroup, "update "+path, path, modPolicy)
+		}
+	}
+
+	if applicationGroup, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		if _, ok := applicationGroup.Values[ACLsKey]; ok {
+			a := &ApplicationGroup{applicationGroup: applicationGroup}
+			acls, err := a.ACLs()
+			if err != nil {
+				return PermissionsReport{}, fmt.Errorf("retrieving ACLs: %w", err)
+			}
+
+			var resources []string
+			for resource := range acls {
+				resources = append(resources, resource)
+			}
+			sort.Strings(resources)
+
+			for _, resource := range resources {
+				report.add(channelGroup, "invoke "+resource, configPath(ChannelGroupKey, ApplicationGroupKey), acls[resource])
+			}
+		}
+	}
+
+	if consortiumsGroup, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		for _, name := range sortedGroupNames(consortiumsGroup) {
+			consortiumGroup := consortiumsGroup.Groups[name]
+			consortiumPath := configPath(ChannelGroupKey, ConsortiumsGroupKey, name)
+			policyPath := consortiumPath + "/" + ChannelCreationPolicyKey
+
+			permission := Permission{Operation: "create channel in consortium " + name}
+
+			policy := &cb.Policy{}
+			err := unmarshalConfigValueAtKey(consortiumGroup, ChannelCreationPolicyKey, policy)
+			if err != nil {
+				permission.Err = fmt.Errorf("retrieving %s: %w", policyPath, err)
+			} else {
+				permission.Policy, permission.Err = resolvePolicyAt(consortiumGroup, consortiumPath, policyPath, policy)
+			}
+
+			report.Permissions = append(report.Permissions, permission)
+		}
+	}
+
+	return report, nil
+}
+
+// add adds the operation authorized by the policy reference, which is
+// resolved relative to basePath unless it is absolute.
+func (r *PermissionsReport) add(channelGroup *cb.ConfigGroup, operation, basePath, policyRef string) {
+	policy, err := resolvePolicyReference(channelGroup, basePath, policyRef)
+	r.Permissions = append(r.Permissions, Permission{
+		Operation: operation,
+		Policy:    policy,
+		Err:       err,
+	})
+}
+
+// resolvePolicyReference resolves a policy reference, which is either an
+// absolute config path such as /Channel/Application/Admins or a path
+// relative to the group at basePath.
+func resolvePolicyReference(channelGroup *cb.ConfigGroup, basePath, policyRef string) (ResolvedPolicy, error) {
+	path := absolutePolicyPath(basePath, policyRef)
+
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(elements) < 2 || elements[0] != ChannelGroupKey {
+		return ResolvedPolicy{}, fmt.Errorf("invalid policy reference %s", path)
+	}
+
+	group := channelGroup
+	for _, name := range elements[1 : len(elements)-1] {
+		subGroup, ok := group.Groups[name]
+		if !ok {
+			return ResolvedPolicy{}, fmt.Errorf("policy %s does not exist", path)
+		}
+		group = subGroup
+	}
+
+	groupPath := "/" + strings.Join(elements[:len(elements)-1], "/")
+
+	return resolvePolicy(group, groupPath, elements[len(elements)-1])
+}
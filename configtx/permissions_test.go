@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPermissionsReport(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	for i := range profile.Application.Organizations {
+		org := &profile.Application.Organizations[i]
+		org.MSP.Name = org.Name + "MSP"
+		org.Policies = DefaultOrgPoliciesFor(org.MSP.Name)
+	}
+	profile.Orderer.Organizations[0].MSP.Name = "OrdererMSP"
+	profile.Orderer.Organizations[0].Policies = defaultOrdererOrgPoliciesFor("OrdererMSP")
+	profile.Application.ACLs = map[string]string{
+		"peer/Propose": "/Channel/Application/Writers",
+		"event/Block":  "Readers",
+		"qscc/GetTx":   "Missing",
+	}
+
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	report, err := c.PermissionsReport()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var operations []string
+	signers := map[string][]Principal{}
+	for _, permission := range report.Permissions {
+		operations = append(operations, permission.Operation)
+		signers[permission.Operation] = permission.Policy.Signers()
+	}
+	gt.Expect(operations).To(Equal([]string{
+		"modify channel config",
+		"update /Channel/Application",
+		"update /Channel/Application/Org1",
+		"update /Channel/Application/Org2",
+		"update /Channel/Orderer",
+		"update /Channel/Orderer/OrdererOrg",
+		"invoke event/Block",
+		"invoke peer/Propose",
+		"invoke qscc/GetTx",
+	}))
+
+	gt.Expect(signers["modify channel config"]).To(Equal([]Principal{
+		{MSPID: "OrdererMSP", Role: "admin"},
+		{MSPID: "Org1MSP", Role: "admin"},
+		{MSPID: "Org2MSP", Role: "admin"},
+	}))
+	gt.Expect(signers["update /Channel/Application/Org1"]).To(Equal([]Principal{
+		{MSPID: "Org1MSP", Role: "admin"},
+	}))
+	gt.Expect(signers["invoke peer/Propose"]).To(Equal([]Principal{
+		{MSPID: "Org1MSP", Role: "admin"},
+		{MSPID: "Org1MSP", Role: "client"},
+		{MSPID: "Org2MSP", Role: "admin"},
+		{MSPID: "Org2MSP", Role: "client"},
+	}))
+
+	invalid := report.Permissions[len(report.Permissions)-1]
+	gt.Expect(invalid.Err).To(MatchError("policy /Channel/Application/Missing does not exist"))
+
+	gt.Expect(report.Operations(Principal{MSPID: "Org1MSP", Role: "client"})).To(Equal([]string{
+		"invoke event/Block",
+		"invoke peer/Propose",
+	}))
+	gt.Expect(report.Operations(Principal{MSPID: "OrdererMSP", Role: "admin"})).To(Equal([]string{
+		"modify channel config",
+		"update /Channel/Orderer",
+		"update /Channel/Orderer/OrdererOrg",
+	}))
+}
+
+func TestPermissionsReportConsortiums(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseSystemChannelProfile(t)
+	for i := range profile.Consortiums[0].Organizations {
+		org := &profile.Consortiums[0].Organizations[i]
+		org.MSP.Name = org.Name + "MSP"
+		org.Policies = DefaultOrgPoliciesFor(org.MSP.Name)
+	}
+
+	block, err := NewSystemChannelGenesisBlock(profile, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	report, err := c.PermissionsReport()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	creation := report.Permissions[len(report.Permissions)-1]
+	gt.Expect(creation.Operation).To(Equal("create channel in consortium Consortium1"))
+	gt.Expect(creation.Err).NotTo(HaveOccurred())
+	gt.Expect(creation.Policy.Path).To(Equal("/Channel/Consortiums/Consortium1/ChannelCreationPolicy"))
+	gt.Expect(creation.Policy.Signers()).To(Equal([]Principal{
+		{MSPID: "Org1MSP", Role: "admin"},
+		{MSPID: "Org2MSP", Role: "admin"},
+	}))
+}
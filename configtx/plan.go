@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Goal is a high-level administrative change that Plan breaks down into
+// discrete config updates.
+type Goal interface {
+	plan(p *planner) error
+}
+
+// PlanStep is one config update of a plan. Its ConfigTx holds the config
+// expected after the previous steps have been committed as the original
+// config and the changes of the step in the updated config, so the update
+// is computed with ComputeMarshaledUpdate.
+type PlanStep struct {
+	// Description describes the change of the step, e.g.
+	// "add consenter orderer4.example.com:7050".
+	Description string
+	ConfigTx    ConfigTx
+}
+
+// Plan breaks the goal down into the ordered config updates required to
+// reach it from the original config. The updates must be submitted in
+// order, each after the previous one has been committed, and the state of
+// the network checked between them, e.g. that a newly added consenter has
+// caught up with the cluster before the next one is added.
+func (c *ConfigTx) Plan(goal Goal) ([]PlanStep, error) {
+	p := &planner{
+		state:   proto.Clone(c.original).(*cb.Config),
+		options: c.options,
+	}
+
+	err := goal.plan(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.steps, nil
+}
+
+// ReplaceOrdererOrg is a goal that replaces the orderer org Old by the
+// orderer org New, whose etcdraft consenters are Consenters. It is planned
+// following the operational rules for changing an etcdraft cluster:
+//
+// 1. New is added, so that the TLS certificates of its consenters are
+// trusted before they join the cluster.
+//
+// 2. The consenters of New are added one at a time, so that the cluster
+// never loses quorum while a new consenter catches up.
+//
+// 3. The legacy channel orderer addresses, if present, are migrated from
+// the endpoints of Old to those of New, so that clients keep reaching the
+// orderers once Old's consenters are gone.
+//
+// 4. The consenters of Old, i.e. those whose TLS certificates were issued
+// by its TLS CAs, are removed one at a time.
+//
+// 5. Old is removed once none of its consenters remain.
+type ReplaceOrdererOrg struct {
+	Old        string
+	New        Organization
+	Consenters []orderer.Consenter
+}
+
+func (r ReplaceOrdererOrg) plan(p *planner) error {
+	ordererGroup, ok := p.state.ChannelGroup.Groups[OrdererGroupKey]
+	if !ok {
+		return errors.New("config does not contain an orderer group")
+	}
+
+	oldOrgGroup, ok := ordererGroup.Groups[r.Old]
+	if !ok {
+		return fmt.Errorf("orderer org %s does not exist", r.Old)
+	}
+
+	if _, ok := ordererGroup.Groups[r.New.Name]; ok {
+		return fmt.Errorf("orderer org %s already exists", r.New.Name)
+	}
+
+	oldMSP, err := getMSPConfig(oldOrgGroup, p.options.certificates)
+	if err != nil {
+		return fmt.Errorf("retrieving MSP of orderer org %s: %w", r.Old, err)
+	}
+
+	oldEndpoints, err := ordererAddresses(oldOrgGroup, EndpointsKey)
+	if err != nil {
+		return fmt.Errorf("retrieving endpoints of orderer org %s: %w", r.Old, err)
+	}
+
+	consenters, err := etcdRaftConsenters(ordererGroup, p.options.certificates)
+	if err != nil {
+		return fmt.Errorf("retrieving consenters: %w", err)
+	}
+
+	var oldConsenters []orderer.Consenter
+	for _, consenter := range consenters {
+		if consenter.ClientTLSCert != nil && oldMSP.VerifyTLSCertificateChain(consenter.ClientTLSCert) == nil {
+			oldConsenters = append(oldConsenters, consenter)
+		}
+	}
+
+	if len(consenters)-len(oldConsenters)+len(r.Consenters) == 0 && len(consenters) > 0 {
+		return fmt.Errorf("replacing orderer org %s would leave no consenters", r.Old)
+	}
+
+	err = p.step(fmt.Sprintf("add orderer org %s", r.New.Name), func(c *ConfigTx) error {
+		return c.Orderer().SetOrganization(r.New)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, consenter := range r.Consenters {
+		consenter := consenter
+		err := p.step(fmt.Sprintf("add consenter %s:%d", consenter.Address.Host, consenter.Address.Port), func(c *ConfigTx) error {
+			return c.Orderer().AddConsenter(consenter)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	legacyAddresses, err := ordererAddresses(p.state.ChannelGroup, OrdererAddressesKey)
+	if err != nil {
+		return fmt.Errorf("retrieving orderer addresses: %w", err)
+	}
+
+	if len(legacyAddresses) > 0 {
+		migrated := legacyAddresses
+		for _, endpoint := range oldEndpoints {
+			migrated, _ = removeAddress(migrated, endpoint)
+		}
+		for _, endpoint := range r.New.OrdererEndpoints {
+			if _, exists := removeAddress(migrated, endpoint); !exists {
+				migrated = append(migrated, endpoint)
+			}
+		}
+
+		if len(migrated) == 0 {
+			return fmt.Errorf("migrating orderer addresses of orderer org %s would leave no orderer addresses", r.Old)
+		}
+
+		err := p.step(fmt.Sprintf("migrate orderer addresses from %s to %s", r.Old, r.New.Name), func(c *ConfigTx) error {
+			return c.Channel().setLegacyOrdererAddresses(migrated)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, consenter := range oldConsenters {
+		consenter := consenter
+		err := p.step(fmt.Sprintf("remove consenter %s:%d", consenter.Address.Host, consenter.Address.Port), func(c *ConfigTx) error {
+			return c.Orderer().RemoveConsenter(consenter)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.step(fmt.Sprintf("remove orderer org %s", r.Old), func(c *ConfigTx) error {
+		c.Orderer().RemoveOrganization(r.Old)
+		return nil
+	})
+}
+
+// planner accumulates the steps of a plan and tracks the config expected
+// after the steps planned so far are committed.
+type planner struct {
+	state   *cb.Config
+	options options
+	steps   []PlanStep
+}
+
+// step plans a config update that applies change to the current state and
+// advances the state to the config expected after committing it.
+func (p *planner) step(description string, change func(c *ConfigTx) error) error {
+	c := ConfigTx{
+		original: p.state,
+		updated:  proto.Clone(p.state).(*cb.Config),
+		options:  p.options,
+	}
+	c.options.certificates = newCertificateInterner(maxInternedCertificates)
+	if c.options.configurationCache {
+		c.cache = &configCache{}
+	}
+
+	err := change(&c)
+	if err != nil {
+		return fmt.Errorf("planning step %q: %w", description, err)
+	}
+
+	next := proto.Clone(c.updated).(*cb.Config)
+	update, err := computeConfigUpdate(p.state, next)
+	if err != nil {
+		return fmt.Errorf("planning step %q: %w", description, err)
+	}
+
+	applyWriteSetVersions(next.ChannelGroup, update.WriteSet)
+	next.Sequence = p.state.Sequence + 1
+
+	p.steps = append(p.steps, PlanStep{Description: description, ConfigTx: c})
+	p.state = next
+
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestPlanReplaceOrdererOrg(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, newOrg, newConsenter := basePlanReplaceOrdererOrg(t)
+
+	steps, err := c.Plan(ReplaceOrdererOrg{
+		Old:        "OrdererOrg",
+		New:        newOrg,
+		Consenters: []orderer.Consenter{newConsenter},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var descriptions []string
+	for _, step := range steps {
+		descriptions = append(descriptions, step.Description)
+	}
+	gt.Expect(descriptions).To(Equal([]string{
+		"add orderer org Org2",
+		"add consenter node-4.example.com:7050",
+		"migrate orderer addresses from OrdererOrg to Org2",
+		"remove consenter node-1.example.com:7050",
+		"remove consenter node-2.example.com:7050",
+		"remove consenter node-3.example.com:7050",
+		"remove orderer org OrdererOrg",
+	}))
+
+	for i, step := range steps {
+		gt.Expect(step.ConfigTx.OriginalConfig().Sequence).To(Equal(uint64(i)))
+		_, err := step.ConfigTx.ComputeMarshaledUpdate("testchannel")
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	// the second step is computed against the orderer group modified by the first
+	gt.Expect(steps[1].ConfigTx.OriginalConfig().ChannelGroup.Groups[OrdererGroupKey].Version).To(Equal(uint64(1)))
+
+	final := steps[len(steps)-1].ConfigTx
+	ordererConfig, err := final.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.EtcdRaft.Consenters).To(Equal([]orderer.Consenter{newConsenter}))
+	gt.Expect(final.HasOrdererOrg("OrdererOrg")).To(BeFalse())
+	gt.Expect(final.HasOrdererOrg("Org2")).To(BeTrue())
+
+	addresses, err := ordererAddresses(final.UpdatedConfig().ChannelGroup, OrdererAddressesKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(addresses).To(Equal([]string{"other.example.com:7050", "orderer.org2.example.com:7050"}))
+}
+
+func TestPlanReplaceOrdererOrgFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		goal        func(newOrg Organization) ReplaceOrdererOrg
+		expectedErr string
+	}{
+		{
+			name: "when the old org does not exist",
+			goal: func(newOrg Organization) ReplaceOrdererOrg {
+				return ReplaceOrdererOrg{Old: "Missing", New: newOrg}
+			},
+			expectedErr: "orderer org Missing does not exist",
+		},
+		{
+			name: "when the new org already exists",
+			goal: func(newOrg Organization) ReplaceOrdererOrg {
+				newOrg.Name = "OrdererOrg"
+				return ReplaceOrdererOrg{Old: "OrdererOrg", New: newOrg}
+			},
+			expectedErr: "orderer org OrdererOrg already exists",
+		},
+		{
+			name: "when no consenters would remain",
+			goal: func(newOrg Organization) ReplaceOrdererOrg {
+				return ReplaceOrdererOrg{Old: "OrdererOrg", New: newOrg}
+			},
+			expectedErr: "replacing orderer org OrdererOrg would leave no consenters",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c, newOrg, _ := basePlanReplaceOrdererOrg(t)
+
+			_, err := c.Plan(tc.goal(newOrg))
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestPlanWithoutOrdererGroup(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := New(&cb.Config{ChannelGroup: newConfigGroup()})
+
+	_, err := c.Plan(ReplaceOrdererOrg{Old: "OrdererOrg"})
+	gt.Expect(err).To(MatchError("config does not contain an orderer group"))
+}
+
+// basePlanReplaceOrdererOrg returns a config with an etcdraft orderer org
+// whose three consenters have TLS certificates issued by its TLS CA, and a
+// new orderer org with a consenter to replace it.
+func basePlanReplaceOrdererOrg(t *testing.T) (ConfigTx, Organization, orderer.Consenter) {
+	gt := NewGomegaWithT(t)
+
+	oldCACert, oldCAPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	oldTLSCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", oldCACert, oldCAPrivKey)
+
+	ordererConf, _ := baseEtcdRaftOrderer(t)
+	ordererConf.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{oldCACert}
+	ordererConf.Organizations[0].MSP.TLSIntermediateCerts = nil
+	for i := range ordererConf.EtcdRaft.Consenters {
+		ordererConf.EtcdRaft.Consenters[i].ClientTLSCert = oldTLSCert
+		ordererConf.EtcdRaft.Consenters[i].ServerTLSCert = oldTLSCert
+	}
+
+	ordererGroup, err := NewOrdererGroup(ordererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.Channel().AddLegacyOrdererAddress("localhost:123")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Channel().AddLegacyOrdererAddress("other.example.com:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+	c = New(c.UpdatedConfig())
+
+	newCACert, newCAPrivKey := generateCACertAndPrivateKey(t, "org2")
+	newTLSCert, _ := generateCertAndPrivateKeyFromCACert(t, "org2", newCACert, newCAPrivKey)
+
+	newMSP, _ := baseMSP(t)
+	newMSP.Name = "Org2MSP"
+	newMSP.TLSRootCerts = []*x509.Certificate{newCACert}
+	newMSP.TLSIntermediateCerts = nil
+
+	newOrg := Organization{
+		Name:             "Org2",
+		Policies:         orgStandardPolicies(),
+		MSP:              newMSP,
+		OrdererEndpoints: []string{"orderer.org2.example.com:7050"},
+	}
+
+	newConsenter := orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: "node-4.example.com", Port: 7050},
+		ClientTLSCert: newTLSCert,
+		ServerTLSCert: newTLSCert,
+	}
+
+	return c, newOrg, newConsenter
+}
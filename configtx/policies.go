@@ -184,6 +184,163 @@ func signaturePolicyToString(sig *cb.SignaturePolicy, IDs []string) (string, err
 	}
 }
 
+// NOutOfOrgs returns a signature policy that is satisfied by signatures of
+// n of the given organizations' identities with the given role, e.g.
+// NOutOfOrgs(2, "admin", "Org1MSP", "Org2MSP", "Org3MSP") for a policy
+// requiring the admins of two of the three organizations to sign.
+// The role must be one of member, admin, client, peer or orderer.
+func NOutOfOrgs(n int, role string, mspIDs ...string) (Policy, error) {
+	if len(mspIDs) == 0 {
+		return Policy{}, errors.New("at least one MSP ID is required")
+	}
+
+	if n < 1 || n > len(mspIDs) {
+		return Policy{}, fmt.Errorf("n must be between 1 and the number of MSP IDs (%d), got %d", len(mspIDs), n)
+	}
+
+	if !validRole(role) {
+		return Policy{}, fmt.Errorf("unknown role '%s'", role)
+	}
+
+	principals := make([]string, len(mspIDs))
+	for i, mspID := range mspIDs {
+		err := validateMSPID(mspID)
+		if err != nil {
+			return Policy{}, err
+		}
+		principals[i] = fmt.Sprintf("'%s.%s'", mspID, role)
+	}
+
+	// render the rule the same way policies read from a config are rendered
+	var rule string
+	switch n {
+	case len(mspIDs):
+		rule = fmt.Sprintf("AND(%s)", strings.Join(principals, ", "))
+	case 1:
+		rule = fmt.Sprintf("OR(%s)", strings.Join(principals, ", "))
+	default:
+		rule = fmt.Sprintf("OUTOF(%d, %s)", n, strings.Join(principals, ", "))
+	}
+
+	return Policy{
+		Type: SignaturePolicyType,
+		Rule: rule,
+	}, nil
+}
+
+// AnyOfOrgs returns a signature policy that is satisfied by a signature of
+// any of the given organizations' identities with the given role.
+func AnyOfOrgs(role string, mspIDs ...string) (Policy, error) {
+	return NOutOfOrgs(1, role, mspIDs...)
+}
+
+// AllOfOrgs returns a signature policy that is satisfied by signatures of
+// all of the given organizations' identities with the given role.
+func AllOfOrgs(role string, mspIDs ...string) (Policy, error) {
+	return NOutOfOrgs(len(mspIDs), role, mspIDs...)
+}
+
+// MajorityOfOrgs returns a signature policy that is satisfied by signatures
+// of more than half of the given organizations' identities with the given
+// role.
+func MajorityOfOrgs(role string, mspIDs ...string) (Policy, error) {
+	return NOutOfOrgs(len(mspIDs)/2+1, role, mspIDs...)
+}
+
+// OrgRoles declares which roles of an organization's identities hold each
+// of its permissions, e.g. OrgRoles{Writers: []string{"admin", "client"}}.
+// The roles must be NodeOU roles, i.e. admin, peer, client or orderer, or
+// member.
+type OrgRoles struct {
+	Readers     []string
+	Writers     []string
+	Admins      []string
+	Endorsement []string
+}
+
+// OrgPolicies returns the Readers, Writers, Admins and Endorsement
+// signature policies of the organization with the given MSP ID, each
+// satisfied by a signature of an identity holding any of the roles
+// declared for it. Readers, Writers and Admins must each be held by at
+// least one role; the Endorsement policy is omitted if no role holds it,
+// as for orderer organizations.
+func OrgPolicies(mspID string, roles OrgRoles) (map[string]Policy, error) {
+	err := validateMSPID(mspID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := []struct {
+		name     string
+		roles    []string
+		required bool
+	}{
+		{name: ReadersPolicyKey, roles: roles.Readers, required: true},
+		{name: WritersPolicyKey, roles: roles.Writers, required: true},
+		{name: AdminsPolicyKey, roles: roles.Admins, required: true},
+		{name: EndorsementPolicyKey, roles: roles.Endorsement},
+	}
+
+	policies := map[string]Policy{}
+	for _, permission := range permissions {
+		if len(permission.roles) == 0 {
+			if permission.required {
+				return nil, fmt.Errorf("no roles hold the %s permission", permission.name)
+			}
+			continue
+		}
+
+		var principals []string
+		seen := map[string]bool{}
+		for _, role := range permission.roles {
+			if !validRole(role) {
+				return nil, fmt.Errorf("unknown role '%s' for the %s permission", role, permission.name)
+			}
+
+			if seen[role] {
+				continue
+			}
+			seen[role] = true
+			principals = append(principals, fmt.Sprintf("'%s.%s'", mspID, role))
+		}
+
+		// render the rule the same way policies read from a config are
+		// rendered
+		gate := "OR"
+		if len(principals) == 1 {
+			gate = "AND"
+		}
+
+		policies[permission.name] = Policy{
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("%s(%s)", gate, strings.Join(principals, ", ")),
+		}
+	}
+
+	return policies, nil
+}
+
+// validRole reports whether role can be used in the principals of the
+// signature policies built by NOutOfOrgs and OrgPolicies.
+func validRole(role string) bool {
+	switch role {
+	case policydsl.RoleMember, policydsl.RoleAdmin, policydsl.RoleClient, policydsl.RolePeer, policydsl.RoleOrderer:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateMSPID returns an error if the MSP ID cannot be used in the
+// principals of a signature policy rule.
+func validateMSPID(mspID string) error {
+	if mspID == "" || strings.ContainsAny(mspID, "'\",()") {
+		return fmt.Errorf("invalid MSP ID '%s'", mspID)
+	}
+
+	return nil
+}
+
 // TODO: evaluate if modPolicy actually needs to be passed in if all callers pass AdminsPolicyKey.
 func setPolicies(cg *cb.ConfigGroup, policyMap map[string]Policy, modPolicy string) error {
 	if policyMap == nil {
@@ -221,12 +378,12 @@ func setPolicy(cg *cb.ConfigGroup, modPolicy, policyName string, policy Policy)
 	case ImplicitMetaPolicyType:
 		imp, err := implicitMetaFromString(policy.Rule)
 		if err != nil {
-			return fmt.Errorf("invalid implicit meta policy rule: '%s': %v", policy.Rule, err)
+			return fmt.Errorf("invalid implicit meta policy rule: '%s': %w", policy.Rule, err)
 		}
 
 		implicitMetaPolicy, err := proto.Marshal(imp)
 		if err != nil {
-			return fmt.Errorf("marshaling implicit meta policy: %v", err)
+			return fmt.Errorf("marshaling implicit meta policy: %w", err)
 		}
 
 		cg.Policies[policyName] = &cb.ConfigPolicy{
@@ -239,12 +396,12 @@ func setPolicy(cg *cb.ConfigGroup, modPolicy, policyName string, policy Policy)
 	case SignaturePolicyType:
 		sp, err := policydsl.FromString(policy.Rule)
 		if err != nil {
-			return fmt.Errorf("invalid signature policy rule: '%s': %v", policy.Rule, err)
+			return fmt.Errorf("invalid signature policy rule: '%s': %w", policy.Rule, err)
 		}
 
 		signaturePolicy, err := proto.Marshal(sp)
 		if err != nil {
-			return fmt.Errorf("marshaling signature policy: %v", err)
+			return fmt.Errorf("marshaling signature policy: %w", err)
 		}
 
 		cg.Policies[policyName] = &cb.ConfigPolicy{
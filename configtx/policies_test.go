@@ -56,7 +56,7 @@ func TestSetConsortiumChannelCreationPolicy(t *testing.T) {
 
 	consortiums, _ := baseConsortiums(t)
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -93,7 +93,7 @@ func TestSetConsortiumChannelCreationPolicyFailures(t *testing.T) {
 
 	consortiums, _ := baseConsortiums(t)
 
-	consortiumsGroup, err := newConsortiumsGroup(consortiums)
+	consortiumsGroup, err := NewConsortiumsGroup(consortiums)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -129,3 +129,226 @@ func TestSetConsortiumChannelCreationPolicyFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestNOutOfOrgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName       string
+		policy         func() (Policy, error)
+		expectedPolicy Policy
+	}{
+		{
+			testName: "when n of the orgs are required",
+			policy: func() (Policy, error) {
+				return NOutOfOrgs(2, "admin", "Org1MSP", "Org2MSP", "Org3MSP")
+			},
+			expectedPolicy: Policy{
+				Type: SignaturePolicyType,
+				Rule: "OUTOF(2, 'Org1MSP.admin', 'Org2MSP.admin', 'Org3MSP.admin')",
+			},
+		},
+		{
+			testName: "when any of the orgs is required",
+			policy: func() (Policy, error) {
+				return AnyOfOrgs("member", "Org1MSP", "Org2MSP")
+			},
+			expectedPolicy: Policy{
+				Type: SignaturePolicyType,
+				Rule: "OR('Org1MSP.member', 'Org2MSP.member')",
+			},
+		},
+		{
+			testName: "when all of the orgs are required",
+			policy: func() (Policy, error) {
+				return AllOfOrgs("peer", "Org1MSP", "Org2MSP")
+			},
+			expectedPolicy: Policy{
+				Type: SignaturePolicyType,
+				Rule: "AND('Org1MSP.peer', 'Org2MSP.peer')",
+			},
+		},
+		{
+			testName: "when a majority of the orgs is required",
+			policy: func() (Policy, error) {
+				return MajorityOfOrgs("admin", "Org1MSP", "Org2MSP", "Org3MSP", "Org4MSP")
+			},
+			expectedPolicy: Policy{
+				Type: SignaturePolicyType,
+				Rule: "OUTOF(3, 'Org1MSP.admin', 'Org2MSP.admin', 'Org3MSP.admin', 'Org4MSP.admin')",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			policy, err := tc.policy()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(policy).To(Equal(tc.expectedPolicy))
+
+			orgGroup := newConfigGroup()
+			err = setPolicy(orgGroup, AdminsPolicyKey, "Endorsement", policy)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			policies, err := getPolicies(orgGroup.Policies)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(policies["Endorsement"]).To(Equal(policy))
+		})
+	}
+}
+
+func TestNOutOfOrgsFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		n           int
+		role        string
+		mspIDs      []string
+		expectedErr string
+	}{
+		{
+			testName:    "when no MSP IDs are provided",
+			n:           1,
+			role:        "admin",
+			expectedErr: "at least one MSP ID is required",
+		},
+		{
+			testName:    "when n is zero",
+			n:           0,
+			role:        "admin",
+			mspIDs:      []string{"Org1MSP"},
+			expectedErr: "n must be between 1 and the number of MSP IDs (1), got 0",
+		},
+		{
+			testName:    "when n exceeds the number of MSP IDs",
+			n:           3,
+			role:        "admin",
+			mspIDs:      []string{"Org1MSP", "Org2MSP"},
+			expectedErr: "n must be between 1 and the number of MSP IDs (2), got 3",
+		},
+		{
+			testName:    "when the role is unknown",
+			n:           1,
+			role:        "operator",
+			mspIDs:      []string{"Org1MSP"},
+			expectedErr: "unknown role 'operator'",
+		},
+		{
+			testName:    "when an MSP ID contains a quote",
+			n:           1,
+			role:        "admin",
+			mspIDs:      []string{"Org1MSP", "Org'2MSP"},
+			expectedErr: "invalid MSP ID 'Org'2MSP'",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			_, err := NOutOfOrgs(tc.n, tc.role, tc.mspIDs...)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestOrgPolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	policies, err := OrgPolicies("Org1MSP", OrgRoles{
+		Readers:     []string{"admin", "peer", "client"},
+		Writers:     []string{"admin", "client"},
+		Admins:      []string{"admin"},
+		Endorsement: []string{"peer", "peer"},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey:     {Type: SignaturePolicyType, Rule: "OR('Org1MSP.admin', 'Org1MSP.peer', 'Org1MSP.client')"},
+		WritersPolicyKey:     {Type: SignaturePolicyType, Rule: "OR('Org1MSP.admin', 'Org1MSP.client')"},
+		AdminsPolicyKey:      {Type: SignaturePolicyType, Rule: "AND('Org1MSP.admin')"},
+		EndorsementPolicyKey: {Type: SignaturePolicyType, Rule: "AND('Org1MSP.peer')"},
+	}))
+
+	policies, err = OrgPolicies("OrdererMSP", OrgRoles{
+		Readers: []string{"member"},
+		Writers: []string{"member"},
+		Admins:  []string{"admin"},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey: {Type: SignaturePolicyType, Rule: "AND('OrdererMSP.member')"},
+		WritersPolicyKey: {Type: SignaturePolicyType, Rule: "AND('OrdererMSP.member')"},
+		AdminsPolicyKey:  {Type: SignaturePolicyType, Rule: "AND('OrdererMSP.admin')"},
+	}))
+
+	group := newConfigGroup()
+	err = setPolicies(group, policies, AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	decoded, err := getPolicies(group.Policies)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded).To(Equal(policies))
+}
+
+func TestOrgPoliciesFailures(t *testing.T) {
+	t.Parallel()
+
+	roles := OrgRoles{
+		Readers: []string{"member"},
+		Writers: []string{"member"},
+		Admins:  []string{"admin"},
+	}
+
+	tests := []struct {
+		testName    string
+		mspID       string
+		roles       func(OrgRoles) OrgRoles
+		expectedErr string
+	}{
+		{
+			testName:    "when the MSP ID is empty",
+			mspID:       "",
+			roles:       func(r OrgRoles) OrgRoles { return r },
+			expectedErr: "invalid MSP ID ''",
+		},
+		{
+			testName: "when no role holds a required permission",
+			mspID:    "Org1MSP",
+			roles: func(r OrgRoles) OrgRoles {
+				r.Admins = nil
+				return r
+			},
+			expectedErr: "no roles hold the Admins permission",
+		},
+		{
+			testName: "when a role is unknown",
+			mspID:    "Org1MSP",
+			roles: func(r OrgRoles) OrgRoles {
+				r.Endorsement = []string{"operator"}
+				return r
+			},
+			expectedErr: "unknown role 'operator' for the Endorsement permission",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			_, err := OrgPolicies(tc.mspID, tc.roles(roles))
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
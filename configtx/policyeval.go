@@ -0,0 +1,260 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// PolicyEvaluator resolves and evaluates the policies of a config. The
+// evaluator of a ConfigTx computes the signers required by proposals and
+// UnsatisfiedPolicies and checks mod policies, so that networks whose
+// orderers and peers evaluate policies with custom plugins can supply
+// matching logic with WithPolicyEvaluator.
+type PolicyEvaluator interface {
+	// Resolve resolves the policy at policyPath, an absolute config path
+	// such as /Channel/Application/Admins, in the channel group. The
+	// signers of the resolved policy are the principals whose signatures
+	// can contribute to satisfying it.
+	Resolve(channelGroup *cb.ConfigGroup, policyPath string) (ResolvedPolicy, error)
+	// Evaluate reports whether signatures by the principals, one signature
+	// per principal, satisfy the policy at policyPath in the channel
+	// group.
+	Evaluate(channelGroup *cb.ConfigGroup, policyPath string, signers []Principal) (bool, error)
+}
+
+// WithPolicyEvaluator uses evaluator instead of FabricPolicyEvaluator to
+// resolve and evaluate policies.
+func WithPolicyEvaluator(evaluator PolicyEvaluator) Option {
+	return func(o *options) {
+		o.policyEvaluator = evaluator
+	}
+}
+
+// evaluator returns the configured policy evaluator.
+func (o options) evaluator() PolicyEvaluator {
+	if o.policyEvaluator == nil {
+		return FabricPolicyEvaluator{}
+	}
+
+	return o.policyEvaluator
+}
+
+// FabricPolicyEvaluator evaluates ImplicitMeta and signature policies like
+// Fabric's built-in policy providers. A principal satisfies an MSP role
+// principal of a signature policy if it belongs to the same MSP and has
+// the same role, and any principal of the MSP satisfies the member role.
+// Principals that are not MSP roles can never be satisfied.
+type FabricPolicyEvaluator struct{}
+
+// Resolve resolves the policy at policyPath like ExplainPolicy.
+func (FabricPolicyEvaluator) Resolve(channelGroup *cb.ConfigGroup, policyPath string) (ResolvedPolicy, error) {
+	return resolvePolicyReference(channelGroup, configPath(ChannelGroupKey), policyPath)
+}
+
+// Evaluate reports whether signatures by the principals satisfy the policy
+// at policyPath.
+func (FabricPolicyEvaluator) Evaluate(channelGroup *cb.ConfigGroup, policyPath string, signers []Principal) (bool, error) {
+	elements := strings.Split(strings.TrimPrefix(policyPath, "/"), "/")
+	if !strings.HasPrefix(policyPath, "/") || len(elements) < 2 || elements[0] != ChannelGroupKey {
+		return false, fmt.Errorf("invalid policy path %s", policyPath)
+	}
+
+	group, _ := configGroupAtPath(channelGroup, elements[1:len(elements)-1])
+	if group == nil {
+		return false, fmt.Errorf("policy %s does not exist", policyPath)
+	}
+
+	configPolicy, ok := group.Policies[elements[len(elements)-1]]
+	if !ok || configPolicy.Policy == nil {
+		return false, fmt.Errorf("policy %s does not exist", policyPath)
+	}
+
+	groupPath := configPath(elements[:len(elements)-1]...)
+
+	return evaluatePolicy(group, groupPath, policyPath, configPolicy.Policy, signers)
+}
+
+// evaluatePolicy evaluates the policy at policyPath. ImplicitMeta policies
+// evaluate the sub-groups of the group at groupPath.
+func evaluatePolicy(group *cb.ConfigGroup, groupPath, policyPath string, policy *cb.Policy, signers []Principal) (bool, error) {
+	switch cb.Policy_PolicyType(policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		imp := &cb.ImplicitMetaPolicy{}
+		err := proto.Unmarshal(policy.Value, imp)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling implicit meta policy %s: %w", policyPath, err)
+		}
+
+		var threshold int
+		switch imp.Rule {
+		case cb.ImplicitMetaPolicy_ANY:
+			threshold = 1
+		case cb.ImplicitMetaPolicy_ALL:
+			threshold = len(group.Groups)
+		case cb.ImplicitMetaPolicy_MAJORITY:
+			threshold = len(group.Groups)/2 + 1
+		default:
+			return false, fmt.Errorf("policy %s has unknown implicit meta rule: %v", policyPath, imp.Rule)
+		}
+
+		var subGroupNames []string
+		for name := range group.Groups {
+			subGroupNames = append(subGroupNames, name)
+		}
+		sort.Strings(subGroupNames)
+
+		satisfied := 0
+		for _, name := range subGroupNames {
+			subGroup := group.Groups[name]
+			// a sub-group without the sub-policy can never be satisfied
+			subPolicy, ok := subGroup.Policies[imp.SubPolicy]
+			if !ok || subPolicy.Policy == nil {
+				continue
+			}
+
+			subGroupPath := groupPath + "/" + name
+			ok, err := evaluatePolicy(subGroup, subGroupPath, subGroupPath+"/"+imp.SubPolicy, subPolicy.Policy, signers)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				satisfied++
+			}
+		}
+
+		return satisfied >= threshold, nil
+	case cb.Policy_SIGNATURE:
+		sp := &cb.SignaturePolicyEnvelope{}
+		err := proto.Unmarshal(policy.Value, sp)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling signature policy %s: %w", policyPath, err)
+		}
+
+		principals := make([]*Principal, len(sp.Identities))
+		for i, identity := range sp.Identities {
+			if identity.PrincipalClassification != mb.MSPPrincipal_ROLE {
+				continue
+			}
+
+			role := &mb.MSPRole{}
+			err := proto.Unmarshal(identity.Principal, role)
+			if err != nil {
+				return false, fmt.Errorf("unmarshaling principal of policy %s: %w", policyPath, err)
+			}
+
+			principals[i] = &Principal{
+				MSPID: role.MspIdentifier,
+				Role:  strings.ToLower(role.Role.String()),
+			}
+		}
+
+		if sp.Rule == nil {
+			return false, fmt.Errorf("signature policy %s has no rule", policyPath)
+		}
+
+		return evaluateSignatureRule(sp.Rule, principals, signers, make([]bool, len(signers))), nil
+	default:
+		return false, fmt.Errorf("policy %s has unknown policy type: %v", policyPath, policy.Type)
+	}
+}
+
+// evaluateSignatureRule reports whether the rule of a signature policy is
+// satisfied by signatures of the signers that are not used yet. As in
+// Fabric, a signature satisfies at most one principal, and the signatures
+// used by the sub-rules of an n out of rule are only consumed by the
+// sub-rules that are satisfied.
+func evaluateSignatureRule(rule *cb.SignaturePolicy, principals []*Principal, signers []Principal, used []bool) bool {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) || principals[t.SignedBy] == nil {
+			return false
+		}
+		principal := principals[t.SignedBy]
+
+		for i, signer := range signers {
+			if used[i] || signer.MSPID != principal.MSPID {
+				continue
+			}
+			if principal.Role == "member" || principal.Role == signer.Role {
+				used[i] = true
+				return true
+			}
+		}
+
+		return false
+	case *cb.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		subUsed := make([]bool, len(used))
+		for _, subRule := range t.NOutOf.Rules {
+			copy(subUsed, used)
+			if evaluateSignatureRule(subRule, principals, signers, subUsed) {
+				verified++
+				copy(used, subUsed)
+			}
+		}
+
+		return verified >= t.NOutOf.N
+	default:
+		return false
+	}
+}
+
+// UnsatisfiedPolicies simulates the validation of the update from the
+// original to the updated config by the orderer: it returns the mod
+// policies required by the update, resolved in the original config, that
+// signatures by the principals would not satisfy. The policies are
+// evaluated with the policy evaluator of the ConfigTx. No policies are
+// returned if the signatures suffice.
+func (c *ConfigTx) UnsatisfiedPolicies(signers []Principal) ([]ProposalPolicy, error) {
+	err := c.transform()
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform updated config: %w", err)
+	}
+
+	update, err := computeConfigUpdate(c.original, proto.Clone(c.updated).(*cb.Config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute update: %w", err)
+	}
+
+	evaluator := c.options.evaluator()
+
+	policies, err := requiredPolicies(c.original.ChannelGroup, update, evaluator)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsatisfied []ProposalPolicy
+	for _, policy := range policies {
+		ok, err := evaluator.Evaluate(c.original.ChannelGroup, policy.Path, signers)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating %s: %w", policy.Path, err)
+		}
+		if !ok {
+			unsatisfied = append(unsatisfied, policy)
+		}
+	}
+
+	return unsatisfied, nil
+}
+
+// absolutePolicyPath returns the config path of a policy reference, which
+// is either an absolute config path or a path relative to the group at
+// basePath.
+func absolutePolicyPath(basePath, policyRef string) string {
+	if strings.HasPrefix(policyRef, "/") {
+		return policyRef
+	}
+
+	return basePath + "/" + policyRef
+}
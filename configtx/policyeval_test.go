@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestFabricPolicyEvaluator(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	err := c.Application().Organization("Org1").SetPolicy(AdminsPolicyKey, "TwoMembers", Policy{
+		Type: SignaturePolicyType,
+		Rule: "AND('Org1MSP.member', 'Org1MSP.member')",
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup := c.UpdatedConfig().ChannelGroup
+
+	org1Admin := Principal{MSPID: "Org1MSP", Role: "admin"}
+	org1Client := Principal{MSPID: "Org1MSP", Role: "client"}
+	org2Admin := Principal{MSPID: "Org2MSP", Role: "admin"}
+
+	tests := []struct {
+		testName  string
+		path      string
+		signers   []Principal
+		satisfied bool
+	}{
+		{
+			testName:  "org policy satisfied",
+			path:      "/Channel/Application/Org1/Admins",
+			signers:   []Principal{org1Admin},
+			satisfied: true,
+		},
+		{
+			testName:  "org policy with wrong role",
+			path:      "/Channel/Application/Org1/Admins",
+			signers:   []Principal{org1Client},
+			satisfied: false,
+		},
+		{
+			testName:  "org policy with wrong MSP",
+			path:      "/Channel/Application/Org1/Admins",
+			signers:   []Principal{org2Admin},
+			satisfied: false,
+		},
+		{
+			testName:  "member role satisfied by any role",
+			path:      "/Channel/Application/Org1/TwoMembers",
+			signers:   []Principal{org1Admin, org1Client},
+			satisfied: true,
+		},
+		{
+			testName:  "signature used once",
+			path:      "/Channel/Application/Org1/TwoMembers",
+			signers:   []Principal{org1Admin},
+			satisfied: false,
+		},
+		{
+			testName:  "implicit meta majority satisfied",
+			path:      "/Channel/Application/Admins",
+			signers:   []Principal{org1Admin, org2Admin},
+			satisfied: true,
+		},
+		{
+			testName:  "implicit meta majority not satisfied",
+			path:      "/Channel/Application/Admins",
+			signers:   []Principal{org1Admin},
+			satisfied: false,
+		},
+		{
+			testName:  "implicit meta any satisfied",
+			path:      "/Channel/Application/Readers",
+			signers:   []Principal{org2Admin},
+			satisfied: true,
+		},
+		{
+			testName:  "no signers",
+			path:      "/Channel/Application/Readers",
+			satisfied: false,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			satisfied, err := FabricPolicyEvaluator{}.Evaluate(channelGroup, tc.path, tc.signers)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(satisfied).To(Equal(tc.satisfied))
+		})
+	}
+}
+
+func TestFabricPolicyEvaluatorFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	channelGroup := c.UpdatedConfig().ChannelGroup
+
+	_, err := FabricPolicyEvaluator{}.Evaluate(channelGroup, "Application/Admins", nil)
+	gt.Expect(err).To(MatchError("invalid policy path Application/Admins"))
+	_, err = FabricPolicyEvaluator{}.Evaluate(channelGroup, "/Channel/Application/Org3/Admins", nil)
+	gt.Expect(err).To(MatchError("policy /Channel/Application/Org3/Admins does not exist"))
+	_, err = FabricPolicyEvaluator{}.Evaluate(channelGroup, "/Channel/Application/Missing", nil)
+	gt.Expect(err).To(MatchError("policy /Channel/Application/Missing does not exist"))
+
+	channelGroup.Groups[ApplicationGroupKey].Policies[AdminsPolicyKey].Policy.Type = 15
+	_, err = FabricPolicyEvaluator{}.Evaluate(channelGroup, "/Channel/Application/Admins", nil)
+	gt.Expect(err).To(MatchError("policy /Channel/Application/Admins has unknown policy type: 15"))
+}
+
+func TestUnsatisfiedPolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org1Admin := Principal{MSPID: "Org1MSP", Role: "admin"}
+	org2Admin := Principal{MSPID: "Org2MSP", Role: "admin"}
+
+	unsatisfied, err := c.UnsatisfiedPolicies([]Principal{org1Admin})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(unsatisfied).To(HaveLen(1))
+	gt.Expect(unsatisfied[0].Path).To(Equal("/Channel/Application/Admins"))
+
+	unsatisfied, err = c.UnsatisfiedPolicies([]Principal{org1Admin, org2Admin})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(unsatisfied).To(BeEmpty())
+}
+
+// customPolicyEvaluator treats every policy as satisfied by the admins of
+// CustomMSP.
+type customPolicyEvaluator struct{}
+
+func (customPolicyEvaluator) Resolve(channelGroup *cb.ConfigGroup, policyPath string) (ResolvedPolicy, error) {
+	return ResolvedPolicy{
+		Path:       policyPath,
+		Policy:     Policy{Type: "Custom", Rule: "CustomMSP admins"},
+		Principals: []Principal{{MSPID: "CustomMSP", Role: "admin"}},
+	}, nil
+}
+
+func (customPolicyEvaluator) Evaluate(channelGroup *cb.ConfigGroup, policyPath string, signers []Principal) (bool, error) {
+	for _, signer := range signers {
+		if signer.MSPID == "CustomMSP" && signer.Role == "admin" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func TestWithPolicyEvaluator(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	base := baseOrgUpdateConfigTx(t)
+	c := New(base.OriginalConfig(), WithPolicyEvaluator(customPolicyEvaluator{}))
+	err := c.Application().SetACLs(map[string]string{"acl2": "Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	proposal, err := c.NewProposal("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proposal.RequiredPolicies).To(Equal([]ProposalPolicy{{
+		Path:    "/Channel/Application/Admins",
+		Rule:    "CustomMSP admins",
+		Signers: []Principal{{MSPID: "CustomMSP", Role: "admin"}},
+	}}))
+
+	unsatisfied, err := c.UnsatisfiedPolicies([]Principal{{MSPID: "CustomMSP", Role: "admin"}})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(unsatisfied).To(BeEmpty())
+}
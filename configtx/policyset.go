@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"gopkg.in/yaml.v2"
+)
+
+// PolicySet is the policy configuration of a channel, i.e. the policies of
+// each of its config groups, as a standalone document that can be encoded
+// as JSON or YAML. It allows policy baselines to be managed independently
+// of the membership data of a channel.
+type PolicySet struct {
+	Groups []PolicySetGroup `json:"groups" yaml:"groups"`
+}
+
+// PolicySetGroup is the policy configuration of a config group.
+type PolicySetGroup struct {
+	// Path is the config path of the group, e.g. /Channel/Application.
+	Path string `json:"path" yaml:"path"`
+	// ModPolicy is the mod policy of the group itself.
+	ModPolicy string            `json:"modPolicy,omitempty" yaml:"modPolicy,omitempty"`
+	Policies  []PolicySetPolicy `json:"policies" yaml:"policies"`
+}
+
+// PolicySetPolicy is a policy of a config group.
+type PolicySetPolicy struct {
+	Name      string `json:"name" yaml:"name"`
+	Type      string `json:"type" yaml:"type"`
+	Rule      string `json:"rule" yaml:"rule"`
+	ModPolicy string `json:"modPolicy,omitempty" yaml:"modPolicy,omitempty"`
+}
+
+// ParsePolicySet decodes a policy set document encoded as YAML or JSON.
+func ParsePolicySet(raw []byte) (PolicySet, error) {
+	set := PolicySet{}
+	err := yaml.UnmarshalStrict(raw, &set)
+	if err != nil {
+		return PolicySet{}, fmt.Errorf("decoding policy set: %w", err)
+	}
+
+	return set, nil
+}
+
+// PolicySet returns the policies of every group of the updated config that
+// defines policies, listing the groups in lexical order of their paths.
+func (c *ConfigTx) PolicySet() (PolicySet, error) {
+	set := PolicySet{}
+
+	var collect func(path string, group *cb.ConfigGroup) error
+	collect = func(path string, group *cb.ConfigGroup) error {
+		if len(group.Policies) > 0 {
+			policies, err := getPolicies(group.Policies)
+			if err != nil {
+				return fmt.Errorf("retrieving policies of %s: %w", path, err)
+			}
+
+			setGroup := PolicySetGroup{
+				Path:      path,
+				ModPolicy: group.ModPolicy,
+			}
+			for _, name := range sortedKeys(group.Policies) {
+				setGroup.Policies = append(setGroup.Policies, PolicySetPolicy{
+					Name:      name,
+					Type:      policies[name].Type,
+					Rule:      policies[name].Rule,
+					ModPolicy: group.Policies[name].ModPolicy,
+				})
+			}
+			set.Groups = append(set.Groups, setGroup)
+		}
+
+		for _, name := range sortedKeys(group.Groups) {
+			err := collect(path+"/"+name, group.Groups[name])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err := collect(configPath(ChannelGroupKey), c.updated.ChannelGroup)
+	if err != nil {
+		return PolicySet{}, err
+	}
+
+	return set, nil
+}
+
+// ApplyPolicySet replaces the policies of each group of the policy set in
+// the updated config with the policies of the set, removing policies the
+// set does not define, and sets the group's mod policy if the set defines
+// one. Groups of the set that do not exist in the updated config, e.g.
+// organizations that are not members of the channel, are skipped and
+// their paths returned. The set is validated before the config is
+// modified, so the config is unchanged if an error is returned.
+func (c *ConfigTx) ApplyPolicySet(set PolicySet) ([]string, error) {
+	type replacement struct {
+		path     string
+		group    *cb.ConfigGroup
+		set      PolicySetGroup
+		policies *cb.ConfigGroup
+	}
+
+	var replacements []replacement
+	var skipped []string
+	seen := map[string]bool{}
+
+	for _, setGroup := range set.Groups {
+		elements := strings.Split(strings.TrimPrefix(setGroup.Path, "/"), "/")
+		if !strings.HasPrefix(setGroup.Path, "/") || elements[0] != ChannelGroupKey {
+			return nil, fmt.Errorf("invalid group path %s", setGroup.Path)
+		}
+
+		if seen[setGroup.Path] {
+			return nil, fmt.Errorf("group %s is defined more than once", setGroup.Path)
+		}
+		seen[setGroup.Path] = true
+
+		group, _ := configGroupAtPath(c.updated.ChannelGroup, elements[1:])
+		if group == nil {
+			skipped = append(skipped, setGroup.Path)
+			continue
+		}
+
+		policies := newConfigGroup()
+		for _, policy := range setGroup.Policies {
+			if _, ok := policies.Policies[policy.Name]; ok {
+				return nil, fmt.Errorf("policy %s of %s is defined more than once", policy.Name, setGroup.Path)
+			}
+
+			err := setPolicy(policies, policy.ModPolicy, policy.Name, Policy{Type: policy.Type, Rule: policy.Rule})
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy %s of %s: %w", policy.Name, setGroup.Path, err)
+			}
+		}
+
+		replacements = append(replacements, replacement{
+			path:     setGroup.Path,
+			group:    group,
+			set:      setGroup,
+			policies: policies,
+		})
+	}
+
+	for _, r := range replacements {
+		r.group.Policies = r.policies.Policies
+		if r.set.ModPolicy != "" {
+			r.group.ModPolicy = r.set.ModPolicy
+		}
+
+		for _, policy := range r.set.Policies {
+			c.checkImplicitMetaPolicy(r.path, r.group, policy.Name, Policy{Type: policy.Type, Rule: policy.Rule})
+		}
+
+		c.notify(r.path, "ApplyPolicySet")
+	}
+
+	return skipped, nil
+}
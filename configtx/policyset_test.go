@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPolicySet(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	source := New(&cb.Config{ChannelGroup: channelGroup})
+	err = source.Application().SetPolicy(AdminsPolicyKey, AdminsPolicyKey, Policy{Type: ImplicitMetaPolicyType, Rule: "ANY Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = source.Application().Organization("Org1").SetPolicy(AdminsPolicyKey, "Auditors", Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.member')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	set, err := source.PolicySet()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(set.Groups).To(HaveLen(3))
+	gt.Expect(set.Groups[0].Path).To(Equal("/Channel/Application"))
+	gt.Expect(set.Groups[0].ModPolicy).To(Equal(AdminsPolicyKey))
+	gt.Expect(set.Groups[0].Policies).To(ContainElement(PolicySetPolicy{
+		Name:      AdminsPolicyKey,
+		Type:      ImplicitMetaPolicyType,
+		Rule:      "ANY Admins",
+		ModPolicy: AdminsPolicyKey,
+	}))
+	gt.Expect(set.Groups[1].Path).To(Equal("/Channel/Application/Org1"))
+	gt.Expect(set.Groups[2].Path).To(Equal("/Channel/Application/Org2"))
+
+	yamlSet, err := yaml.Marshal(set)
+	gt.Expect(err).NotTo(HaveOccurred())
+	jsonSet, err := json.Marshal(set)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for _, raw := range [][]byte{yamlSet, jsonSet} {
+		parsed, err := ParsePolicySet(raw)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(parsed).To(Equal(set))
+
+		targetGroup, _, err := baseApplicationChannelGroup(t)
+		gt.Expect(err).NotTo(HaveOccurred())
+		delete(targetGroup.Groups[ApplicationGroupKey].Groups, "Org2")
+
+		target := New(&cb.Config{ChannelGroup: targetGroup})
+		skipped, err := target.ApplyPolicySet(parsed)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(skipped).To(Equal([]string{"/Channel/Application/Org2"}))
+
+		targetSet, err := target.PolicySet()
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(targetSet.Groups).To(Equal(set.Groups[:2]))
+
+		sourceOrg1 := source.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+		targetOrg1 := target.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+		gt.Expect(proto.Equal(targetOrg1.Policies["Auditors"], sourceOrg1.Policies["Auditors"])).To(BeTrue())
+	}
+}
+
+func TestApplyPolicySetFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		set         PolicySet
+		expectedErr string
+	}{
+		{
+			name:        "when a group path is invalid",
+			set:         PolicySet{Groups: []PolicySetGroup{{Path: "Application"}}},
+			expectedErr: "invalid group path Application",
+		},
+		{
+			name: "when a group is defined more than once",
+			set: PolicySet{Groups: []PolicySetGroup{
+				{Path: "/Channel/Application"},
+				{Path: "/Channel/Application"},
+			}},
+			expectedErr: "group /Channel/Application is defined more than once",
+		},
+		{
+			name: "when a policy is defined more than once",
+			set: PolicySet{Groups: []PolicySetGroup{
+				{
+					Path: "/Channel/Application",
+					Policies: []PolicySetPolicy{
+						{Name: AdminsPolicyKey, Type: ImplicitMetaPolicyType, Rule: "ANY Admins"},
+						{Name: AdminsPolicyKey, Type: ImplicitMetaPolicyType, Rule: "ALL Admins"},
+					},
+				},
+			}},
+			expectedErr: "policy Admins of /Channel/Application is defined more than once",
+		},
+		{
+			name: "when a policy is invalid",
+			set: PolicySet{Groups: []PolicySetGroup{
+				{
+					Path: "/Channel/Application",
+					Policies: []PolicySetPolicy{
+						{Name: AdminsPolicyKey, Type: ImplicitMetaPolicyType, Rule: "SOME Admins"},
+					},
+				},
+			}},
+			expectedErr: "invalid policy Admins of /Channel/Application: invalid implicit meta policy rule: 'SOME Admins': unknown rule type 'SOME', expected ALL, ANY, or MAJORITY",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseApplicationChannelGroup(t)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			_, err = c.ApplyPolicySet(tc.set)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+			gt.Expect(proto.Equal(c.updated, c.original)).To(BeTrue())
+		})
+	}
+}
+
+func TestParsePolicySetFailure(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	_, err := ParsePolicySet([]byte("groups:\n- path: /Channel\n  unknown: true\n"))
+	gt.Expect(err).To(MatchError(ContainSubstring("decoding policy set: ")))
+}
@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+const (
+	// defaultOrdererMSPID is the MSP ID of the orderer organization in the
+	// fabric-samples test network.
+	defaultOrdererMSPID = "OrdererMSP"
+
+	// defaultConsortiumName is the consortium used by the fabric-samples
+	// channel profiles.
+	defaultConsortiumName = "SampleConsortium"
+)
+
+// DefaultTwoOrgChannel returns an application channel configuration
+// mirroring the TwoOrgsApplicationGenesis profile from the fabric-samples
+// test network configtx.yaml. It contains the organizations Org1MSP and
+// Org2MSP, named by their MSP IDs like the organizations loaded by
+// LoadTestNetwork, with the sample policies and ACLs, V2_0 capabilities, and
+// the orderer returned by DefaultEtcdRaftOrderer.
+// The MSP certificates of every organization and the etcdraft consenters
+// must be populated by the caller before the configuration can be used.
+func DefaultTwoOrgChannel() Channel {
+	return Channel{
+		Consortium: defaultConsortiumName,
+		Application: Application{
+			Organizations: []Organization{
+				defaultApplicationOrg("Org1MSP"),
+				defaultApplicationOrg("Org2MSP"),
+			},
+			Capabilities: []string{"V2_0"},
+			Policies:     defaultApplicationPolicies(),
+			ACLs:         defaultACLs(),
+		},
+		Orderer:      DefaultEtcdRaftOrderer(),
+		Capabilities: []string{"V2_0"},
+		Policies:     defaultImplicitMetaPolicies(),
+	}
+}
+
+// DefaultEtcdRaftOrderer returns an etcdraft orderer configuration mirroring
+// the Orderer defaults from the fabric-samples test network configtx.yaml.
+// It contains a single orderer organization, OrdererOrg (OrdererMSP), with
+// the endpoint orderer.example.com:7050.
+// The orderer organization's MSP certificates and the etcdraft consenters
+// must be populated by the caller before the configuration can be used.
+func DefaultEtcdRaftOrderer() Orderer {
+	policies := defaultImplicitMetaPolicies()
+	policies[BlockValidationPolicyKey] = Policy{
+		Type: ImplicitMetaPolicyType,
+		Rule: "ANY Writers",
+	}
+
+	return Orderer{
+		OrdererType:  orderer.ConsensusTypeEtcdRaft,
+		BatchTimeout: 2 * time.Second,
+		BatchSize: orderer.BatchSize{
+			MaxMessageCount:   10,
+			AbsoluteMaxBytes:  99 * 1024 * 1024,
+			PreferredMaxBytes: 512 * 1024,
+		},
+		EtcdRaft: orderer.EtcdRaft{
+			Options: orderer.EtcdRaftOptions{
+				TickInterval:         "500ms",
+				ElectionTick:         10,
+				HeartbeatTick:        1,
+				MaxInflightBlocks:    5,
+				SnapshotIntervalSize: 16 * 1024 * 1024,
+			},
+		},
+		Organizations: []Organization{
+			{
+				Name:             "OrdererOrg",
+				Policies:         defaultOrdererOrgPoliciesFor(defaultOrdererMSPID),
+				MSP:              MSP{Name: defaultOrdererMSPID},
+				OrdererEndpoints: []string{"orderer.example.com:7050"},
+			},
+		},
+		Capabilities: []string{"V2_0"},
+		Policies:     policies,
+		State:        orderer.ConsensusStateNormal,
+	}
+}
+
+// DefaultOrgPoliciesFor returns the Readers, Writers, Admins, and Endorsement
+// signature policies used for application organizations in the fabric-samples
+// test network configtx.yaml for the given MSP ID.
+func DefaultOrgPoliciesFor(mspID string) map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.peer', '%[1]s.client')", mspID),
+		},
+		WritersPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.client')", mspID),
+		},
+		AdminsPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%s.admin')", mspID),
+		},
+		EndorsementPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%s.peer')", mspID),
+		},
+	}
+}
+
+// defaultOrdererOrgPoliciesFor returns the policies used for the orderer
+// organization in the fabric-samples test network configtx.yaml.
+func defaultOrdererOrgPoliciesFor(mspID string) map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%s.member')", mspID),
+		},
+		WritersPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%s.member')", mspID),
+		},
+		AdminsPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: fmt.Sprintf("OR('%s.admin')", mspID),
+		},
+	}
+}
+
+func defaultApplicationOrg(mspID string) Organization {
+	return Organization{
+		Name:     mspID,
+		Policies: DefaultOrgPoliciesFor(mspID),
+		MSP:      MSP{Name: mspID},
+	}
+}
+
+func defaultImplicitMetaPolicies() map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey: {
+			Type: ImplicitMetaPolicyType,
+			Rule: "ANY Readers",
+		},
+		WritersPolicyKey: {
+			Type: ImplicitMetaPolicyType,
+			Rule: "ANY Writers",
+		},
+		AdminsPolicyKey: {
+			Type: ImplicitMetaPolicyType,
+			Rule: "MAJORITY Admins",
+		},
+	}
+}
+
+func defaultApplicationPolicies() map[string]Policy {
+	policies := defaultImplicitMetaPolicies()
+	policies[EndorsementPolicyKey] = Policy{
+		Type: ImplicitMetaPolicyType,
+		Rule: "MAJORITY Endorsement",
+	}
+	policies[LifecycleEndorsementPolicyKey] = Policy{
+		Type: ImplicitMetaPolicyType,
+		Rule: "MAJORITY Endorsement",
+	}
+
+	return policies
+}
+
+// defaultACLs returns the application ACLs from the fabric-samples test
+// network configtx.yaml.
+func defaultACLs() map[string]string {
+	return map[string]string{
+		"_lifecycle/CheckCommitReadiness":      "/Channel/Application/Writers",
+		"_lifecycle/CommitChaincodeDefinition": "/Channel/Application/Writers",
+		"_lifecycle/QueryChaincodeDefinition":  "/Channel/Application/Writers",
+		"_lifecycle/QueryChaincodeDefinitions": "/Channel/Application/Writers",
+		"lscc/ChaincodeExists":                 "/Channel/Application/Readers",
+		"lscc/GetDeploymentSpec":               "/Channel/Application/Readers",
+		"lscc/GetChaincodeData":                "/Channel/Application/Readers",
+		"lscc/GetInstantiatedChaincodes":       "/Channel/Application/Readers",
+		"qscc/GetChainInfo":                    "/Channel/Application/Readers",
+		"qscc/GetBlockByNumber":                "/Channel/Application/Readers",
+		"qscc/GetBlockByHash":                  "/Channel/Application/Readers",
+		"qscc/GetTransactionByID":              "/Channel/Application/Readers",
+		"qscc/GetBlockByTxID":                  "/Channel/Application/Readers",
+		"cscc/GetConfigBlock":                  "/Channel/Application/Readers",
+		"cscc/GetChannelConfig":                "/Channel/Application/Readers",
+		"peer/Propose":                         "/Channel/Application/Writers",
+		"peer/ChaincodeToChaincode":            "/Channel/Application/Writers",
+		"event/Block":                          "/Channel/Application/Readers",
+		"event/FilteredBlock":                  "/Channel/Application/Readers",
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDefaultOrgPoliciesFor(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	policies := DefaultOrgPoliciesFor("Org3MSP")
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: "OR('Org3MSP.admin', 'Org3MSP.peer', 'Org3MSP.client')",
+		},
+		WritersPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: "OR('Org3MSP.admin', 'Org3MSP.client')",
+		},
+		AdminsPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: "OR('Org3MSP.admin')",
+		},
+		EndorsementPolicyKey: {
+			Type: SignaturePolicyType,
+			Rule: "OR('Org3MSP.peer')",
+		},
+	}))
+}
+
+func TestDefaultTwoOrgChannel(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channel := DefaultTwoOrgChannel()
+	gt.Expect(channel.Application.Organizations).To(HaveLen(2))
+	gt.Expect(channel.Application.Organizations[0].Name).To(Equal("Org1MSP"))
+	gt.Expect(channel.Application.Organizations[1].Name).To(Equal("Org2MSP"))
+	gt.Expect(channel.Orderer.OrdererType).To(Equal("etcdraft"))
+
+	populateDefaultProfile(t, &channel)
+
+	block, err := NewApplicationChannelGenesisBlock(channel, "mychannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(block).NotTo(BeNil())
+
+	marshaledUpdate, err := NewMarshaledCreateChannelTx(channel, "mychannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaledUpdate).NotTo(BeEmpty())
+
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	org1Policies, err := c.Application().Organization("Org1MSP").Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org1Policies).To(HaveLen(4))
+	gt.Expect(org1Policies[ReadersPolicyKey]).To(Equal(DefaultOrgPoliciesFor("Org1MSP")[ReadersPolicyKey]))
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(channel.Orderer.BatchTimeout))
+	gt.Expect(ordererConfig.BatchSize).To(Equal(channel.Orderer.BatchSize))
+	gt.Expect(ordererConfig.EtcdRaft.Options).To(Equal(channel.Orderer.EtcdRaft.Options))
+}
+
+// populateDefaultProfile fills in the certificate material which cannot be
+// defaulted by the sample profiles.
+func populateDefaultProfile(t *testing.T, channel *Channel) {
+	for i, org := range channel.Application.Organizations {
+		msp, _ := baseMSP(t)
+		msp.Name = org.MSP.Name
+		channel.Application.Organizations[i].MSP = msp
+	}
+
+	for i, org := range channel.Orderer.Organizations {
+		msp, _ := baseMSP(t)
+		msp.Name = org.MSP.Name
+		channel.Orderer.Organizations[i].MSP = msp
+	}
+
+	raftOrderer, _ := baseEtcdRaftOrderer(t)
+	channel.Orderer.EtcdRaft.Consenters = raftOrderer.EtcdRaft.Consenters
+}
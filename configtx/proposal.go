@@ -0,0 +1,445 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// ProposalVersion is the version of the proposal format written by
+// Proposal.Marshal.
+const ProposalVersion = 1
+
+// Proposal is a config update together with what its signers need to
+// review it and the signatures collected so far. It is created by the
+// organization proposing the update, passed to each organization that
+// must sign it and finally converted to an envelope with Envelope and
+// submitted to the orderer.
+type Proposal struct {
+	ChannelID string
+	// ConfigUpdate is the marshaled config update.
+	ConfigUpdate []byte
+	// Summary describes the changes of the update, one change per line,
+	// e.g. "modified value /Channel/Application/Org1/MSP".
+	Summary []string
+	// RequiredPolicies are the mod policies of the modified config
+	// elements, all of which must be satisfied by the signatures.
+	RequiredPolicies []ProposalPolicy
+	Signatures       []*cb.ConfigSignature
+	// Freshness, if set, binds the proposal to the config it was computed
+	// from so that it can be rejected before submission once it no longer
+	// applies to the channel.
+	Freshness *ProposalFreshness
+}
+
+// ProposalFreshness asserts the state of the channel a proposal was
+// computed from. It is not covered by the signatures of the proposal and
+// only protects against submitting stale proposals by mistake.
+type ProposalFreshness struct {
+	// Sequence is the sequence of the config the update was computed
+	// from. The update can only be applied to a channel at this sequence.
+	Sequence  uint64
+	CreatedAt time.Time
+	// ExpiresAt is the time after which the proposal must no longer be
+	// submitted. A zero time never expires.
+	ExpiresAt time.Time
+}
+
+// ProposalPolicy is a mod policy that must be satisfied by the signatures
+// of a proposal.
+type ProposalPolicy struct {
+	// Path is the config path of the policy, e.g.
+	// /Channel/Application/Admins.
+	Path string
+	Rule string
+	// Signers are the principals whose signatures can contribute to
+	// satisfying the policy.
+	Signers []Principal
+}
+
+// NewProposal computes the config update from the original to the updated
+// config like ComputeMarshaledUpdate and returns a proposal for it without
+// signatures.
+func (c *ConfigTx) NewProposal(channelID string) (*Proposal, error) {
+	marshaledUpdate, err := c.ComputeMarshaledUpdate(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update: %w", err)
+	}
+
+	differences, err := DiffAt([]string{ChannelGroupKey}, c.original, c.updated)
+	if err != nil {
+		return nil, fmt.Errorf("summarizing config update: %w", err)
+	}
+
+	var summary []string
+	for _, d := range differences {
+		summary = append(summary, fmt.Sprintf("%s %s %s", d.Change, d.Element, d.Path))
+	}
+
+	policies, err := requiredPolicies(c.original.ChannelGroup, update, c.options.evaluator())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proposal{
+		ChannelID:        channelID,
+		ConfigUpdate:     marshaledUpdate,
+		Summary:          summary,
+		RequiredPolicies: policies,
+	}, nil
+}
+
+// NewProposalWithFreshness returns a proposal like NewProposal, bound to
+// the sequence of the original config. The proposal expires validFor
+// after it was created, according to the clock of the ConfigTx, or never
+// if validFor is zero.
+func (c *ConfigTx) NewProposalWithFreshness(channelID string, validFor time.Duration) (*Proposal, error) {
+	if validFor < 0 {
+		return nil, fmt.Errorf("invalid validity period %s", validFor)
+	}
+
+	p, err := c.NewProposal(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt := c.options.now().UTC()
+	p.Freshness = &ProposalFreshness{
+		Sequence:  c.original.Sequence,
+		CreatedAt: createdAt,
+	}
+	if validFor != 0 {
+		p.Freshness.ExpiresAt = createdAt.Add(validFor)
+	}
+
+	return p, nil
+}
+
+// VerifyFreshness checks that a proposal bound with
+// NewProposalWithFreshness still applies to the live config of the
+// channel at time now: the channel must still be at the sequence the
+// update was computed from, and the proposal must not have expired. A
+// proposal without freshness is always fresh.
+func (p *Proposal) VerifyFreshness(live *cb.Config, now time.Time) error {
+	if p.Freshness == nil {
+		return nil
+	}
+
+	if live.GetSequence() != p.Freshness.Sequence {
+		return fmt.Errorf("proposal was computed from config sequence %d but channel %s is at sequence %d, recompute the update from the current config",
+			p.Freshness.Sequence, p.ChannelID, live.GetSequence())
+	}
+
+	if !p.Freshness.ExpiresAt.IsZero() && now.After(p.Freshness.ExpiresAt) {
+		return fmt.Errorf("proposal expired at %s", p.Freshness.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Sign adds a signature of the config update by the signing identity to
+// the proposal.
+func (p *Proposal) Sign(signer *SigningIdentity) error {
+	signature, err := signer.CreateConfigSignature(p.ConfigUpdate)
+	if err != nil {
+		return fmt.Errorf("signing proposal: %w", err)
+	}
+
+	p.Signatures = append(p.Signatures, signature)
+
+	return nil
+}
+
+// SignerMSPIDs returns the sorted, distinct MSP IDs of the identities that
+// signed the proposal. The signatures themselves are not verified.
+func (p *Proposal) SignerMSPIDs() ([]string, error) {
+	seen := map[string]bool{}
+	var mspIDs []string
+
+	for i, signature := range p.Signatures {
+		header := &cb.SignatureHeader{}
+		err := proto.Unmarshal(signature.SignatureHeader, header)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling signature header of signature %d: %w", i, err)
+		}
+
+		creator := &mb.SerializedIdentity{}
+		err = proto.Unmarshal(header.Creator, creator)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling creator of signature %d: %w", i, err)
+		}
+
+		if !seen[creator.Mspid] {
+			seen[creator.Mspid] = true
+			mspIDs = append(mspIDs, creator.Mspid)
+		}
+	}
+	sort.Strings(mspIDs)
+
+	return mspIDs, nil
+}
+
+// Envelope returns the config update envelope with the collected
+// signatures, ready to be submitted to the orderer.
+func (p *Proposal) Envelope() (*cb.Envelope, error) {
+	return NewEnvelope(p.ConfigUpdate, p.Signatures...)
+}
+
+// SubmissionEnvelope returns the envelope of the proposal like Envelope
+// after checking with VerifyFreshness that it still applies to the live
+// config of the channel.
+func (p *Proposal) SubmissionEnvelope(live *cb.Config, now time.Time) (*cb.Envelope, error) {
+	err := p.VerifyFreshness(live, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Envelope()
+}
+
+// proposalFile is the JSON encoding of a proposal.
+type proposalFile struct {
+	Version          int                  `json:"version"`
+	ChannelID        string               `json:"channel_id"`
+	ConfigUpdate     []byte               `json:"config_update"`
+	Summary          []string             `json:"summary,omitempty"`
+	RequiredPolicies []proposalFilePolicy `json:"required_policies,omitempty"`
+	Signatures       []proposalSignature  `json:"signatures,omitempty"`
+	Freshness        *proposalFreshness   `json:"freshness,omitempty"`
+}
+
+type proposalFilePolicy struct {
+	Path    string   `json:"path"`
+	Rule    string   `json:"rule"`
+	Signers []string `json:"signers,omitempty"`
+}
+
+type proposalFreshness struct {
+	Sequence  uint64     `json:"sequence"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type proposalSignature struct {
+	SignatureHeader []byte `json:"signature_header"`
+	Signature       []byte `json:"signature"`
+}
+
+// Marshal encodes the proposal as JSON so that it can be written to a
+// single file and passed between organizations.
+func (p *Proposal) Marshal() ([]byte, error) {
+	f := proposalFile{
+		Version:      ProposalVersion,
+		ChannelID:    p.ChannelID,
+		ConfigUpdate: p.ConfigUpdate,
+		Summary:      p.Summary,
+	}
+
+	for _, policy := range p.RequiredPolicies {
+		filePolicy := proposalFilePolicy{Path: policy.Path, Rule: policy.Rule}
+		for _, signer := range policy.Signers {
+			filePolicy.Signers = append(filePolicy.Signers, signer.String())
+		}
+		f.RequiredPolicies = append(f.RequiredPolicies, filePolicy)
+	}
+
+	for _, signature := range p.Signatures {
+		f.Signatures = append(f.Signatures, proposalSignature{
+			SignatureHeader: signature.SignatureHeader,
+			Signature:       signature.Signature,
+		})
+	}
+
+	if p.Freshness != nil {
+		f.Freshness = &proposalFreshness{
+			Sequence:  p.Freshness.Sequence,
+			CreatedAt: p.Freshness.CreatedAt,
+		}
+		if !p.Freshness.ExpiresAt.IsZero() {
+			f.Freshness.ExpiresAt = &p.Freshness.ExpiresAt
+		}
+	}
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// UnmarshalProposal decodes a proposal encoded by Proposal.Marshal,
+// upgrading proposals encoded by older versions of this library.
+func UnmarshalProposal(data []byte) (*Proposal, error) {
+	data, err := proposalSchema.upgrade(data)
+	if err != nil {
+		return nil, err
+	}
+
+	f := proposalFile{}
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return nil, fmt.Errorf("decoding proposal: %w", err)
+	}
+
+	if len(f.ConfigUpdate) == 0 {
+		return nil, errors.New("proposal does not contain a config update")
+	}
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(f.ConfigUpdate, update)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update: %w", err)
+	}
+
+	if update.ChannelId != f.ChannelID {
+		return nil, fmt.Errorf("config update is for channel %s, not %s", update.ChannelId, f.ChannelID)
+	}
+
+	p := &Proposal{
+		ChannelID:    f.ChannelID,
+		ConfigUpdate: f.ConfigUpdate,
+		Summary:      f.Summary,
+	}
+
+	for _, filePolicy := range f.RequiredPolicies {
+		policy := ProposalPolicy{Path: filePolicy.Path, Rule: filePolicy.Rule}
+		for _, signer := range filePolicy.Signers {
+			principal, err := parsePrincipal(signer)
+			if err != nil {
+				return nil, fmt.Errorf("invalid signer of policy %s: %w", filePolicy.Path, err)
+			}
+			policy.Signers = append(policy.Signers, principal)
+		}
+		p.RequiredPolicies = append(p.RequiredPolicies, policy)
+	}
+
+	for _, signature := range f.Signatures {
+		p.Signatures = append(p.Signatures, &cb.ConfigSignature{
+			SignatureHeader: signature.SignatureHeader,
+			Signature:       signature.Signature,
+		})
+	}
+
+	if f.Freshness != nil {
+		p.Freshness = &ProposalFreshness{
+			Sequence:  f.Freshness.Sequence,
+			CreatedAt: f.Freshness.CreatedAt,
+		}
+		if f.Freshness.ExpiresAt != nil {
+			p.Freshness.ExpiresAt = *f.Freshness.ExpiresAt
+		}
+	}
+
+	return p, nil
+}
+
+// parsePrincipal parses a principal in the form returned by
+// Principal.String, e.g. Org1MSP.admin.
+func parsePrincipal(s string) (Principal, error) {
+	i := strings.LastIndex(s, ".")
+	if i <= 0 || i == len(s)-1 {
+		return Principal{}, fmt.Errorf("principal %s must be of the form MSPID.role", s)
+	}
+
+	return Principal{MSPID: s[:i], Role: s[i+1:]}, nil
+}
+
+// requiredPolicies returns the mod policies, resolved in the original
+// config, of the existing elements the update modifies. Like the
+// orderer, it governs the modification of a group by the group's mod
+// policy and that of a value or policy by its own mod policy, both
+// relative to the group. Elements the update adds are governed by the mod
+// policy of the group they are added to.
+func requiredPolicies(channelGroup *cb.ConfigGroup, update *cb.ConfigUpdate, evaluator PolicyEvaluator) ([]ProposalPolicy, error) {
+	type reference struct {
+		elementPath string
+		basePath    string
+		modPolicy   string
+	}
+
+	var references []reference
+
+	var walk func(group, readSet, writeSet *cb.ConfigGroup, groupPath string)
+	walk = func(group, readSet, writeSet *cb.ConfigGroup, groupPath string) {
+		if readSet == nil || readSet.Version != writeSet.Version {
+			references = append(references, reference{groupPath, groupPath, group.ModPolicy})
+		}
+
+		for _, name := range sortedKeys(writeSet.Values) {
+			value, ok := group.Values[name]
+			if !ok {
+				continue
+			}
+			if read, ok := readSet.GetValues()[name]; !ok || read.Version != writeSet.Values[name].Version {
+				references = append(references, reference{groupPath + "/" + name, groupPath, value.ModPolicy})
+			}
+		}
+
+		for _, name := range sortedKeys(writeSet.Policies) {
+			policy, ok := group.Policies[name]
+			if !ok {
+				continue
+			}
+			if read, ok := readSet.GetPolicies()[name]; !ok || read.Version != writeSet.Policies[name].Version {
+				references = append(references, reference{groupPath + "/" + name, groupPath, policy.ModPolicy})
+			}
+		}
+
+		for _, name := range sortedKeys(writeSet.Groups) {
+			subGroup, ok := group.Groups[name]
+			if !ok {
+				continue
+			}
+			walk(subGroup, readSet.GetGroups()[name], writeSet.Groups[name], groupPath+"/"+name)
+		}
+	}
+
+	channelPath := configPath(ChannelGroupKey)
+	walk(channelGroup, update.ReadSet, update.WriteSet, channelPath)
+
+	seen := map[string]bool{}
+	var policies []ProposalPolicy
+
+	for _, ref := range references {
+		if ref.modPolicy == "" {
+			return nil, fmt.Errorf("config element %s has no mod policy", ref.elementPath)
+		}
+
+		resolved, err := evaluator.Resolve(channelGroup, absolutePolicyPath(ref.basePath, ref.modPolicy))
+		if err != nil {
+			return nil, fmt.Errorf("resolving mod policy of %s: %w", ref.elementPath, err)
+		}
+
+		if seen[resolved.Path] {
+			continue
+		}
+		seen[resolved.Path] = true
+
+		policies = append(policies, ProposalPolicy{
+			Path:    resolved.Path,
+			Rule:    resolved.Policy.Rule,
+			Signers: resolved.Signers(),
+		})
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Path < policies[j].Path
+	})
+
+	return policies, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestProposal(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, privateKeys := baseApplication(t)
+	for i, mspID := range []string{"Org1MSP", "Org2MSP"} {
+		org := &application.Organizations[i]
+		org.MSP.Name = mspID
+		org.Policies[AdminsPolicyKey] = Policy{Type: SignaturePolicyType, Rule: "OR('" + mspID + ".admin')"}
+	}
+
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().SetACLs(map[string]string{"acl1": "Writers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	p, err := c.NewProposal("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(p.ChannelID).To(Equal("testchannel"))
+	gt.Expect(p.Summary).To(Equal([]string{
+		"modified value /Channel/Application/Values/ACLs",
+		"modified group /Channel/Application/Org1",
+		"added value /Channel/Application/Org1/Values/AnchorPeers",
+	}))
+	gt.Expect(p.RequiredPolicies).To(Equal([]ProposalPolicy{
+		{
+			Path:    "/Channel/Application/Admins",
+			Rule:    "MAJORITY Admins",
+			Signers: []Principal{{MSPID: "Org1MSP", Role: "admin"}, {MSPID: "Org2MSP", Role: "admin"}},
+		},
+		{
+			Path:    "/Channel/Application/Org1/Admins",
+			Rule:    "AND('Org1MSP.admin')",
+			Signers: []Principal{{MSPID: "Org1MSP", Role: "admin"}},
+		},
+	}))
+
+	signer := &SigningIdentity{
+		Certificate: application.Organizations[0].MSP.RootCerts[0],
+		PrivateKey:  privateKeys[0],
+		MSPID:       "Org1MSP",
+	}
+	err = p.Sign(signer)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	mspIDs, err := p.SignerMSPIDs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mspIDs).To(Equal([]string{"Org1MSP"}))
+
+	marshaled, err := p.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	unmarshaled, err := UnmarshalProposal(marshaled)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(unmarshaled).To(Equal(p))
+
+	env, err := unmarshaled.Envelope()
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(env.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+	err = proto.Unmarshal(payload.Data, configUpdateEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(configUpdateEnvelope.ConfigUpdate).To(Equal(p.ConfigUpdate))
+	gt.Expect(configUpdateEnvelope.Signatures).To(HaveLen(1))
+}
+
+func TestUnmarshalProposalFailures(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		proposal    string
+		expectedErr string
+	}{
+		{
+			name:        "when the proposal is not JSON",
+			proposal:    "proposal",
+			expectedErr: "decoding proposal: invalid character 'p' looking for beginning of value",
+		},
+		{
+			name:        "when the version is unsupported",
+			proposal:    `{"version": 2}`,
+			expectedErr: "unsupported proposal version 2",
+		},
+		{
+			name:        "when the config update is missing",
+			proposal:    `{"version": 1, "channel_id": "testchannel"}`,
+			expectedErr: "proposal does not contain a config update",
+		},
+		{
+			name:        "when the config update is for another channel",
+			proposal:    `{"version": 1, "channel_id": "testchannel", "config_update": "CgVvdGhlcg=="}`,
+			expectedErr: "config update is for channel other, not testchannel",
+		},
+		{
+			name:        "when a signer is invalid",
+			proposal:    `{"version": 1, "channel_id": "other", "config_update": "CgVvdGhlcg==", "required_policies": [{"path": "/Channel/Admins", "signers": ["admin"]}]}`,
+			expectedErr: "invalid signer of policy /Channel/Admins: principal admin must be of the form MSPID.role",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := UnmarshalProposal([]byte(tc.proposal))
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestProposalFreshness(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	live := &cb.Config{Sequence: 4, ChannelGroup: channelGroup}
+
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := New(live, WithClock(fixedClock(now)))
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	p, err := c.NewProposalWithFreshness("testchannel", time.Hour)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(p.Freshness).To(Equal(&ProposalFreshness{
+		Sequence:  4,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}))
+
+	marshaled, err := p.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	unmarshaled, err := UnmarshalProposal(marshaled)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(unmarshaled).To(Equal(p))
+
+	_, err = unmarshaled.SubmissionEnvelope(live, now.Add(time.Minute))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = unmarshaled.VerifyFreshness(live, now.Add(2*time.Hour))
+	gt.Expect(err).To(MatchError("proposal expired at 2020-06-01T13:00:00Z"))
+
+	_, err = unmarshaled.SubmissionEnvelope(&cb.Config{Sequence: 5, ChannelGroup: channelGroup}, now)
+	gt.Expect(err).To(MatchError("proposal was computed from config sequence 4 but channel testchannel is at sequence 5, recompute the update from the current config"))
+
+	p, err = c.NewProposalWithFreshness("testchannel", 0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(p.Freshness.ExpiresAt.IsZero()).To(BeTrue())
+	gt.Expect(p.VerifyFreshness(live, now.Add(24*365*time.Hour))).To(Succeed())
+
+	marshaled, err = p.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(marshaled)).NotTo(ContainSubstring("expires_at"))
+	unmarshaled, err = UnmarshalProposal(marshaled)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(unmarshaled).To(Equal(p))
+
+	_, err = c.NewProposalWithFreshness("testchannel", -time.Second)
+	gt.Expect(err).To(MatchError("invalid validity period -1s"))
+
+	p.Freshness = nil
+	gt.Expect(p.VerifyFreshness(&cb.Config{Sequence: 10}, now)).To(Succeed())
+}
@@ -0,0 +1,276 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrProposalNotFound is returned by a ProposalStore for proposals it does
+// not hold.
+var ErrProposalNotFound = errors.New("proposal not found")
+
+// ProposalStore persists encoded proposals by ID, so that the state of a
+// signature collection that spans days survives restarts of the service
+// coordinating it. Implementations for files and memory are provided;
+// others, e.g. for object stores or databases, only need to store the
+// data opaquely and must be safe for concurrent use.
+type ProposalStore interface {
+	// Put stores the data of the proposal with the ID, replacing the
+	// data stored before.
+	Put(id string, data []byte) error
+	// Get returns the data of the proposal with the ID, or an error
+	// wrapping ErrProposalNotFound if there is none.
+	Get(id string) ([]byte, error)
+	// Delete removes the proposal with the ID. Deleting a proposal that
+	// does not exist is not an error.
+	Delete(id string) error
+	// List returns the IDs of the stored proposals in lexical order.
+	List() ([]string, error)
+}
+
+// SaveProposal encodes the proposal with Proposal.Marshal and stores it
+// with the ID.
+func SaveProposal(store ProposalStore, id string, p *Proposal) error {
+	data, err := p.Marshal()
+	if err != nil {
+		return fmt.Errorf("encoding proposal %s: %w", id, err)
+	}
+
+	err = store.Put(id, data)
+	if err != nil {
+		return fmt.Errorf("storing proposal %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// LoadProposal returns the proposal stored with the ID.
+func LoadProposal(store ProposalStore, id string) (*Proposal, error) {
+	data, err := store.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("loading proposal %s: %w", id, err)
+	}
+
+	p, err := UnmarshalProposal(data)
+	if err != nil {
+		return nil, fmt.Errorf("loading proposal %s: %w", id, err)
+	}
+
+	return p, nil
+}
+
+// SignStoredProposal adds a signature by the signing identity to the
+// proposal stored with the ID and stores the signed proposal. Concurrent
+// signers of the same proposal must be serialized by the caller, as a
+// signature added between loading and storing the proposal is lost.
+func SignStoredProposal(store ProposalStore, id string, signer *SigningIdentity) (*Proposal, error) {
+	p, err := LoadProposal(store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.Sign(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = SaveProposal(store, id, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// MemoryProposalStore is a ProposalStore that holds proposals in memory,
+// e.g. for tests and short-lived tools.
+type MemoryProposalStore struct {
+	mutex     sync.Mutex
+	proposals map[string][]byte
+}
+
+// NewMemoryProposalStore returns an empty MemoryProposalStore.
+func NewMemoryProposalStore() *MemoryProposalStore {
+	return &MemoryProposalStore{proposals: map[string][]byte{}}
+}
+
+// Put stores a copy of the data with the ID.
+func (m *MemoryProposalStore) Put(id string, data []byte) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.proposals[id] = append([]byte(nil), data...)
+
+	return nil
+}
+
+// Get returns a copy of the data stored with the ID.
+func (m *MemoryProposalStore) Get(id string) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	data, ok := m.proposals[id]
+	if !ok {
+		return nil, ErrProposalNotFound
+	}
+
+	return append([]byte(nil), data...), nil
+}
+
+// Delete removes the data stored with the ID.
+func (m *MemoryProposalStore) Delete(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.proposals, id)
+
+	return nil
+}
+
+// List returns the IDs of the stored proposals in lexical order.
+func (m *MemoryProposalStore) List() ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ids := make([]string, 0, len(m.proposals))
+	for id := range m.proposals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// proposalFileExtension is the extension of the files of a
+// FileProposalStore.
+const proposalFileExtension = ".json"
+
+// FileProposalStore is a ProposalStore that keeps each proposal in a file
+// named after its ID in a directory. Files are replaced atomically, so
+// that a crash while storing a proposal leaves its previous version in
+// place.
+type FileProposalStore struct {
+	dir string
+}
+
+// NewFileProposalStore returns a FileProposalStore that keeps proposals in
+// dir, which is created when the first proposal is stored.
+func NewFileProposalStore(dir string) *FileProposalStore {
+	return &FileProposalStore{dir: dir}
+}
+
+// Put writes the data to the file of the ID.
+func (f *FileProposalStore) Put(id string, data []byte) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(f.dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory %s: %w", f.dir, err)
+	}
+
+	tmp, err := ioutil.TempFile(f.dir, "."+id+"-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file for %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Get reads the file of the ID.
+func (f *FileProposalStore) Get(id string) ([]byte, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrProposalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return data, nil
+}
+
+// Delete removes the file of the ID.
+func (f *FileProposalStore) Delete(id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// List returns the IDs of the proposal files in the directory in lexical
+// order.
+func (f *FileProposalStore) List() ([]string, error) {
+	entries, err := ioutil.ReadDir(f.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %s: %w", f.dir, err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, proposalFileExtension) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, proposalFileExtension))
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// path returns the path of the file of the ID. IDs must be usable as file
+// names and not be hidden files.
+func (f *FileProposalStore) path(id string) (string, error) {
+	if id == "" || strings.HasPrefix(id, ".") || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid proposal ID '%s'", id)
+	}
+
+	return filepath.Join(f.dir, id+proposalFileExtension), nil
+}
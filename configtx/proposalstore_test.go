@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestProposalStores(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		newStore func(t *testing.T) ProposalStore
+	}{
+		{
+			testName: "memory",
+			newStore: func(t *testing.T) ProposalStore {
+				return NewMemoryProposalStore()
+			},
+		},
+		{
+			testName: "file",
+			newStore: func(t *testing.T) ProposalStore {
+				dir, err := ioutil.TempDir("", "proposals")
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { os.RemoveAll(dir) })
+
+				return NewFileProposalStore(filepath.Join(dir, "store"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			store := tt.newStore(t)
+			p, signers := storedProposal(t)
+
+			ids, err := store.List()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(ids).To(BeEmpty())
+
+			_, err = LoadProposal(store, "update-1")
+			gt.Expect(errors.Is(err, ErrProposalNotFound)).To(BeTrue())
+
+			err = SaveProposal(store, "update-2", p)
+			gt.Expect(err).NotTo(HaveOccurred())
+			err = SaveProposal(store, "update-1", p)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			ids, err = store.List()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(ids).To(Equal([]string{"update-1", "update-2"}))
+
+			loaded, err := LoadProposal(store, "update-1")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(loaded).To(Equal(p))
+
+			// signatures collected by separate processes accumulate
+			for _, signer := range signers {
+				_, err = SignStoredProposal(store, "update-1", signer)
+				gt.Expect(err).NotTo(HaveOccurred())
+			}
+
+			loaded, err = LoadProposal(store, "update-1")
+			gt.Expect(err).NotTo(HaveOccurred())
+			mspIDs, err := loaded.SignerMSPIDs()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(mspIDs).To(Equal([]string{"Org1MSP", "Org2MSP"}))
+
+			err = store.Delete("update-1")
+			gt.Expect(err).NotTo(HaveOccurred())
+			err = store.Delete("update-1")
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			ids, err = store.List()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(ids).To(Equal([]string{"update-2"}))
+
+			_, err = SignStoredProposal(store, "update-1", signers[0])
+			gt.Expect(err).To(MatchError("loading proposal update-1: proposal not found"))
+		})
+	}
+}
+
+func TestFileProposalStoreFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "proposals")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	store := NewFileProposalStore(dir)
+
+	for _, id := range []string{"", ".hidden", "../update", `dir\update`} {
+		err = store.Put(id, []byte("{}"))
+		gt.Expect(err).To(MatchError("invalid proposal ID '" + id + "'"))
+		_, err = store.Get(id)
+		gt.Expect(err).To(MatchError("invalid proposal ID '" + id + "'"))
+		err = store.Delete(id)
+		gt.Expect(err).To(MatchError("invalid proposal ID '" + id + "'"))
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("not json"), 0o644)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ids, err := store.List()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ids).To(Equal([]string{"corrupt"}))
+
+	_, err = LoadProposal(store, "corrupt")
+	gt.Expect(err).To(HaveOccurred())
+	gt.Expect(err.Error()).To(HavePrefix("loading proposal corrupt: "))
+}
+
+// storedProposal returns a proposal to add an anchor peer to Org1 and
+// signing identities of the admins of Org1 and Org2.
+func storedProposal(t *testing.T) (*Proposal, []*SigningIdentity) {
+	gt := NewGomegaWithT(t)
+
+	application, privateKeys := baseApplication(t)
+	var signers []*SigningIdentity
+	for i, mspID := range []string{"Org1MSP", "Org2MSP"} {
+		org := &application.Organizations[i]
+		org.MSP.Name = mspID
+		signers = append(signers, &SigningIdentity{
+			Certificate: org.MSP.RootCerts[0],
+			PrivateKey:  privateKeys[i],
+			MSPID:       mspID,
+		})
+	}
+
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	p, err := c.NewProposal("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return p, signers
+}
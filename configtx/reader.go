@@ -0,0 +1,289 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ConfigReader provides read-only access to a config. It exposes the
+// getters of ConfigTx but none of its setters, and holds its own copy of
+// the config, so it can be handed to reporting and monitoring code without
+// risk of the config being modified through it. Every getter decodes the
+// config anew, so the returned values can be modified by the caller, except
+// for parsed certificates, which are shared between the values returned by
+// the same reader. Certificates are never shared with the ConfigTx the
+// reader was created from or with other readers.
+type ConfigReader struct {
+	tx ConfigTx
+}
+
+// NewConfigReader returns a reader of a copy of the config. The options
+// that affect decoding, such as WithValueTypes and WithValidityTime, are
+// applied to the getters; options that only affect mutations are ignored.
+func NewConfigReader(config *cb.Config, opts ...Option) ConfigReader {
+	return newConfigReader(config, newOptions(opts...))
+}
+
+// Reader returns a reader of a snapshot of the updated config. Later
+// changes to the ConfigTx are not visible through the reader.
+func (c *ConfigTx) Reader() ConfigReader {
+	return newConfigReader(c.updated, c.options)
+}
+
+// WithSnapshots publishes a snapshot of the updated config after each
+// mutation made through the ConfigTx, so that Snapshot can be called from
+// other goroutines, e.g. by monitors, while the ConfigTx is mutated. The
+// config is copied once per mutation.
+func WithSnapshots() Option {
+	return func(o *options) {
+		o.snapshots = true
+	}
+}
+
+// Snapshot returns a reader of the updated config as of the last mutation.
+// If the ConfigTx was created with WithSnapshots, the reader is the last
+// published snapshot and Snapshot, as well as the returned reader, may be
+// used concurrently with mutations of the ConfigTx in another goroutine.
+// Otherwise it is equivalent to Reader and must not be called concurrently
+// with mutations. Changes made directly to the config returned by
+// UpdatedConfig are only published with the next mutation.
+func (c *ConfigTx) Snapshot() ConfigReader {
+	if c.snapshots == nil {
+		return c.Reader()
+	}
+
+	return c.snapshots.load()
+}
+
+func newConfigReader(config *cb.Config, o options) ConfigReader {
+	snapshot := proto.Clone(config).(*cb.Config)
+	// the reader owns its certificates like it owns its config
+	o.certificates = newCertificateInterner(maxInternedCertificates)
+
+	return ConfigReader{
+		tx: ConfigTx{
+			original: snapshot,
+			updated:  snapshot,
+			options:  o,
+		},
+	}
+}
+
+// Config returns a copy of the config.
+func (r ConfigReader) Config() *cb.Config {
+	return proto.Clone(r.tx.updated).(*cb.Config)
+}
+
+// Channel returns the channel configuration.
+func (r ConfigReader) Channel() (Channel, error) {
+	return r.tx.Channel().Configuration()
+}
+
+// ChannelCapabilities returns the capabilities of the channel.
+func (r ConfigReader) ChannelCapabilities() ([]string, error) {
+	return r.tx.Channel().Capabilities()
+}
+
+// ChannelPolicies returns the policies of the channel group.
+func (r ConfigReader) ChannelPolicies() (map[string]Policy, error) {
+	return r.tx.Channel().Policies()
+}
+
+// Application returns the application configuration.
+func (r ConfigReader) Application() (Application, error) {
+	if !r.hasGroup(ApplicationGroupKey) {
+		return Application{}, errors.New("config does not contain an application group")
+	}
+
+	return r.tx.Application().Configuration()
+}
+
+// ApplicationOrg returns the configuration of the application org.
+func (r ConfigReader) ApplicationOrg(name string) (Organization, error) {
+	if !r.hasGroup(ApplicationGroupKey) {
+		return Organization{}, errors.New("config does not contain an application group")
+	}
+
+	org := r.tx.Application().Organization(name)
+	if org == nil {
+		return Organization{}, fmt.Errorf("application org %s does not exist", name)
+	}
+
+	return org.Configuration()
+}
+
+// ACLs returns the ACLs of the application.
+func (r ConfigReader) ACLs() (map[string]string, error) {
+	if !r.hasGroup(ApplicationGroupKey) {
+		return nil, errors.New("config does not contain an application group")
+	}
+
+	return r.tx.Application().ACLs()
+}
+
+// Orderer returns the orderer configuration.
+func (r ConfigReader) Orderer() (Orderer, error) {
+	if !r.hasGroup(OrdererGroupKey) {
+		return Orderer{}, errors.New("config does not contain an orderer group")
+	}
+
+	return r.tx.Orderer().Configuration()
+}
+
+// OrdererOrg returns the configuration of the orderer org.
+func (r ConfigReader) OrdererOrg(name string) (Organization, error) {
+	if !r.hasGroup(OrdererGroupKey) {
+		return Organization{}, errors.New("config does not contain an orderer group")
+	}
+
+	org := r.tx.Orderer().Organization(name)
+	if org == nil {
+		return Organization{}, fmt.Errorf("orderer org %s does not exist", name)
+	}
+
+	return org.Configuration()
+}
+
+// Consortiums returns the configurations of the consortiums.
+func (r ConfigReader) Consortiums() ([]Consortium, error) {
+	if !r.hasGroup(ConsortiumsGroupKey) {
+		return nil, errors.New("config does not contain a consortiums group")
+	}
+
+	return r.tx.Consortiums().Configuration()
+}
+
+// Consortium returns the configuration of the consortium.
+func (r ConfigReader) Consortium(name string) (Consortium, error) {
+	if !r.hasGroup(ConsortiumsGroupKey) {
+		return Consortium{}, errors.New("config does not contain a consortiums group")
+	}
+
+	consortium := r.tx.Consortium(name)
+	if consortium == nil {
+		return Consortium{}, fmt.Errorf("consortium %s does not exist", name)
+	}
+
+	return consortium.Configuration()
+}
+
+// ConsortiumOrg returns the configuration of the org of the consortium.
+func (r ConfigReader) ConsortiumOrg(consortiumName, name string) (Organization, error) {
+	if !r.hasGroup(ConsortiumsGroupKey) {
+		return Organization{}, errors.New("config does not contain a consortiums group")
+	}
+
+	consortium := r.tx.Consortium(consortiumName)
+	if consortium == nil {
+		return Organization{}, fmt.Errorf("consortium %s does not exist", consortiumName)
+	}
+
+	org := consortium.Organization(name)
+	if org == nil {
+		return Organization{}, fmt.Errorf("org %s does not exist in consortium %s", name, consortiumName)
+	}
+
+	return org.Configuration()
+}
+
+// HasApplicationOrg reports whether the application org exists.
+func (r ConfigReader) HasApplicationOrg(name string) bool {
+	return r.tx.HasApplicationOrg(name)
+}
+
+// HasOrdererOrg reports whether the orderer org exists.
+func (r ConfigReader) HasOrdererOrg(name string) bool {
+	return r.tx.HasOrdererOrg(name)
+}
+
+// MSPForOrg returns the MSP of the organization, looked up like
+// ConfigTx.MSPForOrg.
+func (r ConfigReader) MSPForOrg(name string) (MSP, error) {
+	return r.tx.MSPForOrg(name)
+}
+
+// ValueAt returns a copy of the value at path, e.g.
+// /Channel/Application/Org1/Values/AnchorPeers.
+func (r ConfigReader) ValueAt(path string) (*cb.ConfigValue, error) {
+	return r.tx.ValueAt(path)
+}
+
+// ModPolicies lists the mod policies of the group at path and of its
+// values and policies.
+func (r ConfigReader) ModPolicies(path []string) ([]ModPolicyEntry, error) {
+	return r.tx.ModPolicies(path)
+}
+
+// ModPolicyAt returns the mod policy of the element at path, e.g.
+// /Channel/Orderer/Values/BatchSize.
+func (r ConfigReader) ModPolicyAt(path string) (string, error) {
+	return r.tx.ModPolicyAt(path)
+}
+
+// Walk calls fn for every group, value and policy of a copy of the config,
+// like the Walk function.
+func (r ConfigReader) Walk(fn WalkFunc) error {
+	return Walk(r.Config(), fn)
+}
+
+// Validate checks the config like ConfigTx.Validate.
+func (r ConfigReader) Validate() error {
+	return r.tx.Validate()
+}
+
+// ValidationReport validates the config like ConfigTx.ValidationReport.
+func (r ConfigReader) ValidationReport(warnings ...Warning) ValidationReport {
+	return r.tx.ValidationReport(warnings...)
+}
+
+// snapshotStore holds the last published snapshot of the updated config.
+// It is referenced by pointer so that copies of a ConfigTx share the
+// snapshots, like they share the updated config.
+type snapshotStore struct {
+	mutex  sync.RWMutex
+	reader ConfigReader
+}
+
+// newSnapshotStore returns a store holding a snapshot of the config.
+func newSnapshotStore(config *cb.Config, o options) *snapshotStore {
+	return &snapshotStore{reader: newConfigReader(config, o)}
+}
+
+// publish replaces the snapshot with a snapshot of the config. It is
+// called by the goroutine mutating the config, after the mutation.
+func (s *snapshotStore) publish(config *cb.Config, o options) {
+	if s == nil {
+		return
+	}
+
+	reader := newConfigReader(config, o)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.reader = reader
+}
+
+// load returns the last published snapshot.
+func (s *snapshotStore) load() ConfigReader {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.reader
+}
+
+// hasGroup reports whether the channel group contains the group.
+func (r ConfigReader) hasGroup(key string) bool {
+	_, ok := r.tx.updated.GetChannelGroup().GetGroups()[key]
+	return ok
+}
@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	original := proto.Clone(config)
+
+	r := NewConfigReader(config)
+
+	channel, err := r.Channel()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Capabilities).To(Equal([]string{"V2_0"}))
+
+	capabilities, err := r.ChannelCapabilities()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(capabilities).To(Equal([]string{"V2_0"}))
+
+	policies, err := r.ChannelPolicies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(HaveKey(AdminsPolicyKey))
+
+	application, err := r.Application()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(application.Organizations).To(HaveLen(2))
+
+	org, err := r.ApplicationOrg("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.Name).To(Equal("Org1"))
+	gt.Expect(r.HasApplicationOrg("Org1")).To(BeTrue())
+
+	acls, err := r.ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{"acl1": "hi"}))
+
+	ordererConf, err := r.Orderer()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConf.OrdererType).To(Equal(profile.Orderer.OrdererType))
+
+	ordererOrg, err := r.OrdererOrg("OrdererOrg")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererOrg.OrdererEndpoints).To(Equal([]string{"localhost:123"}))
+	gt.Expect(r.HasOrdererOrg("OrdererOrg")).To(BeTrue())
+
+	msp, err := r.MSPForOrg("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Name).To(Equal(org.MSP.Name))
+
+	_, err = r.ValueAt("/Channel/Orderer/Values/BatchSize")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	modPolicy, err := r.ModPolicyAt("/Channel/Application")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal(AdminsPolicyKey))
+
+	var paths []string
+	err = r.Walk(func(path string, group *cb.ConfigGroup, value *cb.ConfigValue, policy *cb.ConfigPolicy) error {
+		if group != nil {
+			paths = append(paths, path)
+			group.ModPolicy = "Modified"
+		}
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(paths).To(ContainElement("/Channel/Application/Org1"))
+
+	// modifying the returned values does not modify the config
+	acls["acl1"] = "Modified"
+	r.Config().ChannelGroup.Groups[ApplicationGroupKey].ModPolicy = "Modified"
+	config.ChannelGroup.Groups[ApplicationGroupKey].ModPolicy = "Modified"
+
+	acls, err = r.ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{"acl1": "hi"}))
+	gt.Expect(proto.Equal(r.Config(), original)).To(BeTrue())
+}
+
+func TestConfigTxReader(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	err = c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	r := c.Reader()
+
+	// changes made after the reader was created are not visible
+	err = c.Application().SetACLs(map[string]string{"acl3": "Readers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	acls, err := r.ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{"acl2": "Writers"}))
+
+	// certificates are not shared with the ConfigTx or other readers
+	msp, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	readerMSP, err := r.MSPForOrg("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	snapshotMSP, err := c.Snapshot().MSPForOrg("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(readerMSP.RootCerts[0]).To(Equal(msp.RootCerts[0]))
+	gt.Expect(readerMSP.RootCerts[0]).NotTo(BeIdenticalTo(msp.RootCerts[0]))
+	gt.Expect(snapshotMSP.RootCerts[0]).NotTo(BeIdenticalTo(msp.RootCerts[0]))
+	gt.Expect(snapshotMSP.RootCerts[0]).NotTo(BeIdenticalTo(readerMSP.RootCerts[0]))
+}
+
+func TestConfigTxSnapshot(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config, WithSnapshots())
+
+	done := make(chan struct{})
+	readErrs := make(chan error, 1)
+	go func() {
+		defer close(readErrs)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			_, err := c.Snapshot().ACLs()
+			if err != nil {
+				readErrs <- err
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		err := c.Application().SetACLs(map[string]string{fmt.Sprintf("acl%d", i): "Writers"})
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	close(done)
+	gt.Expect(<-readErrs).NotTo(HaveOccurred())
+
+	acls, err := c.Snapshot().ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{"acl19": "Writers"}))
+
+	// changes made directly to the updated config are not published
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].ModPolicy = ReadersPolicyKey
+	modPolicy, err := c.Snapshot().ModPolicyAt("/Channel/Application")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modPolicy).To(Equal(AdminsPolicyKey))
+}
+
+func TestConfigReaderFailures(t *testing.T) {
+	t.Parallel()
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := ConfigFromBlock(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ordererOnly := proto.Clone(config).(*cb.Config)
+	delete(ordererOnly.ChannelGroup.Groups, ApplicationGroupKey)
+
+	tests := []struct {
+		testName    string
+		config      *cb.Config
+		read        func(r ConfigReader) error
+		expectedErr string
+	}{
+		{
+			testName: "when the application org does not exist",
+			config:   config,
+			read: func(r ConfigReader) error {
+				_, err := r.ApplicationOrg("Org3")
+				return err
+			},
+			expectedErr: "application org Org3 does not exist",
+		},
+		{
+			testName: "when the orderer org does not exist",
+			config:   config,
+			read: func(r ConfigReader) error {
+				_, err := r.OrdererOrg("Org1")
+				return err
+			},
+			expectedErr: "orderer org Org1 does not exist",
+		},
+		{
+			testName: "when the config does not contain consortiums",
+			config:   config,
+			read: func(r ConfigReader) error {
+				_, err := r.ConsortiumOrg("SampleConsortium", "Org1")
+				return err
+			},
+			expectedErr: "config does not contain a consortiums group",
+		},
+		{
+			testName: "when the config does not contain an application group",
+			config:   ordererOnly,
+			read: func(r ConfigReader) error {
+				_, err := r.ACLs()
+				return err
+			},
+			expectedErr: "config does not contain an application group",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			err := tt.read(NewConfigReader(tt.config))
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
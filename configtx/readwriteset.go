@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Access is how a config update uses a config element.
+type Access string
+
+// The ways a config update uses config elements.
+const (
+	// AccessRead is an element of the read set that is not modified. The
+	// update depends on its version, e.g. because it modifies a member of
+	// the element.
+	AccessRead Access = "read"
+	// AccessModified is an element of the write set with an incremented
+	// version.
+	AccessModified Access = "modified"
+	// AccessAdded is an element of the write set with version 0 that is
+	// not in the read set.
+	AccessAdded Access = "added"
+)
+
+// ReadWriteSets is the read set and write set of a config update decoded
+// into the elements they contain.
+type ReadWriteSets struct {
+	ChannelID string
+	Elements  []ReadWriteElement
+}
+
+// ReadWriteElement is an element of the read set or write set of a config
+// update, together with the version the orderer expects the element to
+// have in the config the update is applied to.
+type ReadWriteElement struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Application/Org1/AnchorPeers.
+	Path    string
+	Element ElementType
+	Access  Access
+	// InReadSet and ReadVersion report whether the element is in the read
+	// set and its version there; InWriteSet and WriteVersion likewise for
+	// the write set.
+	InReadSet    bool
+	ReadVersion  uint64
+	InWriteSet   bool
+	WriteVersion uint64
+	// ModPolicy is the mod policy of the element in the write set, which
+	// must be satisfied to modify it.
+	ModPolicy string
+	// Content describes the content of added and modified values and
+	// policies, like UpdateChange.Content.
+	Content string
+	// Expectation states the version the element must have in the config
+	// the update is applied to, e.g. "requires version 1", or that it must
+	// not exist.
+	Expectation string
+	// InCurrent and CurrentVersion report whether the element exists in
+	// the current config passed to DecodeReadWriteSets and its version
+	// there.
+	InCurrent      bool
+	CurrentVersion uint64
+	// Mismatch explains why the orderer rejects the update because of the
+	// version of the element, or is empty if the versions are consistent.
+	Mismatch string
+}
+
+// String returns the elements of the read and write sets, one per line,
+// with the versions they require and their version mismatches.
+func (s ReadWriteSets) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "read and write sets of config update for channel %s\n", s.ChannelID)
+	for _, e := range s.Elements {
+		var versions []string
+		if e.InReadSet {
+			versions = append(versions, fmt.Sprintf("read: %d", e.ReadVersion))
+		}
+		if e.InWriteSet {
+			versions = append(versions, fmt.Sprintf("write: %d", e.WriteVersion), fmt.Sprintf("mod_policy: %q", e.ModPolicy))
+		}
+
+		fmt.Fprintf(&b, "%s %s %s [%s] %s", e.Access, e.Element, e.Path, strings.Join(versions, ", "), e.Expectation)
+		if e.Mismatch != "" {
+			fmt.Fprintf(&b, " VERSION MISMATCH: %s", e.Mismatch)
+		}
+		if e.Content != "" {
+			fmt.Fprintf(&b, ": %s", e.Content)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// Err returns the version mismatches of the elements as ValidationErrors,
+// or nil if there are none.
+func (s ReadWriteSets) Err() error {
+	var mismatches ValidationErrors
+	for _, e := range s.Elements {
+		if e.Mismatch != "" {
+			mismatches = append(mismatches, fmt.Errorf("%s %s: %s", e.Element, e.Path, e.Mismatch))
+		}
+	}
+
+	return mismatches.err()
+}
+
+// DecodeReadWriteSets decodes the read set and write set of a config update
+// into the elements they contain, each annotated with the version the
+// orderer expects it to have, to explain why the orderer rejects an update
+// with a version mismatch. The orderer requires that the elements of the
+// read set have exactly their read set version in the config, that
+// modified elements have the version preceding their write set version
+// and that added elements do not exist. If current, the config the update
+// is to be applied to, is not nil, the elements are compared to it and
+// the mismatches are reported; the read and write sets are checked against
+// each other in any case.
+func DecodeReadWriteSets(update *cb.ConfigUpdate, current *cb.Config) (ReadWriteSets, error) {
+	if update == nil || update.WriteSet == nil {
+		return ReadWriteSets{}, errors.New("config update does not contain a write set")
+	}
+
+	var currentGroup *cb.ConfigGroup
+	if current != nil {
+		currentGroup = current.ChannelGroup
+	}
+
+	d := &readWriteSetDecoder{
+		sets:       ReadWriteSets{ChannelID: update.ChannelId},
+		hasCurrent: current != nil,
+	}
+	err := d.decodeGroup(configPath(ChannelGroupKey), update.ReadSet, update.WriteSet, currentGroup)
+	if err != nil {
+		return ReadWriteSets{}, err
+	}
+
+	return d.sets, nil
+}
+
+// readWriteSetDecoder collects the elements of the read and write sets.
+type readWriteSetDecoder struct {
+	sets       ReadWriteSets
+	hasCurrent bool
+}
+
+// decodeGroup adds the group at path and its members. Any of the read set,
+// write set and current group is nil if it does not contain the group.
+func (d *readWriteSetDecoder) decodeGroup(path string, readSet, writeSet, current *cb.ConfigGroup) error {
+	e := ReadWriteElement{Path: path, Element: ElementGroup}
+	if readSet != nil {
+		e.InReadSet, e.ReadVersion = true, readSet.Version
+	}
+	if writeSet != nil {
+		e.InWriteSet, e.WriteVersion, e.ModPolicy = true, writeSet.Version, writeSet.ModPolicy
+	}
+	if current != nil {
+		e.InCurrent, e.CurrentVersion = true, current.Version
+	}
+	d.add(e)
+
+	for _, name := range unionKeys(readSet.GetValues(), writeSet.GetValues()) {
+		e := ReadWriteElement{Path: path + "/" + name, Element: ElementValue}
+		if value, ok := readSet.GetValues()[name]; ok {
+			e.InReadSet, e.ReadVersion = true, value.Version
+		}
+		if value, ok := writeSet.GetValues()[name]; ok {
+			e.InWriteSet, e.WriteVersion, e.ModPolicy = true, value.Version, value.ModPolicy
+			if !e.InReadSet || e.ReadVersion != e.WriteVersion {
+				content, err := describeValue(name, value.Value)
+				if err != nil {
+					return fmt.Errorf("decoding value %s/%s: %w", path, name, err)
+				}
+				e.Content = content
+			}
+		}
+		if value, ok := current.GetValues()[name]; ok {
+			e.InCurrent, e.CurrentVersion = true, value.Version
+		}
+		d.add(e)
+	}
+
+	for _, name := range unionKeys(readSet.GetPolicies(), writeSet.GetPolicies()) {
+		e := ReadWriteElement{Path: path + "/" + name, Element: ElementPolicy}
+		if policy, ok := readSet.GetPolicies()[name]; ok {
+			e.InReadSet, e.ReadVersion = true, policy.Version
+		}
+		if policy, ok := writeSet.GetPolicies()[name]; ok {
+			e.InWriteSet, e.WriteVersion, e.ModPolicy = true, policy.Version, policy.ModPolicy
+			if !e.InReadSet || e.ReadVersion != e.WriteVersion {
+				e.Content = policyRuleString(policy.Policy)
+			}
+		}
+		if policy, ok := current.GetPolicies()[name]; ok {
+			e.InCurrent, e.CurrentVersion = true, policy.Version
+		}
+		d.add(e)
+	}
+
+	for _, name := range unionKeys(readSet.GetGroups(), writeSet.GetGroups()) {
+		err := d.decodeGroup(path+"/"+name, readSet.GetGroups()[name], writeSet.GetGroups()[name], current.GetGroups()[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// add classifies the element, sets the version it requires and its
+// mismatches, and adds it to the decoded elements.
+func (d *readWriteSetDecoder) add(e ReadWriteElement) {
+	switch {
+	case e.InWriteSet && !e.InReadSet && e.WriteVersion == 0:
+		e.Access = AccessAdded
+		e.Expectation = "requires that it does not exist"
+		if d.hasCurrent && e.InCurrent {
+			e.Mismatch = fmt.Sprintf("it is added, but it already exists at version %d", e.CurrentVersion)
+		}
+
+	case e.InWriteSet && (!e.InReadSet || e.WriteVersion != e.ReadVersion):
+		e.Access = AccessModified
+		e.Expectation = fmt.Sprintf("requires version %d", e.WriteVersion-1)
+		switch {
+		case e.InReadSet && e.WriteVersion != e.ReadVersion+1:
+			e.Mismatch = fmt.Sprintf("write set version %d is not the read set version %d incremented by one", e.WriteVersion, e.ReadVersion)
+		case d.hasCurrent && !e.InCurrent:
+			e.Mismatch = fmt.Sprintf("it does not exist, so it must be added with version 0 instead of version %d", e.WriteVersion)
+		case d.hasCurrent && e.CurrentVersion+1 != e.WriteVersion:
+			e.Mismatch = fmt.Sprintf("write set version %d requires version %d, but it is at version %d", e.WriteVersion, e.WriteVersion-1, e.CurrentVersion)
+		}
+
+	default:
+		e.Access = AccessRead
+		e.Expectation = fmt.Sprintf("requires version %d", e.ReadVersion)
+		switch {
+		case d.hasCurrent && !e.InCurrent:
+			e.Mismatch = fmt.Sprintf("it is read at version %d, but it does not exist", e.ReadVersion)
+		case d.hasCurrent && e.CurrentVersion != e.ReadVersion:
+			e.Mismatch = fmt.Sprintf("read set version %d does not match version %d", e.ReadVersion, e.CurrentVersion)
+		}
+	}
+
+	d.sets.Elements = append(d.sets.Elements, e)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestDecodeReadWriteSets(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config, update := readWriteSetsTestUpdate(t)
+
+	sets, err := DecodeReadWriteSets(update, config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(sets.ChannelID).To(Equal("testchannel"))
+	gt.Expect(sets.Err()).NotTo(HaveOccurred())
+
+	elements := map[string]ReadWriteElement{}
+	for _, e := range sets.Elements {
+		elements[e.Path] = e
+	}
+
+	gt.Expect(elements["/Channel/Application"]).To(Equal(ReadWriteElement{
+		Path:           "/Channel/Application",
+		Element:        ElementGroup,
+		Access:         AccessRead,
+		InReadSet:      true,
+		InWriteSet:     true,
+		Expectation:    "requires version 0",
+		InCurrent:      true,
+		CurrentVersion: 0,
+	}))
+	gt.Expect(elements["/Channel/Application/ACLs"]).To(Equal(ReadWriteElement{
+		Path:         "/Channel/Application/ACLs",
+		Element:      ElementValue,
+		Access:       AccessModified,
+		InWriteSet:   true,
+		WriteVersion: 1,
+		ModPolicy:    AdminsPolicyKey,
+		Content:      `{"acls":{"acl2":{"policyRef":"Writers"}}}`,
+		Expectation:  "requires version 0",
+		InCurrent:    true,
+	}))
+	gt.Expect(elements["/Channel/Application/Org1/AnchorPeers"]).To(Equal(ReadWriteElement{
+		Path:        "/Channel/Application/Org1/AnchorPeers",
+		Element:     ElementValue,
+		Access:      AccessAdded,
+		InWriteSet:  true,
+		ModPolicy:   AdminsPolicyKey,
+		Content:     `{"anchorPeers":[{"host":"peer0.org1","port":7051}]}`,
+		Expectation: "requires that it does not exist",
+	}))
+
+	gt.Expect(sets.String()).To(ContainSubstring(
+		`modified value /Channel/Application/ACLs [write: 1, mod_policy: "Admins"] requires version 0: {"acls":{"acl2":{"policyRef":"Writers"}}}` + "\n",
+	))
+}
+
+func TestDecodeReadWriteSetsMismatches(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config, update := readWriteSetsTestUpdate(t)
+
+	// the config was updated by another update in the meantime
+	current := proto.Clone(config).(*cb.Config)
+	application := current.ChannelGroup.Groups[ApplicationGroupKey]
+	application.Version = 1
+	application.Values[ACLsKey].Version = 1
+	application.Groups["Org1"].Values[AnchorPeersKey] = &cb.ConfigValue{ModPolicy: AdminsPolicyKey}
+
+	sets, err := DecodeReadWriteSets(update, current)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(sets.Err()).To(MatchError(
+		"group /Channel/Application: read set version 0 does not match version 1; " +
+			"value /Channel/Application/ACLs: write set version 1 requires version 0, but it is at version 1; " +
+			"value /Channel/Application/Org1/AnchorPeers: it is added, but it already exists at version 0",
+	))
+	gt.Expect(sets.String()).To(ContainSubstring(
+		"read group /Channel/Application [read: 0, write: 0, mod_policy: \"\"] requires version 0 VERSION MISMATCH: read set version 0 does not match version 1\n",
+	))
+
+	// the read and write sets are inconsistent
+	update.ReadSet.Groups[ApplicationGroupKey].Values = map[string]*cb.ConfigValue{ACLsKey: {}}
+	update.WriteSet.Groups[ApplicationGroupKey].Values[ACLsKey].Version = 2
+	sets, err = DecodeReadWriteSets(update, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(sets.Err()).To(MatchError("value /Channel/Application/ACLs: write set version 2 is not the read set version 0 incremented by one"))
+}
+
+func TestDecodeReadWriteSetsFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	_, err := DecodeReadWriteSets(&cb.ConfigUpdate{}, nil)
+	gt.Expect(err).To(MatchError("config update does not contain a write set"))
+
+	_, err = DecodeReadWriteSets(&cb.ConfigUpdate{
+		WriteSet: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				ACLsKey: {Value: []byte("garbage")},
+			},
+		},
+	}, nil)
+	gt.Expect(err).To(MatchError(ContainSubstring("decoding value /Channel/ACLs: ")))
+}
+
+// readWriteSetsTestUpdate returns an application channel config and an
+// update of it that modifies the ACLs and adds an anchor peer to Org1.
+func readWriteSetsTestUpdate(t *testing.T) (*cb.Config, *cb.ConfigUpdate) {
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+	err = c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return config, update
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ChannelMembership compares the organizations of an application channel
+// with those of the consortium the channel was created from. Organizations
+// are identified by their MSP ID, as org names may differ between the
+// consortium and the channel.
+type ChannelMembership struct {
+	ChannelID  string
+	Consortium string
+	// MissingOrgs are the MSP IDs of the consortium's organizations that
+	// are not members of the channel.
+	MissingOrgs []string
+	// UnknownOrgs are the MSP IDs of the channel's application
+	// organizations that are not members of the consortium.
+	UnknownOrgs []string
+}
+
+// Consistent returns true if the channel and its consortium have the same
+// organizations.
+func (m ChannelMembership) Consistent() bool {
+	return len(m.MissingOrgs) == 0 && len(m.UnknownOrgs) == 0
+}
+
+// ReconcileMembership compares the application organizations of each of
+// the channel configs, keyed by channel ID, with the organizations of the
+// consortium named by the channel in the system channel config. It returns
+// the membership of every channel, sorted by channel ID, so that the
+// channels whose membership has drifted from their consortium can be
+// updated.
+func ReconcileMembership(systemChannel *cb.Config, channels map[string]*cb.Config) ([]ChannelMembership, error) {
+	consortiums, ok := systemChannel.GetChannelGroup().GetGroups()[ConsortiumsGroupKey]
+	if !ok {
+		return nil, errors.New("system channel config does not contain consortiums")
+	}
+
+	channelIDs := make([]string, 0, len(channels))
+	for channelID := range channels {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	var report []ChannelMembership
+	for _, channelID := range channelIDs {
+		channelGroup := channels[channelID].GetChannelGroup()
+		if channelGroup == nil {
+			return nil, fmt.Errorf("config of channel %s does not contain a channel group", channelID)
+		}
+
+		consortium := &cb.Consortium{}
+		err := unmarshalConfigValueAtKey(channelGroup, ConsortiumKey, consortium)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving consortium of channel %s: %w", channelID, err)
+		}
+
+		consortiumGroup, ok := consortiums.Groups[consortium.Name]
+		if !ok {
+			return nil, fmt.Errorf("consortium %s of channel %s does not exist", consortium.Name, channelID)
+		}
+
+		consortiumMSPIDs, err := orgMSPIDs(consortiumGroup)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving organizations of consortium %s: %w", consortium.Name, err)
+		}
+
+		channelMSPIDs, err := orgMSPIDs(channelGroup.Groups[ApplicationGroupKey])
+		if err != nil {
+			return nil, fmt.Errorf("retrieving organizations of channel %s: %w", channelID, err)
+		}
+
+		membership := ChannelMembership{
+			ChannelID:  channelID,
+			Consortium: consortium.Name,
+		}
+		for _, mspID := range sortedKeys(consortiumMSPIDs) {
+			if _, ok := channelMSPIDs[mspID]; !ok {
+				membership.MissingOrgs = append(membership.MissingOrgs, mspID)
+			}
+		}
+		for _, mspID := range sortedKeys(channelMSPIDs) {
+			if _, ok := consortiumMSPIDs[mspID]; !ok {
+				membership.UnknownOrgs = append(membership.UnknownOrgs, mspID)
+			}
+		}
+
+		report = append(report, membership)
+	}
+
+	return report, nil
+}
+
+// orgMSPIDs returns the org groups of the group keyed by the MSP IDs of
+// the organizations.
+func orgMSPIDs(group *cb.ConfigGroup) (map[string]*cb.ConfigGroup, error) {
+	mspIDs := map[string]*cb.ConfigGroup{}
+
+	for _, orgName := range sortedKeys(group.GetGroups()) {
+		fabricMSPConfig, err := getFabricMSPConfig(group.Groups[orgName])
+		if err != nil {
+			return nil, fmt.Errorf("retrieving MSP of %s: %w", orgName, err)
+		}
+
+		mspIDs[fabricMSPConfig.Name] = group.Groups[orgName]
+	}
+
+	return mspIDs, nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestReconcileMembership(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	org1, org2, org3 := reconcileTestOrg(t, "Org1"), reconcileTestOrg(t, "Org2"), reconcileTestOrg(t, "Org3")
+
+	consortiumsGroup, err := NewConsortiumsGroup([]Consortium{
+		{Name: "Consortium1", Organizations: []Organization{org1, org2}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	systemChannel := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ConsortiumsGroupKey: consortiumsGroup,
+			},
+		},
+	}
+
+	channels := map[string]*cb.Config{
+		"drifted":    reconcileTestChannel(t, "Consortium1", org1, org3),
+		"consistent": reconcileTestChannel(t, "Consortium1", org2, org1),
+	}
+
+	report, err := ReconcileMembership(systemChannel, channels)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(Equal([]ChannelMembership{
+		{
+			ChannelID:  "consistent",
+			Consortium: "Consortium1",
+		},
+		{
+			ChannelID:   "drifted",
+			Consortium:  "Consortium1",
+			MissingOrgs: []string{"Org2MSP"},
+			UnknownOrgs: []string{"Org3MSP"},
+		},
+	}))
+	gt.Expect(report[0].Consistent()).To(BeTrue())
+	gt.Expect(report[1].Consistent()).To(BeFalse())
+
+	_, err = ReconcileMembership(systemChannel, map[string]*cb.Config{
+		"other": reconcileTestChannel(t, "Consortium2", org1),
+	})
+	gt.Expect(err).To(MatchError("consortium Consortium2 of channel other does not exist"))
+
+	_, err = ReconcileMembership(systemChannel, map[string]*cb.Config{
+		"nameless": {ChannelGroup: newConfigGroup()},
+	})
+	gt.Expect(err).To(MatchError("retrieving consortium of channel nameless: config does not contain value for Consortium"))
+
+	_, err = ReconcileMembership(channels["drifted"], channels)
+	gt.Expect(err).To(MatchError("system channel config does not contain consortiums"))
+}
+
+func reconcileTestOrg(t *testing.T, name string) Organization {
+	msp, _ := baseMSP(t)
+	msp.Name = name + "MSP"
+
+	return Organization{
+		Name:     name,
+		Policies: applicationOrgStandardPolicies(),
+		MSP:      msp,
+	}
+}
+
+func reconcileTestChannel(t *testing.T, consortium string, orgs ...Organization) *cb.Config {
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	application.Organizations = orgs
+	applicationGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[ApplicationGroupKey] = applicationGroup
+	err = setValue(channelGroup, consortiumValue(consortium), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return &cb.Config{ChannelGroup: channelGroup}
+}
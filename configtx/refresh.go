@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// CARefresh is the outcome of RefreshCABundles.
+type CARefresh struct {
+	// Updated are the config paths of the organizations whose MSP was
+	// updated.
+	Updated []string
+	// Unmatched are the MSP IDs of the CA bundles that do not match any
+	// organization of the config.
+	Unmatched []string
+}
+
+// caBundle holds the certificates of a CA bundle directory. A nil field
+// is a certificate directory missing from the bundle.
+type caBundle struct {
+	rootCerts            []*x509.Certificate
+	intermediateCerts    []*x509.Certificate
+	tlsRootCerts         []*x509.Certificate
+	tlsIntermediateCerts []*x509.Certificate
+}
+
+// RefreshCABundles replaces the CA certificates of the MSPs of the
+// application, orderer and consortium organizations of the updated config
+// with the CA bundles in dir, in a single update. Each sub-directory of dir
+// is the CA bundle of the MSP ID it is named after and is laid out like a
+// local MSP directory: the root, intermediate, TLS root and TLS
+// intermediate certificates are read from the cacerts, intermediatecerts,
+// tlscacerts and tlsintermediatecerts directories. The certificates of a
+// directory missing from a bundle are left unchanged. Every MSP is
+// validated before the config is modified, so the config is unchanged if
+// an error is returned.
+func (c *ConfigTx) RefreshCABundles(dir string) (CARefresh, error) {
+	bundles, err := readCABundles(dir)
+	if err != nil {
+		return CARefresh{}, err
+	}
+
+	type refresh struct {
+		path string
+		msp  MSP
+	}
+
+	var refreshes []refresh
+	matched := map[string]bool{}
+
+	orgs := orgGroupsByPath(c.updated.ChannelGroup)
+	for _, path := range sortedKeys(orgs) {
+		msp, err := getMSPConfig(orgs[path], c.options.certificates)
+		if err != nil {
+			return CARefresh{}, fmt.Errorf("retrieving MSP of %s: %w", path, err)
+		}
+
+		bundle, ok := bundles[msp.Name]
+		if !ok {
+			continue
+		}
+		matched[msp.Name] = true
+
+		refreshed := msp
+		for _, field := range []struct {
+			certs  []*x509.Certificate
+			target *[]*x509.Certificate
+		}{
+			{bundle.rootCerts, &refreshed.RootCerts},
+			{bundle.intermediateCerts, &refreshed.IntermediateCerts},
+			{bundle.tlsRootCerts, &refreshed.TLSRootCerts},
+			{bundle.tlsIntermediateCerts, &refreshed.TLSIntermediateCerts},
+		} {
+			if field.certs != nil {
+				*field.target = field.certs
+			}
+		}
+
+		if sameCerts(msp.RootCerts, refreshed.RootCerts) &&
+			sameCerts(msp.IntermediateCerts, refreshed.IntermediateCerts) &&
+			sameCerts(msp.TLSRootCerts, refreshed.TLSRootCerts) &&
+			sameCerts(msp.TLSIntermediateCerts, refreshed.TLSIntermediateCerts) {
+			continue
+		}
+
+		err = refreshed.validateCACerts()
+		if err != nil {
+			return CARefresh{}, fmt.Errorf("refreshed MSP of %s: %w", path, err)
+		}
+
+		refreshes = append(refreshes, refresh{path: path, msp: refreshed})
+	}
+
+	result := CARefresh{}
+
+	for _, r := range refreshes {
+		mspConfig, err := newMSPConfig(r.msp)
+		if err != nil {
+			return CARefresh{}, fmt.Errorf("new msp config of %s: %w", r.path, err)
+		}
+
+		err = setValue(orgs[r.path], mspValue(mspConfig), AdminsPolicyKey)
+		if err != nil {
+			return CARefresh{}, err
+		}
+
+		c.notify(r.path, "RefreshCABundles")
+		c.checkAdminCerts(r.path, r.msp)
+
+		result.Updated = append(result.Updated, r.path)
+	}
+
+	for mspID := range bundles {
+		if !matched[mspID] {
+			result.Unmatched = append(result.Unmatched, mspID)
+		}
+	}
+	sort.Strings(result.Unmatched)
+
+	return result, nil
+}
+
+// readCABundles reads the CA bundles in dir keyed by MSP ID.
+func readCABundles(dir string) (map[string]caBundle, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %s: %w", dir, err)
+	}
+
+	bundles := map[string]caBundle{}
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+
+		bundleDir := filepath.Join(dir, info.Name())
+		bundle := caBundle{}
+		for _, field := range []struct {
+			dir    string
+			target *[]*x509.Certificate
+		}{
+			{mspCACertsDir, &bundle.rootCerts},
+			{mspIntermediateCertsDir, &bundle.intermediateCerts},
+			{mspTLSCACertsDir, &bundle.tlsRootCerts},
+			{mspTLSIntermediateCertsDir, &bundle.tlsIntermediateCerts},
+		} {
+			certsDir := filepath.Join(bundleDir, field.dir)
+			if _, err := os.Stat(certsDir); os.IsNotExist(err) {
+				continue
+			}
+
+			certs, err := readCertsDir(certsDir)
+			if err != nil {
+				return nil, err
+			}
+			*field.target = certs
+		}
+
+		if bundle.rootCerts != nil && len(bundle.rootCerts) == 0 {
+			return nil, fmt.Errorf("no root certificates found in %s", filepath.Join(bundleDir, mspCACertsDir))
+		}
+
+		bundles[info.Name()] = bundle
+	}
+
+	return bundles, nil
+}
+
+// sameCerts reports whether both lists contain the same certificates in
+// the same order.
+func sameCerts(certs1, certs2 []*x509.Certificate) bool {
+	if len(certs1) != len(certs2) {
+		return false
+	}
+
+	for i := range certs1 {
+		if !certs1[i].Equal(certs2[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestRefreshCABundles(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "cabundles")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	c, application := refreshTestConfigTx(t)
+
+	newCA, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	newTLSCA, _ := generateCACertAndPrivateKey(t, "tls.org1.example.com")
+	writeFile(t, filepath.Join(dir, "Org1MSP", "cacerts", "ca.pem"), pemEncodeX509Certificate(newCA))
+	writeFile(t, filepath.Join(dir, "Org1MSP", "intermediatecerts", "ca.pem"), pemEncodeX509Certificate(newCA))
+	writeFile(t, filepath.Join(dir, "Org1MSP", "tlscacerts", "tlsca.pem"), pemEncodeX509Certificate(newTLSCA))
+	writeFile(t, filepath.Join(dir, "Org2MSP", "cacerts", "ca.pem"), pemEncodeX509Certificate(application.Organizations[1].MSP.RootCerts[0]))
+	writeFile(t, filepath.Join(dir, "Org3MSP", "cacerts", "ca.pem"), pemEncodeX509Certificate(newCA))
+
+	var mutations []Mutation
+	c.options.observers = []Observer{func(m Mutation) { mutations = append(mutations, m) }}
+
+	refresh, err := c.RefreshCABundles(dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(refresh).To(Equal(CARefresh{
+		Updated:   []string{"/Channel/Application/Org1"},
+		Unmatched: []string{"Org3MSP"},
+	}))
+	gt.Expect(mutations).To(Equal([]Mutation{{Path: "/Channel/Application/Org1", Operation: "RefreshCABundles"}}))
+
+	msp, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.RootCerts).To(Equal([]*x509.Certificate{newCA}))
+	gt.Expect(msp.IntermediateCerts).To(Equal([]*x509.Certificate{newCA}))
+	gt.Expect(msp.TLSRootCerts).To(Equal([]*x509.Certificate{newTLSCA}))
+	gt.Expect(msp.TLSIntermediateCerts).To(Equal(application.Organizations[0].MSP.TLSIntermediateCerts))
+	gt.Expect(msp.Admins).To(Equal(application.Organizations[0].MSP.Admins))
+
+	gt.Expect(proto.Equal(c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"], c.original.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"])).To(BeTrue())
+}
+
+func TestRefreshCABundlesFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "cabundles")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	c, _ := refreshTestConfigTx(t)
+
+	_, err = c.RefreshCABundles(filepath.Join(dir, "missing"))
+	gt.Expect(err).To(MatchError(ContainSubstring("reading directory " + filepath.Join(dir, "missing"))))
+
+	err = os.MkdirAll(filepath.Join(dir, "Org2MSP", "cacerts"), 0o755)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.RefreshCABundles(dir)
+	gt.Expect(err).To(MatchError("no root certificates found in " + filepath.Join(dir, "Org2MSP", "cacerts")))
+
+	newCA, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	writeFile(t, filepath.Join(dir, "Org2MSP", "cacerts", "ca.pem"), pemEncodeX509Certificate(newCA))
+	_, err = c.RefreshCABundles(dir)
+	gt.Expect(err).To(MatchError(ContainSubstring("refreshed MSP of /Channel/Application/Org2: intermediate cert not signed by any root certs of this MSP")))
+	gt.Expect(proto.Equal(c.updated, c.original)).To(BeTrue())
+}
+
+func refreshTestConfigTx(t *testing.T) (ConfigTx, Application) {
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	application.Organizations[0].MSP.Name = "Org1MSP"
+	application.Organizations[1].MSP.Name = "Org2MSP"
+
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	}), application
+}
@@ -0,0 +1,242 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// rejectedKeyPattern matches the config key named in the version errors of
+// the orderer, e.g. "proposed update requires that key [Group]
+// /Channel/Application be at version 0, but it is currently at version 1".
+var rejectedKeyPattern = regexp.MustCompile(`key \[(?:Group|Value|Policy)\]\s*(/\S+)`)
+
+// RejectionDiagnosis explains the rejection of a config update by the
+// orderer.
+type RejectionDiagnosis struct {
+	Status cb.Status
+	Info   string
+	// RejectedPath is the config path of the element named by the
+	// rejection, if any.
+	RejectedPath string
+	// Conflicts are the elements whose versions in the update do not match
+	// the current config. If there are any, the config was updated since
+	// the update was computed and the update must be computed again.
+	Conflicts []VersionConflict
+}
+
+// VersionConflict is an element of a config update whose version does not
+// match the current config.
+type VersionConflict struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Application/ACLs.
+	Path    string
+	Element ElementType
+	// Mismatch explains the version mismatch, like
+	// ReadWriteElement.Mismatch.
+	Mismatch string
+	// Proposed and Current describe the content of the value or policy in
+	// the write set of the update and in the current config, like
+	// UpdateChange.Content. They are empty if the element is not in the
+	// write set or the current config.
+	Proposed string
+	Current  string
+	// Changes are the differences between the members of a group modified
+	// by the update and the members of the current group. Members of the
+	// current group missing from the update are reported as added; they
+	// were added since the update was computed, or the update removes
+	// them. Members of the update missing from the current group, other
+	// than those the update adds, are reported as removed; they were
+	// removed since the update was computed.
+	Changes []Difference
+}
+
+// IsVersionConflict reports whether the update was rejected because the
+// config was updated since the update was computed.
+func (d RejectionDiagnosis) IsVersionConflict() bool {
+	return len(d.Conflicts) > 0
+}
+
+// String returns the diagnosis with the conflicting elements and what
+// changed underneath them.
+func (d RejectionDiagnosis) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "config update rejected with status %s", d.Status)
+	if d.Info != "" {
+		fmt.Fprintf(&b, ": %s", d.Info)
+	}
+	b.WriteString("\n")
+
+	if !d.IsVersionConflict() {
+		b.WriteString("the versions of the update match the current config, the rejection is not caused by a version conflict\n")
+		return b.String()
+	}
+
+	b.WriteString("the config was updated since the update was computed:\n")
+	for _, conflict := range d.Conflicts {
+		fmt.Fprintf(&b, "  %s %s: %s\n", conflict.Element, conflict.Path, conflict.Mismatch)
+		for _, change := range conflict.Changes {
+			if change.Change == ChangeAdded {
+				fmt.Fprintf(&b, "    %s %s is in the current config, but not in the update\n", change.Element, change.Path)
+			} else {
+				fmt.Fprintf(&b, "    %s %s is in the update, but no longer in the current config\n", change.Element, change.Path)
+			}
+		}
+		if conflict.Proposed != "" {
+			fmt.Fprintf(&b, "    proposed: %s\n", conflict.Proposed)
+		}
+		if conflict.Current != "" {
+			fmt.Fprintf(&b, "    current: %s\n", conflict.Current)
+		}
+	}
+	b.WriteString("compute the update again from the current config and collect its signatures again\n")
+
+	return b.String()
+}
+
+// DiagnoseRejection explains why the orderer rejected a config update with
+// the status and info string, e.g. those of a broadcast.StatusError, by
+// comparing the read set and write set of the update with the current
+// config of the channel. Most rejections are version conflicts: another
+// update was committed since the update was computed, so the versions it
+// expects no longer match. The diagnosis lists the conflicting elements
+// and, for each, what changed underneath the update.
+func DiagnoseRejection(status cb.Status, info string, update *cb.ConfigUpdate, current *cb.Config) (RejectionDiagnosis, error) {
+	if current.GetChannelGroup() == nil {
+		return RejectionDiagnosis{}, errors.New("current config does not contain a channel group")
+	}
+
+	sets, err := DecodeReadWriteSets(update, current)
+	if err != nil {
+		return RejectionDiagnosis{}, err
+	}
+
+	d := RejectionDiagnosis{
+		Status: status,
+		Info:   info,
+	}
+	if match := rejectedKeyPattern.FindStringSubmatch(info); match != nil {
+		d.RejectedPath = match[1]
+	}
+
+	for _, e := range sets.Elements {
+		if e.Mismatch == "" {
+			continue
+		}
+
+		conflict := VersionConflict{
+			Path:     e.Path,
+			Element:  e.Element,
+			Mismatch: e.Mismatch,
+		}
+
+		switch e.Element {
+		case ElementGroup:
+			conflict.Changes = groupChanges(e, update.ReadSet, update.WriteSet, current.ChannelGroup)
+		default:
+			conflict.Proposed = elementContent(update.WriteSet, e.Path, e.Element)
+			conflict.Current = elementContent(current.ChannelGroup, e.Path, e.Element)
+		}
+
+		d.Conflicts = append(d.Conflicts, conflict)
+	}
+
+	return d, nil
+}
+
+// groupChanges returns the differences between the members of the group of
+// the element in the update and in the current config. Only groups modified
+// by the update list all of their members, so the changes of other groups
+// are unknown.
+func groupChanges(e ReadWriteElement, readSet, writeSet, current *cb.ConfigGroup) []Difference {
+	if e.Access != AccessModified {
+		return nil
+	}
+
+	elements := strings.Split(strings.TrimPrefix(e.Path, "/"), "/")[1:]
+	read, _ := configGroupAtPath(readSet, elements)
+	written, _ := configGroupAtPath(writeSet, elements)
+	currentGroup, _ := configGroupAtPath(current, elements)
+	if written == nil || currentGroup == nil {
+		return nil
+	}
+
+	var changes []Difference
+	// existed is whether the member existed when the update was computed,
+	// i.e. whether it is read or modified rather than added by the update
+	member := func(element ElementType, name string, existed, inWritten, inCurrent bool) {
+		path := e.Path + "/" + name
+		if element != ElementGroup {
+			path = elementPath(e.Path, element, name)
+		}
+
+		switch {
+		case inCurrent && !inWritten:
+			changes = append(changes, Difference{Path: path, Element: element, Change: ChangeAdded})
+		case !inCurrent && existed:
+			changes = append(changes, Difference{Path: path, Element: element, Change: ChangeRemoved})
+		}
+	}
+
+	for _, name := range unionKeys(written.Values, currentGroup.Values) {
+		_, inRead := read.GetValues()[name]
+		w, inWritten := written.Values[name]
+		_, inCurrent := currentGroup.Values[name]
+		member(ElementValue, name, inRead || w.GetVersion() > 0, inWritten, inCurrent)
+	}
+
+	for _, name := range unionKeys(written.Policies, currentGroup.Policies) {
+		_, inRead := read.GetPolicies()[name]
+		w, inWritten := written.Policies[name]
+		_, inCurrent := currentGroup.Policies[name]
+		member(ElementPolicy, name, inRead || w.GetVersion() > 0, inWritten, inCurrent)
+	}
+
+	for _, name := range unionKeys(written.Groups, currentGroup.Groups) {
+		_, inRead := read.GetGroups()[name]
+		w, inWritten := written.Groups[name]
+		_, inCurrent := currentGroup.Groups[name]
+		member(ElementGroup, name, inRead || w.GetVersion() > 0, inWritten, inCurrent)
+	}
+
+	return changes
+}
+
+// elementContent describes the content of the value or policy at path in
+// the channel group, or returns an empty string if it does not exist.
+func elementContent(channelGroup *cb.ConfigGroup, path string, element ElementType) string {
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	group, _ := configGroupAtPath(channelGroup, elements[1:len(elements)-1])
+	name := elements[len(elements)-1]
+
+	switch element {
+	case ElementValue:
+		value, ok := group.GetValues()[name]
+		if !ok {
+			return ""
+		}
+		content, err := describeValue(name, value.Value)
+		if err != nil {
+			return fmt.Sprintf("<undecodable value: %s>", err)
+		}
+		return content
+	case ElementPolicy:
+		policy, ok := group.GetPolicies()[name]
+		if !ok {
+			return ""
+		}
+		return policyRuleString(policy.Policy)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiagnoseRejection(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config := undoTestConfig(t)
+
+	// the proposer removes Org2 and modifies the ACLs
+	c := New(config)
+	c.Application().RemoveOrganization("Org2")
+	err := c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// meanwhile, another update added Org3 and modified the ACLs
+	c = New(config)
+	err = c.Application().SetACLs(map[string]string{"acl3": "Readers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	current := c.UpdatedConfig()
+	application := current.ChannelGroup.Groups[ApplicationGroupKey]
+	application.Version = 1
+	application.Values[ACLsKey].Version = 1
+	application.Groups["Org3"] = proto.Clone(application.Groups["Org1"]).(*cb.ConfigGroup)
+
+	info := "error applying config update to existing channel 'testchannel': error authorizing update: " +
+		"error validating DeltaSet: attempt to set key [Group]  /Channel/Application to version 1, but key is at version 1"
+
+	d, err := DiagnoseRejection(cb.Status_BAD_REQUEST, info, update, current)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(d.IsVersionConflict()).To(BeTrue())
+	gt.Expect(d.RejectedPath).To(Equal("/Channel/Application"))
+	gt.Expect(d.Conflicts).To(Equal([]VersionConflict{
+		{
+			Path:     "/Channel/Application",
+			Element:  ElementGroup,
+			Mismatch: "write set version 1 requires version 0, but it is at version 1",
+			Changes: []Difference{
+				{Path: "/Channel/Application/Org2", Element: ElementGroup, Change: ChangeAdded},
+				{Path: "/Channel/Application/Org3", Element: ElementGroup, Change: ChangeAdded},
+			},
+		},
+		{
+			Path:     "/Channel/Application/ACLs",
+			Element:  ElementValue,
+			Mismatch: "write set version 1 requires version 0, but it is at version 1",
+			Proposed: `{"acls":{"acl2":{"policyRef":"Writers"}}}`,
+			Current:  `{"acls":{"acl3":{"policyRef":"Readers"}}}`,
+		},
+	}))
+
+	gt.Expect(d.String()).To(Equal(
+		"config update rejected with status BAD_REQUEST: " + info + "\n" +
+			"the config was updated since the update was computed:\n" +
+			"  group /Channel/Application: write set version 1 requires version 0, but it is at version 1\n" +
+			"    group /Channel/Application/Org2 is in the current config, but not in the update\n" +
+			"    group /Channel/Application/Org3 is in the current config, but not in the update\n" +
+			"  value /Channel/Application/ACLs: write set version 1 requires version 0, but it is at version 1\n" +
+			`    proposed: {"acls":{"acl2":{"policyRef":"Writers"}}}` + "\n" +
+			`    current: {"acls":{"acl3":{"policyRef":"Readers"}}}` + "\n" +
+			"compute the update again from the current config and collect its signatures again\n",
+	))
+
+	// the update is not in conflict with the config it was computed from
+	d, err = DiagnoseRejection(cb.Status_FORBIDDEN, "implicit policy evaluation failed", update, config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(d.IsVersionConflict()).To(BeFalse())
+	gt.Expect(d.RejectedPath).To(BeEmpty())
+	gt.Expect(d.String()).To(ContainSubstring("the rejection is not caused by a version conflict"))
+
+	_, err = DiagnoseRejection(cb.Status_BAD_REQUEST, info, update, &cb.Config{})
+	gt.Expect(err).To(MatchError("current config does not contain a channel group"))
+}
@@ -0,0 +1,232 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+)
+
+// CertificateReplacement describes a certificate in the original config
+// that was replaced by another certificate in the updated config.
+type CertificateReplacement struct {
+	// Path is the config path of the organization for MSP certificates
+	// and of the orderer group for consenter certificates.
+	Path string
+	// Field names the replaced certificate, e.g. RootCerts or Admins for
+	// MSP certificates and ClientTLSCert or ServerTLSCert for consenter
+	// certificates.
+	Field string
+	// Consenter is the address of the consenter whose certificate was
+	// replaced. It is empty for MSP certificates.
+	Consenter orderer.EtcdAddress
+	Original  *x509.Certificate
+	Updated   *x509.Certificate
+	// SameKey reports whether the updated certificate uses the same public
+	// key as the original one. Same-key renewals are not subject to the
+	// restrictions placed on cluster membership changes while new-key
+	// replacements are.
+	SameKey bool
+}
+
+// CertificateReplacements compares the original and updated config and
+// returns the MSP and consenter certificates that were replaced.
+// A removed MSP certificate is considered replaced by an added certificate
+// in the same MSP field with the same subject. A consenter certificate is
+// considered replaced when the consenter with the same address uses a
+// different certificate.
+func (c *ConfigTx) CertificateReplacements() ([]CertificateReplacement, error) {
+	var replacements []CertificateReplacement
+
+	originalOrgs := orgGroupsByPath(c.original.ChannelGroup)
+	updatedOrgs := orgGroupsByPath(c.updated.ChannelGroup)
+
+	var paths []string
+	for path := range updatedOrgs {
+		if _, ok := originalOrgs[path]; ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		originalMSP, err := getMSPConfig(originalOrgs[path], c.options.certificates)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving original MSP of %s: %w", path, err)
+		}
+
+		updatedMSP, err := getMSPConfig(updatedOrgs[path], c.options.certificates)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving updated MSP of %s: %w", path, err)
+		}
+
+		for _, field := range []struct {
+			name              string
+			original, updated []*x509.Certificate
+		}{
+			{"RootCerts", originalMSP.RootCerts, updatedMSP.RootCerts},
+			{"IntermediateCerts", originalMSP.IntermediateCerts, updatedMSP.IntermediateCerts},
+			{"Admins", originalMSP.Admins, updatedMSP.Admins},
+			{"TLSRootCerts", originalMSP.TLSRootCerts, updatedMSP.TLSRootCerts},
+			{"TLSIntermediateCerts", originalMSP.TLSIntermediateCerts, updatedMSP.TLSIntermediateCerts},
+		} {
+			for _, r := range replacedCerts(field.original, field.updated) {
+				replacements = append(replacements, CertificateReplacement{
+					Path:     path,
+					Field:    field.name,
+					Original: r[0],
+					Updated:  r[1],
+					SameKey:  samePublicKey(r[0], r[1]),
+				})
+			}
+		}
+	}
+
+	originalConsenters, err := etcdRaftConsenters(c.original.ChannelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving original consenters: %w", err)
+	}
+
+	updatedConsenters, err := etcdRaftConsenters(c.updated.ChannelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving updated consenters: %w", err)
+	}
+
+	ordererPath := configPath(ChannelGroupKey, OrdererGroupKey)
+	for _, updated := range updatedConsenters {
+		for _, original := range originalConsenters {
+			if original.Address != updated.Address {
+				continue
+			}
+
+			for _, field := range []struct {
+				name              string
+				original, updated *x509.Certificate
+			}{
+				{"ClientTLSCert", original.ClientTLSCert, updated.ClientTLSCert},
+				{"ServerTLSCert", original.ServerTLSCert, updated.ServerTLSCert},
+			} {
+				if field.original == nil || field.updated == nil || field.original.Equal(field.updated) {
+					continue
+				}
+
+				replacements = append(replacements, CertificateReplacement{
+					Path:      ordererPath,
+					Field:     field.name,
+					Consenter: updated.Address,
+					Original:  field.original,
+					Updated:   field.updated,
+					SameKey:   samePublicKey(field.original, field.updated),
+				})
+			}
+		}
+	}
+
+	return replacements, nil
+}
+
+// replacedCerts pairs each certificate that was removed from original with
+// a certificate added in updated that has the same subject. Each added
+// certificate is paired with at most one removed certificate.
+func replacedCerts(original, updated []*x509.Certificate) [][2]*x509.Certificate {
+	var pairs [][2]*x509.Certificate
+	paired := make([]bool, len(updated))
+	for _, removed := range original {
+		if containsCert(updated, removed) {
+			continue
+		}
+
+		for i, added := range updated {
+			if paired[i] || containsCert(original, added) {
+				continue
+			}
+
+			if bytes.Equal(removed.RawSubject, added.RawSubject) {
+				pairs = append(pairs, [2]*x509.Certificate{removed, added})
+				paired[i] = true
+				break
+			}
+		}
+	}
+
+	return pairs
+}
+
+func containsCert(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// samePublicKey reports whether both certificates contain the same public
+// key.
+func samePublicKey(cert1, cert2 *x509.Certificate) bool {
+	return bytes.Equal(cert1.RawSubjectPublicKeyInfo, cert2.RawSubjectPublicKeyInfo)
+}
+
+// orgGroupsByPath returns the application, orderer and consortium
+// organization groups of the channel group keyed by config path.
+func orgGroupsByPath(channelGroup *cb.ConfigGroup) map[string]*cb.ConfigGroup {
+	orgs := map[string]*cb.ConfigGroup{}
+
+	for _, key := range []string{ApplicationGroupKey, OrdererGroupKey} {
+		group, ok := channelGroup.Groups[key]
+		if !ok {
+			continue
+		}
+
+		for orgName, orgGroup := range group.Groups {
+			orgs[configPath(ChannelGroupKey, key, orgName)] = orgGroup
+		}
+	}
+
+	if consortiums, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		for consortiumName, consortium := range consortiums.Groups {
+			for orgName, orgGroup := range consortium.Groups {
+				orgs[configPath(ChannelGroupKey, ConsortiumsGroupKey, consortiumName, orgName)] = orgGroup
+			}
+		}
+	}
+
+	return orgs
+}
+
+// etcdRaftConsenters returns the consenters of the orderer group. It
+// returns no consenters when the orderer group does not exist or does not
+// use the etcdraft consensus type.
+func etcdRaftConsenters(ordererGroup *cb.ConfigGroup, interner *certificateInterner) ([]orderer.Consenter, error) {
+	if ordererGroup == nil {
+		return nil, nil
+	}
+
+	consensusTypeProto := &ob.ConsensusType{}
+	err := unmarshalConfigValueAtKey(ordererGroup, orderer.ConsensusTypeKey, consensusTypeProto)
+	if err != nil {
+		return nil, err
+	}
+
+	if consensusTypeProto.Type != orderer.ConsensusTypeEtcdRaft {
+		return nil, nil
+	}
+
+	etcdRaft, err := unmarshalEtcdRaftMetadata(consensusTypeProto.Metadata, interner)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling etcd raft metadata: %w", err)
+	}
+
+	return etcdRaft.Consenters, nil
+}
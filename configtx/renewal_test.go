@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestCertificateReplacements(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseEtcdRaftOrderer(t)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+
+	signerCert, signerPrivKey := generateCACertAndPrivateKey(t, "signer.example.com")
+
+	ordererOrg := c.Orderer().Organization("OrdererOrg")
+	msp, err := ordererOrg.MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	originalAdmin := msp.Admins[0]
+	renewedAdmin := renewCert(t, originalAdmin, signerCert, signerPrivKey)
+	msp.Admins = []*x509.Certificate{renewedAdmin}
+
+	originalTLSRoot := msp.TLSRootCerts[0]
+	newTLSRoot, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	msp.TLSRootCerts = []*x509.Certificate{newTLSRoot}
+
+	err = ordererOrg.SetMSP(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConf, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	originalServerCert := ordererConf.EtcdRaft.Consenters[0].ServerTLSCert
+	renewedServerCert := renewCert(t, originalServerCert, signerCert, signerPrivKey)
+	ordererConf.EtcdRaft.Consenters[0].ServerTLSCert = renewedServerCert
+
+	originalClientCert := ordererConf.EtcdRaft.Consenters[1].ClientTLSCert
+	newClientCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", signerCert, signerPrivKey)
+	ordererConf.EtcdRaft.Consenters[1].ClientTLSCert = newClientCert
+
+	err = c.Orderer().SetEtcdRaftConsensusType(ordererConf.EtcdRaft, ordererConf.State)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	replacements, err := c.CertificateReplacements()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(replacements).To(Equal([]CertificateReplacement{
+		{
+			Path:     "/Channel/Orderer/OrdererOrg",
+			Field:    "Admins",
+			Original: originalAdmin,
+			Updated:  renewedAdmin,
+			SameKey:  true,
+		},
+		{
+			Path:     "/Channel/Orderer/OrdererOrg",
+			Field:    "TLSRootCerts",
+			Original: originalTLSRoot,
+			Updated:  newTLSRoot,
+			SameKey:  false,
+		},
+		{
+			Path:      "/Channel/Orderer",
+			Field:     "ServerTLSCert",
+			Consenter: ordererConf.EtcdRaft.Consenters[0].Address,
+			Original:  originalServerCert,
+			Updated:   renewedServerCert,
+			SameKey:   true,
+		},
+		{
+			Path:      "/Channel/Orderer",
+			Field:     "ClientTLSCert",
+			Consenter: ordererConf.EtcdRaft.Consenters[1].Address,
+			Original:  originalClientCert,
+			Updated:   newClientCert,
+			SameKey:   false,
+		},
+	}))
+}
+
+func TestCertificateReplacementsWithoutChanges(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	newRootCert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	err = c.Application().Organization("Org1").MSP().AddRootCert(newRootCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	replacements, err := c.CertificateReplacements()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(replacements).To(BeEmpty())
+}
+
+func TestReplacedCertsPairsEachAddedCertOnce(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	removed1, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	removed2, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	added, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	gt.Expect(removed1.RawSubject).To(Equal(added.RawSubject))
+	gt.Expect(removed2.RawSubject).To(Equal(added.RawSubject))
+
+	pairs := replacedCerts([]*x509.Certificate{removed1, removed2}, []*x509.Certificate{added})
+	gt.Expect(pairs).To(Equal([][2]*x509.Certificate{{removed1, added}}))
+}
+
+// renewCert returns a certificate with the same subject and public key as
+// cert that is signed by the given signer.
+func renewCert(t *testing.T, cert, signerCert *x509.Certificate, signerPrivKey *ecdsa.PrivateKey) *x509.Certificate {
+	gt := NewGomegaWithT(t)
+
+	template := &x509.Certificate{
+		SerialNumber:          generateSerialNumber(t),
+		Subject:               cert.Subject,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(2 * YEAR),
+		KeyUsage:              cert.KeyUsage,
+		ExtKeyUsage:           cert.ExtKeyUsage,
+		BasicConstraintsValid: cert.BasicConstraintsValid,
+		IsCA:                  cert.IsCA,
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, signerCert, cert.PublicKey, signerPrivKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	renewed, err := x509.ParseCertificate(derBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return renewed
+}
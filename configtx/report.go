@@ -0,0 +1,216 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ValidationReportVersion is the version of the validation report format
+// written by ValidationReport.Marshal.
+const ValidationReportVersion = 1
+
+// Severity is the severity of a finding of a validation report.
+type Severity string
+
+const (
+	// SeverityError is the severity of findings that cause the config
+	// update to be rejected or the channel to malfunction.
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of non-fatal findings.
+	SeverityWarning Severity = "warning"
+)
+
+// FindingCode identifies the check that produced a finding. Codes are
+// stable, so that CI systems can act on specific findings.
+type FindingCode string
+
+const (
+	// CodeInvalidMSP is the code of MSP values that cannot be decoded.
+	CodeInvalidMSP FindingCode = "invalid-msp"
+	// CodeInvalidCACert is the code of MSP CA certificates that are not CA
+	// certificates or not issued by a root certificate of the MSP.
+	CodeInvalidCACert FindingCode = "invalid-ca-cert"
+	// CodeCertValidity is the code of MSP CA certificates that are not
+	// valid as of the validity time.
+	CodeCertValidity FindingCode = "cert-validity"
+	// CodeCertChain is the code of admin and consenter TLS certificates
+	// that do not chain to the CAs of their organizations.
+	CodeCertChain FindingCode = "cert-chain"
+	// CodeAnchorPeer is the code of anchor peers that are declared twice
+	// or point at orderer endpoints.
+	CodeAnchorPeer FindingCode = "anchor-peer"
+	// CodeCapabilityGap is the code of application ACLs and policies that
+	// the application capability level does not support.
+	CodeCapabilityGap FindingCode = "capability-gap"
+	// CodeInvalidOrderer is the code of orderer configurations that cannot
+	// be decoded.
+	CodeInvalidOrderer FindingCode = "invalid-orderer"
+	// CodeConsenterHostname is the code of etcdraft consenter server TLS
+	// certificates that are not valid for the consenter's host.
+	CodeConsenterHostname FindingCode = "consenter-hostname"
+	// CodeEndpointCoverage is the code of orderer endpoints that no TLS
+	// certificate issued by the TLS CAs of their organization could serve.
+	CodeEndpointCoverage FindingCode = "endpoint-coverage"
+	// CodeConsensusMigration is the code of consensus type migrations that
+	// the orderer would reject.
+	CodeConsensusMigration FindingCode = "consensus-migration"
+	// CodeWarning is the code of warnings.
+	CodeWarning FindingCode = "warning"
+	// CodeDeprecation is the code of warnings about deprecated constructs.
+	CodeDeprecation FindingCode = "deprecation"
+)
+
+// remediations are the remediation hints of the findings of the codes.
+var remediations = map[FindingCode]string{
+	CodeInvalidMSP:         "encode the MSP of the organization from a valid MSP configuration",
+	CodeInvalidCACert:      "use CA certificates with the CA basic constraint and the certificate signing key usage, with intermediate certificates issued by a root certificate of the MSP",
+	CodeCertValidity:       "renew the CA certificates and update the MSP before the certificates expire",
+	CodeCertChain:          "issue the certificates from a CA of the organization's MSP or add the issuing CA to the MSP",
+	CodeAnchorPeer:         "remove the duplicate anchor peers and correct the anchor peer addresses copied from other organizations",
+	CodeCapabilityGap:      "raise the application capability level or remove the ACLs and policies that require a higher level",
+	CodeInvalidOrderer:     "encode the orderer values from a valid orderer configuration",
+	CodeConsenterHostname:  "issue the server TLS certificate of the consenter with the consenter's host in its subject alternative names",
+	CodeEndpointCoverage:   "correct the host of the orderer endpoint or issue the server TLS certificate of the orderer from a TLS CA of the organization for the host",
+	CodeConsensusMigration: "migrate the consensus type in maintenance mode, changing only the consensus type and its metadata",
+}
+
+// ReportFinding is a finding of a validation report.
+type ReportFinding struct {
+	Severity Severity
+	Code     FindingCode
+	// Path is the location in the config the finding applies to, e.g.
+	// /Channel/Application/Org1.
+	Path    string
+	Message string
+	// Remediation is a hint on how to resolve the finding, if any.
+	Remediation string
+
+	// err is the error of the validator that produced the finding.
+	err error
+}
+
+// ValidationReport holds the findings of Validate and warnings in a form
+// that can be encoded as JSON, e.g. so that CI systems can gate changes of
+// channel definitions on the validation results.
+type ValidationReport struct {
+	Findings []ReportFinding
+}
+
+// ValidationReport validates the updated config like Validate and returns
+// the findings followed by the warnings, e.g. those collected with
+// WithWarningHandler while editing the config.
+func (c *ConfigTx) ValidationReport(warnings ...Warning) ValidationReport {
+	report := ValidationReport{Findings: c.validationFindings()}
+
+	for _, warning := range warnings {
+		finding := ReportFinding{
+			Severity: SeverityWarning,
+			Code:     CodeWarning,
+			Path:     warning.Path,
+			Message:  warning.Message,
+		}
+		if warning.Deprecation() {
+			finding.Code = CodeDeprecation
+			finding.Remediation = fmt.Sprintf("use %s instead", warning.Replacement)
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	return report
+}
+
+// Passed reports whether the report has no findings of SeverityError.
+func (r ValidationReport) Passed() bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == SeverityError {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validationReportFile is the JSON encoding of a validation report:
+//
+//	{
+//	  "version": 1,
+//	  "passed": false,
+//	  "findings": [
+//	    {
+//	      "severity": "error",
+//	      "code": "anchor-peer",
+//	      "path": "/Channel/Application",
+//	      "message": "anchor peer ...",
+//	      "remediation": "remove the duplicate anchor peers ..."
+//	    }
+//	  ]
+//	}
+type validationReportFile struct {
+	Version  int                       `json:"version"`
+	Passed   bool                      `json:"passed"`
+	Findings []validationReportFinding `json:"findings"`
+}
+
+type validationReportFinding struct {
+	Severity    Severity    `json:"severity"`
+	Code        FindingCode `json:"code"`
+	Path        string      `json:"path"`
+	Message     string      `json:"message"`
+	Remediation string      `json:"remediation,omitempty"`
+}
+
+// Marshal encodes the report as JSON in the validation report format.
+func (r ValidationReport) Marshal() ([]byte, error) {
+	f := validationReportFile{
+		Version:  ValidationReportVersion,
+		Passed:   r.Passed(),
+		Findings: []validationReportFinding{},
+	}
+
+	for _, finding := range r.Findings {
+		f.Findings = append(f.Findings, validationReportFinding{
+			Severity:    finding.Severity,
+			Code:        finding.Code,
+			Path:        finding.Path,
+			Message:     finding.Message,
+			Remediation: finding.Remediation,
+		})
+	}
+
+	return json.MarshalIndent(f, "", "  ")
+}
+
+// UnmarshalValidationReport decodes a report encoded by
+// ValidationReport.Marshal, upgrading reports encoded by older versions of
+// this library.
+func UnmarshalValidationReport(data []byte) (ValidationReport, error) {
+	data, err := validationReportSchema.upgrade(data)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+
+	f := validationReportFile{}
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		return ValidationReport{}, fmt.Errorf("decoding validation report: %w", err)
+	}
+
+	report := ValidationReport{}
+	for _, finding := range f.Findings {
+		report.Findings = append(report.Findings, ReportFinding{
+			Severity:    finding.Severity,
+			Code:        finding.Code,
+			Path:        finding.Path,
+			Message:     finding.Message,
+			Remediation: finding.Remediation,
+		})
+	}
+
+	return report, nil
+}
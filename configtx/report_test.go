@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidationReport(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var warnings []Warning
+	c := New(config, WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+
+	report := c.ValidationReport()
+	gt.Expect(report.Passed()).To(BeTrue())
+	gt.Expect(report.Findings).To(BeEmpty())
+
+	marshaledReport, err := report.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaledReport).To(MatchJSON(`{"version": 1, "passed": true, "findings": []}`))
+
+	for _, orgName := range []string{"Org1", "Org2"} {
+		err = c.Application().Organization(orgName).AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+	err = c.Channel().AddLegacyOrdererAddress("orderer.example.com:7050")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).NotTo(BeEmpty())
+
+	report = c.ValidationReport(warnings...)
+	gt.Expect(report.Passed()).To(BeFalse())
+	gt.Expect(report.Findings[0]).To(Equal(ReportFinding{
+		Severity:    SeverityError,
+		Code:        CodeAnchorPeer,
+		Path:        "/Channel/Application",
+		Message:     "anchor peer peer0.org1.example.com:7051 of application org Org2 is also an anchor peer of application org Org1",
+		Remediation: remediations[CodeAnchorPeer],
+		err:         report.Findings[0].err,
+	}))
+	gt.Expect(report.Findings[0].err).To(HaveOccurred())
+
+	last := report.Findings[len(report.Findings)-1]
+	gt.Expect(last.Severity).To(Equal(SeverityWarning))
+	gt.Expect(last.Code).To(Equal(CodeDeprecation))
+	gt.Expect(last.Path).To(Equal("/Channel"))
+	gt.Expect(last.Remediation).To(HavePrefix("use orderer endpoints of each orderer org"))
+
+	err = c.Validate()
+	gt.Expect(err).To(MatchError(report.Findings[0].Message))
+
+	marshaledReport, err = report.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(marshaledReport)).To(ContainSubstring(`"passed": false`))
+	gt.Expect(string(marshaledReport)).To(ContainSubstring(`"code": "anchor-peer"`))
+
+	decoded, err := UnmarshalValidationReport(marshaledReport)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded.Passed()).To(BeFalse())
+	gt.Expect(decoded.Findings).To(HaveLen(len(report.Findings)))
+	gt.Expect(decoded.Findings[0].Code).To(Equal(CodeAnchorPeer))
+	gt.Expect(decoded.Findings[0].Message).To(Equal(report.Findings[0].Message))
+}
+
+func TestUnmarshalValidationReportFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	_, err := UnmarshalValidationReport([]byte("invalid"))
+	gt.Expect(err).To(MatchError(HavePrefix("decoding validation report: ")))
+
+	_, err = UnmarshalValidationReport([]byte(`{"version": 2}`))
+	gt.Expect(err).To(MatchError(ContainSubstring("validation report")))
+}
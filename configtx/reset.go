@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Reset discards every modification of the updated config, restoring it
+// to the original config. The config returned by UpdatedConfig remains
+// valid, but groups retrieved before the reset, e.g. with Application,
+// must be retrieved again. An error is returned if the ConfigTx was created
+// with WithoutClone, as it does not keep the original config.
+func (c *ConfigTx) Reset() error {
+	if c.options.withoutClone {
+		return errWithoutClone("Reset")
+	}
+
+	c.updated.Reset()
+	proto.Merge(c.updated, c.original)
+
+	c.notify(configPath(ChannelGroupKey), "Reset")
+
+	return nil
+}
+
+// ResetGroup discards the modifications of the group at path, e.g.
+// []string{"Channel", "Application", "Org1"}, and of its subtree,
+// restoring it to the original config. A group that was added to the
+// updated config is removed, and a group that was removed is restored if
+// its parent group exists. Like Reset, it is not supported with
+// WithoutClone.
+func (c *ConfigTx) ResetGroup(path []string) error {
+	if c.options.withoutClone {
+		return errWithoutClone("ResetGroup")
+	}
+
+	if len(path) == 0 || path[0] != ChannelGroupKey {
+		return fmt.Errorf("path must start with %s", ChannelGroupKey)
+	}
+
+	if len(path) == 1 {
+		return c.Reset()
+	}
+
+	groupPath := configPath(path...)
+	originalGroup, _ := configGroupAtPath(c.original.ChannelGroup, path[1:])
+	updatedGroup, _ := configGroupAtPath(c.updated.ChannelGroup, path[1:])
+	if originalGroup == nil && updatedGroup == nil {
+		return fmt.Errorf("group %s does not exist in either config", groupPath)
+	}
+
+	parentPath, name := path[:len(path)-1], path[len(path)-1]
+	parent, _ := configGroupAtPath(c.updated.ChannelGroup, parentPath[1:])
+	if parent == nil {
+		return fmt.Errorf("parent group %s does not exist in the updated config", configPath(parentPath...))
+	}
+
+	if originalGroup == nil {
+		delete(parent.Groups, name)
+	} else {
+		if parent.Groups == nil {
+			parent.Groups = map[string]*cb.ConfigGroup{}
+		}
+		parent.Groups[name] = proto.Clone(originalGroup).(*cb.ConfigGroup)
+	}
+
+	c.notify(groupPath, "ResetGroup")
+
+	return nil
+}
+
+// errWithoutClone returns the error of an operation that needs the original
+// config, which a ConfigTx created with WithoutClone does not keep.
+func errWithoutClone(operation string) error {
+	return fmt.Errorf("%s is not supported without a copy of the original config, see WithoutClone", operation)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestReset(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	updated := c.UpdatedConfig()
+
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.Application().RemoveOrganization("Org2")
+
+	gt.Expect(c.Reset()).To(Succeed())
+
+	gt.Expect(proto.Equal(c.UpdatedConfig(), c.OriginalConfig())).To(BeTrue())
+	gt.Expect(c.UpdatedConfig()).To(BeIdenticalTo(updated))
+	gt.Expect(c.UpdatedConfig().ChannelGroup).NotTo(BeIdenticalTo(c.OriginalConfig().ChannelGroup))
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).To(MatchError("failed to compute update: no differences detected between original and updated config"))
+}
+
+func TestResetGroup(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+	var mutations []Mutation
+	c = New(c.OriginalConfig(), WithObserver(func(m Mutation) { mutations = append(mutations, m) }))
+
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org2.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org3 := proto.Clone(c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"])
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org3"] = org3.(*cb.ConfigGroup)
+
+	err = c.ResetGroup([]string{"Channel", "Application", "Org1"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.ResetGroup([]string{"Channel", "Application", "Org3"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	applicationGroup := c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey]
+	gt.Expect(proto.Equal(applicationGroup.Groups["Org1"], c.OriginalConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"])).To(BeTrue())
+	gt.Expect(applicationGroup.Groups).NotTo(HaveKey("Org3"))
+	gt.Expect(applicationGroup.Groups["Org2"].Values).To(HaveKey(AnchorPeersKey))
+
+	c.Application().RemoveOrganization("Org2")
+	err = c.ResetGroup([]string{"Channel", "Application", "Org2"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(c.UpdatedConfig(), c.OriginalConfig())).To(BeTrue())
+
+	gt.Expect(mutations).To(ContainElement(Mutation{Path: "/Channel/Application/Org1", Operation: "ResetGroup"}))
+}
+
+func TestResetWithoutClone(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	base := baseOrgUpdateConfigTx(t)
+	config := base.OriginalConfig()
+	expected := proto.Clone(config).(*cb.Config)
+	c := New(config, WithoutClone())
+
+	err := c.Reset()
+	gt.Expect(err).To(MatchError("Reset is not supported without a copy of the original config, see WithoutClone"))
+	err = c.ResetGroup([]string{"Channel", "Application", "Org1"})
+	gt.Expect(err).To(MatchError("ResetGroup is not supported without a copy of the original config, see WithoutClone"))
+
+	// the config of the caller is left intact
+	gt.Expect(proto.Equal(config, expected)).To(BeTrue())
+	gt.Expect(config.ChannelGroup).NotTo(BeNil())
+}
+
+func TestResetGroupFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := baseOrgUpdateConfigTx(t)
+
+	err := c.ResetGroup([]string{"Application"})
+	gt.Expect(err).To(MatchError("path must start with Channel"))
+	err = c.ResetGroup([]string{"Channel", "Application", "Org3"})
+	gt.Expect(err).To(MatchError("group /Channel/Application/Org3 does not exist in either config"))
+
+	delete(c.UpdatedConfig().ChannelGroup.Groups, ApplicationGroupKey)
+	err = c.ResetGroup([]string{"Channel", "Application", "Org1"})
+	gt.Expect(err).To(MatchError("parent group /Channel/Application does not exist in the updated config"))
+}
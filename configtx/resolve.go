@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+)
+
+// ResolvedPolicy is a policy in which ImplicitMeta policies have been
+// replaced by the policies of the sub-groups they evaluate, down to the
+// signature policies that name the principals who must sign.
+type ResolvedPolicy struct {
+	// Path is the config path of the policy, e.g.
+	// /Channel/Orderer/OrdererOrg/Writers.
+	Path   string
+	Policy Policy
+	// Threshold is the number of sub-policies that must be satisfied for
+	// an ImplicitMeta policy. It is zero for signature policies.
+	Threshold int
+	// SubPolicies are the resolved policies of the sub-groups evaluated by
+	// an ImplicitMeta policy. Sub-groups that do not define the evaluated
+	// policy can never be satisfied and are omitted.
+	SubPolicies []ResolvedPolicy
+	// Principals are the principals named by a signature policy.
+	Principals []Principal
+}
+
+// Principal is an MSP role principal of a signature policy.
+type Principal struct {
+	MSPID string
+	// Role is one of member, admin, client, peer or orderer.
+	Role string
+}
+
+// String returns the principal as used in signature policy rules, e.g.
+// OrdererMSP.member.
+func (p Principal) String() string {
+	return p.MSPID + "." + p.Role
+}
+
+// Signers returns the distinct principals named by the signature policies
+// of the resolved policy, sorted by MSP ID and role. A signature of one of
+// these principals is required to satisfy the policy.
+func (r ResolvedPolicy) Signers() []Principal {
+	seen := map[Principal]bool{}
+	var signers []Principal
+
+	var collect func(ResolvedPolicy)
+	collect = func(r ResolvedPolicy) {
+		for _, p := range r.Principals {
+			if !seen[p] {
+				seen[p] = true
+				signers = append(signers, p)
+			}
+		}
+		for _, sub := range r.SubPolicies {
+			collect(sub)
+		}
+	}
+	collect(r)
+
+	sort.Slice(signers, func(i, j int) bool {
+		if signers[i].MSPID != signers[j].MSPID {
+			return signers[i].MSPID < signers[j].MSPID
+		}
+		return signers[i].Role < signers[j].Role
+	})
+
+	return signers
+}
+
+// BlockValidationPolicy returns the orderer's BlockValidation policy in the
+// updated config resolved into the signature policies of the orderer
+// orgs. The signers of the resolved policy are the identities whose
+// signatures make a block valid for peers of the channel.
+func (o *OrdererGroup) BlockValidationPolicy() (ResolvedPolicy, error) {
+	return resolvePolicy(o.ordererGroup, configPath(ChannelGroupKey, OrdererGroupKey), BlockValidationPolicyKey)
+}
+
+// resolvePolicy resolves the policy policyName of the group at groupPath.
+func resolvePolicy(group *cb.ConfigGroup, groupPath, policyName string) (ResolvedPolicy, error) {
+	policyPath := groupPath + "/" + policyName
+
+	configPolicy, ok := group.Policies[policyName]
+	if !ok || configPolicy.Policy == nil {
+		return ResolvedPolicy{}, fmt.Errorf("policy %s does not exist", policyPath)
+	}
+
+	return resolvePolicyAt(group, groupPath, policyPath, configPolicy.Policy)
+}
+
+// resolvePolicyAt resolves the policy at policyPath. ImplicitMeta policies
+// evaluate the sub-groups of the group at groupPath.
+func resolvePolicyAt(group *cb.ConfigGroup, groupPath, policyPath string, policy *cb.Policy) (ResolvedPolicy, error) {
+	switch cb.Policy_PolicyType(policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		imp := &cb.ImplicitMetaPolicy{}
+		err := proto.Unmarshal(policy.Value, imp)
+		if err != nil {
+			return ResolvedPolicy{}, fmt.Errorf("unmarshaling implicit meta policy %s: %w", policyPath, err)
+		}
+
+		rule, err := implicitMetaToString(imp)
+		if err != nil {
+			return ResolvedPolicy{}, fmt.Errorf("policy %s: %w", policyPath, err)
+		}
+
+		resolved := ResolvedPolicy{
+			Path:   policyPath,
+			Policy: Policy{Type: ImplicitMetaPolicyType, Rule: rule},
+		}
+
+		switch imp.Rule {
+		case cb.ImplicitMetaPolicy_ANY:
+			resolved.Threshold = 1
+		case cb.ImplicitMetaPolicy_ALL:
+			resolved.Threshold = len(group.Groups)
+		case cb.ImplicitMetaPolicy_MAJORITY:
+			resolved.Threshold = len(group.Groups)/2 + 1
+		}
+
+		var subGroupNames []string
+		for name := range group.Groups {
+			subGroupNames = append(subGroupNames, name)
+		}
+		sort.Strings(subGroupNames)
+
+		for _, name := range subGroupNames {
+			subGroup := group.Groups[name]
+			if _, ok := subGroup.Policies[imp.SubPolicy]; !ok {
+				continue
+			}
+
+			sub, err := resolvePolicy(subGroup, groupPath+"/"+name, imp.SubPolicy)
+			if err != nil {
+				return ResolvedPolicy{}, err
+			}
+			resolved.SubPolicies = append(resolved.SubPolicies, sub)
+		}
+
+		return resolved, nil
+	case cb.Policy_SIGNATURE:
+		sp := &cb.SignaturePolicyEnvelope{}
+		err := proto.Unmarshal(policy.Value, sp)
+		if err != nil {
+			return ResolvedPolicy{}, fmt.Errorf("unmarshaling signature policy %s: %w", policyPath, err)
+		}
+
+		rule, err := signatureMetaToString(sp)
+		if err != nil {
+			return ResolvedPolicy{}, fmt.Errorf("policy %s: %w", policyPath, err)
+		}
+
+		resolved := ResolvedPolicy{
+			Path:   policyPath,
+			Policy: Policy{Type: SignaturePolicyType, Rule: rule},
+		}
+
+		for _, identity := range sp.Identities {
+			if identity.PrincipalClassification != mb.MSPPrincipal_ROLE {
+				continue
+			}
+
+			role := &mb.MSPRole{}
+			err := proto.Unmarshal(identity.Principal, role)
+			if err != nil {
+				return ResolvedPolicy{}, fmt.Errorf("unmarshaling principal of policy %s: %w", policyPath, err)
+			}
+
+			resolved.Principals = append(resolved.Principals, Principal{
+				MSPID: role.MspIdentifier,
+				Role:  strings.ToLower(role.Role.String()),
+			})
+		}
+
+		return resolved, nil
+	default:
+		return ResolvedPolicy{}, fmt.Errorf("policy %s has unknown policy type: %v", policyPath, policy.Type)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestBlockValidationPolicy(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSoloOrderer(t)
+	baseOrdererConf.Organizations[0].MSP.Name = "OrdererMSP"
+	baseOrdererConf.Organizations[0].Policies = defaultOrdererOrgPoliciesFor("OrdererMSP")
+
+	org2 := baseOrdererConf.Organizations[0]
+	org2.Name = "OrdererOrg2"
+	org2.MSP.Name = "Orderer2MSP"
+	org2.Policies = defaultOrdererOrgPoliciesFor("Orderer2MSP")
+	org2.Policies[WritersPolicyKey] = Policy{
+		Type: SignaturePolicyType,
+		Rule: "OR('Orderer2MSP.orderer', 'Orderer2MSP.admin')",
+	}
+	baseOrdererConf.Organizations = append(baseOrdererConf.Organizations, org2)
+
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+
+	resolved, err := c.Orderer().BlockValidationPolicy()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resolved).To(Equal(ResolvedPolicy{
+		Path:      "/Channel/Orderer/BlockValidation",
+		Policy:    Policy{Type: ImplicitMetaPolicyType, Rule: "ANY Writers"},
+		Threshold: 1,
+		SubPolicies: []ResolvedPolicy{
+			{
+				Path:       "/Channel/Orderer/OrdererOrg/Writers",
+				Policy:     Policy{Type: SignaturePolicyType, Rule: "AND('OrdererMSP.member')"},
+				Principals: []Principal{{MSPID: "OrdererMSP", Role: "member"}},
+			},
+			{
+				Path:   "/Channel/Orderer/OrdererOrg2/Writers",
+				Policy: Policy{Type: SignaturePolicyType, Rule: "OR('Orderer2MSP.orderer', 'Orderer2MSP.admin')"},
+				Principals: []Principal{
+					{MSPID: "Orderer2MSP", Role: "orderer"},
+					{MSPID: "Orderer2MSP", Role: "admin"},
+				},
+			},
+		},
+	}))
+
+	gt.Expect(resolved.Signers()).To(Equal([]Principal{
+		{MSPID: "Orderer2MSP", Role: "admin"},
+		{MSPID: "Orderer2MSP", Role: "orderer"},
+		{MSPID: "OrdererMSP", Role: "member"},
+	}))
+	gt.Expect(resolved.Signers()[0].String()).To(Equal("Orderer2MSP.admin"))
+}
+
+func TestBlockValidationPolicyFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName     string
+		ordererGroup func(og *cb.ConfigGroup)
+		expectedErr  string
+	}{
+		{
+			testName: "when the BlockValidation policy is missing",
+			ordererGroup: func(og *cb.ConfigGroup) {
+				delete(og.Policies, BlockValidationPolicyKey)
+			},
+			expectedErr: "policy /Channel/Orderer/BlockValidation does not exist",
+		},
+		{
+			testName: "when a sub-policy has an unknown type",
+			ordererGroup: func(og *cb.ConfigGroup) {
+				og.Groups["OrdererOrg"].Policies[WritersPolicyKey].Policy.Type = 15
+			},
+			expectedErr: "policy /Channel/Orderer/OrdererOrg/Writers has unknown policy type: 15",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			baseOrdererConf, _ := baseSoloOrderer(t)
+			ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+			gt.Expect(err).NotTo(HaveOccurred())
+			tc.ordererGroup(ordererGroup)
+
+			c := New(&cb.Config{
+				ChannelGroup: &cb.ConfigGroup{
+					Groups: map[string]*cb.ConfigGroup{
+						OrdererGroupKey: ordererGroup,
+					},
+				},
+			})
+
+			_, err = c.Orderer().BlockValidationPolicy()
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// RunbookAction is the operation performed by a step of a runbook.
+type RunbookAction string
+
+const (
+	// RunbookStartNode starts the orderer node of a consenter.
+	RunbookStartNode RunbookAction = "start node"
+	// RunbookSubmitUpdate submits the config update to the orderers.
+	RunbookSubmitUpdate RunbookAction = "submit config update"
+	// RunbookJoinChannel joins the orderer node of a consenter to the
+	// channel with osnadmin.
+	RunbookJoinChannel RunbookAction = "join channel"
+	// RunbookRestartNode restarts the orderer node of a consenter.
+	RunbookRestartNode RunbookAction = "restart node"
+	// RunbookRemoveChannel removes the channel from the orderer node of a
+	// consenter with osnadmin.
+	RunbookRemoveChannel RunbookAction = "remove channel"
+	// RunbookStopNode stops the orderer node of a consenter.
+	RunbookStopNode RunbookAction = "stop node"
+)
+
+// RunbookStep is one operation of a runbook.
+type RunbookStep struct {
+	Action RunbookAction
+	// Consenter is the address of the consenter whose node the step
+	// operates on. It is not set for RunbookSubmitUpdate.
+	Consenter orderer.EtcdAddress
+	// Instructions describe how to perform the step, including the
+	// osnadmin command for RunbookJoinChannel and RunbookRemoveChannel.
+	Instructions string
+}
+
+// Runbook is the ordered operational procedure of a config update that
+// changes the etcdraft consenters of a channel.
+type Runbook struct {
+	ChannelID string
+	Steps     []RunbookStep
+}
+
+// String returns the numbered steps of the runbook, one per line.
+func (r Runbook) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "runbook for channel %s\n", r.ChannelID)
+	for i, step := range r.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step.Instructions)
+	}
+
+	return b.String()
+}
+
+// ConsenterRunbook returns the operational procedure that goes with the
+// change of the etcdraft consenters from the original to the updated
+// config, so that the update and the node operations are carried out in
+// the order required by the cluster:
+//
+// 1. The node of an added consenter is started before the update is
+// submitted, so that it can be joined to the channel once the update is
+// committed.
+//
+// 2. The config update is submitted.
+//
+// 3. The node of an added consenter is joined to the channel with the
+// config block of the update, the node of a consenter whose TLS
+// certificates were replaced is restarted with the new certificates, and
+// the channel is removed from the node of a removed consenter, which can
+// then be stopped.
+//
+// Consenters are identified by their address. As etcdraft only supports
+// changing one consenter per config update, an update that changes more is
+// rejected. An update that does not change the consenters has a runbook
+// without steps.
+func (c *ConfigTx) ConsenterRunbook(channelID string) (Runbook, error) {
+	if channelID == "" {
+		return Runbook{}, errors.New("channel ID is required")
+	}
+
+	originalConsenters, err := etcdRaftConsenters(c.original.ChannelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return Runbook{}, fmt.Errorf("retrieving original consenters: %w", err)
+	}
+
+	updatedConsenters, err := etcdRaftConsenters(c.updated.ChannelGroup.Groups[OrdererGroupKey], c.options.certificates)
+	if err != nil {
+		return Runbook{}, fmt.Errorf("retrieving updated consenters: %w", err)
+	}
+
+	var added, rotated, removed []orderer.EtcdAddress
+	for _, updated := range updatedConsenters {
+		original, ok := consenterAt(originalConsenters, updated.Address)
+		switch {
+		case !ok:
+			added = append(added, updated.Address)
+		case !sameCert(original.ClientTLSCert, updated.ClientTLSCert) || !sameCert(original.ServerTLSCert, updated.ServerTLSCert):
+			rotated = append(rotated, updated.Address)
+		}
+	}
+	for _, original := range originalConsenters {
+		if _, ok := consenterAt(updatedConsenters, original.Address); !ok {
+			removed = append(removed, original.Address)
+		}
+	}
+
+	runbook := Runbook{ChannelID: channelID}
+
+	changes := len(added) + len(rotated) + len(removed)
+	if changes == 0 {
+		return runbook, nil
+	}
+	if changes > 1 {
+		return Runbook{}, fmt.Errorf("update changes %d consenters, but etcdraft only supports changing one consenter per config update", changes)
+	}
+
+	for _, address := range added {
+		runbook.Steps = append(runbook.Steps, RunbookStep{
+			Action:       RunbookStartNode,
+			Consenter:    address,
+			Instructions: fmt.Sprintf("start orderer %s with the TLS certificates of the new consenter and without joining it to channel %s", consenterAddress(address), channelID),
+		})
+	}
+
+	runbook.Steps = append(runbook.Steps, RunbookStep{
+		Action:       RunbookSubmitUpdate,
+		Instructions: fmt.Sprintf("submit the signed config update to channel %s and wait for the config block that commits it", channelID),
+	})
+
+	for _, address := range added {
+		runbook.Steps = append(runbook.Steps, RunbookStep{
+			Action:    RunbookJoinChannel,
+			Consenter: address,
+			Instructions: fmt.Sprintf("join orderer %s to channel %s with the committed config block and wait until it has caught up with the cluster: "+
+				"osnadmin channel join --channelID %s --config-block <config block> -o <admin address of %s>", consenterAddress(address), channelID, channelID, consenterAddress(address)),
+		})
+	}
+
+	for _, address := range rotated {
+		runbook.Steps = append(runbook.Steps, RunbookStep{
+			Action:       RunbookRestartNode,
+			Consenter:    address,
+			Instructions: fmt.Sprintf("restart orderer %s with its new TLS certificates and wait until it has caught up with the cluster", consenterAddress(address)),
+		})
+	}
+
+	for _, address := range removed {
+		runbook.Steps = append(runbook.Steps,
+			RunbookStep{
+				Action:    RunbookRemoveChannel,
+				Consenter: address,
+				Instructions: fmt.Sprintf("remove channel %s from orderer %s: osnadmin channel remove --channelID %s -o <admin address of %s>",
+					channelID, consenterAddress(address), channelID, consenterAddress(address)),
+			},
+			RunbookStep{
+				Action:       RunbookStopNode,
+				Consenter:    address,
+				Instructions: fmt.Sprintf("stop orderer %s if it is not a consenter of other channels", consenterAddress(address)),
+			},
+		)
+	}
+
+	return runbook, nil
+}
+
+// consenterAt returns the consenter with the address, if any.
+func consenterAt(consenters []orderer.Consenter, address orderer.EtcdAddress) (orderer.Consenter, bool) {
+	for _, consenter := range consenters {
+		if consenter.Address == address {
+			return consenter, true
+		}
+	}
+
+	return orderer.Consenter{}, false
+}
+
+// sameCert reports whether the certificates are equal. Two nil
+// certificates are equal.
+func sameCert(cert1, cert2 *x509.Certificate) bool {
+	if cert1 == nil || cert2 == nil {
+		return cert1 == cert2
+	}
+
+	return cert1.Equal(cert2)
+}
+
+// consenterAddress returns the host:port form of a consenter address.
+func consenterAddress(address orderer.EtcdAddress) string {
+	return fmt.Sprintf("%s:%d", address.Host, address.Port)
+}
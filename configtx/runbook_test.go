@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConsenterRunbook(t *testing.T) {
+	t.Parallel()
+
+	node1 := orderer.EtcdAddress{Host: "node-1.example.com", Port: 7050}
+	node4 := orderer.EtcdAddress{Host: "node-4.example.com", Port: 7050}
+
+	tests := []struct {
+		name            string
+		change          func(c *ConfigTx, newConsenter orderer.Consenter) error
+		expectedActions []RunbookAction
+		expectedNodes   []orderer.EtcdAddress
+	}{
+		{
+			name: "add consenter",
+			change: func(c *ConfigTx, newConsenter orderer.Consenter) error {
+				return c.Orderer().AddConsenter(newConsenter)
+			},
+			expectedActions: []RunbookAction{RunbookStartNode, RunbookSubmitUpdate, RunbookJoinChannel},
+			expectedNodes:   []orderer.EtcdAddress{node4, {}, node4},
+		},
+		{
+			name: "remove consenter",
+			change: func(c *ConfigTx, newConsenter orderer.Consenter) error {
+				ordererConf, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				return c.Orderer().RemoveConsenter(ordererConf.EtcdRaft.Consenters[0])
+			},
+			expectedActions: []RunbookAction{RunbookSubmitUpdate, RunbookRemoveChannel, RunbookStopNode},
+			expectedNodes:   []orderer.EtcdAddress{{}, node1, node1},
+		},
+		{
+			name: "rotate consenter certificates",
+			change: func(c *ConfigTx, newConsenter orderer.Consenter) error {
+				ordererConf, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				ordererConf.EtcdRaft.Consenters[0].ClientTLSCert = newConsenter.ClientTLSCert
+				ordererConf.EtcdRaft.Consenters[0].ServerTLSCert = newConsenter.ServerTLSCert
+				return c.Orderer().SetConfiguration(ordererConf)
+			},
+			expectedActions: []RunbookAction{RunbookSubmitUpdate, RunbookRestartNode},
+			expectedNodes:   []orderer.EtcdAddress{{}, node1},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			c, _, newConsenter := basePlanReplaceOrdererOrg(t)
+			err := tc.change(&c, newConsenter)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			runbook, err := c.ConsenterRunbook("testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(runbook.ChannelID).To(Equal("testchannel"))
+
+			var actions []RunbookAction
+			var nodes []orderer.EtcdAddress
+			for _, step := range runbook.Steps {
+				actions = append(actions, step.Action)
+				nodes = append(nodes, step.Consenter)
+			}
+			gt.Expect(actions).To(Equal(tc.expectedActions))
+			gt.Expect(nodes).To(Equal(tc.expectedNodes))
+		})
+	}
+}
+
+func TestConsenterRunbookString(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c, _, newConsenter := basePlanReplaceOrdererOrg(t)
+	err := c.Orderer().AddConsenter(newConsenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	runbook, err := c.ConsenterRunbook("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(runbook.String()).To(Equal(`runbook for channel testchannel
+1. start orderer node-4.example.com:7050 with the TLS certificates of the new consenter and without joining it to channel testchannel
+2. submit the signed config update to channel testchannel and wait for the config block that commits it
+3. join orderer node-4.example.com:7050 to channel testchannel with the committed config block and wait until it has caught up with the cluster: osnadmin channel join --channelID testchannel --config-block <config block> -o <admin address of node-4.example.com:7050>
+`))
+}
+
+func TestConsenterRunbookWithoutConsenterChanges(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c, _, _ := basePlanReplaceOrdererOrg(t)
+	err := c.Orderer().BatchSize().SetMaxMessageCount(20)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	runbook, err := c.ConsenterRunbook("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(runbook.Steps).To(BeEmpty())
+}
+
+func TestConsenterRunbookFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c, _, newConsenter := basePlanReplaceOrdererOrg(t)
+
+	_, err := c.ConsenterRunbook("")
+	gt.Expect(err).To(MatchError("channel ID is required"))
+
+	ordererConf, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().AddConsenter(newConsenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().RemoveConsenter(ordererConf.EtcdRaft.Consenters[0])
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.ConsenterRunbook("testchannel")
+	gt.Expect(err).To(MatchError("update changes 2 consenters, but etcdraft only supports changing one consenter per config update"))
+}
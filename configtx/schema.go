@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// schema describes a versioned JSON format written by this library, such
+// as org bundles and proposals. Documents written by older versions of the
+// library are upgraded to the current version of the format by the
+// migrations of the schema before they are decoded, so that stored
+// documents remain loadable when the format changes.
+//
+// To change a format, increment its version, update its Go encoding and
+// register a migration to the new version that rewrites documents of the
+// previous version.
+type schema struct {
+	// name is the name of the format used in errors, e.g. "org bundle".
+	name string
+	// version is the current version of the format.
+	version int
+	// migrations upgrade documents to the version following the key, e.g.
+	// migrations[1] upgrades a version 1 document to version 2. Versions
+	// below the oldest migration, other than the current version, are not
+	// supported.
+	migrations map[int]schemaMigration
+}
+
+// schemaMigration rewrites a decoded JSON document of a version of a
+// format in place to the next version.
+type schemaMigration func(doc map[string]interface{}) error
+
+var (
+	orgBundleSchema = schema{
+		name:    "org bundle",
+		version: OrgBundleVersion,
+	}
+
+	proposalSchema = schema{
+		name:    "proposal",
+		version: ProposalVersion,
+	}
+
+	validationReportSchema = schema{
+		name:    "validation report",
+		version: ValidationReportVersion,
+	}
+)
+
+// upgrade returns the document, read from its "version" field, upgraded to
+// the current version of the format. A document of the current version is
+// returned unchanged.
+func (s schema) upgrade(raw []byte) ([]byte, error) {
+	doc := map[string]interface{}{}
+	err := json.Unmarshal(raw, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", s.name, err)
+	}
+
+	version := 0
+	if v, ok := doc["version"].(float64); ok && v == float64(int(v)) {
+		version = int(v)
+	}
+
+	if version == s.version {
+		return raw, nil
+	}
+
+	if version > s.version {
+		return nil, fmt.Errorf("unsupported %s version %d", s.name, version)
+	}
+
+	for ; version < s.version; version++ {
+		migrate, ok := s.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("unsupported %s version %d", s.name, version)
+		}
+
+		err := migrate(doc)
+		if err != nil {
+			return nil, fmt.Errorf("upgrading %s from version %d to %d: %w", s.name, version, version+1, err)
+		}
+		doc["version"] = version + 1
+	}
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding upgraded %s: %w", s.name, err)
+	}
+
+	return upgraded, nil
+}
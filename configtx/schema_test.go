@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSchemaUpgrade(t *testing.T) {
+	t.Parallel()
+
+	s := schema{
+		name:    "test document",
+		version: 3,
+		migrations: map[int]schemaMigration{
+			1: func(doc map[string]interface{}) error {
+				doc["anchorPeers"] = doc["anchor_peers"]
+				delete(doc, "anchor_peers")
+				return nil
+			},
+			2: func(doc map[string]interface{}) error {
+				if _, ok := doc["name"]; !ok {
+					return errors.New("document does not contain a name")
+				}
+				doc["mspID"] = doc["name"]
+				return nil
+			},
+		},
+	}
+
+	tests := []struct {
+		testName    string
+		doc         string
+		expectedDoc string
+		expectedErr string
+	}{
+		{
+			testName:    "when the document is of the current version",
+			doc:         `{"version": 3, "name": "Org1"}`,
+			expectedDoc: `{"version": 3, "name": "Org1"}`,
+		},
+		{
+			testName:    "when the document is of a previous version",
+			doc:         `{"version": 2, "name": "Org1"}`,
+			expectedDoc: `{"version": 3, "name": "Org1", "mspID": "Org1"}`,
+		},
+		{
+			testName:    "when the document is of the oldest supported version",
+			doc:         `{"version": 1, "name": "Org1", "anchor_peers": ["peer0"]}`,
+			expectedDoc: `{"version": 3, "name": "Org1", "mspID": "Org1", "anchorPeers": ["peer0"]}`,
+		},
+		{
+			testName:    "when the document is of a newer version",
+			doc:         `{"version": 4}`,
+			expectedErr: "unsupported test document version 4",
+		},
+		{
+			testName:    "when the document does not have a version",
+			doc:         `{"name": "Org1"}`,
+			expectedErr: "unsupported test document version 0",
+		},
+		{
+			testName:    "when a migration fails",
+			doc:         `{"version": 2}`,
+			expectedErr: "upgrading test document from version 2 to 3: document does not contain a name",
+		},
+		{
+			testName:    "when the document is not JSON",
+			doc:         `version: 3`,
+			expectedErr: "decoding test document: invalid character 'v' looking for beginning of value",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			upgraded, err := s.upgrade([]byte(tc.doc))
+			if tc.expectedErr != "" {
+				gt.Expect(err).To(MatchError(tc.expectedErr))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(upgraded).To(MatchJSON(tc.expectedDoc))
+		})
+	}
+}
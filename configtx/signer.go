@@ -14,6 +14,7 @@ import (
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -29,6 +30,52 @@ type SigningIdentity struct {
 	Certificate *x509.Certificate
 	PrivateKey  crypto.PrivateKey
 	MSPID       string
+	// Rand is the source of randomness for signature header nonces and
+	// signatures. If nil, crypto/rand is used.
+	Rand io.Reader
+	// Serializer serializes the identity as the creator of signature
+	// headers. If nil, the identity is serialized by an
+	// X509IdentitySerializer for its MSP ID and certificate.
+	Serializer IdentitySerializer
+}
+
+// IdentitySerializer serializes a signing identity as the creator of the
+// signature headers of config signatures and envelopes. It allows MSP
+// providers other than the X.509 MSP, e.g. Idemix, to supply their own
+// serialization of identities.
+type IdentitySerializer interface {
+	// Serialize returns the marshaled msp.SerializedIdentity of the
+	// identity.
+	Serialize() ([]byte, error)
+}
+
+// X509IdentitySerializer serializes an identity of the X.509 MSP as its
+// MSP ID and PEM encoded certificate.
+type X509IdentitySerializer struct {
+	MSPID       string
+	Certificate *x509.Certificate
+}
+
+// Serialize returns the marshaled msp.SerializedIdentity of the identity.
+func (x X509IdentitySerializer) Serialize() ([]byte, error) {
+	if x.Certificate == nil {
+		return nil, errors.New("certificate is required")
+	}
+
+	pemBytes := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: x.Certificate.Raw,
+	})
+
+	idBytes, err := proto.Marshal(&mb.SerializedIdentity{
+		Mspid:   x.MSPID,
+		IdBytes: pemBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling serialized identity: %w", err)
+	}
+
+	return idBytes, nil
 }
 
 type ecdsaSignature struct {
@@ -93,12 +140,12 @@ func toLowS(key ecdsa.PublicKey, sig ecdsaSignature) ecdsaSignature {
 func (s *SigningIdentity) CreateConfigSignature(marshaledUpdate []byte) (*cb.ConfigSignature, error) {
 	signatureHeader, err := s.signatureHeader()
 	if err != nil {
-		return nil, fmt.Errorf("creating signature header: %v", err)
+		return nil, fmt.Errorf("creating signature header: %w", err)
 	}
 
 	header, err := proto.Marshal(signatureHeader)
 	if err != nil {
-		return nil, fmt.Errorf("marshaling signature header: %v", err)
+		return nil, fmt.Errorf("marshaling signature header: %w", err)
 	}
 
 	configSignature := &cb.ConfigSignature{
@@ -106,12 +153,12 @@ func (s *SigningIdentity) CreateConfigSignature(marshaledUpdate []byte) (*cb.Con
 	}
 
 	configSignature.Signature, err = s.Sign(
-		rand.Reader,
+		s.random(),
 		concatenateBytes(configSignature.SignatureHeader, marshaledUpdate),
 		nil,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("signing config update: %v", err)
+		return nil, fmt.Errorf("signing config update: %w", err)
 	}
 
 	return configSignature, nil
@@ -121,29 +168,29 @@ func (s *SigningIdentity) CreateConfigSignature(marshaledUpdate []byte) (*cb.Con
 func (s *SigningIdentity) SignEnvelope(e *cb.Envelope) error {
 	signatureHeader, err := s.signatureHeader()
 	if err != nil {
-		return fmt.Errorf("creating signature header: %v", err)
+		return fmt.Errorf("creating signature header: %w", err)
 	}
 
 	sHeader, err := proto.Marshal(signatureHeader)
 	if err != nil {
-		return fmt.Errorf("marshaling signature header: %v", err)
+		return fmt.Errorf("marshaling signature header: %w", err)
 	}
 
 	payload := &cb.Payload{}
 	err = proto.Unmarshal(e.Payload, payload)
 	if err != nil {
-		return fmt.Errorf("unmarshaling envelope payload: %v", err)
+		return fmt.Errorf("unmarshaling envelope payload: %w", err)
 	}
 	payload.Header.SignatureHeader = sHeader
 
 	payloadBytes, err := proto.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshaling payload: %v", err)
+		return fmt.Errorf("marshaling payload: %w", err)
 	}
 
-	sig, err := s.Sign(rand.Reader, payloadBytes, nil)
+	sig, err := s.Sign(s.random(), payloadBytes, nil)
 	if err != nil {
-		return fmt.Errorf("signing envelope payload: %v", err)
+		return fmt.Errorf("signing envelope payload: %w", err)
 	}
 
 	e.Payload = payloadBytes
@@ -153,20 +200,17 @@ func (s *SigningIdentity) SignEnvelope(e *cb.Envelope) error {
 }
 
 func (s *SigningIdentity) signatureHeader() (*cb.SignatureHeader, error) {
-	pemBytes := pem.EncodeToMemory(&pem.Block{
-		Type:  "CERTIFICATE",
-		Bytes: s.Certificate.Raw,
-	})
+	serializer := s.Serializer
+	if serializer == nil {
+		serializer = X509IdentitySerializer{MSPID: s.MSPID, Certificate: s.Certificate}
+	}
 
-	idBytes, err := proto.Marshal(&mb.SerializedIdentity{
-		Mspid:   s.MSPID,
-		IdBytes: pemBytes,
-	})
+	idBytes, err := serializer.Serialize()
 	if err != nil {
-		return nil, fmt.Errorf("marshaling serialized identity: %v", err)
+		return nil, fmt.Errorf("serializing identity: %w", err)
 	}
 
-	nonce, err := newNonce()
+	nonce, err := newNonce(s.random())
 	if err != nil {
 		return nil, err
 	}
@@ -177,13 +221,22 @@ func (s *SigningIdentity) signatureHeader() (*cb.SignatureHeader, error) {
 	}, nil
 }
 
-// newNonce generates a 24-byte nonce using the crypto/rand package.
-func newNonce() ([]byte, error) {
+// random returns the source of randomness of the signing identity.
+func (s *SigningIdentity) random() io.Reader {
+	if s.Rand == nil {
+		return rand.Reader
+	}
+
+	return s.Rand
+}
+
+// newNonce generates a 24-byte nonce using the given source of randomness.
+func newNonce(random io.Reader) ([]byte, error) {
 	nonce := make([]byte, 24)
 
-	_, err := rand.Read(nonce)
+	_, err := io.ReadFull(random, nonce)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get random bytes: %v", err)
+		return nil, fmt.Errorf("failed to get random bytes: %w", err)
 	}
 
 	return nonce, nil
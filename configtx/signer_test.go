@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -16,6 +17,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"errors"
 	"io"
 	"math/big"
 	"testing"
@@ -23,6 +25,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
 	. "github.com/onsi/gomega"
 )
 
@@ -166,13 +169,89 @@ func TestSignEnvelope(t *testing.T) {
 	gt.Expect(expectedSignatures.Signature).To(Equal(configSignature.Signature))
 }
 
+func TestSigningIdentityRand(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	cert, privateKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	nonce := bytes.Repeat([]byte{7}, 24)
+	signingIdentity := SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privateKey,
+		MSPID:       "test-msp",
+		Rand:        io.MultiReader(bytes.NewReader(nonce), rand.Reader),
+	}
+
+	configSignature, err := signingIdentity.CreateConfigSignature([]byte("config-update"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signatureHeader := &cb.SignatureHeader{}
+	err = proto.Unmarshal(configSignature.SignatureHeader, signatureHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signatureHeader.Nonce).To(Equal(nonce))
+
+	signingIdentity.Rand = bytes.NewReader(nil)
+	_, err = signingIdentity.CreateConfigSignature([]byte("config-update"))
+	gt.Expect(err).To(MatchError("creating signature header: failed to get random bytes: EOF"))
+}
+
+type identitySerializerFunc func() ([]byte, error)
+
+func (f identitySerializerFunc) Serialize() ([]byte, error) {
+	return f()
+}
+
+func TestSigningIdentitySerializer(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	cert, privateKey := generateCACertAndPrivateKey(t, "org1.example.com")
+
+	creator, err := X509IdentitySerializer{MSPID: "test-msp", Certificate: cert}.Serialize()
+	gt.Expect(err).NotTo(HaveOccurred())
+	serializedIdentity := &mb.SerializedIdentity{}
+	err = proto.Unmarshal(creator, serializedIdentity)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(serializedIdentity.Mspid).To(Equal("test-msp"))
+	gt.Expect(serializedIdentity.IdBytes).To(Equal(pemEncodeX509Certificate(cert)))
+
+	signingIdentity := SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privateKey,
+		MSPID:       "test-msp",
+	}
+	sh, err := signingIdentity.signatureHeader()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(sh.Creator).To(Equal(creator))
+
+	idemixCreator := protoMarshal(t, &mb.SerializedIdentity{Mspid: "idemix-msp", IdBytes: []byte("idemix identity")})
+	signingIdentity.Serializer = identitySerializerFunc(func() ([]byte, error) {
+		return idemixCreator, nil
+	})
+	configSignature, err := signingIdentity.CreateConfigSignature([]byte("config-update"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	signatureHeader := &cb.SignatureHeader{}
+	err = proto.Unmarshal(configSignature.SignatureHeader, signatureHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signatureHeader.Creator).To(Equal(idemixCreator))
+
+	signingIdentity.Serializer = identitySerializerFunc(func() ([]byte, error) {
+		return nil, errors.New("no credential")
+	})
+	_, err = signingIdentity.CreateConfigSignature([]byte("config-update"))
+	gt.Expect(err).To(MatchError("creating signature header: serializing identity: no credential"))
+
+	_, err = X509IdentitySerializer{MSPID: "test-msp"}.Serialize()
+	gt.Expect(err).To(MatchError("certificate is required"))
+}
+
 func TestSignEnvelopeWithAnchorPeers(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
 
 	baseApplicationConf, _ := baseApplication(t)
 
-	applicationGroup, err := newApplicationGroup(baseApplicationConf)
+	applicationGroup, err := NewApplicationGroup(baseApplicationConf)
 	gt.Expect(err).NotTo(HaveOccurred())
 
 	config := &cb.Config{
@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// SizeEntry is the encoded size of a config element.
+type SizeEntry struct {
+	// Path is the config path of the element, e.g.
+	// /Channel/Application/Org1/MSP.
+	Path    string
+	Element ElementType
+	// Bytes is the size of the protobuf encoding of the element. The size
+	// of a group includes the sizes of its values, policies and
+	// sub-groups.
+	Bytes int
+}
+
+// SizeReport attributes the size of a config to its elements.
+type SizeReport struct {
+	// Total is the size of the marshaled config.
+	Total int
+	// Elements are the groups, values and policies of the config in the
+	// order of the config tree, each group followed by its values,
+	// policies and sub-groups in lexical order.
+	Elements []SizeEntry
+}
+
+// SizeReport reports the encoded size of the updated config and of each of
+// its elements, e.g. to find the MSPs and values that contribute the most
+// to a config that approaches the maximum block size of the orderer.
+func (c *ConfigTx) SizeReport() SizeReport {
+	report := SizeReport{Total: proto.Size(c.updated)}
+	report.addGroup(configPath(ChannelGroupKey), c.updated.ChannelGroup)
+
+	return report
+}
+
+// addGroup adds the sizes of the group at groupPath and its members.
+func (r *SizeReport) addGroup(groupPath string, group *cb.ConfigGroup) {
+	r.Elements = append(r.Elements, SizeEntry{Path: groupPath, Element: ElementGroup, Bytes: proto.Size(group)})
+
+	for _, name := range sortedKeys(group.Values) {
+		r.Elements = append(r.Elements, SizeEntry{Path: groupPath + "/" + name, Element: ElementValue, Bytes: proto.Size(group.Values[name])})
+	}
+
+	for _, name := range sortedKeys(group.Policies) {
+		r.Elements = append(r.Elements, SizeEntry{Path: groupPath + "/" + name, Element: ElementPolicy, Bytes: proto.Size(group.Policies[name])})
+	}
+
+	for _, name := range sortedKeys(group.Groups) {
+		r.addGroup(groupPath+"/"+name, group.Groups[name])
+	}
+}
+
+// Largest returns the n largest values and policies, largest first. Groups
+// are left out as their sizes include those of their members. Elements of
+// the same size are ordered by path.
+func (r SizeReport) Largest(n int) []SizeEntry {
+	var entries []SizeEntry
+	for _, entry := range r.Elements {
+		if entry.Element != ElementGroup {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Bytes != entries[j].Bytes {
+			return entries[i].Bytes > entries[j].Bytes
+		}
+		return entries[i].Path < entries[j].Path
+	})
+
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+
+	return entries
+}
+
+// String returns the total size followed by the size of each element and
+// its share of the total, one per line.
+func (r SizeReport) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "config size: %d bytes\n", r.Total)
+	for _, entry := range r.Elements {
+		share := 0.0
+		if r.Total > 0 {
+			share = float64(entry.Bytes) * 100 / float64(r.Total)
+		}
+		fmt.Fprintf(&b, "%s %s: %d bytes (%.1f%%)\n", entry.Element, entry.Path, entry.Bytes, share)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestSizeReport(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	report := c.SizeReport()
+	gt.Expect(report.Total).To(Equal(proto.Size(c.UpdatedConfig())))
+
+	sizes := map[string]int{}
+	for _, entry := range report.Elements {
+		sizes[entry.Path] = entry.Bytes
+	}
+	gt.Expect(report.Elements[0]).To(Equal(SizeEntry{Path: "/Channel", Element: ElementGroup, Bytes: proto.Size(channelGroup)}))
+	gt.Expect(sizes).To(HaveKeyWithValue("/Channel/Application/Org1/MSP", proto.Size(channelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey])))
+	gt.Expect(sizes).To(HaveKeyWithValue("/Channel/Application/Admins", proto.Size(channelGroup.Groups[ApplicationGroupKey].Policies[AdminsPolicyKey])))
+
+	// adding certificates to an MSP increases the size attributed to it
+	cert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+	err = c.Application().Organization("Org1").MSP().AddRootCert(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	grown := c.SizeReport()
+	gt.Expect(grown.Total).To(BeNumerically(">", report.Total+len(cert.Raw)))
+	largest := grown.Largest(1)
+	gt.Expect(largest).To(Equal([]SizeEntry{{
+		Path:    "/Channel/Application/Org1/MSP",
+		Element: ElementValue,
+		Bytes:   proto.Size(c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey]),
+	}}))
+
+	for _, entry := range grown.Largest(len(grown.Elements)) {
+		gt.Expect(entry.Element).NotTo(Equal(ElementGroup))
+	}
+}
+
+func TestSizeReportString(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	report := SizeReport{
+		Total: 200,
+		Elements: []SizeEntry{
+			{Path: "/Channel", Element: ElementGroup, Bytes: 198},
+			{Path: "/Channel/Consortium", Element: ElementValue, Bytes: 50},
+		},
+	}
+	gt.Expect(report.String()).To(Equal(`config size: 200 bytes
+group /Channel: 198 bytes (99.0%)
+value /Channel/Consortium: 50 bytes (25.0%)
+`))
+}
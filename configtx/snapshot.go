@@ -0,0 +1,140 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ElementVersion is the version and mod policy of a config element. The
+// version of an element is incremented each time a config update modifies
+// it.
+type ElementVersion struct {
+	Version   uint64
+	ModPolicy string
+}
+
+// GroupVersions are the versions of a config group and of its values,
+// policies and sub-groups, keyed by name.
+type GroupVersions struct {
+	// Path is the config path of the group, e.g. /Channel/Application.
+	Path string
+	ElementVersion
+	Values   map[string]ElementVersion
+	Policies map[string]ElementVersion
+	Groups   map[string]GroupVersions
+}
+
+// VersionsAbove returns the sorted config paths of the group and of the
+// elements in its subtree whose version is greater than threshold. A high
+// version indicates an element that has been modified by many updates.
+func (g GroupVersions) VersionsAbove(threshold uint64) []string {
+	var paths []string
+
+	var collect func(GroupVersions)
+	collect = func(g GroupVersions) {
+		if g.Version > threshold {
+			paths = append(paths, g.Path)
+		}
+		for name, v := range g.Values {
+			if v.Version > threshold {
+				paths = append(paths, g.Path+"/"+name)
+			}
+		}
+		for name, p := range g.Policies {
+			if p.Version > threshold {
+				paths = append(paths, g.Path+"/"+name)
+			}
+		}
+		for _, sub := range g.Groups {
+			collect(sub)
+		}
+	}
+	collect(g)
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+// ApplicationSnapshot is the application configuration together with the
+// versions of the config elements it was decoded from.
+type ApplicationSnapshot struct {
+	Application Application
+	Versions    GroupVersions
+}
+
+// OrdererSnapshot is the orderer configuration together with the versions
+// of the config elements it was decoded from.
+type OrdererSnapshot struct {
+	Orderer  Orderer
+	Versions GroupVersions
+}
+
+// Snapshot returns the application configuration of the updated config
+// along with the version of each of its config elements. Versions of
+// elements modified since the ConfigTx was created are those of the
+// original config until the update is computed.
+func (a *ApplicationGroup) Snapshot() (ApplicationSnapshot, error) {
+	application, err := a.Configuration()
+	if err != nil {
+		return ApplicationSnapshot{}, fmt.Errorf("retrieving application configuration: %w", err)
+	}
+
+	return ApplicationSnapshot{
+		Application: application,
+		Versions:    groupVersions(a.path(), a.applicationGroup),
+	}, nil
+}
+
+// Snapshot returns the orderer configuration of the updated config along
+// with the version of each of its config elements. Versions of elements
+// modified since the ConfigTx was created are those of the original
+// config until the update is computed.
+func (o *OrdererGroup) Snapshot() (OrdererSnapshot, error) {
+	ordererConfig, err := o.Configuration()
+	if err != nil {
+		return OrdererSnapshot{}, fmt.Errorf("retrieving orderer configuration: %w", err)
+	}
+
+	return OrdererSnapshot{
+		Orderer:  ordererConfig,
+		Versions: groupVersions(o.path(), o.ordererGroup),
+	}, nil
+}
+
+// groupVersions returns the versions of the group at path and its
+// subtree.
+func groupVersions(path string, group *cb.ConfigGroup) GroupVersions {
+	versions := GroupVersions{
+		Path: path,
+		ElementVersion: ElementVersion{
+			Version:   group.Version,
+			ModPolicy: group.ModPolicy,
+		},
+		Values:   map[string]ElementVersion{},
+		Policies: map[string]ElementVersion{},
+		Groups:   map[string]GroupVersions{},
+	}
+
+	for name, value := range group.Values {
+		versions.Values[name] = ElementVersion{Version: value.Version, ModPolicy: value.ModPolicy}
+	}
+
+	for name, policy := range group.Policies {
+		versions.Policies[name] = ElementVersion{Version: policy.Version, ModPolicy: policy.ModPolicy}
+	}
+
+	for name, subGroup := range group.Groups {
+		versions.Groups[name] = groupVersions(path+"/"+name, subGroup)
+	}
+
+	return versions
+}
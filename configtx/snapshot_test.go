@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestApplicationSnapshot(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	appGroup.Version = 3
+	appGroup.Values[ACLsKey].Version = 2
+	appGroup.Groups["Org1"].Version = 1
+	appGroup.Groups["Org1"].Values[MSPKey].Version = 14
+	appGroup.Groups["Org1"].Policies[AdminsPolicyKey].Version = 11
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+			},
+		},
+	})
+
+	snapshot, err := c.Application().Snapshot()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedApplication, err := c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(snapshot.Application.Organizations).To(ConsistOf(expectedApplication.Organizations))
+	gt.Expect(snapshot.Application.Capabilities).To(Equal(expectedApplication.Capabilities))
+	gt.Expect(snapshot.Application.Policies).To(Equal(expectedApplication.Policies))
+	gt.Expect(snapshot.Application.ACLs).To(Equal(expectedApplication.ACLs))
+
+	versions := snapshot.Versions
+	gt.Expect(versions.Path).To(Equal("/Channel/Application"))
+	gt.Expect(versions.ElementVersion).To(Equal(ElementVersion{Version: 3, ModPolicy: AdminsPolicyKey}))
+	gt.Expect(versions.Values[ACLsKey]).To(Equal(ElementVersion{Version: 2, ModPolicy: AdminsPolicyKey}))
+	gt.Expect(versions.Groups).To(HaveLen(2))
+
+	org1 := versions.Groups["Org1"]
+	gt.Expect(org1.Path).To(Equal("/Channel/Application/Org1"))
+	gt.Expect(org1.Version).To(Equal(uint64(1)))
+	gt.Expect(org1.Values[MSPKey].Version).To(Equal(uint64(14)))
+
+	gt.Expect(versions.VersionsAbove(10)).To(Equal([]string{
+		"/Channel/Application/Org1/Admins",
+		"/Channel/Application/Org1/MSP",
+	}))
+	gt.Expect(versions.VersionsAbove(2)).To(Equal([]string{
+		"/Channel/Application",
+		"/Channel/Application/Org1/Admins",
+		"/Channel/Application/Org1/MSP",
+	}))
+}
+
+func TestOrdererSnapshot(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseOrdererOfType(t, orderer.ConsensusTypeEtcdRaft)
+	ordererGroup, err := NewOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererGroup.Values[orderer.BatchSizeKey].Version = 27
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	})
+
+	snapshot, err := c.Orderer().Snapshot()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedOrderer, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(snapshot.Orderer).To(Equal(expectedOrderer))
+
+	gt.Expect(snapshot.Versions.Path).To(Equal("/Channel/Orderer"))
+	gt.Expect(snapshot.Versions.Values[orderer.BatchSizeKey]).To(Equal(ElementVersion{Version: 27, ModPolicy: AdminsPolicyKey}))
+	gt.Expect(snapshot.Versions.VersionsAbove(0)).To(Equal([]string{"/Channel/Orderer/BatchSize"}))
+}
+
+func TestSnapshotFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	application, _ := baseApplication(t)
+	appGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+	appGroup.Values[ACLsKey].Value = []byte("invalid")
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: appGroup,
+				OrdererGroupKey:     newConfigGroup(),
+			},
+		},
+	})
+
+	_, err = c.Application().Snapshot()
+	gt.Expect(err).To(MatchError(ContainSubstring("retrieving application configuration: ")))
+
+	_, err = c.Orderer().Snapshot()
+	gt.Expect(err).To(MatchError(ContainSubstring("retrieving orderer configuration: ")))
+}
@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// SplitUpdate computes the update from the original to the updated config
+// like ComputeMarshaledUpdate. If the marshaled update is larger than
+// maxBytes, e.g. because the MSPs of many organizations were refreshed at
+// once, it is split into a series of smaller updates that each change a
+// subset of the organizations, followed by an update with the remaining
+// changes outside of the organizations. Each update is computed against the
+// config that results from committing the updates before it, so they must
+// be signed and submitted in order, each after the previous one has been
+// committed. The envelope of an update adds headers and signatures to its
+// size, so maxBytes should leave headroom below the orderer's limit.
+func (c *ConfigTx) SplitUpdate(channelID string, maxBytes int) ([][]byte, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if maxBytes <= 0 {
+		return nil, errors.New("maximum update size must be positive")
+	}
+
+	err := c.transform()
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform updated config: %w", err)
+	}
+
+	err = c.options.checkPathGuard(c.original, c.updated)
+	if err != nil {
+		return nil, fmt.Errorf("config update not permitted: %w", err)
+	}
+
+	s := &updateSplitter{
+		channelID: channelID,
+		state:     proto.Clone(c.original).(*cb.Config),
+		target:    c.updated,
+	}
+
+	update, err := s.compute(proto.Clone(c.updated).(*cb.Config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute update: %w", err)
+	}
+	if len(update.marshaled) <= maxBytes {
+		return [][]byte{update.marshaled}, nil
+	}
+
+	var batch []string
+	for _, orgPath := range s.changedOrgPaths() {
+		candidate, err := s.compute(s.withOrgs(append(batch, orgPath)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute update of %s: %w", orgPath, err)
+		}
+		if len(candidate.marshaled) <= maxBytes {
+			batch = append(batch, orgPath)
+			continue
+		}
+		if len(batch) == 0 {
+			return nil, fmt.Errorf("update of %s alone is %d bytes, exceeding the limit of %d bytes", orgPath, len(candidate.marshaled), maxBytes)
+		}
+
+		err = s.commit(s.withOrgs(batch))
+		if err != nil {
+			return nil, err
+		}
+
+		candidate, err = s.compute(s.withOrgs([]string{orgPath}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute update of %s: %w", orgPath, err)
+		}
+		if len(candidate.marshaled) > maxBytes {
+			return nil, fmt.Errorf("update of %s alone is %d bytes, exceeding the limit of %d bytes", orgPath, len(candidate.marshaled), maxBytes)
+		}
+		batch = []string{orgPath}
+	}
+
+	if len(batch) > 0 {
+		err = s.commit(s.withOrgs(batch))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the remaining changes are outside of the organizations
+	remaining := proto.Clone(c.updated).(*cb.Config)
+	_, _, updated := computeGroupUpdate(proto.Clone(s.state.ChannelGroup).(*cb.ConfigGroup), proto.Clone(remaining.ChannelGroup).(*cb.ConfigGroup), false)
+	if updated {
+		update, err := s.compute(remaining)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute remaining update: %w", err)
+		}
+		if len(update.marshaled) > maxBytes {
+			return nil, fmt.Errorf("update outside of the organizations is %d bytes, exceeding the limit of %d bytes", len(update.marshaled), maxBytes)
+		}
+
+		err = s.commit(remaining)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.updates, nil
+}
+
+// updateSplitter tracks the config that results from committing the
+// updates planned so far.
+type updateSplitter struct {
+	channelID string
+	// state is the config after the planned updates are committed.
+	state *cb.Config
+	// target is the updated config.
+	target  *cb.Config
+	updates [][]byte
+}
+
+type computedUpdate struct {
+	update    *cb.ConfigUpdate
+	marshaled []byte
+}
+
+// compute computes the update from the current state to the intermediate
+// config, which is modified in the process.
+func (s *updateSplitter) compute(intermediate *cb.Config) (computedUpdate, error) {
+	update, err := computeConfigUpdate(s.state, intermediate)
+	if err != nil {
+		return computedUpdate{}, err
+	}
+
+	update.ChannelId = s.channelID
+
+	marshaled, err := proto.Marshal(update)
+	if err != nil {
+		return computedUpdate{}, fmt.Errorf("marshaling config update: %w", err)
+	}
+
+	return computedUpdate{update: update, marshaled: marshaled}, nil
+}
+
+// commit plans the update to the intermediate config and advances the
+// state to the config that results from committing it.
+func (s *updateSplitter) commit(intermediate *cb.Config) error {
+	update, err := s.compute(intermediate)
+	if err != nil {
+		return fmt.Errorf("failed to compute update: %w", err)
+	}
+
+	applyWriteSetVersions(intermediate.ChannelGroup, update.update.WriteSet)
+	intermediate.Sequence = s.state.Sequence + 1
+
+	s.state = intermediate
+	s.updates = append(s.updates, update.marshaled)
+
+	return nil
+}
+
+// withOrgs returns the current state with the organizations at the paths
+// replaced by their updated versions.
+func (s *updateSplitter) withOrgs(orgPaths []string) *cb.Config {
+	intermediate := proto.Clone(s.state).(*cb.Config)
+
+	for _, orgPath := range orgPaths {
+		elements := strings.Split(strings.TrimPrefix(orgPath, "/"), "/")
+		parentPath, name := elements[1:len(elements)-1], elements[len(elements)-1]
+
+		parent, _ := configGroupAtPath(intermediate.ChannelGroup, parentPath)
+		targetOrg, _ := configGroupAtPath(s.target.ChannelGroup, elements[1:])
+		if targetOrg == nil {
+			delete(parent.Groups, name)
+			continue
+		}
+		parent.Groups[name] = proto.Clone(targetOrg).(*cb.ConfigGroup)
+	}
+
+	return intermediate
+}
+
+// changedOrgPaths returns the paths of the organizations that differ
+// between the current state and the updated config and whose parent group
+// exists in both.
+func (s *updateSplitter) changedOrgPaths() []string {
+	stateOrgs := orgGroupsByPath(s.state.ChannelGroup)
+	targetOrgs := orgGroupsByPath(s.target.ChannelGroup)
+
+	paths := map[string]bool{}
+	for path := range stateOrgs {
+		paths[path] = true
+	}
+	for path := range targetOrgs {
+		paths[path] = true
+	}
+
+	var changed []string
+	for path := range paths {
+		elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		parentPath := elements[1 : len(elements)-1]
+		if _, err := configGroupAtPath(s.state.ChannelGroup, parentPath); err != nil {
+			continue
+		}
+		if _, err := configGroupAtPath(s.target.ChannelGroup, parentPath); err != nil {
+			continue
+		}
+
+		stateOrg, inState := stateOrgs[path]
+		targetOrg, inTarget := targetOrgs[path]
+		if inState && inTarget {
+			if _, _, updated := computeGroupUpdate(proto.Clone(stateOrg).(*cb.ConfigGroup), proto.Clone(targetOrg).(*cb.ConfigGroup), false); !updated {
+				continue
+			}
+		}
+
+		changed = append(changed, path)
+	}
+	sort.Strings(changed)
+
+	return changed
+}
+
+// applyWriteSetVersions sets the versions of the elements of the group to
+// the versions they have in the write set, as committing the update does.
+func applyWriteSetVersions(group, writeSet *cb.ConfigGroup) {
+	group.Version = writeSet.Version
+
+	for name, value := range writeSet.Values {
+		if v, ok := group.Values[name]; ok {
+			v.Version = value.Version
+		}
+	}
+
+	for name, policy := range writeSet.Policies {
+		if p, ok := group.Policies[name]; ok {
+			p.Version = policy.Version
+		}
+	}
+
+	for name, subGroup := range writeSet.Groups {
+		if g, ok := group.Groups[name]; ok {
+			applyWriteSetVersions(g, subGroup)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestSplitUpdate(t *testing.T) {
+	t.Parallel()
+
+	orgGroup := func(msp byte) *cb.ConfigGroup {
+		return &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				MSPKey: {
+					Value:     bytes.Repeat([]byte{msp}, 400),
+					ModPolicy: AdminsPolicyKey,
+				},
+			},
+		}
+	}
+
+	original := &cb.Config{
+		Sequence: 3,
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": orgGroup('a'),
+						"Org2": orgGroup('a'),
+						"Org3": orgGroup('a'),
+					},
+				},
+			},
+			Values: map[string]*cb.ConfigValue{
+				CapabilitiesKey: {Value: []byte("v1")},
+			},
+		},
+	}
+
+	c := New(original)
+	c.updated.ChannelGroup.Values[CapabilitiesKey].Value = []byte("v2")
+	application := c.updated.ChannelGroup.Groups[ApplicationGroupKey]
+	application.Groups["Org1"] = orgGroup('b')
+	application.Groups["Org2"] = orgGroup('b')
+	delete(application.Groups, "Org3")
+	application.Groups["Org4"] = orgGroup('b')
+
+	t.Run("when the update fits", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		expected, err := c.ComputeMarshaledUpdate("testchannel")
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		updates, err := c.SplitUpdate("testchannel", 1<<20)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(updates).To(HaveLen(1))
+
+		expectedUpdate := &cb.ConfigUpdate{}
+		err = proto.Unmarshal(expected, expectedUpdate)
+		gt.Expect(err).NotTo(HaveOccurred())
+		update := &cb.ConfigUpdate{}
+		err = proto.Unmarshal(updates[0], update)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(update, expectedUpdate)).To(BeTrue())
+	})
+
+	t.Run("when the update is too large", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		updates, err := c.SplitUpdate("testchannel", 600)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(updates).To(HaveLen(4))
+
+		var orgs [][]string
+		var readVersions []uint64
+		for _, marshaled := range updates {
+			gt.Expect(len(marshaled)).To(BeNumerically("<=", 600))
+
+			update := &cb.ConfigUpdate{}
+			err := proto.Unmarshal(marshaled, update)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(update.ChannelId).To(Equal("testchannel"))
+
+			readApplication := update.ReadSet.Groups[ApplicationGroupKey]
+			writeApplication := update.WriteSet.Groups[ApplicationGroupKey]
+			if writeApplication == nil {
+				orgs = append(orgs, nil)
+				readVersions = append(readVersions, 0)
+				continue
+			}
+			readVersions = append(readVersions, readApplication.Version)
+
+			var written []string
+			for _, name := range sortedKeys(writeApplication.Groups) {
+				if _, ok := writeApplication.Groups[name].Values[MSPKey]; ok {
+					written = append(written, name)
+				}
+			}
+			orgs = append(orgs, written)
+		}
+
+		// each update is computed against the result of the previous ones
+		gt.Expect(orgs).To(Equal([][]string{{"Org1"}, {"Org2"}, {"Org4"}, nil}))
+		gt.Expect(readVersions).To(Equal([]uint64{0, 0, 1, 0}))
+
+		last := &cb.ConfigUpdate{}
+		err = proto.Unmarshal(updates[3], last)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(last.WriteSet.Values[CapabilitiesKey].Value).To(Equal([]byte("v2")))
+		gt.Expect(last.WriteSet.Values[CapabilitiesKey].Version).To(Equal(uint64(1)))
+	})
+
+	t.Run("when a single organization is too large", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		_, err := c.SplitUpdate("testchannel", 100)
+		gt.Expect(err).To(MatchError(MatchRegexp(`^update of /Channel/Application/Org1 alone is \d+ bytes, exceeding the limit of 100 bytes$`)))
+	})
+}
+
+func TestSplitUpdateFailures(t *testing.T) {
+	t.Parallel()
+
+	c := New(&cb.Config{ChannelGroup: newConfigGroup()})
+
+	for _, tc := range []struct {
+		name        string
+		channelID   string
+		maxBytes    int
+		expectedErr string
+	}{
+		{
+			name:        "when channel ID is not specified",
+			maxBytes:    10,
+			expectedErr: "channel ID is required",
+		},
+		{
+			name:        "when the maximum size is not positive",
+			channelID:   "testchannel",
+			expectedErr: "maximum update size must be positive",
+		},
+		{
+			name:        "when nothing changed",
+			channelID:   "testchannel",
+			maxBytes:    10,
+			expectedErr: "failed to compute update: no differences detected between original and updated config",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := c.SplitUpdate(tc.channelID, tc.maxBytes)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
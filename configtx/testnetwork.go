@@ -0,0 +1,248 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Locations of the artifacts created by the fabric-samples test network,
+// relative to the test-network directory.
+const (
+	testNetworkPeerOrgsDir    = "organizations/peerOrganizations"
+	testNetworkOrdererOrgsDir = "organizations/ordererOrganizations"
+	testNetworkGenesisBlock   = "system-genesis-block/genesis.block"
+	testNetworkArtifactsDir   = "channel-artifacts"
+
+	testNetworkOrdererOrgName = "OrdererOrg"
+	testNetworkOrdererMSPID   = "OrdererMSP"
+)
+
+// TestNetwork contains the artifacts of a network created by the
+// fabric-samples test network scripts.
+type TestNetwork struct {
+	// PeerOrganizations are the organizations found in
+	// organizations/peerOrganizations. Each organization is named by its
+	// MSP ID, e.g. Org1MSP for org1.example.com, and uses the policies
+	// defined by the test network.
+	PeerOrganizations []Organization
+	// OrdererOrganization is the organization found in
+	// organizations/ordererOrganizations.
+	OrdererOrganization Organization
+	// Admins contains the signing identity of the admin user of each
+	// organization, keyed by MSP ID.
+	Admins map[string]*SigningIdentity
+	// SystemGenesisBlock is the system channel genesis block. It is nil when
+	// the network was created without a system channel.
+	SystemGenesisBlock *cb.Block
+	// ChannelBlocks contains the channel blocks in channel-artifacts, keyed
+	// by channel ID.
+	ChannelBlocks map[string]*cb.Block
+	// ChannelCreateTxs contains the create channel transactions in
+	// channel-artifacts, keyed by channel ID.
+	ChannelCreateTxs map[string]*cb.Envelope
+	// AnchorPeerUpdates contains the anchor peer update transactions in
+	// channel-artifacts, keyed by MSP ID.
+	AnchorPeerUpdates map[string]*cb.Envelope
+}
+
+// LoadTestNetwork loads the organizations and channel artifacts of a
+// fabric-samples test network from the test-network directory.
+func LoadTestNetwork(dir string) (*TestNetwork, error) {
+	network := &TestNetwork{
+		Admins:            map[string]*SigningIdentity{},
+		ChannelBlocks:     map[string]*cb.Block{},
+		ChannelCreateTxs:  map[string]*cb.Envelope{},
+		AnchorPeerUpdates: map[string]*cb.Envelope{},
+	}
+
+	peerOrgDomains, err := subdirectories(filepath.Join(dir, testNetworkPeerOrgsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, domain := range peerOrgDomains {
+		mspID, err := testNetworkMSPID(domain)
+		if err != nil {
+			return nil, err
+		}
+		orgDir := filepath.Join(dir, testNetworkPeerOrgsDir, domain)
+
+		org, err := loadTestNetworkOrg(network, mspID, mspID, domain, orgDir)
+		if err != nil {
+			return nil, err
+		}
+		org.Policies = DefaultOrgPoliciesFor(mspID)
+
+		network.PeerOrganizations = append(network.PeerOrganizations, org)
+	}
+
+	ordererOrgDomains, err := subdirectories(filepath.Join(dir, testNetworkOrdererOrgsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ordererOrgDomains) != 1 {
+		return nil, fmt.Errorf("expected exactly one orderer organization in %s, found %d", filepath.Join(dir, testNetworkOrdererOrgsDir), len(ordererOrgDomains))
+	}
+
+	ordererOrgDir := filepath.Join(dir, testNetworkOrdererOrgsDir, ordererOrgDomains[0])
+	network.OrdererOrganization, err = loadTestNetworkOrg(network, testNetworkOrdererOrgName, testNetworkOrdererMSPID, ordererOrgDomains[0], ordererOrgDir)
+	if err != nil {
+		return nil, err
+	}
+	network.OrdererOrganization.Policies = defaultOrdererOrgPoliciesFor(testNetworkOrdererMSPID)
+
+	genesisBlockFile := filepath.Join(dir, testNetworkGenesisBlock)
+	if _, err := os.Stat(genesisBlockFile); err == nil {
+		network.SystemGenesisBlock = &cb.Block{}
+		err = readProtoFile(genesisBlockFile, network.SystemGenesisBlock)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = loadTestNetworkArtifacts(network, filepath.Join(dir, testNetworkArtifactsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	return network, nil
+}
+
+// Channel returns a config transaction for the config in the channel block
+// of channelID. Note that the channel block in channel-artifacts is the
+// channel genesis block and does not reflect later config updates.
+func (n *TestNetwork) Channel(channelID string, opts ...Option) (ConfigTx, error) {
+	block, ok := n.ChannelBlocks[channelID]
+	if !ok {
+		return ConfigTx{}, fmt.Errorf("no channel block found for channel %s", channelID)
+	}
+
+	config, err := ConfigFromBlock(block)
+	if err != nil {
+		return ConfigTx{}, fmt.Errorf("extracting config from block for channel %s: %w", channelID, err)
+	}
+
+	return New(config, opts...), nil
+}
+
+// loadTestNetworkOrg loads the MSP and, if present, the admin signing
+// identity of the organization in orgDir.
+func loadTestNetworkOrg(network *TestNetwork, name, mspID, domain, orgDir string) (Organization, error) {
+	msp, err := LoadMSPDir(mspID, filepath.Join(orgDir, "msp"))
+	if err != nil {
+		return Organization{}, fmt.Errorf("loading MSP for %s: %w", name, err)
+	}
+
+	adminMSPDir := filepath.Join(orgDir, "users", "Admin@"+domain, "msp")
+	if _, err := os.Stat(adminMSPDir); err == nil {
+		admin, err := LoadSigningIdentity(mspID, adminMSPDir)
+		if err != nil {
+			return Organization{}, fmt.Errorf("loading admin identity for %s: %w", name, err)
+		}
+		network.Admins[mspID] = admin
+	}
+
+	return Organization{
+		Name: name,
+		MSP:  msp,
+	}, nil
+}
+
+// loadTestNetworkArtifacts loads the channel blocks and transactions in the
+// channel-artifacts directory, if it exists.
+func loadTestNetworkArtifacts(network *TestNetwork, dir string) error {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading directory %s: %w", dir, err)
+	}
+
+	for _, info := range infos {
+		name := info.Name()
+		file := filepath.Join(dir, name)
+
+		switch {
+		case strings.HasSuffix(name, ".block"):
+			block := &cb.Block{}
+			err = readProtoFile(file, block)
+			if err != nil {
+				return err
+			}
+			network.ChannelBlocks[strings.TrimSuffix(name, ".block")] = block
+		case strings.HasSuffix(name, "anchors.tx"):
+			envelope := &cb.Envelope{}
+			err = readProtoFile(file, envelope)
+			if err != nil {
+				return err
+			}
+			network.AnchorPeerUpdates[strings.TrimSuffix(name, "anchors.tx")] = envelope
+		case strings.HasSuffix(name, ".tx"):
+			envelope := &cb.Envelope{}
+			err = readProtoFile(file, envelope)
+			if err != nil {
+				return err
+			}
+			network.ChannelCreateTxs[strings.TrimSuffix(name, ".tx")] = envelope
+		}
+	}
+
+	return nil
+}
+
+// testNetworkMSPID returns the MSP ID the test network uses for the
+// organization with the given domain, e.g. Org1MSP for org1.example.com.
+func testNetworkMSPID(domain string) (string, error) {
+	label := strings.SplitN(domain, ".", 2)[0]
+	if label == "" {
+		return "", fmt.Errorf("invalid organization domain '%s': empty first label", domain)
+	}
+
+	return strings.ToUpper(label[:1]) + label[1:] + "MSP", nil
+}
+
+// subdirectories returns the names of the directories in dir in lexical
+// order, skipping hidden directories such as .git.
+func subdirectories(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, info := range infos {
+		if info.IsDir() && !strings.HasPrefix(info.Name(), ".") {
+			names = append(names, info.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func readProtoFile(file string, msg proto.Message) error {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", file, err)
+	}
+
+	err = proto.Unmarshal(raw, msg)
+	if err != nil {
+		return fmt.Errorf("unmarshaling %s: %w", file, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestLoadTestNetwork(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "test-network")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	for _, domain := range []string{"org1.example.com", "org2.example.com"} {
+		orgDir := filepath.Join(dir, "organizations", "peerOrganizations", domain)
+		caCert, caPrivKey := generateCACertAndPrivateKey(t, domain)
+		writeMSPDir(t, filepath.Join(orgDir, "msp"), caCert, caCert, nodeOUsConfigYAML)
+
+		adminCert, adminPrivKey := generateCertAndPrivateKeyFromCACert(t, domain, caCert, caPrivKey)
+		writeSigningIdentity(t, filepath.Join(orgDir, "users", "Admin@"+domain, "msp"), adminCert, adminPrivKey)
+	}
+
+	ordererCACert, _ := generateCACertAndPrivateKey(t, "example.com")
+	writeMSPDir(t, filepath.Join(dir, "organizations", "ordererOrganizations", "example.com", "msp"), ordererCACert, ordererCACert, nodeOUsConfigYAML)
+
+	// hidden directories, e.g. of version control, are not organizations
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "organizations", "peerOrganizations", ".git"), 0755)).To(Succeed())
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "organizations", "ordererOrganizations", ".DS_Store"), 0755)).To(Succeed())
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "mychannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	writeFile(t, filepath.Join(dir, "channel-artifacts", "mychannel.block"), marshalOrPanic(block))
+
+	envelope := &cb.Envelope{Payload: []byte("payload")}
+	writeFile(t, filepath.Join(dir, "channel-artifacts", "mychannel.tx"), marshalOrPanic(envelope))
+	writeFile(t, filepath.Join(dir, "channel-artifacts", "Org1MSPanchors.tx"), marshalOrPanic(envelope))
+
+	network, err := LoadTestNetwork(dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(network.PeerOrganizations).To(HaveLen(2))
+	gt.Expect(network.PeerOrganizations[0].Name).To(Equal("Org1MSP"))
+	gt.Expect(network.PeerOrganizations[0].MSP.Name).To(Equal("Org1MSP"))
+	gt.Expect(network.PeerOrganizations[0].Policies).To(Equal(DefaultOrgPoliciesFor("Org1MSP")))
+	gt.Expect(network.PeerOrganizations[1].Name).To(Equal("Org2MSP"))
+	gt.Expect(network.OrdererOrganization.Name).To(Equal("OrdererOrg"))
+	gt.Expect(network.OrdererOrganization.MSP.Name).To(Equal("OrdererMSP"))
+	gt.Expect(network.OrdererOrganization.MSP.RootCerts[0]).To(Equal(ordererCACert))
+
+	gt.Expect(network.Admins).To(HaveLen(2))
+	gt.Expect(network.Admins["Org2MSP"].MSPID).To(Equal("Org2MSP"))
+
+	gt.Expect(network.SystemGenesisBlock).To(BeNil())
+	gt.Expect(network.ChannelBlocks).To(HaveKey("mychannel"))
+	gt.Expect(proto.Equal(network.ChannelCreateTxs["mychannel"], envelope)).To(BeTrue())
+	gt.Expect(proto.Equal(network.AnchorPeerUpdates["Org1MSP"], envelope)).To(BeTrue())
+
+	c, err := network.Channel("mychannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.Application().Organization("Org1")).NotTo(BeNil())
+
+	_, err = network.Channel("otherchannel")
+	gt.Expect(err).To(MatchError("no channel block found for channel otherchannel"))
+}
+
+func TestLoadTestNetworkFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "test-network")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	_, err = LoadTestNetwork(dir)
+	gt.Expect(err).To(MatchError(HavePrefix("reading directory " + filepath.Join(dir, "organizations", "peerOrganizations"))))
+
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "organizations", "peerOrganizations", "org1.example.com", "msp"), 0755)).To(Succeed())
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "organizations", "ordererOrganizations"), 0755)).To(Succeed())
+
+	_, err = LoadTestNetwork(dir)
+	gt.Expect(err).To(MatchError("loading MSP for Org1MSP: no root certificates found in " + filepath.Join(dir, "organizations", "peerOrganizations", "org1.example.com", "msp", "cacerts")))
+
+	_, err = testNetworkMSPID(".example.com")
+	gt.Expect(err).To(MatchError("invalid organization domain '.example.com': empty first label"))
+}
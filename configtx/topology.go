@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Topology summarizes the nodes of a channel described by its config, e.g.
+// to generate connection profiles or monitoring targets.
+type Topology struct {
+	// ConsensusType is the consensus type of the ordering service, e.g.
+	// etcdraft. It is empty if the config does not contain an orderer
+	// group.
+	ConsensusType string
+	// ApplicationOrgs and OrdererOrgs are sorted by name.
+	ApplicationOrgs []TopologyOrg
+	OrdererOrgs     []TopologyOrg
+	// Consenters are the etcdraft consenters of the channel.
+	Consenters []orderer.EtcdAddress
+	// OrdererAddresses are the legacy orderer addresses of the channel.
+	OrdererAddresses []string
+}
+
+// TopologyOrg summarizes the nodes of an organization.
+type TopologyOrg struct {
+	Name  string
+	MSPID string
+	// AnchorPeers are set for application organizations.
+	AnchorPeers []Address
+	// OrdererEndpoints and Consenters are set for orderer organizations.
+	// The consenters of an organization are those whose client TLS
+	// certificate was issued by the TLS CAs of its MSP.
+	OrdererEndpoints []string
+	Consenters       []orderer.EtcdAddress
+}
+
+// Topology returns a summary of the organizations, anchor peers, orderer
+// endpoints and consenters of the updated config.
+func (c *ConfigTx) Topology() (Topology, error) {
+	topology := Topology{}
+	channelGroup := c.updated.ChannelGroup
+
+	legacyAddresses, err := ordererAddresses(channelGroup, OrdererAddressesKey)
+	if err != nil {
+		return Topology{}, fmt.Errorf("retrieving orderer addresses: %w", err)
+	}
+	topology.OrdererAddresses = legacyAddresses
+
+	if applicationGroup, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		for _, orgName := range sortedKeys(applicationGroup.Groups) {
+			orgGroup := applicationGroup.Groups[orgName]
+
+			fabricMSPConfig, err := getFabricMSPConfig(orgGroup)
+			if err != nil {
+				return Topology{}, fmt.Errorf("retrieving MSP of application org %s: %w", orgName, err)
+			}
+
+			org := TopologyOrg{
+				Name:  orgName,
+				MSPID: fabricMSPConfig.Name,
+			}
+
+			if _, ok := orgGroup.Values[AnchorPeersKey]; ok {
+				anchorPeers := &pb.AnchorPeers{}
+				err := unmarshalConfigValueAtKey(orgGroup, AnchorPeersKey, anchorPeers)
+				if err != nil {
+					return Topology{}, fmt.Errorf("retrieving anchor peers of application org %s: %w", orgName, err)
+				}
+
+				for _, anchorPeer := range anchorPeers.AnchorPeers {
+					org.AnchorPeers = append(org.AnchorPeers, Address{Host: anchorPeer.Host, Port: int(anchorPeer.Port)})
+				}
+			}
+
+			topology.ApplicationOrgs = append(topology.ApplicationOrgs, org)
+		}
+	}
+
+	ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]
+	if !ok {
+		return topology, nil
+	}
+
+	consensusType := &ob.ConsensusType{}
+	err = unmarshalConfigValueAtKey(ordererGroup, orderer.ConsensusTypeKey, consensusType)
+	if err != nil {
+		return Topology{}, fmt.Errorf("retrieving consensus type: %w", err)
+	}
+	topology.ConsensusType = consensusType.Type
+
+	consenters, err := etcdRaftConsenters(ordererGroup, c.options.certificates)
+	if err != nil {
+		return Topology{}, fmt.Errorf("retrieving consenters: %w", err)
+	}
+	for _, consenter := range consenters {
+		topology.Consenters = append(topology.Consenters, consenter.Address)
+	}
+
+	for _, orgName := range sortedKeys(ordererGroup.Groups) {
+		orgGroup := ordererGroup.Groups[orgName]
+
+		msp, err := getMSPConfig(orgGroup, c.options.certificates)
+		if err != nil {
+			return Topology{}, fmt.Errorf("retrieving MSP of orderer org %s: %w", orgName, err)
+		}
+
+		endpoints, err := ordererAddresses(orgGroup, EndpointsKey)
+		if err != nil {
+			return Topology{}, fmt.Errorf("retrieving endpoints of orderer org %s: %w", orgName, err)
+		}
+
+		org := TopologyOrg{
+			Name:             orgName,
+			MSPID:            msp.Name,
+			OrdererEndpoints: endpoints,
+		}
+
+		for _, consenter := range consenters {
+			if consenter.ClientTLSCert != nil && msp.VerifyTLSCertificateChain(consenter.ClientTLSCert) == nil {
+				org.Consenters = append(org.Consenters, consenter.Address)
+			}
+		}
+
+		topology.OrdererOrgs = append(topology.OrdererOrgs, org)
+	}
+
+	return topology, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestTopology(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, newOrg, newConsenter := basePlanReplaceOrdererOrg(t)
+
+	application, _ := baseApplication(t)
+	application.Organizations[0].MSP.Name = "Org1MSP"
+	application.Organizations[1].MSP.Name = "Org2MSP"
+	applicationGroup, err := NewApplicationGroup(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey] = applicationGroup
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().SetOrganization(newOrg)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().AddConsenter(newConsenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	topology, err := c.Topology()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	oldConsenters := []orderer.EtcdAddress{
+		{Host: "node-1.example.com", Port: 7050},
+		{Host: "node-2.example.com", Port: 7050},
+		{Host: "node-3.example.com", Port: 7050},
+	}
+	gt.Expect(topology).To(Equal(Topology{
+		ConsensusType: orderer.ConsensusTypeEtcdRaft,
+		ApplicationOrgs: []TopologyOrg{
+			{
+				Name:        "Org1",
+				MSPID:       "Org1MSP",
+				AnchorPeers: []Address{{Host: "peer0.org1.example.com", Port: 7051}},
+			},
+			{
+				Name:  "Org2",
+				MSPID: "Org2MSP",
+			},
+		},
+		OrdererOrgs: []TopologyOrg{
+			{
+				Name:             "OrdererOrg",
+				MSPID:            "MSPID",
+				OrdererEndpoints: []string{"localhost:123"},
+				Consenters:       oldConsenters,
+			},
+			{
+				Name:             "Org2",
+				MSPID:            "Org2MSP",
+				OrdererEndpoints: []string{"orderer.org2.example.com:7050"},
+				Consenters:       []orderer.EtcdAddress{newConsenter.Address},
+			},
+		},
+		Consenters:       append(oldConsenters, newConsenter.Address),
+		OrdererAddresses: []string{"localhost:123", "other.example.com:7050"},
+	}))
+}
+
+func TestTopologyFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c, _, _ := basePlanReplaceOrdererOrg(t)
+	delete(c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Values, orderer.ConsensusTypeKey)
+
+	_, err := c.Topology()
+	gt.Expect(err).To(MatchError("retrieving consensus type: config does not contain value for ConsensusType"))
+}
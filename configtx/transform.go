@@ -0,0 +1,43 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Transformer modifies the updated config before a config update is
+// computed from it. Transformers can be used to normalize values or to
+// enforce conventions on every update. Returning an error aborts the
+// computation of the update.
+type Transformer func(updated *cb.Config) error
+
+// WithTransformers registers transformers that are run, in order, on the
+// updated config each time a config update is computed.
+func WithTransformers(transformers ...Transformer) Option {
+	return func(o *options) {
+		o.transformers = append(o.transformers, transformers...)
+	}
+}
+
+// transform runs the registered transformers on the updated config.
+func (c *ConfigTx) transform() error {
+	if len(c.options.transformers) > 0 {
+		c.cache.invalidate()
+	}
+
+	for i, transformer := range c.options.transformers {
+		err := transformer(c.updated)
+		if err != nil {
+			return fmt.Errorf("transformer %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
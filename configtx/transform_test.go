@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestComputeMarshaledUpdateWithTransformers(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	original := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				"foo": {Value: []byte("foo")},
+			},
+		},
+	}
+
+	var calls []string
+	stripForbidden := func(updated *cb.Config) error {
+		calls = append(calls, "strip")
+		delete(updated.ChannelGroup.Values, "forbidden")
+		return nil
+	}
+	addMarker := func(updated *cb.Config) error {
+		calls = append(calls, "mark")
+		updated.ChannelGroup.Values["marker"] = &cb.ConfigValue{Value: []byte("marker")}
+		return nil
+	}
+
+	c := New(original, WithTransformers(stripForbidden), WithTransformers(addMarker))
+	c.updated.ChannelGroup.Values["forbidden"] = &cb.ConfigValue{Value: []byte("forbidden")}
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(calls).To(Equal([]string{"strip", "mark"}))
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(update.WriteSet.Values).To(HaveKey("marker"))
+	gt.Expect(update.WriteSet.Values).NotTo(HaveKey("forbidden"))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Values).NotTo(HaveKey("forbidden"))
+}
+
+func TestComputeMarshaledUpdateTransformerFailure(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	original := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				"foo": {Value: []byte("foo")},
+			},
+		},
+	}
+
+	reject := func(updated *cb.Config) error {
+		return errors.New("org naming convention violated")
+	}
+
+	c := New(original, WithTransformers(reject))
+	c.updated.ChannelGroup.Values["foo"].Value = []byte("bar")
+
+	_, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).To(MatchError("failed to transform updated config: transformer 0: org naming convention violated"))
+}
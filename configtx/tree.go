@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// PrintTree writes the hierarchy of the updated config to w as an indented
+// tree. Every group, value, and policy is listed with its version and
+// mod_policy, and policies are rendered with their rules.
+func (c *ConfigTx) PrintTree(w io.Writer) error {
+	return printGroupTree(w, ChannelGroupKey, c.updated.ChannelGroup, 0)
+}
+
+func printGroupTree(w io.Writer, name string, group *cb.ConfigGroup, depth int) error {
+	indent := strings.Repeat("  ", depth)
+
+	_, err := fmt.Fprintf(w, "%s%s %s\n", indent, name, versionString(group.Version, group.ModPolicy))
+	if err != nil {
+		return err
+	}
+
+	if len(group.Values) > 0 {
+		_, err = fmt.Fprintf(w, "%s  Values:\n", indent)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range sortedKeys(group.Values) {
+			value := group.Values[key]
+			_, err = fmt.Fprintf(w, "%s    %s %s\n", indent, key, versionString(value.Version, value.ModPolicy))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(group.Policies) > 0 {
+		_, err = fmt.Fprintf(w, "%s  Policies:\n", indent)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range sortedKeys(group.Policies) {
+			policy := group.Policies[key]
+			_, err = fmt.Fprintf(w, "%s    %s %s: %s\n", indent, key, versionString(policy.Version, policy.ModPolicy), policyRuleString(policy.Policy))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(group.Groups) > 0 {
+		_, err = fmt.Fprintf(w, "%s  Groups:\n", indent)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range sortedKeys(group.Groups) {
+			err = printGroupTree(w, key, group.Groups[key], depth+2)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func versionString(version uint64, modPolicy string) string {
+	return fmt.Sprintf("[version: %d, mod_policy: %q]", version, modPolicy)
+}
+
+// policyRuleString returns a human readable representation of a policy
+// and falls back to the policy type when the policy cannot be decoded.
+func policyRuleString(policy *cb.Policy) string {
+	if policy == nil {
+		return "<no policy>"
+	}
+
+	policies, err := getPolicies(map[string]*cb.ConfigPolicy{"": {Policy: policy}})
+	if err != nil {
+		return fmt.Sprintf("<undecodable policy of type %d>", policy.Type)
+	}
+
+	return fmt.Sprintf("%s %s", policies[""].Type, policies[""].Rule)
+}
+
+// sortedKeys returns the keys of a config groups, values, or policies map
+// in lexical order.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+
+	switch m := m.(type) {
+	case map[string]*cb.ConfigGroup:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*cb.ConfigValue:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*cb.ConfigPolicy:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestPrintTree(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup := newConfigGroup()
+	channelGroup.ModPolicy = AdminsPolicyKey
+	channelGroup.Version = 2
+	err := setPolicies(channelGroup, standardPolicies(), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup, capabilitiesValue([]string{"V2_0"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	applicationGroup := newConfigGroup()
+	applicationGroup.ModPolicy = AdminsPolicyKey
+	orgGroup := newConfigGroup()
+	orgGroup.Version = 1
+	err = setPolicy(orgGroup, AdminsPolicyKey, AdminsPolicyKey, Policy{Type: SignaturePolicyType, Rule: "OR('Org1MSP.admin', 'Org1MSP.peer')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	orgGroup.Policies["Broken"] = &cb.ConfigPolicy{Policy: &cb.Policy{Type: 42}}
+	applicationGroup.Groups["Org1"] = orgGroup
+	channelGroup.Groups[ApplicationGroupKey] = applicationGroup
+
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	buf := &bytes.Buffer{}
+	err = c.PrintTree(buf)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(buf.String()).To(Equal(`Channel [version: 2, mod_policy: "Admins"]
+  Values:
+    Capabilities [version: 0, mod_policy: "Admins"]
+  Policies:
+    Admins [version: 0, mod_policy: "Admins"]: ImplicitMeta MAJORITY Admins
+    Readers [version: 0, mod_policy: "Admins"]: ImplicitMeta ANY Readers
+    Writers [version: 0, mod_policy: "Admins"]: ImplicitMeta ANY Writers
+  Groups:
+    Application [version: 0, mod_policy: "Admins"]
+      Groups:
+        Org1 [version: 1, mod_policy: ""]
+          Policies:
+            Admins [version: 0, mod_policy: "Admins"]: Signature OR('Org1MSP.admin', 'Org1MSP.peer')
+            Broken [version: 0, mod_policy: ""]: <undecodable policy of type 42>
+`))
+}
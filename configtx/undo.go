@@ -0,0 +1,275 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// EnableUndo records the mutations of the updated config made through the
+// ConfigTx, so that they can be stepped through with Undo and Redo, e.g.
+// by interactive editors. Only the group modified by a mutation is copied,
+// not the whole config. At most depth mutations are kept, or all of them
+// if depth is not positive. Changes made directly to the config returned
+// by UpdatedConfig are not recorded; they are undone together with the
+// next mutation of the same group.
+func EnableUndo(depth int) Option {
+	return func(o *options) {
+		o.undo = true
+		o.undoDepth = depth
+	}
+}
+
+// Undo reverts the last mutation of the updated config that has not been
+// undone. Groups retrieved before, e.g. with Application, must be
+// retrieved again.
+func (c *ConfigTx) Undo() error {
+	if c.history == nil {
+		return errors.New("undo is not enabled")
+	}
+
+	step, ok := c.history.undo()
+	if !ok {
+		return errors.New("nothing to undo")
+	}
+
+	err := c.restore(step.path, step.before)
+	if err != nil {
+		return fmt.Errorf("undoing %s of %s: %w", step.operation, configPath(step.path...), err)
+	}
+
+	c.observe(configPath(step.path...), "Undo")
+
+	return nil
+}
+
+// Redo reapplies the last mutation reverted by Undo. Mutations made after
+// an Undo discard the mutations that could be redone. Groups retrieved
+// before, e.g. with Application, must be retrieved again.
+func (c *ConfigTx) Redo() error {
+	if c.history == nil {
+		return errors.New("undo is not enabled")
+	}
+
+	step, ok := c.history.redo()
+	if !ok {
+		return errors.New("nothing to redo")
+	}
+
+	err := c.restore(step.path, step.after)
+	if err != nil {
+		return fmt.Errorf("redoing %s of %s: %w", step.operation, configPath(step.path...), err)
+	}
+
+	c.observe(configPath(step.path...), "Redo")
+
+	return nil
+}
+
+// CanUndo reports whether there is a mutation to undo.
+func (c *ConfigTx) CanUndo() bool {
+	return c.history.undoable() > 0
+}
+
+// CanRedo reports whether there is a mutation to redo.
+func (c *ConfigTx) CanRedo() bool {
+	return c.history.redoable() > 0
+}
+
+// restore replaces the group at path in the updated config with a copy of
+// group, or removes it if group is nil.
+func (c *ConfigTx) restore(path []string, group *cb.ConfigGroup) error {
+	if len(path) == 1 {
+		c.updated.ChannelGroup = proto.Clone(group).(*cb.ConfigGroup)
+		return nil
+	}
+
+	parentPath, name := path[:len(path)-1], path[len(path)-1]
+	parent, _ := configGroupAtPath(c.updated.ChannelGroup, parentPath[1:])
+	if parent == nil {
+		return fmt.Errorf("parent group %s does not exist in the updated config", configPath(parentPath...))
+	}
+
+	if group == nil {
+		delete(parent.Groups, name)
+		return nil
+	}
+
+	if parent.Groups == nil {
+		parent.Groups = map[string]*cb.ConfigGroup{}
+	}
+	parent.Groups[name] = proto.Clone(group).(*cb.ConfigGroup)
+
+	return nil
+}
+
+// undoStep is a recorded mutation: the group at path before and after the
+// mutation, nil if it did not exist.
+type undoStep struct {
+	path      []string
+	operation string
+	before    *cb.ConfigGroup
+	after     *cb.ConfigGroup
+}
+
+// undoHistory holds the recorded mutations and a snapshot of the updated
+// config after the last of them, from which the group before the next
+// mutation is taken. It is referenced by pointer so that copies of a
+// ConfigTx share the history, like they share the updated config.
+type undoHistory struct {
+	mutex    sync.Mutex
+	depth    int
+	snapshot *cb.ConfigGroup
+	undone   []undoStep
+	done     []undoStep
+}
+
+// newUndoHistory returns a history of mutations of the config.
+func newUndoHistory(config *cb.Config, depth int) *undoHistory {
+	return &undoHistory{
+		depth:    depth,
+		snapshot: proto.Clone(config.ChannelGroup).(*cb.ConfigGroup),
+	}
+}
+
+// record records the mutation of the group at path in the channel group
+// and discards the mutations that could be redone.
+func (h *undoHistory) record(channelGroup *cb.ConfigGroup, path, operation string) {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(elements) > 1 {
+		if _, err := configGroupAtPath(h.snapshot, elements[1:len(elements)-1]); err != nil {
+			// the parent was created by a change that was not recorded
+			elements = elements[:1]
+		}
+	}
+
+	before, _ := configGroupAtPath(h.snapshot, elements[1:])
+	step := undoStep{
+		path:      elements,
+		operation: operation,
+		before:    before,
+	}
+	if after, _ := configGroupAtPath(channelGroup, elements[1:]); after != nil {
+		step.after = proto.Clone(after).(*cb.ConfigGroup)
+	}
+
+	if step.before == nil && step.after == nil {
+		return
+	}
+
+	// the snapshot gets its own copy, as its groups are replaced by later
+	// mutations, and the group before the mutation is detached from it
+	h.replace(elements, step.after)
+
+	h.done = append(h.done, step)
+	if h.depth > 0 && len(h.done) > h.depth {
+		h.done = h.done[len(h.done)-h.depth:]
+	}
+	h.undone = nil
+}
+
+// undo returns the last recorded mutation and reverts the snapshot to the
+// group before it.
+func (h *undoHistory) undo() (undoStep, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.done) == 0 {
+		return undoStep{}, false
+	}
+
+	step := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, step)
+	h.replace(step.path, step.before)
+
+	return step, true
+}
+
+// redo returns the last undone mutation and advances the snapshot to the
+// group after it.
+func (h *undoHistory) redo() (undoStep, bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if len(h.undone) == 0 {
+		return undoStep{}, false
+	}
+
+	step := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, step)
+	h.replace(step.path, step.after)
+
+	return step, true
+}
+
+// replace replaces the group at path in the snapshot with a copy of
+// group, or removes it if group is nil.
+func (h *undoHistory) replace(path []string, group *cb.ConfigGroup) {
+	if group != nil {
+		group = proto.Clone(group).(*cb.ConfigGroup)
+	}
+
+	if len(path) == 1 {
+		h.snapshot = group
+		return
+	}
+
+	parent, _ := configGroupAtPath(h.snapshot, path[1:len(path)-1])
+	if parent == nil {
+		return
+	}
+
+	name := path[len(path)-1]
+	if group == nil {
+		delete(parent.Groups, name)
+		return
+	}
+
+	if parent.Groups == nil {
+		parent.Groups = map[string]*cb.ConfigGroup{}
+	}
+	parent.Groups[name] = group
+}
+
+// undoable returns the number of mutations that can be undone.
+func (h *undoHistory) undoable() int {
+	if h == nil {
+		return 0
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return len(h.done)
+}
+
+// redoable returns the number of mutations that can be redone.
+func (h *undoHistory) redoable() int {
+	if h == nil {
+		return 0
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return len(h.undone)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestUndoRedo(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	config := undoTestConfig(t)
+
+	var mutations []Mutation
+	c := New(config, EnableUndo(0), WithObserver(func(m Mutation) {
+		mutations = append(mutations, m)
+	}))
+	gt.Expect(c.CanUndo()).To(BeFalse())
+
+	states := []*cb.Config{proto.Clone(c.UpdatedConfig()).(*cb.Config)}
+	edits := []func() error{
+		func() error {
+			return c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+		},
+		func() error {
+			return c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1", Port: 7051})
+		},
+		func() error {
+			c.Application().RemoveOrganization("Org2")
+			return nil
+		},
+		func() error {
+			return c.Reset()
+		},
+	}
+	for _, edit := range edits {
+		gt.Expect(edit()).To(Succeed())
+		states = append(states, proto.Clone(c.UpdatedConfig()).(*cb.Config))
+	}
+
+	for i := len(states) - 2; i >= 0; i-- {
+		gt.Expect(c.CanUndo()).To(BeTrue())
+		gt.Expect(c.Undo()).To(Succeed())
+		gt.Expect(proto.Equal(c.UpdatedConfig(), states[i])).To(BeTrue(), "state %d", i)
+	}
+	gt.Expect(c.CanUndo()).To(BeFalse())
+	gt.Expect(c.Undo()).To(MatchError("nothing to undo"))
+
+	for i := 1; i < len(states); i++ {
+		gt.Expect(c.CanRedo()).To(BeTrue())
+		gt.Expect(c.Redo()).To(Succeed())
+		gt.Expect(proto.Equal(c.UpdatedConfig(), states[i])).To(BeTrue(), "state %d", i)
+	}
+	gt.Expect(c.CanRedo()).To(BeFalse())
+	gt.Expect(c.Redo()).To(MatchError("nothing to redo"))
+
+	gt.Expect(mutations[len(edits)]).To(Equal(Mutation{Path: "/Channel", Operation: "Undo"}))
+	gt.Expect(mutations[len(edits)+1]).To(Equal(Mutation{Path: "/Channel/Application", Operation: "Undo"}))
+}
+
+func TestUndoDiscardsRedoOnMutation(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := New(undoTestConfig(t), EnableUndo(0))
+
+	err := c.Application().SetACLs(map[string]string{"acl2": "Writers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.Undo()).To(Succeed())
+	gt.Expect(c.CanRedo()).To(BeTrue())
+
+	err = c.Application().SetACLs(map[string]string{"acl3": "Readers"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.CanRedo()).To(BeFalse())
+
+	gt.Expect(c.Undo()).To(Succeed())
+	acls, err := c.Application().ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{"acl1": "hi"}))
+}
+
+func TestUndoDepth(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := New(undoTestConfig(t), EnableUndo(2))
+
+	for _, acl := range []string{"acl2", "acl3", "acl4"} {
+		err := c.Application().SetACLs(map[string]string{acl: "Writers"})
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	gt.Expect(c.Undo()).To(Succeed())
+	gt.Expect(c.Undo()).To(Succeed())
+	gt.Expect(c.Undo()).To(MatchError("nothing to undo"))
+
+	acls, err := c.Application().ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{"acl2": "Writers"}))
+}
+
+func TestUndoNotEnabled(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := New(undoTestConfig(t))
+	gt.Expect(c.CanUndo()).To(BeFalse())
+	gt.Expect(c.CanRedo()).To(BeFalse())
+	gt.Expect(c.Undo()).To(MatchError("undo is not enabled"))
+	gt.Expect(c.Redo()).To(MatchError("undo is not enabled"))
+}
+
+func undoTestConfig(t *testing.T) *cb.Config {
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return config
+}
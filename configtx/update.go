@@ -9,6 +9,7 @@ package configtx
 import (
 	"bytes"
 	"fmt"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -25,7 +26,9 @@ func computeConfigUpdate(original, updated *cb.Config) (*cb.ConfigUpdate, error)
 		return nil, fmt.Errorf("no channel group included for updated config")
 	}
 
-	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	// The top-level subtrees, e.g. Application, Orderer and Consortiums, are
+	// compared concurrently, as they can contain many organizations
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup, true)
 	if !groupUpdated {
 		return nil, fmt.Errorf("no differences detected between original and updated config")
 	}
@@ -130,7 +133,19 @@ func computeValuesMapUpdate(original, updated map[string]*cb.ConfigValue) (readS
 	return
 }
 
-func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readSet, writeSet, sameSet map[string]*cb.ConfigGroup, updatedMembers bool) {
+// groupUpdate is the read and write set of a group of a config update.
+type groupUpdate struct {
+	readSet  *cb.ConfigGroup
+	writeSet *cb.ConfigGroup
+	updated  bool
+}
+
+// computeGroupsMapUpdate computes the updates of the groups of a map. If
+// parallel is set, the update of each group is computed in its own
+// goroutine, which is safe as the groups are disjoint subtrees of the
+// config. The updates are assembled in the order of the group names, so the
+// result does not depend on the order in which they complete.
+func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup, parallel bool) (readSet, writeSet, sameSet map[string]*cb.ConfigGroup, updatedMembers bool) {
 	readSet = make(map[string]*cb.ConfigGroup)
 	writeSet = make(map[string]*cb.ConfigGroup)
 
@@ -138,47 +153,76 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 	// config which was the same to add to the read/write sets
 	sameSet = make(map[string]*cb.ConfigGroup)
 
-	for groupName, originalGroup := range original {
-		updatedGroup, ok := updated[groupName]
-		if !ok {
+	for groupName := range original {
+		if _, ok := updated[groupName]; !ok {
 			updatedMembers = true
-			continue
 		}
+	}
 
-		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
-		if !groupUpdated {
-			sameSet[groupName] = groupReadSet
-			continue
+	groupNames := sortedKeys(updated)
+	updates := make([]groupUpdate, len(groupNames))
+	computeUpdate := func(i int) {
+		originalGroup, ok := original[groupNames[i]]
+		if !ok {
+			// The group is new, so it is written in full
+			originalGroup = newConfigGroup()
 		}
 
-		readSet[groupName] = groupReadSet
-		writeSet[groupName] = groupWriteSet
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updated[groupNames[i]], false)
+		updates[i] = groupUpdate{readSet: groupReadSet, writeSet: groupWriteSet, updated: groupUpdated}
+	}
 
+	if parallel {
+		var wg sync.WaitGroup
+		for i := range groupNames {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				computeUpdate(i)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range groupNames {
+			computeUpdate(i)
+		}
 	}
 
-	for groupName, updatedGroup := range updated {
-		if _, ok := original[groupName]; ok {
-			// If the updatedGroup is in the original set of groups, it was already handled
+	for i, groupName := range groupNames {
+		update := updates[i]
+
+		if _, ok := original[groupName]; !ok {
+			// If the updatedGroup is not in the original set of groups, it is added
+			updatedMembers = true
+			writeSet[groupName] = &cb.ConfigGroup{
+				Version:   0,
+				ModPolicy: updated[groupName].ModPolicy,
+				Policies:  update.writeSet.Policies,
+				Values:    update.writeSet.Values,
+				Groups:    update.writeSet.Groups,
+			}
 			continue
 		}
-		updatedMembers = true
-		_, groupWriteSet, _ := computeGroupUpdate(newConfigGroup(), updatedGroup)
-		writeSet[groupName] = &cb.ConfigGroup{
-			Version:   0,
-			ModPolicy: updatedGroup.ModPolicy,
-			Policies:  groupWriteSet.Policies,
-			Values:    groupWriteSet.Values,
-			Groups:    groupWriteSet.Groups,
+
+		if !update.updated {
+			sameSet[groupName] = update.readSet
+			continue
 		}
+
+		readSet[groupName] = update.readSet
+		writeSet[groupName] = update.writeSet
 	}
 
 	return
 }
 
-func computeGroupUpdate(original, updated *cb.ConfigGroup) (readSet, writeSet *cb.ConfigGroup, updatedGroup bool) {
+// computeGroupUpdate computes the read and write set of the update of a
+// group. If parallel is set, the updates of its sub-groups are computed
+// concurrently.
+func computeGroupUpdate(original, updated *cb.ConfigGroup, parallel bool) (readSet, writeSet *cb.ConfigGroup, updatedGroup bool) {
 	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
 	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
-	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups, parallel)
 
 	// If the updated group is 'Equal' to the updated group (none of the members nor the mod policy changed)
 	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
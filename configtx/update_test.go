@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	. "github.com/onsi/gomega"
 )
@@ -591,3 +593,45 @@ func TestTwiceNestedModification(t *testing.T) {
 
 	gt.Expect(expectedWriteSet).To(Equal(cu.WriteSet), "Mismatched write set")
 }
+
+func TestParallelSubtreesUpdate(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	newChannelGroup := func() *cb.ConfigGroup {
+		channelGroup := &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{}}
+		for _, subtree := range []string{ApplicationGroupKey, OrdererGroupKey, ConsortiumsGroupKey} {
+			subtreeGroup := &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{}}
+			for i := 0; i < 50; i++ {
+				subtreeGroup.Groups[fmt.Sprintf("Org%d", i)] = &cb.ConfigGroup{
+					Values: map[string]*cb.ConfigValue{"value": {Value: []byte("original")}},
+				}
+			}
+			channelGroup.Groups[subtree] = subtreeGroup
+		}
+		return channelGroup
+	}
+
+	original := newChannelGroup()
+	modify := func(group *cb.ConfigGroup) {
+		group.Groups[ApplicationGroupKey].Groups["Org7"].Values["value"].Value = []byte("updated")
+		delete(group.Groups[OrdererGroupKey].Groups, "Org3")
+		group.Groups[ConsortiumsGroupKey].Groups["NewOrg"] = &cb.ConfigGroup{ModPolicy: "Admins"}
+	}
+
+	updated := newChannelGroup()
+	modify(updated)
+	cu, err := computeConfigUpdate(&cb.Config{ChannelGroup: original}, &cb.Config{ChannelGroup: updated})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	sequentialUpdated := newChannelGroup()
+	modify(sequentialUpdated)
+	readSet, writeSet, groupUpdated := computeGroupUpdate(newChannelGroup(), sequentialUpdated, false)
+	gt.Expect(groupUpdated).To(BeTrue())
+
+	gt.Expect(proto.Equal(cu.ReadSet, readSet)).To(BeTrue(), "Mismatched read set")
+	gt.Expect(proto.Equal(cu.WriteSet, writeSet)).To(BeTrue(), "Mismatched write set")
+	gt.Expect(proto.Equal(updated, sequentialUpdated)).To(BeTrue(), "Mismatched updated config")
+	gt.Expect(cu.WriteSet.Groups[ApplicationGroupKey].Groups["Org7"].Values["value"].Version).To(Equal(uint64(1)))
+	gt.Expect(cu.WriteSet.Groups[OrdererGroupKey].Groups).NotTo(HaveKey("Org3"))
+	gt.Expect(cu.WriteSet.Groups[ConsortiumsGroupKey].Groups).To(HaveKey("NewOrg"))
+}
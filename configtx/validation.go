@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// ValidationErrors is the list of findings of a validation that checks all
+// of a config instead of stopping at the first failure. Validators return
+// it, possibly wrapped with context, so that all of the problems with a
+// config can be fixed at once; use errors.As to retrieve the individual
+// findings.
+type ValidationErrors []error
+
+// Error returns the messages of the findings separated by semicolons.
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, err := range v {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// append adds err to the findings. The findings of a ValidationErrors
+// returned by another validator are added individually.
+func (v ValidationErrors) append(err error) ValidationErrors {
+	if err == nil {
+		return v
+	}
+
+	var findings ValidationErrors
+	if errors.As(err, &findings) {
+		return append(v, findings...)
+	}
+
+	return append(v, err)
+}
+
+// err returns the findings as an error, or nil if there are none.
+func (v ValidationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return v
+}
+
+// WithStrictValidation makes ComputeMarshaledUpdate validate the updated
+// config with Validate and refuse to compute the update of a config with
+// findings, so that invalid updates are not submitted to the orderer.
+func WithStrictValidation() Option {
+	return func(o *options) {
+		o.strictValidation = true
+	}
+}
+
+// Validate checks the updated config for problems that would cause the
+// config update to be rejected or the channel to malfunction: invalid or
+// expired MSP CA certificates, admin and consenter certificates that do not
+// chain to the CAs of their organizations, application ACLs and policies
+// that the application capability level does not support, anchor peers
+// that are declared twice or point at orderer endpoints, etcdraft consenter
+// server TLS certificates that are not valid for the consenter's host,
+// orderer endpoints that no TLS certificate of their organization could
+// serve and consensus type migrations that the orderer would reject. All findings are
+// returned as ValidationErrors.
+//
+// The validity periods of the certificates are checked as of the current
+// time, or as of the time set with WithValidityTime, so that e.g. the
+// certificates that will have expired in 90 days can be found.
+func (c *ConfigTx) Validate() error {
+	var findings ValidationErrors
+	for _, finding := range c.validationFindings() {
+		findings = append(findings, finding.err)
+	}
+
+	return findings.err()
+}
+
+// validationFindings runs the checks of Validate and returns their
+// findings.
+func (c *ConfigTx) validationFindings() []ReportFinding {
+	var findings []ReportFinding
+
+	// report adds err as a single finding
+	report := func(code FindingCode, path string, err error) {
+		if err == nil {
+			return
+		}
+
+		findings = append(findings, ReportFinding{
+			Severity:    SeverityError,
+			Code:        code,
+			Path:        path,
+			Message:     err.Error(),
+			Remediation: remediations[code],
+			err:         err,
+		})
+	}
+
+	// reportAll adds the findings of a validator individually
+	reportAll := func(code FindingCode, path string, err error) {
+		for _, err := range ValidationErrors(nil).append(err) {
+			report(code, path, err)
+		}
+	}
+
+	orgs := orgGroupsByPath(c.updated.ChannelGroup)
+
+	var paths []string
+	for path := range orgs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		msp, err := getMSPConfig(orgs[path], c.options.certificates)
+		if err != nil {
+			report(CodeInvalidMSP, path, fmt.Errorf("retrieving MSP of %s: %w", path, err))
+			continue
+		}
+
+		err = msp.validateCACerts()
+		if err != nil {
+			report(CodeInvalidCACert, path, fmt.Errorf("MSP of %s: %w", path, err))
+		}
+
+		err = msp.validateValidityPeriods(c.options.validityAt())
+		if err != nil {
+			report(CodeCertValidity, path, fmt.Errorf("MSP of %s: %w", path, err))
+		}
+	}
+
+	reportAll(CodeCertChain, configPath(ChannelGroupKey), c.VerifyCertificateChains())
+
+	if _, ok := c.updated.ChannelGroup.Groups[ApplicationGroupKey]; ok {
+		applicationPath := configPath(ChannelGroupKey, ApplicationGroupKey)
+		reportAll(CodeAnchorPeer, applicationPath, c.Application().VerifyAnchorPeers())
+		reportAll(CodeCapabilityGap, applicationPath, c.Application().VerifyCapabilities())
+	}
+
+	ordererPath := configPath(ChannelGroupKey, OrdererGroupKey)
+	if _, ok := c.updated.ChannelGroup.Groups[OrdererGroupKey]; ok {
+		ordererConfig, err := c.Orderer().Configuration()
+		if err != nil {
+			report(CodeInvalidOrderer, ordererPath, fmt.Errorf("retrieving orderer configuration: %w", err))
+		} else if ordererConfig.OrdererType == orderer.ConsensusTypeEtcdRaft {
+			reportAll(CodeConsenterHostname, ordererPath, c.Orderer().VerifyConsenterHostnames())
+			reportAll(CodeEndpointCoverage, ordererPath, c.Orderer().VerifyEndpointCoverage())
+		}
+	}
+
+	reportAll(CodeConsensusMigration, ordererPath, c.VerifyConsensusMigration())
+
+	return findings
+}
@@ -0,0 +1,185 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	var findings ValidationErrors
+	gt.Expect(findings.err()).To(BeNil())
+
+	findings = findings.append(nil)
+	findings = findings.append(errors.New("first"))
+	findings = findings.append(fmt.Errorf("nested: %w", ValidationErrors{errors.New("second"), errors.New("third")}))
+	gt.Expect(findings).To(HaveLen(3))
+
+	err := fmt.Errorf("invalid config: %w", findings.err())
+	gt.Expect(err).To(MatchError("invalid config: first; second; third"))
+
+	var unwrapped ValidationErrors
+	gt.Expect(errors.As(err, &unwrapped)).To(BeTrue())
+	gt.Expect(unwrapped).To(Equal(findings))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	err = c.Validate()
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestValidateReportsAllFindings(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config)
+
+	for _, orgName := range []string{"Org1", "Org2"} {
+		msp, err := c.Application().Organization(orgName).MSP().Configuration()
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		foreignCACert, foreignCAPrivKey := generateCACertAndPrivateKey(t, "foreign.example.com")
+		foreignAdminCert, _ := generateCertAndPrivateKeyFromCACert(t, "foreign.example.com", foreignCACert, foreignCAPrivKey)
+		msp.Admins = []*x509.Certificate{foreignAdminCert}
+
+		err = c.Application().Organization(orgName).SetMSP(msp)
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	ordererGroup, err := NewOrdererGroup(etcdRaftOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey] = ordererGroup
+
+	err = c.Validate()
+	gt.Expect(err).To(HaveOccurred())
+
+	var findings ValidationErrors
+	gt.Expect(errors.As(err, &findings)).To(BeTrue())
+
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.Error())
+	}
+	gt.Expect(messages).To(ContainElement(HavePrefix("admin certificate CN=user.foreign.example.com,O=foreign.example.com of /Channel/Application/Org1: ")))
+	gt.Expect(messages).To(ContainElement(HavePrefix("admin certificate CN=user.foreign.example.com,O=foreign.example.com of /Channel/Application/Org2: ")))
+	gt.Expect(messages).To(ContainElement(HavePrefix("server TLS certificate of consenter node-1.example.com:7050 is not valid for host node-1.example.com: ")))
+	gt.Expect(messages).To(ContainElement(HavePrefix("server TLS certificate of consenter node-3.example.com:7050 is not valid for host node-3.example.com: ")))
+}
+
+func TestValidateMSPFailure(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	c := New(&cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": {
+							Values: map[string]*cb.ConfigValue{
+								MSPKey: {Value: []byte("invalid")},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	err := c.Validate()
+	gt.Expect(err).To(MatchError(HavePrefix("retrieving MSP of /Channel/Application/Org1: ")))
+}
+
+func TestValidateAsOfValidityTime(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config, WithValidityTime(time.Now().Add(90*24*time.Hour)))
+	err = c.Validate()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c = New(config, WithValidityTime(time.Now().Add(2*YEAR)))
+	err = c.Validate()
+	gt.Expect(err).To(MatchError(ContainSubstring("MSP of /Channel/Application/Org1: invalid root cert: expired at ")))
+	gt.Expect(err).To(MatchError(ContainSubstring("MSP of /Channel/Orderer/OrdererOrg: invalid root cert: expired at ")))
+
+	c = New(config, WithValidityTime(time.Now().Add(-YEAR)))
+	err = c.Validate()
+	gt.Expect(err).To(MatchError(ContainSubstring("MSP of /Channel/Application/Org1: invalid root cert: not valid before ")))
+}
+
+func TestWithStrictValidation(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(config, WithStrictValidation())
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).To(MatchError("updated config is not valid: " +
+		"anchor peer peer0.org1.example.com:7051 of application org Org2 is also an anchor peer of application org Org1"))
+
+	// without strict validation, the update is computed
+	c = New(c.OriginalConfig())
+	err = c.Application().Organization("Org2").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// standardValueTypes maps the keys of the config values defined by Fabric
+// to constructors for their message types.
+var standardValueTypes = map[string]func() proto.Message{
+	ConsortiumKey:                  func() proto.Message { return &cb.Consortium{} },
+	HashingAlgorithmKey:            func() proto.Message { return &cb.HashingAlgorithm{} },
+	BlockDataHashingStructureKey:   func() proto.Message { return &cb.BlockDataHashingStructure{} },
+	CapabilitiesKey:                func() proto.Message { return &cb.Capabilities{} },
+	EndpointsKey:                   func() proto.Message { return &cb.OrdererAddresses{} },
+	OrdererAddressesKey:            func() proto.Message { return &cb.OrdererAddresses{} },
+	MSPKey:                         func() proto.Message { return &mb.MSPConfig{} },
+	ACLsKey:                        func() proto.Message { return &pb.ACLs{} },
+	AnchorPeersKey:                 func() proto.Message { return &pb.AnchorPeers{} },
+	orderer.KafkaBrokersKey:        func() proto.Message { return &ob.KafkaBrokers{} },
+	orderer.ConsensusTypeKey:       func() proto.Message { return &ob.ConsensusType{} },
+	orderer.BatchSizeKey:           func() proto.Message { return &ob.BatchSize{} },
+	orderer.BatchTimeoutKey:        func() proto.Message { return &ob.BatchTimeout{} },
+	orderer.ChannelRestrictionsKey: func() proto.Message { return &ob.ChannelRestrictions{} },
+}
+
+// ConfigValue unmarshals the config value at path in the updated config
+// into msg. The path lists the groups beneath the channel group followed
+// by the value key, e.g. ConfigValue(msg, "Orderer", "BatchSize").
+// This can be used to read custom values that are not covered by the
+// typed API.
+func (c *ConfigTx) ConfigValue(msg proto.Message, path ...string) error {
+	value, err := configValueAtPath(c.updated.ChannelGroup, path)
+	if err != nil {
+		return err
+	}
+
+	err = proto.Unmarshal(value.Value, msg)
+	if err != nil {
+		return fmt.Errorf("unmarshaling config value at %s: %w", valuePath(path), err)
+	}
+
+	return nil
+}
+
+// StandardConfigValue returns the config value at path in the updated
+// config decoded into the message type Fabric defines for its key, e.g.
+// *orderer.BatchSize for StandardConfigValue("Orderer", "BatchSize").
+// The path is interpreted as in ConfigValue.
+func (c *ConfigTx) StandardConfigValue(path ...string) (proto.Message, error) {
+	if len(path) == 0 {
+		return nil, errors.New("config value path is required")
+	}
+
+	newMessage, ok := standardValueTypes[path[len(path)-1]]
+	if !ok {
+		return nil, fmt.Errorf("config value at %s is not a standard config value", valuePath(path))
+	}
+
+	msg := newMessage()
+	err := c.ConfigValue(msg, path...)
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// ConfigValueJSON returns the standard config value at path in the updated
+// config encoded using the canonical protobuf JSON mapping. The path is
+// interpreted as in ConfigValue.
+func (c *ConfigTx) ConfigValueJSON(path ...string) ([]byte, error) {
+	msg, err := c.StandardConfigValue(path...)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	err = (&jsonpb.Marshaler{}).Marshal(buf, msg)
+	if err != nil {
+		return nil, fmt.Errorf("encoding config value at %s as JSON: %w", valuePath(path), err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SetConfigValueJSON decodes jsonValue, which must use the canonical
+// protobuf JSON mapping of the message type Fabric defines for the value
+// key, and sets it at path in the updated config with the given mod
+// policy. The path is interpreted as in ConfigValue and every group in
+// the path must already exist. If the value already exists, it will be
+// overwritten. An empty mod policy keeps the mod policy of an existing
+// value and is invalid for a new value.
+func (c *ConfigTx) SetConfigValueJSON(jsonValue []byte, modPolicy string, path ...string) error {
+	if len(path) == 0 {
+		return errors.New("config value path is required")
+	}
+
+	key := path[len(path)-1]
+	newMessage, ok := standardValueTypes[key]
+	if !ok {
+		return fmt.Errorf("config value at %s is not a standard config value", valuePath(path))
+	}
+
+	group, err := configGroupAtPath(c.updated.ChannelGroup, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	if modPolicy == "" {
+		existing, ok := group.Values[key]
+		if !ok {
+			return fmt.Errorf("mod policy is required for new value %s", valuePath(path))
+		}
+		modPolicy = existing.ModPolicy
+	}
+
+	msg := newMessage()
+	err = jsonpb.Unmarshal(bytes.NewReader(jsonValue), msg)
+	if err != nil {
+		return fmt.Errorf("decoding JSON for config value at %s: %w", valuePath(path), err)
+	}
+
+	err = setValue(group, &standardConfigValue{key: key, value: msg}, modPolicy)
+	if err != nil {
+		return err
+	}
+
+	c.notify(valuePath(path[:len(path)-1]), "SetConfigValueJSON")
+
+	return nil
+}
+
+// configValueAtPath returns the config value at path beneath the channel group.
+func configValueAtPath(channelGroup *cb.ConfigGroup, path []string) (*cb.ConfigValue, error) {
+	if len(path) == 0 {
+		return nil, errors.New("config value path is required")
+	}
+
+	group, err := configGroupAtPath(channelGroup, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := group.Values[path[len(path)-1]]
+	if !ok {
+		return nil, fmt.Errorf("config value %s does not exist", valuePath(path))
+	}
+
+	return value, nil
+}
+
+// configGroupAtPath returns the config group at path beneath the channel
+// group. A nil channel group contains no groups.
+func configGroupAtPath(channelGroup *cb.ConfigGroup, path []string) (*cb.ConfigGroup, error) {
+	group := channelGroup
+	for i, groupName := range path {
+		var ok bool
+		group, ok = group.GetGroups()[groupName]
+		if !ok {
+			return nil, fmt.Errorf("config group %s does not exist", valuePath(path[:i+1]))
+		}
+	}
+
+	return group, nil
+}
+
+// valuePath returns the config path of an element beneath the channel group.
+func valuePath(path []string) string {
+	return configPath(append([]string{ChannelGroupKey}, path...)...)
+}
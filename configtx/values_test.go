@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigValue(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	config, err := ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config.ChannelGroup.Groups[ApplicationGroupKey].Values["Custom"] = &cb.ConfigValue{
+		Value: marshalOrPanic(&cb.HashingAlgorithm{Name: "custom"}),
+	}
+
+	c := New(config)
+
+	batchSize := &ob.BatchSize{}
+	err = c.ConfigValue(batchSize, OrdererGroupKey, "BatchSize")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(batchSize.MaxMessageCount).To(Equal(uint32(100)))
+
+	custom := &cb.HashingAlgorithm{}
+	err = c.ConfigValue(custom, ApplicationGroupKey, "Custom")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(custom.Name).To(Equal("custom"))
+
+	msg, err := c.StandardConfigValue(OrdererGroupKey, "BatchSize")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(msg, batchSize)).To(BeTrue())
+
+	msg, err = c.StandardConfigValue(HashingAlgorithmKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(BeAssignableToTypeOf(&cb.HashingAlgorithm{}))
+}
+
+func TestConfigValueFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		path        []string
+		expectedErr string
+	}{
+		{
+			testName:    "when the path is empty",
+			path:        nil,
+			expectedErr: "config value path is required",
+		},
+		{
+			testName:    "when a group does not exist",
+			path:        []string{ApplicationGroupKey, "Org3", MSPKey},
+			expectedErr: "config group /Channel/Application/Org3 does not exist",
+		},
+		{
+			testName:    "when the value does not exist",
+			path:        []string{ApplicationGroupKey, AnchorPeersKey},
+			expectedErr: "config value /Channel/Application/AnchorPeers does not exist",
+		},
+		{
+			testName:    "when the value is not a standard value",
+			path:        []string{ApplicationGroupKey, "Custom"},
+			expectedErr: "config value at /Channel/Application/Custom is not a standard config value",
+		},
+		{
+			testName:    "when the value cannot be unmarshaled",
+			path:        []string{ApplicationGroupKey, ACLsKey},
+			expectedErr: "unmarshaling config value at /Channel/Application/ACLs: unexpected EOF",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseApplicationChannelGroup(t)
+			gt.Expect(err).NotTo(HaveOccurred())
+			channelGroup.Groups[ApplicationGroupKey].Values[ACLsKey] = &cb.ConfigValue{Value: []byte{0x0a}}
+
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			_, err = c.StandardConfigValue(tc.path...)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func TestConfigValueJSON(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[OrdererGroupKey].Values[orderer.BatchSizeKey].ModPolicy = "/Channel/Orderer/Writers"
+
+	var mutations []Mutation
+	c := New(&cb.Config{ChannelGroup: channelGroup}, WithObserver(func(m Mutation) {
+		mutations = append(mutations, m)
+	}))
+
+	jsonValue, err := c.ConfigValueJSON(OrdererGroupKey, orderer.BatchSizeKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(jsonValue).To(MatchJSON(`{"maxMessageCount":100,"absoluteMaxBytes":100,"preferredMaxBytes":100}`))
+
+	err = c.SetConfigValueJSON([]byte(`{"maxCount":"42"}`), AdminsPolicyKey, OrdererGroupKey, orderer.ChannelRestrictionsKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(mutations).To(Equal([]Mutation{{Path: "/Channel/Orderer", Operation: "SetConfigValueJSON"}}))
+
+	restrictions := &ob.ChannelRestrictions{}
+	err = c.ConfigValue(restrictions, OrdererGroupKey, orderer.ChannelRestrictionsKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(restrictions.MaxCount).To(Equal(uint64(42)))
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Values[orderer.ChannelRestrictionsKey].ModPolicy).To(Equal(AdminsPolicyKey))
+
+	// an empty mod policy keeps the mod policy of the existing value
+	err = c.SetConfigValueJSON([]byte(`{"maxMessageCount":50}`), "", OrdererGroupKey, orderer.BatchSizeKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups[OrdererGroupKey].Values[orderer.BatchSizeKey].ModPolicy).To(Equal("/Channel/Orderer/Writers"))
+}
+
+func TestSetConfigValueJSONFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		jsonValue   string
+		modPolicy   string
+		path        []string
+		expectedErr string
+	}{
+		{
+			testName:    "when the path is empty",
+			modPolicy:   AdminsPolicyKey,
+			jsonValue:   `{}`,
+			expectedErr: "config value path is required",
+		},
+		{
+			testName:    "when the value is not a standard value",
+			modPolicy:   AdminsPolicyKey,
+			jsonValue:   `{}`,
+			path:        []string{OrdererGroupKey, "Custom"},
+			expectedErr: "config value at /Channel/Orderer/Custom is not a standard config value",
+		},
+		{
+			testName:    "when a group does not exist",
+			modPolicy:   AdminsPolicyKey,
+			jsonValue:   `{}`,
+			path:        []string{ApplicationGroupKey, ACLsKey},
+			expectedErr: "config group /Channel/Application does not exist",
+		},
+		{
+			testName:    "when the JSON does not match the message type",
+			modPolicy:   AdminsPolicyKey,
+			jsonValue:   `{"unknown":true}`,
+			path:        []string{OrdererGroupKey, orderer.BatchSizeKey},
+			expectedErr: `decoding JSON for config value at /Channel/Orderer/BatchSize: unknown field "unknown" in orderer.BatchSize`,
+		},
+		{
+			testName:    "when the mod policy of a new value is empty",
+			jsonValue:   `{"brokers":["kafka0:9092"]}`,
+			path:        []string{OrdererGroupKey, orderer.KafkaBrokersKey},
+			expectedErr: "mod policy is required for new value /Channel/Orderer/KafkaBrokers",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			err = c.SetConfigValueJSON([]byte(tc.jsonValue), tc.modPolicy, tc.path...)
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
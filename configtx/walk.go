@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// SkipGroup is returned by a WalkFunc called for a group to skip the
+// values, policies and sub-groups of the group.
+var SkipGroup = errors.New("skip this group")
+
+// WalkFunc is called by Walk for each element of a config. Exactly one of
+// group, value and policy is set, depending on the type of the element at
+// path, e.g. /Channel/Application/Org1 for a group or
+// /Channel/Application/Org1/MSP for a value. The elements are those of the
+// walked config and must not be modified.
+type WalkFunc func(path string, group *cb.ConfigGroup, value *cb.ConfigValue, policy *cb.ConfigPolicy) error
+
+// Walk calls fn for every group, value and policy of the config, e.g. the
+// original or updated config of a ConfigTx, in the order of the config
+// tree: each group is visited before its values, policies and sub-groups,
+// which are visited in lexical order. If fn returns SkipGroup for a group,
+// its members are skipped, and if it returns SkipGroup for a value or
+// policy, the remaining members of its group are skipped. Any other error
+// stops the walk and is returned.
+func Walk(config *cb.Config, fn WalkFunc) error {
+	if config == nil || config.ChannelGroup == nil {
+		return errors.New("config does not contain a channel group")
+	}
+
+	err := walkGroup(configPath(ChannelGroupKey), config.ChannelGroup, fn)
+	if err == SkipGroup {
+		return nil
+	}
+
+	return err
+}
+
+// walkGroup walks the group at groupPath and its members.
+func walkGroup(groupPath string, group *cb.ConfigGroup, fn WalkFunc) error {
+	err := fn(groupPath, group, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(group.Values) {
+		err := fn(groupPath+"/"+name, nil, group.Values[name], nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(group.Policies) {
+		err := fn(groupPath+"/"+name, nil, nil, group.Policies[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(group.Groups) {
+		err := walkGroup(groupPath+"/"+name, group.Groups[name], fn)
+		if err != nil && err != SkipGroup {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Values: map[string]*cb.ConfigValue{
+				"Consortium": {ModPolicy: AdminsPolicyKey},
+			},
+			Policies: map[string]*cb.ConfigPolicy{
+				AdminsPolicyKey:  {ModPolicy: AdminsPolicyKey},
+				ReadersPolicyKey: {ModPolicy: AdminsPolicyKey},
+			},
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: {
+					Values: map[string]*cb.ConfigValue{
+						"BatchSize": {ModPolicy: AdminsPolicyKey},
+					},
+				},
+				ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"Org1": {
+							Values: map[string]*cb.ConfigValue{MSPKey: {ModPolicy: AdminsPolicyKey}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var visited []string
+	err := Walk(config, func(path string, group *cb.ConfigGroup, value *cb.ConfigValue, policy *cb.ConfigPolicy) error {
+		switch {
+		case group != nil:
+			visited = append(visited, "group "+path)
+		case value != nil:
+			visited = append(visited, "value "+path)
+		case policy != nil:
+			visited = append(visited, "policy "+path)
+		}
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(visited).To(Equal([]string{
+		"group /Channel",
+		"value /Channel/Consortium",
+		"policy /Channel/Admins",
+		"policy /Channel/Readers",
+		"group /Channel/Application",
+		"group /Channel/Application/Org1",
+		"value /Channel/Application/Org1/MSP",
+		"group /Channel/Orderer",
+		"value /Channel/Orderer/BatchSize",
+	}))
+
+	visited = nil
+	err = Walk(config, func(path string, group *cb.ConfigGroup, value *cb.ConfigValue, policy *cb.ConfigPolicy) error {
+		visited = append(visited, path)
+		if path == "/Channel/Application" || path == "/Channel/Admins" {
+			return SkipGroup
+		}
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(visited).To(Equal([]string{
+		"/Channel",
+		"/Channel/Consortium",
+		"/Channel/Admins",
+	}))
+
+	visited = nil
+	err = Walk(config, func(path string, group *cb.ConfigGroup, value *cb.ConfigValue, policy *cb.ConfigPolicy) error {
+		visited = append(visited, path)
+		if path == "/Channel/Application" {
+			return SkipGroup
+		}
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(visited).To(ContainElement("/Channel/Orderer/BatchSize"))
+	gt.Expect(visited).NotTo(ContainElement("/Channel/Application/Org1"))
+
+	err = Walk(config, func(path string, group *cb.ConfigGroup, value *cb.ConfigValue, policy *cb.ConfigPolicy) error {
+		if path == "/Channel/Orderer/BatchSize" {
+			return errors.New("walk failed")
+		}
+		return nil
+	})
+	gt.Expect(err).To(MatchError("walk failed"))
+
+	err = Walk(&cb.Config{}, nil)
+	gt.Expect(err).To(MatchError("config does not contain a channel group"))
+}
@@ -0,0 +1,377 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Warning is a non-fatal finding about a channel configuration, such as the
+// use of a deprecated construct or a policy that can never be satisfied.
+type Warning struct {
+	// Path is the location in the config the warning applies to,
+	// e.g. /Channel/Application/Org1.
+	Path    string
+	Message string
+	// Replacement is set on warnings about deprecated constructs and
+	// describes the modern equivalent to use instead.
+	Replacement string
+}
+
+// Deprecation reports whether the warning is about a deprecated construct.
+func (w Warning) Deprecation() bool {
+	return w.Replacement != ""
+}
+
+// WarningHandler is invoked with each warning found while building or
+// editing a channel configuration.
+type WarningHandler func(Warning)
+
+// WithWarningHandler registers a handler that is invoked with non-fatal
+// findings when building channel artifacts or editing a ConfigTx.
+func WithWarningHandler(handler WarningHandler) Option {
+	return func(o *options) {
+		o.warningHandler = handler
+	}
+}
+
+// emitWarnings passes each warning to the configured handler, if any.
+func (o options) emitWarnings(warnings []Warning) {
+	if o.warningHandler == nil {
+		return
+	}
+
+	for _, w := range warnings {
+		o.warningHandler(w)
+	}
+}
+
+// warn passes warnings found while editing the config to the handler
+// registered with the ConfigTx. It is safe to call on a nil ConfigTx.
+func (c *ConfigTx) warn(warnings ...Warning) {
+	if c == nil {
+		return
+	}
+
+	c.options.emitWarnings(warnings)
+}
+
+// configPath joins config group names into a config path.
+func configPath(elements ...string) string {
+	return "/" + strings.Join(elements, "/")
+}
+
+// channelWarnings returns the warnings for a channel configuration that
+// is about to be encoded.
+func channelWarnings(channelConfig Channel, withOrderer bool) []Warning {
+	var warnings []Warning
+
+	channelPath := configPath(ChannelGroupKey)
+	applicationPath := configPath(ChannelGroupKey, ApplicationGroupKey)
+	ordererPath := configPath(ChannelGroupKey, OrdererGroupKey)
+
+	if withOrderer {
+		warnings = append(warnings, consensusTypeWarnings(ordererPath, channelConfig.Orderer.OrdererType)...)
+		warnings = append(warnings, implicitMetaWarnings(ordererPath, channelConfig.Orderer.Policies, len(channelConfig.Orderer.Organizations))...)
+		warnings = append(warnings, capabilityMismatchWarnings(ordererPath, channelConfig.Orderer.Capabilities, channelConfig.Capabilities)...)
+	}
+
+	if len(channelConfig.Consortiums) == 0 {
+		warnings = append(warnings, implicitMetaWarnings(applicationPath, channelConfig.Application.Policies, len(channelConfig.Application.Organizations))...)
+		warnings = append(warnings, capabilityMismatchWarnings(applicationPath, channelConfig.Application.Capabilities, channelConfig.Capabilities)...)
+	}
+
+	for _, org := range channelConfig.Application.Organizations {
+		warnings = append(warnings, implicitMetaWarnings(configPath(ChannelGroupKey, ApplicationGroupKey, org.Name), org.Policies, 0)...)
+	}
+
+	channelLevel := capabilityLevel(channelConfig.Capabilities)
+	if len(channelConfig.Consortiums) == 0 {
+		for _, org := range channelConfig.Application.Organizations {
+			warnings = append(warnings, adminCertsWarnings(configPath(ChannelGroupKey, ApplicationGroupKey, org.Name), org.MSP, channelLevel)...)
+		}
+	}
+	if withOrderer {
+		for _, org := range channelConfig.Orderer.Organizations {
+			warnings = append(warnings, adminCertsWarnings(configPath(ChannelGroupKey, OrdererGroupKey, org.Name), org.MSP, channelLevel)...)
+		}
+	}
+	for _, consortium := range channelConfig.Consortiums {
+		for _, org := range consortium.Organizations {
+			warnings = append(warnings, adminCertsWarnings(configPath(ChannelGroupKey, ConsortiumsGroupKey, consortium.Name, org.Name), org.MSP, channelLevel)...)
+		}
+	}
+
+	if len(channelConfig.Capabilities) == 0 && withOrderer {
+		warnings = append(warnings, Warning{
+			Path:    channelPath,
+			Message: "no channel capabilities are defined",
+		})
+	}
+
+	return warnings
+}
+
+// consensusTypeWarnings returns deprecation warnings for the consensus type.
+func consensusTypeWarnings(path, consensusType string) []Warning {
+	switch consensusType {
+	case orderer.ConsensusTypeKafka:
+		return []Warning{{
+			Path:        path,
+			Message:     "the kafka consensus type is deprecated, use etcdraft instead",
+			Replacement: "the etcdraft consensus type, migrated to with OrdererGroup.MigrateToEtcdRaft while the orderer is in maintenance mode",
+		}}
+	case orderer.ConsensusTypeSolo:
+		return []Warning{{
+			Path:        path,
+			Message:     "the solo consensus type is deprecated, use etcdraft instead",
+			Replacement: "the etcdraft consensus type",
+		}}
+	}
+
+	return nil
+}
+
+// The channel capability levels from which the modern equivalents of
+// deprecated constructs are available.
+var (
+	// ordererEndpointsLevel enables orderer endpoints defined per orderer
+	// org instead of the global orderer addresses.
+	ordererEndpointsLevel = []int{1, 4, 2}
+	// adminOULevel enables the classification of admins by the admin OU
+	// instead of by admin certificates.
+	adminOULevel = []int{1, 4, 3}
+)
+
+// adminCertsWarnings returns a deprecation warning when an MSP that
+// classifies admins by the admin OU also lists admin certificates. Nothing
+// is returned when the channel capability level does not support the admin
+// OU.
+func adminCertsWarnings(path string, msp MSP, channelLevel []int) []Warning {
+	if compareCapabilityLevels(channelLevel, adminOULevel) < 0 {
+		return nil
+	}
+
+	if !msp.NodeOUs.Enable || msp.NodeOUs.AdminOUIdentifier.OrganizationalUnitIdentifier == "" || len(msp.Admins) == 0 {
+		return nil
+	}
+
+	return []Warning{{
+		Path:        path,
+		Message:     "admin certificates are deprecated for MSPs that enable NodeOUs with an admin OU",
+		Replacement: "admin identities carrying the admin OU; remove the admin certificates with OrganizationMSP.RemoveAdminCert",
+	}}
+}
+
+// legacyOrdererAddressesWarnings returns a deprecation warning for the
+// global orderer addresses. Nothing is returned when the channel capability
+// level does not support orderer endpoints per orderer org.
+func legacyOrdererAddressesWarnings(channelLevel []int) []Warning {
+	if compareCapabilityLevels(channelLevel, ordererEndpointsLevel) < 0 {
+		return nil
+	}
+
+	return []Warning{{
+		Path:        configPath(ChannelGroupKey),
+		Message:     "the global orderer addresses are deprecated",
+		Replacement: "orderer endpoints of each orderer org, set with OrdererGroup.AddOrdererEndpoint; remove the global addresses with ChannelGroup.RemoveLegacyOrdererAddresses",
+	}}
+}
+
+// implicitMetaWarnings returns warnings for ImplicitMeta policies in a group
+// with no sub-groups, which are either never or always satisfied.
+func implicitMetaWarnings(path string, policies map[string]Policy, subGroups int) []Warning {
+	if subGroups > 0 {
+		return nil
+	}
+
+	var warnings []Warning
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		policy := policies[name]
+		if policy.Type != ImplicitMetaPolicyType {
+			continue
+		}
+
+		warnings = append(warnings, emptyImplicitMetaWarning(path, name, policy.Rule))
+	}
+
+	return warnings
+}
+
+func emptyImplicitMetaWarning(path, name, rule string) Warning {
+	outcome := "can never be satisfied"
+	if strings.HasPrefix(rule, cb.ImplicitMetaPolicy_ALL.String()) {
+		outcome = "is always satisfied"
+	}
+
+	return Warning{
+		Path:    path,
+		Message: fmt.Sprintf("ImplicitMeta policy %s '%s' has no sub-groups to evaluate and %s", name, rule, outcome),
+	}
+}
+
+// capabilityMismatchWarnings returns a warning when the group enables a
+// capability level newer than the channel capability level. Nothing is
+// returned when the channel capabilities are not known.
+func capabilityMismatchWarnings(path string, groupCapabilities, channelCapabilities []string) []Warning {
+	groupLevel := capabilityLevel(groupCapabilities)
+	channelLevel := capabilityLevel(channelCapabilities)
+
+	if groupLevel == nil || channelLevel == nil || compareCapabilityLevels(groupLevel, channelLevel) <= 0 {
+		return nil
+	}
+
+	return []Warning{{
+		Path: path,
+		Message: fmt.Sprintf("capability level %s is newer than the channel capability level %s",
+			capabilityLevelString(groupLevel), capabilityLevelString(channelLevel)),
+	}}
+}
+
+// capabilityLevel returns the highest version among capabilities of the
+// form V<major>_<minor>[_<patch>]. Unrecognized capabilities are ignored.
+func capabilityLevel(capabilities []string) []int {
+	var highest []int
+
+	for _, capability := range capabilities {
+		if !strings.HasPrefix(capability, "V") {
+			continue
+		}
+
+		var level []int
+		for _, part := range strings.Split(strings.TrimPrefix(capability, "V"), "_") {
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				level = nil
+				break
+			}
+			level = append(level, n)
+		}
+
+		if level != nil && compareCapabilityLevels(level, highest) > 0 {
+			highest = level
+		}
+	}
+
+	return highest
+}
+
+func compareCapabilityLevels(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func capabilityLevelString(level []int) string {
+	parts := make([]string, len(level))
+	for i, n := range level {
+		parts[i] = strconv.Itoa(n)
+	}
+
+	return "V" + strings.Join(parts, "_")
+}
+
+// checkCapabilityLevels warns when the application or orderer capability
+// level in the updated config is newer than the channel capability level.
+func (c *ConfigTx) checkCapabilityLevels() {
+	if c == nil || c.options.warningHandler == nil {
+		return
+	}
+
+	channelGroup := c.updated.ChannelGroup
+	channelCapabilities, err := getCapabilities(channelGroup)
+	if err != nil {
+		return
+	}
+
+	for _, groupKey := range []string{ApplicationGroupKey, OrdererGroupKey} {
+		group, ok := channelGroup.Groups[groupKey]
+		if !ok {
+			continue
+		}
+
+		capabilities, err := getCapabilities(group)
+		if err != nil {
+			continue
+		}
+
+		c.warn(capabilityMismatchWarnings(configPath(ChannelGroupKey, groupKey), capabilities, channelCapabilities)...)
+	}
+}
+
+// checkImplicitMetaPolicy warns when an ImplicitMeta policy is set on a
+// group with no sub-groups.
+func (c *ConfigTx) checkImplicitMetaPolicy(path string, group *cb.ConfigGroup, name string, policy Policy) {
+	if policy.Type != ImplicitMetaPolicyType || len(group.Groups) > 0 {
+		return
+	}
+
+	c.warn(emptyImplicitMetaWarning(path, name, policy.Rule))
+}
+
+// channelCapabilityLevel returns the channel capability level of the
+// updated config, or nil if it cannot be determined.
+func (c *ConfigTx) channelCapabilityLevel() []int {
+	capabilities, err := getCapabilities(c.updated.ChannelGroup)
+	if err != nil {
+		return nil
+	}
+
+	return capabilityLevel(capabilities)
+}
+
+// checkAdminCerts warns when the MSP of the organization at path lists
+// admin certificates although it classifies admins by the admin OU.
+func (c *ConfigTx) checkAdminCerts(path string, msp MSP) {
+	if c == nil || c.options.warningHandler == nil {
+		return
+	}
+
+	c.warn(adminCertsWarnings(path, msp, c.channelCapabilityLevel())...)
+}
+
+// checkLegacyOrdererAddresses warns when the updated config sets the
+// global orderer addresses.
+func (c *ConfigTx) checkLegacyOrdererAddresses() {
+	if c == nil || c.options.warningHandler == nil {
+		return
+	}
+
+	if _, ok := c.updated.ChannelGroup.Values[OrdererAddressesKey]; !ok {
+		return
+	}
+
+	c.warn(legacyOrdererAddressesWarnings(c.channelCapabilityLevel())...)
+}
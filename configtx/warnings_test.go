@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestBuildWarnings(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.Orderer.OrdererType = orderer.ConsensusTypeKafka
+	profile.Orderer.Kafka = orderer.Kafka{Brokers: []string{"broker1:9092"}}
+	profile.Capabilities = []string{"V1_4_3"}
+	profile.Application.Capabilities = []string{"V2_0"}
+	for i, org := range profile.Application.Organizations {
+		profile.Application.Organizations[i].Policies = DefaultOrgPoliciesFor(org.MSP.Name)
+	}
+	profile.Application.Organizations[0].Policies[AdminsPolicyKey] = Policy{
+		Type: ImplicitMetaPolicyType,
+		Rule: "ALL Admins",
+	}
+
+	var warnings []Warning
+	_, err := NewApplicationChannelGenesisBlock(profile, "testchannel", WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).To(Equal([]Warning{
+		{
+			Path:        "/Channel/Orderer",
+			Message:     "the kafka consensus type is deprecated, use etcdraft instead",
+			Replacement: "the etcdraft consensus type, migrated to with OrdererGroup.MigrateToEtcdRaft while the orderer is in maintenance mode",
+		},
+		{
+			Path:    "/Channel/Application",
+			Message: "capability level V2_0 is newer than the channel capability level V1_4_3",
+		},
+		{
+			Path:    "/Channel/Application/Org1",
+			Message: "ImplicitMeta policy Admins 'ALL Admins' has no sub-groups to evaluate and is always satisfied",
+		},
+	}))
+}
+
+func TestBuildWarningsWithoutHandler(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.Orderer.OrdererType = orderer.ConsensusTypeKafka
+	profile.Orderer.Kafka = orderer.Kafka{Brokers: []string{"broker1:9092"}}
+
+	_, err := NewApplicationChannelGenesisBlock(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestEditWarnings(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup, capabilitiesValue([]string{"V1_4_3"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var warnings []Warning
+	c := New(&cb.Config{ChannelGroup: channelGroup}, WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+
+	err = c.Application().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().Organization("Org1").SetPolicy(AdminsPolicyKey, "Readers", Policy{
+		Type: ImplicitMetaPolicyType,
+		Rule: "ANY Readers",
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(warnings).To(Equal([]Warning{
+		{
+			Path:    "/Channel/Application",
+			Message: "capability level V2_0 is newer than the channel capability level V1_4_3",
+		},
+		{
+			Path:    "/Channel/Application/Org1",
+			Message: "ImplicitMeta policy Readers 'ANY Readers' has no sub-groups to evaluate and can never be satisfied",
+		},
+	}))
+
+	warnings = nil
+	err = c.Channel().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).To(BeEmpty())
+}
+
+func TestCapabilityLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		capabilities  []string
+		expectedLevel []int
+	}{
+		{capabilities: nil, expectedLevel: nil},
+		{capabilities: []string{"V1_4_2", "V1_3"}, expectedLevel: []int{1, 4, 2}},
+		{capabilities: []string{"V1_4_3", "V2_0"}, expectedLevel: []int{2, 0}},
+		{capabilities: []string{"Vfoo", "bar", "V1_1"}, expectedLevel: []int{1, 1}},
+	}
+
+	for _, tc := range tests {
+		gt := NewGomegaWithT(t)
+		gt.Expect(capabilityLevel(tc.capabilities)).To(Equal(tc.expectedLevel))
+	}
+}
+
+func TestDeprecationWarnings(t *testing.T) {
+	t.Parallel()
+
+	adminCertsWarning := Warning{
+		Path:        "/Channel/Application/Org1",
+		Message:     "admin certificates are deprecated for MSPs that enable NodeOUs with an admin OU",
+		Replacement: "admin identities carrying the admin OU; remove the admin certificates with OrganizationMSP.RemoveAdminCert",
+	}
+	legacyAddressesWarning := Warning{
+		Path:        "/Channel",
+		Message:     "the global orderer addresses are deprecated",
+		Replacement: "orderer endpoints of each orderer org, set with OrdererGroup.AddOrdererEndpoint; remove the global addresses with ChannelGroup.RemoveLegacyOrdererAddresses",
+	}
+
+	tests := []struct {
+		name          string
+		capabilities  []string
+		buildWarnings []Warning
+		editWarnings  []Warning
+	}{
+		{
+			name:          "capability level supports the replacements",
+			capabilities:  []string{"V2_0"},
+			buildWarnings: []Warning{adminCertsWarning},
+			editWarnings: []Warning{
+				{
+					Path:        "/Channel/Application/Org2",
+					Message:     adminCertsWarning.Message,
+					Replacement: adminCertsWarning.Replacement,
+				},
+				legacyAddressesWarning,
+			},
+		},
+		{
+			name:          "capability level supports orderer endpoints only",
+			capabilities:  []string{"V1_4_2"},
+			buildWarnings: nil,
+			editWarnings:  []Warning{legacyAddressesWarning},
+		},
+		{
+			name:          "capability level supports neither replacement",
+			capabilities:  []string{"V1_3"},
+			buildWarnings: nil,
+			editWarnings:  nil,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			profile, _, _ := baseApplicationChannelProfile(t)
+			profile.Orderer.OrdererType = orderer.ConsensusTypeEtcdRaft
+			profile.Capabilities = tc.capabilities
+			profile.Application.Capabilities = nil
+			profile.Orderer.Capabilities = nil
+			profile.Application.Organizations[0].MSP.NodeOUs.Enable = true
+
+			var warnings []Warning
+			handler := WithWarningHandler(func(w Warning) {
+				if w.Deprecation() {
+					warnings = append(warnings, w)
+				}
+			})
+
+			etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+			profile.Orderer.EtcdRaft = etcdRaftOrderer.EtcdRaft
+
+			block, err := NewApplicationChannelGenesisBlock(profile, "testchannel", handler)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(warnings).To(Equal(tc.buildWarnings))
+
+			config, err := ConfigFromBlock(block)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			warnings = nil
+			c := New(config, handler)
+
+			err = c.Application().Organization("Org2").MSP().SetEnableNodeOUs(true)
+			gt.Expect(err).NotTo(HaveOccurred())
+			err = c.Channel().AddLegacyOrdererAddress("127.0.0.1:7050")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(warnings).To(Equal(tc.editWarnings))
+		})
+	}
+}
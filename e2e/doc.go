@@ -0,0 +1,23 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package e2e validates the config updates built with this module against
+// a real Fabric ordering service. It starts a single etcdraft ordering
+// node in Docker, creates channels from genesis blocks built with
+// configtx, submits config updates and checks that the orderer accepts
+// or rejects them.
+//
+// The harness and its tests are only built with the e2e build tag and
+// require Docker with the compose plugin or docker-compose, the
+// hyperledger/fabric-orderer and hyperledger/fabric-tools images, and the
+// ports 7050 and 7053 to be free:
+//
+//	go test -tags e2e ./e2e/...
+//
+// The Fabric version of the images defaults to 2.3 and can be set with the
+// FABRIC_VERSION environment variable. Tests are skipped when Docker is not
+// available.
+package e2e
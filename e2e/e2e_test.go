@@ -0,0 +1,96 @@
+// +build e2e
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package e2e
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-config/broadcast"
+	"github.com/hyperledger/fabric-config/configtx"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigUpdates(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	n := Start(t)
+
+	channelID := "e2echannel"
+	n.CreateChannel(t, channelID, n.ChannelConfig(t))
+
+	t.Run("accepted update", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		c := configtx.New(n.Config(t, channelID))
+		err := c.Orderer().BatchSize().SetMaxMessageCount(20)
+		gt.Expect(err).NotTo(HaveOccurred())
+		err = c.Application().Organization("Org1MSP").AddAnchorPeer(configtx.Address{Host: "peer0.org1.example.com", Port: 7051})
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		env := signedUpdate(t, n, c, channelID, "OrdererMSP", "Org1MSP")
+		err = n.Submit(t, env, n.Admin(t, "Org1MSP"))
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		updated := configtx.New(n.WaitForSequence(t, channelID, c.OriginalConfig().Sequence+1))
+		ordererConfig, err := updated.Orderer().Configuration()
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(ordererConfig.BatchSize.MaxMessageCount).To(Equal(uint32(20)))
+		anchorPeers, err := updated.Application().Organization("Org1MSP").AnchorPeers()
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(anchorPeers).To(ConsistOf(configtx.Address{Host: "peer0.org1.example.com", Port: 7051}))
+	})
+
+	t.Run("rejected update", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		c := configtx.New(n.Config(t, channelID))
+		err := c.Orderer().BatchSize().SetMaxMessageCount(30)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		// The orderer group can only be modified by the orderer admins.
+		env := signedUpdate(t, n, c, channelID, "Org1MSP")
+		err = n.Submit(t, env, n.Admin(t, "Org1MSP"))
+
+		var statusErr *broadcast.StatusError
+		gt.Expect(errors.As(err, &statusErr)).To(BeTrue(), "expected status error, got %v", err)
+		gt.Expect(statusErr.Status).To(BeElementOf(cb.Status_BAD_REQUEST, cb.Status_FORBIDDEN))
+
+		config := n.Config(t, channelID)
+		gt.Expect(config.Sequence).To(Equal(c.OriginalConfig().Sequence))
+	})
+
+	gt.Expect(n.Config(t, channelID).Sequence).To(Equal(uint64(1)))
+}
+
+// signedUpdate returns the envelope of the config update of c, signed by
+// the admins of the MSP IDs.
+func signedUpdate(t *testing.T, n *Network, c configtx.ConfigTx, channelID string, mspIDs ...string) *cb.Envelope {
+	marshaledUpdate, err := c.ComputeMarshaledUpdate(channelID)
+	if err != nil {
+		t.Fatalf("computing config update: %s", err)
+	}
+
+	var signatures []*cb.ConfigSignature
+	for _, mspID := range mspIDs {
+		signature, err := n.Admin(t, mspID).CreateConfigSignature(marshaledUpdate)
+		if err != nil {
+			t.Fatalf("signing config update as %s: %s", mspID, err)
+		}
+		signatures = append(signatures, signature)
+	}
+
+	env, err := configtx.NewEnvelope(marshaledUpdate, signatures...)
+	if err != nil {
+		t.Fatalf("creating envelope: %s", err)
+	}
+
+	return env
+}
@@ -0,0 +1,373 @@
+// +build e2e
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package e2e
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-config/broadcast"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/osnadmin"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// ordererHost is the host name of the ordering node on the Docker
+	// network, which its TLS certificate is issued for.
+	ordererHost = "orderer.example.com"
+	// ordererAddress and ordererAdminURL are the endpoints of the ordering
+	// node published on the Docker host.
+	ordererAddress  = "localhost:7050"
+	ordererAdminURL = "https://localhost:7053"
+
+	ordererOrgDir = "organizations/ordererOrganizations/example.com"
+
+	// startTimeout bounds how long the harness waits for the ordering
+	// node to accept requests and for blocks to be committed.
+	startTimeout = time.Minute
+)
+
+// Network is a Fabric network with a single etcdraft ordering node running
+// in Docker. Its crypto material is generated with cryptogen in the layout
+// of the fabric-samples test network, with the orderer organization
+// OrdererMSP and the peer organizations Org1MSP and Org2MSP, which have no
+// peers.
+type Network struct {
+	// Dir contains the crypto material of the network.
+	Dir string
+	// Organizations are the organizations and admin identities of the
+	// network.
+	Organizations *configtx.TestNetwork
+
+	compose    []string
+	tlsCACerts *x509.CertPool
+	adminCert  tls.Certificate
+	conn       *grpc.ClientConn
+}
+
+// Start generates the crypto material of a network and starts its ordering
+// node. The network is torn down when the test completes. The test is
+// skipped if Docker is not available.
+func Start(t *testing.T) *Network {
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "fabric-config-e2e")
+	if err != nil {
+		t.Fatalf("creating network directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatalf("locating testdata: %s", err)
+	}
+
+	n := &Network{Dir: dir}
+
+	run(t, "docker", "run", "--rm",
+		"--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()),
+		"-v", testdata+":/testdata:ro",
+		"-v", dir+":/network",
+		"hyperledger/fabric-tools:"+fabricVersion(),
+		"cryptogen", "generate", "--config=/testdata/crypto-config.yaml", "--output=/network/organizations",
+	)
+
+	n.Organizations, err = configtx.LoadTestNetwork(dir)
+	if err != nil {
+		t.Fatalf("loading organizations: %s", err)
+	}
+
+	n.tlsCACerts = x509.NewCertPool()
+	caCert, err := ioutil.ReadFile(filepath.Join(dir, ordererOrgDir, "tlsca", "tlsca.example.com-cert.pem"))
+	if err != nil {
+		t.Fatalf("reading orderer TLS CA certificate: %s", err)
+	}
+	n.tlsCACerts.AppendCertsFromPEM(caCert)
+
+	adminTLSDir := filepath.Join(dir, ordererOrgDir, "users", "Admin@example.com", "tls")
+	n.adminCert, err = tls.LoadX509KeyPair(filepath.Join(adminTLSDir, "client.crt"), filepath.Join(adminTLSDir, "client.key"))
+	if err != nil {
+		t.Fatalf("loading orderer admin TLS certificate: %s", err)
+	}
+
+	n.compose = composeCommand(t, filepath.Join(testdata, "docker-compose.yaml"), "fabric-config-e2e-"+strconv.Itoa(os.Getpid()))
+	t.Cleanup(func() { n.runCompose(t, "down", "--volumes") })
+	n.runCompose(t, "up", "-d")
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	n.conn, err = grpc.DialContext(ctx, ordererAddress,
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:    n.tlsCACerts,
+			ServerName: ordererHost,
+		})),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		t.Fatalf("connecting to orderer: %s", err)
+	}
+	t.Cleanup(func() { n.conn.Close() })
+
+	return n
+}
+
+// Admin returns the signing identity of the admin of the organization
+// with the MSP ID, e.g. OrdererMSP or Org1MSP.
+func (n *Network) Admin(t *testing.T, mspID string) *configtx.SigningIdentity {
+	admin, ok := n.Organizations.Admins[mspID]
+	if !ok {
+		t.Fatalf("no admin identity for %s", mspID)
+	}
+
+	return admin
+}
+
+// ChannelConfig returns the configuration of a channel with the
+// organizations of the network: the fabric-samples two org channel whose
+// etcdraft consenter is the ordering node of the network.
+func (n *Network) ChannelConfig(t *testing.T) configtx.Channel {
+	serverCert := readCertificate(t, filepath.Join(n.Dir, ordererOrgDir, "orderers", ordererHost, "tls", "server.crt"))
+
+	channel := configtx.DefaultTwoOrgChannel()
+	channel.Consortium = ""
+	channel.Application.Organizations = n.Organizations.PeerOrganizations
+	channel.Orderer.Organizations[0].MSP = n.Organizations.OrdererOrganization.MSP
+	channel.Orderer.EtcdRaft.Consenters = []orderer.Consenter{{
+		Address:       orderer.EtcdAddress{Host: ordererHost, Port: 7050},
+		ClientTLSCert: serverCert,
+		ServerTLSCert: serverCert,
+	}}
+
+	return channel
+}
+
+// CreateChannel joins the ordering node to a new channel created from the
+// genesis block of the channel config.
+func (n *Network) CreateChannel(t *testing.T, channelID string, channel configtx.Channel) {
+	block, err := configtx.NewApplicationChannelGenesisBlock(channel, channelID)
+	if err != nil {
+		t.Fatalf("creating genesis block of channel %s: %s", channelID, err)
+	}
+
+	client := osnadmin.NewClient(ordererAdminURL, n.tlsCACerts, n.adminCert)
+
+	deadline := time.Now().Add(startTimeout)
+	for {
+		_, err = client.Join(block)
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		t.Fatalf("joining orderer to channel %s: %s", channelID, err)
+	}
+
+	n.WaitForSequence(t, channelID, 0)
+}
+
+// Submit signs the config update envelope with the identity, which must
+// satisfy the Writers policy of the channel, and submits it to the
+// ordering node. It returns the error of the submission, e.g. a
+// broadcast.StatusError if the orderer rejects the update.
+func (n *Network) Submit(t *testing.T, env *cb.Envelope, signer *configtx.SigningIdentity) error {
+	err := signer.SignEnvelope(env)
+	if err != nil {
+		t.Fatalf("signing envelope: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	client := broadcast.NewClient(broadcast.FromAtomicBroadcastClient(ob.NewAtomicBroadcastClient(n.conn)))
+
+	return client.Submit(ctx, env)
+}
+
+// Config returns the config of the newest block of the channel, which the
+// harness expects to be a config block as only config updates are
+// submitted.
+func (n *Network) Config(t *testing.T, channelID string) *cb.Config {
+	block, err := n.newestBlock(channelID)
+	if err != nil {
+		t.Fatalf("retrieving newest block of channel %s: %s", channelID, err)
+	}
+
+	config, err := configtx.ConfigFromBlock(block)
+	if err != nil {
+		t.Fatalf("extracting config of channel %s: %s", channelID, err)
+	}
+
+	return config
+}
+
+// WaitForSequence waits until the config of the channel reaches the
+// sequence and returns it.
+func (n *Network) WaitForSequence(t *testing.T, channelID string, sequence uint64) *cb.Config {
+	deadline := time.Now().Add(startTimeout)
+	for {
+		block, err := n.newestBlock(channelID)
+		if err == nil {
+			config, err := configtx.ConfigFromBlock(block)
+			if err == nil && config.Sequence >= sequence {
+				return config
+			}
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("channel %s did not reach config sequence %d", channelID, sequence)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// newestBlock retrieves the newest block of the channel from the
+// ordering node, as the admin of Org1.
+func (n *Network) newestBlock(channelID string) (*cb.Block, error) {
+	newest := &ob.SeekPosition{Type: &ob.SeekPosition_Newest{Newest: &ob.SeekNewest{}}}
+	seekInfo := &ob.SeekInfo{
+		Start:    newest,
+		Stop:     newest,
+		Behavior: ob.SeekInfo_BLOCK_UNTIL_READY,
+	}
+
+	env, err := configtx.NewEnvelopeOfType(cb.HeaderType_DELIVER_SEEK_INFO, channelID, seekInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	admin, ok := n.Organizations.Admins["Org1MSP"]
+	if !ok {
+		return nil, errors.New("no admin identity for Org1MSP")
+	}
+	err = admin.SignEnvelope(env)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := ob.NewAtomicBroadcastClient(n.conn).Deliver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var block *cb.Block
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		switch r := resp.Type.(type) {
+		case *ob.DeliverResponse_Block:
+			block = r.Block
+		case *ob.DeliverResponse_Status:
+			if r.Status != cb.Status_SUCCESS {
+				return nil, fmt.Errorf("deliver responded with status %s", r.Status)
+			}
+			if block == nil {
+				return nil, errors.New("deliver did not return a block")
+			}
+			return block, nil
+		}
+	}
+}
+
+// runCompose runs docker compose with the compose file of the network.
+func (n *Network) runCompose(t *testing.T, args ...string) {
+	cmd := append(append([]string{}, n.compose...), args...)
+	runEnv(t, []string{
+		"E2E_ORGANIZATIONS_DIR=" + filepath.Join(n.Dir, "organizations"),
+		"FABRIC_VERSION=" + fabricVersion(),
+	}, cmd[0], cmd[1:]...)
+}
+
+// composeCommand returns the docker compose command for the compose file
+// and project, using the compose plugin if it is installed and
+// docker-compose otherwise.
+func composeCommand(t *testing.T, file, project string) []string {
+	if exec.Command("docker", "compose", "version").Run() == nil {
+		return []string{"docker", "compose", "-f", file, "-p", project}
+	}
+
+	if _, err := exec.LookPath("docker-compose"); err != nil {
+		t.Skip("docker compose is not available")
+	}
+
+	return []string{"docker-compose", "-f", file, "-p", project}
+}
+
+// fabricVersion returns the version of the Fabric images to run.
+func fabricVersion() string {
+	if version := os.Getenv("FABRIC_VERSION"); version != "" {
+		return version
+	}
+
+	return "2.3"
+}
+
+func run(t *testing.T, name string, args ...string) {
+	runEnv(t, nil, name, args...)
+}
+
+func runEnv(t *testing.T, env []string, name string, args ...string) {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("running %s %v: %s\n%s", name, args, err, output)
+	}
+}
+
+func readCertificate(t *testing.T, file string) *x509.Certificate {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading %s: %s", file, err)
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		t.Fatalf("no PEM data found in %s", file)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("parsing %s: %s", file, err)
+	}
+
+	return cert
+}
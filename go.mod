@@ -7,4 +7,7 @@ require (
 	github.com/golang/protobuf v1.3.3
 	github.com/hyperledger/fabric-protos-go v0.0.0-20200424173316-dd554ba3746e
 	github.com/onsi/gomega v1.9.0
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a
+	google.golang.org/grpc v1.23.0
+	gopkg.in/yaml.v2 v2.2.4
 )
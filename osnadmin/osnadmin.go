@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package osnadmin provides a client for the channel participation API of
+// an ordering service node (OSN), which is used to join ordering nodes to
+// channels and to remove them from channels.
+package osnadmin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// channelsPath is the path of the channel participation API.
+const channelsPath = "/participation/v1/channels"
+
+// ChannelInfo is the status of a channel on an ordering service node.
+type ChannelInfo struct {
+	Name              string `json:"name"`
+	URL               string `json:"url"`
+	ConsensusRelation string `json:"consensusRelation"`
+	Status            string `json:"status"`
+	Height            uint64 `json:"height"`
+}
+
+// Client calls the channel participation API of an ordering service node.
+type Client struct {
+	// OSNURL is the base URL of the node's admin endpoint,
+	// e.g. https://orderer.example.com:7053.
+	OSNURL string
+	// HTTPClient is used to send requests to the node.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for the admin endpoint at osnURL that
+// authenticates with the TLS client certificate and trusts the CA
+// certificates in caCertPool.
+func NewClient(osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) *Client {
+	return &Client{
+		OSNURL: osnURL,
+		HTTPClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:      caCertPool,
+					Certificates: []tls.Certificate{tlsClientCert},
+				},
+			},
+		},
+	}
+}
+
+// NewJoinRequest returns the request that joins the node at osnURL to the
+// channel defined by the config block.
+func NewJoinRequest(osnURL string, configBlock *cb.Block) (*http.Request, error) {
+	if configBlock == nil {
+		return nil, errors.New("config block is required")
+	}
+
+	blockBytes, err := proto.Marshal(configBlock)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling config block: %w", err)
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("config-block", "config.block")
+	if err != nil {
+		return nil, fmt.Errorf("creating form file: %w", err)
+	}
+
+	_, err = part.Write(blockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("writing config block: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, channelsURL(osnURL), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req, nil
+}
+
+// NewRemoveRequest returns the request that removes the channel from the
+// node at osnURL.
+func NewRemoveRequest(osnURL, channelID string) (*http.Request, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	return http.NewRequest(http.MethodDelete, channelsURL(osnURL)+"/"+channelID, nil)
+}
+
+// Join joins the node to the channel defined by the config block and
+// returns the channel's status on the node.
+func (c *Client) Join(configBlock *cb.Block) (ChannelInfo, error) {
+	req, err := NewJoinRequest(c.OSNURL, configBlock)
+	if err != nil {
+		return ChannelInfo{}, err
+	}
+
+	resp, err := c.do(req, http.StatusCreated)
+	if err != nil {
+		return ChannelInfo{}, fmt.Errorf("joining channel: %w", err)
+	}
+	defer resp.Body.Close()
+
+	channelInfo := ChannelInfo{}
+	err = json.NewDecoder(resp.Body).Decode(&channelInfo)
+	if err != nil {
+		return ChannelInfo{}, fmt.Errorf("decoding channel info: %w", err)
+	}
+
+	return channelInfo, nil
+}
+
+// Remove removes the channel from the node.
+func (c *Client) Remove(channelID string) error {
+	req, err := NewRemoveRequest(c.OSNURL, channelID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, http.StatusNoContent)
+	if err != nil {
+		return fmt.Errorf("removing channel %s: %w", channelID, err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// do sends the request and returns an error describing the response if its
+// status code is not the expected one.
+func (c *Client) do(req *http.Request, expectedStatus int) (*http.Response, error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != expectedStatus {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+
+	return resp, nil
+}
+
+// responseError returns the error reported in the body of a failed
+// response, falling back to the response status.
+func responseError(resp *http.Response) error {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err == nil {
+		errorResponse := struct {
+			Error string `json:"error"`
+		}{}
+		if json.Unmarshal(body, &errorResponse) == nil && errorResponse.Error != "" {
+			return fmt.Errorf("%s: %s", resp.Status, errorResponse.Error)
+		}
+	}
+
+	return errors.New(resp.Status)
+}
+
+func channelsURL(osnURL string) string {
+	return strings.TrimSuffix(osnURL, "/") + channelsPath
+}
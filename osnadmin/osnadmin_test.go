@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	block := &cb.Block{Header: &cb.BlockHeader{Number: 0}, Data: &cb.BlockData{Data: [][]byte{[]byte("config")}}}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Expect(r.Method).To(Equal(http.MethodPost))
+		gt.Expect(r.URL.Path).To(Equal("/participation/v1/channels"))
+
+		file, _, err := r.FormFile("config-block")
+		gt.Expect(err).NotTo(HaveOccurred())
+		blockBytes, err := ioutil.ReadAll(file)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(blockBytes).To(Equal(marshalOrPanic(block)))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"mychannel","url":"/participation/v1/channels/mychannel","consensusRelation":"consenter","status":"onboarding","height":0}`))
+	}))
+	defer server.Close()
+
+	caCertPool := x509.NewCertPool()
+	caCertPool.AddCert(server.Certificate())
+	client := NewClient(server.URL, caCertPool, tls.Certificate{})
+
+	channelInfo, err := client.Join(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelInfo).To(Equal(ChannelInfo{
+		Name:              "mychannel",
+		URL:               "/participation/v1/channels/mychannel",
+		ConsensusRelation: "consenter",
+		Status:            "onboarding",
+	}))
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gt.Expect(r.Method).To(Equal(http.MethodDelete))
+		gt.Expect(r.URL.Path).To(Equal("/participation/v1/channels/mychannel"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &Client{OSNURL: server.URL + "/"}
+
+	err := client.Remove("mychannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestClientFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		status      int
+		body        string
+		call        func(c *Client) error
+		expectedErr string
+	}{
+		{
+			testName: "when removing a channel that does not exist",
+			status:   http.StatusNotFound,
+			body:     `{"error":"cannot remove: channel does not exist"}`,
+			call: func(c *Client) error {
+				return c.Remove("mychannel")
+			},
+			expectedErr: "removing channel mychannel: 404 Not Found: cannot remove: channel does not exist",
+		},
+		{
+			testName: "when the error response is not JSON",
+			status:   http.StatusInternalServerError,
+			body:     "boom",
+			call: func(c *Client) error {
+				return c.Remove("mychannel")
+			},
+			expectedErr: "removing channel mychannel: 500 Internal Server Error",
+		},
+		{
+			testName: "when the channel ID is missing",
+			call: func(c *Client) error {
+				return c.Remove("")
+			},
+			expectedErr: "channel ID is required",
+		},
+		{
+			testName: "when joining fails",
+			status:   http.StatusMethodNotAllowed,
+			body:     `{"error":"cannot join: system channel exists"}`,
+			call: func(c *Client) error {
+				_, err := c.Join(&cb.Block{})
+				return err
+			},
+			expectedErr: "joining channel: 405 Method Not Allowed: cannot join: system channel exists",
+		},
+		{
+			testName: "when the config block is missing",
+			call: func(c *Client) error {
+				_, err := c.Join(nil)
+				return err
+			},
+			expectedErr: "config block is required",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			err := tc.call(&Client{OSNURL: server.URL})
+			gt.Expect(err).To(MatchError(tc.expectedErr))
+		})
+	}
+}
+
+func marshalOrPanic(msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -94,7 +94,7 @@ func (pf *plainField) PopulateFrom(source interface{}) error {
 	}
 	value, err := pf.populateFrom(source, pf.vType)
 	if err != nil {
-		return fmt.Errorf("error in PopulateFrom for field %s for message %T: %s", pf.name, pf.msg, err)
+		return fmt.Errorf("error in PopulateFrom for field %s for message %T: %w", pf.name, pf.msg, err)
 	}
 	pf.value.Set(value)
 	return nil
@@ -114,7 +114,7 @@ func (pf *plainField) PopulateTo() (interface{}, error) {
 
 	value, err := pf.populateTo(pf.value)
 	if err != nil {
-		return nil, fmt.Errorf("error in PopulateTo for field %s for message %T: %s", pf.name, pf.msg, err)
+		return nil, fmt.Errorf("error in PopulateTo for field %s for message %T: %w", pf.name, pf.msg, err)
 	}
 	return value, nil
 }
@@ -139,7 +139,7 @@ func (mf *mapField) PopulateFrom(source interface{}) error {
 		}
 		newValue, err := mf.populateFrom(k, v, mf.vType.Elem())
 		if err != nil {
-			return fmt.Errorf("error in PopulateFrom for map field %s with key %s for message %T: %s", mf.name, k, mf.msg, err)
+			return fmt.Errorf("error in PopulateFrom for map field %s with key %s for message %T: %w", mf.name, k, mf.msg, err)
 		}
 		result.SetMapIndex(reflect.ValueOf(k), newValue)
 	}
@@ -169,7 +169,7 @@ func (mf *mapField) PopulateTo() (interface{}, error) {
 
 		value, err := mf.populateTo(k, subValue)
 		if err != nil {
-			return nil, fmt.Errorf("error in PopulateTo for map field %s and key %s for message %T: %s", mf.name, k, mf.msg, err)
+			return nil, fmt.Errorf("error in PopulateTo for map field %s and key %s for message %T: %w", mf.name, k, mf.msg, err)
 		}
 		result[k] = value
 	}
@@ -197,7 +197,7 @@ func (sf *sliceField) PopulateFrom(source interface{}) error {
 		}
 		subValue, err := sf.populateFrom(i, v, sf.vType.Elem())
 		if err != nil {
-			return fmt.Errorf("error in PopulateFrom for slice field %s at index %d for message %T: %s", sf.name, i, sf.msg, err)
+			return fmt.Errorf("error in PopulateFrom for slice field %s at index %d for message %T: %w", sf.name, i, sf.msg, err)
 		}
 		result.Index(i).Set(subValue)
 	}
@@ -221,7 +221,7 @@ func (sf *sliceField) PopulateTo() (interface{}, error) {
 
 		value, err := sf.populateTo(i, subValue)
 		if err != nil {
-			return nil, fmt.Errorf("error in PopulateTo for slice field %s at index %d for message %T: %s", sf.name, i, sf.msg, err)
+			return nil, fmt.Errorf("error in PopulateTo for slice field %s at index %d for message %T: %w", sf.name, i, sf.msg, err)
 		}
 		result[i] = value
 	}
@@ -274,7 +274,7 @@ func jsonToMap(marshaled []byte) (map[string]interface{}, error) {
 	d.UseNumber()
 	err := d.Decode(&tree)
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshaling intermediate JSON: %s", err)
+		return nil, fmt.Errorf("error unmarshaling intermediate JSON: %w", err)
 	}
 	return tree, nil
 }
@@ -356,7 +356,7 @@ func recursivelyCreateTreeFromMessage(msg proto.Message) (tree map[string]interf
 		// Because this function is recursive, it's difficult to determine which level
 		// of the proto the error originated from, this wrapper leaves breadcrumbs for debugging
 		if err != nil {
-			err = fmt.Errorf("%T: %s", msg, err)
+			err = fmt.Errorf("%T: %w", msg, err)
 		}
 	}()
 
@@ -415,7 +415,7 @@ func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.M
 		// Because this function is recursive, it's difficult to determine which level
 		// of the proto the error orginated from, this wrapper leaves breadcrumbs for debugging
 		if err != nil {
-			err = fmt.Errorf("%T: %s", msg, err)
+			err = fmt.Errorf("%T: %w", msg, err)
 		}
 	}()
 
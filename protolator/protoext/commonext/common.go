@@ -51,7 +51,7 @@ func (p *Payload) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
 	}
 	ch := &common.ChannelHeader{}
 	if err := proto.Unmarshal(p.Header.ChannelHeader, ch); err != nil {
-		return nil, fmt.Errorf("corrupt channel header: %s", err)
+		return nil, fmt.Errorf("corrupt channel header: %w", err)
 	}
 
 	switch ch.Type {
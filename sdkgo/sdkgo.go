@@ -0,0 +1,95 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package sdkgo converts between the outputs of the configtx package and
+// the inputs and outputs of the resource management client of
+// fabric-sdk-go (pkg/client/resmgmt).
+//
+// Both libraries use the protos of fabric-protos-go, so config signatures
+// and blocks can be passed between them directly. The helpers here cover
+// the channel config transactions, which resmgmt reads as an io.Reader over
+// a marshaled envelope while configtx works with marshaled config updates.
+package sdkgo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// ChannelConfig wraps a marshaled config update, e.g. the output of
+// configtx.NewMarshaledCreateChannelTx or ConfigTx.ComputeMarshaledUpdate,
+// and its signatures in a config update envelope. The returned reader can
+// be used as the ChannelConfig of a resmgmt.SaveChannelRequest.
+func ChannelConfig(marshaledUpdate []byte, signatures ...*cb.ConfigSignature) (io.Reader, error) {
+	envelope, err := configtx.NewEnvelope(marshaledUpdate, signatures...)
+	if err != nil {
+		return nil, fmt.Errorf("creating envelope: %w", err)
+	}
+
+	envelopeBytes, err := proto.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling envelope: %w", err)
+	}
+
+	return bytes.NewReader(envelopeBytes), nil
+}
+
+// MarshaledUpdate returns the marshaled config update of a channel config
+// transaction as read by resmgmt, e.g. a channel transaction file created
+// by configtxgen. The config update can be signed using
+// configtx.SigningIdentity.CreateConfigSignature.
+func MarshaledUpdate(channelConfig io.Reader) ([]byte, error) {
+	configUpdateEnvelope, err := readConfigUpdateEnvelope(channelConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return configUpdateEnvelope.ConfigUpdate, nil
+}
+
+// ConfigSignatures returns the signatures of a channel config transaction
+// as read by resmgmt. They can be passed to configtx.NewEnvelope or to
+// resmgmt.WithConfigSignatures.
+func ConfigSignatures(channelConfig io.Reader) ([]*cb.ConfigSignature, error) {
+	configUpdateEnvelope, err := readConfigUpdateEnvelope(channelConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return configUpdateEnvelope.Signatures, nil
+}
+
+// ConfigTx returns a config transaction for the config in a config block,
+// e.g. the block returned by resmgmt's QueryConfigBlockFromOrderer.
+func ConfigTx(block *cb.Block, opts ...configtx.Option) (configtx.ConfigTx, error) {
+	config, err := configtx.ConfigFromBlock(block)
+	if err != nil {
+		return configtx.ConfigTx{}, fmt.Errorf("extracting config from block: %w", err)
+	}
+
+	return configtx.New(config, opts...), nil
+}
+
+func readConfigUpdateEnvelope(channelConfig io.Reader) (*cb.ConfigUpdateEnvelope, error) {
+	envelopeBytes, err := ioutil.ReadAll(channelConfig)
+	if err != nil {
+		return nil, fmt.Errorf("reading channel config: %w", err)
+	}
+
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(envelopeBytes, envelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling envelope: %w", err)
+	}
+
+	return configtx.ConfigUpdateEnvelopeFromEnvelope(envelope)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sdkgo
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestChannelConfigRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	marshaledUpdate, err := proto.Marshal(&cb.ConfigUpdate{ChannelId: "testchannel"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signature := &cb.ConfigSignature{
+		SignatureHeader: []byte("signature-header"),
+		Signature:       []byte("signature"),
+	}
+
+	channelConfig, err := ChannelConfig(marshaledUpdate, signature)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	envelopeBytes := &bytes.Buffer{}
+	_, err = envelopeBytes.ReadFrom(channelConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	update, err := MarshaledUpdate(bytes.NewReader(envelopeBytes.Bytes()))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(update).To(Equal(marshaledUpdate))
+
+	signatures, err := ConfigSignatures(bytes.NewReader(envelopeBytes.Bytes()))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signatures).To(HaveLen(1))
+	gt.Expect(proto.Equal(signatures[0], signature)).To(BeTrue())
+}
+
+func TestChannelConfigFailures(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	_, err := ChannelConfig([]byte("not a config update"))
+	gt.Expect(err).To(MatchError(ContainSubstring("creating envelope: unmarshaling config update")))
+}
+
+func TestMarshaledUpdateFailures(t *testing.T) {
+	t.Parallel()
+
+	configEnvelope, err := proto.Marshal(&cb.Envelope{
+		Payload: protoMarshal(t, &cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoMarshal(t, &cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG)}),
+			},
+		}),
+	})
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		testName      string
+		channelConfig []byte
+		expectedErr   string
+	}{
+		{
+			testName:      "when the channel config is not an envelope",
+			channelConfig: []byte("not an envelope"),
+			expectedErr:   "unmarshaling envelope",
+		},
+		{
+			testName:      "when the payload header is missing",
+			channelConfig: protoMarshal(t, &cb.Envelope{}),
+			expectedErr:   "payload header is missing",
+		},
+		{
+			testName:      "when the envelope is not a config update",
+			channelConfig: configEnvelope,
+			expectedErr:   "envelope has header type CONFIG, not CONFIG_UPDATE",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			_, err := MarshaledUpdate(bytes.NewReader(tc.channelConfig))
+			gt.Expect(err).To(MatchError(ContainSubstring(tc.expectedErr)))
+		})
+	}
+}
+
+func TestConfigTx(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	config := &cb.Config{
+		Sequence:     3,
+		ChannelGroup: &cb.ConfigGroup{Version: 1},
+	}
+
+	block := &cb.Block{
+		Data: &cb.BlockData{
+			Data: [][]byte{
+				protoMarshal(t, &cb.Envelope{
+					Payload: protoMarshal(t, &cb.Payload{
+						Data: protoMarshal(t, &cb.ConfigEnvelope{Config: config}),
+					}),
+				}),
+			},
+		},
+	}
+
+	c, err := ConfigTx(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(c.OriginalConfig(), config)).To(BeTrue())
+
+	_, err = ConfigTx(&cb.Block{})
+	gt.Expect(err).To(MatchError("extracting config from block: block contains no data"))
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	gt := NewGomegaWithT(t)
+
+	b, err := proto.Marshal(msg)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return b
+}
@@ -0,0 +1,185 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+)
+
+// DeliverStream is a Deliver stream to an ordering service node. The
+// stream returned by the gRPC AtomicBroadcast client implements it.
+type DeliverStream interface {
+	Send(*cb.Envelope) error
+	Recv() (*ob.DeliverResponse, error)
+	CloseSend() error
+}
+
+// Deliverer opens Deliver streams to an ordering service node.
+type Deliverer interface {
+	Deliver(ctx context.Context) (DeliverStream, error)
+}
+
+// DelivererFunc is a function that implements Deliverer.
+type DelivererFunc func(ctx context.Context) (DeliverStream, error)
+
+// Deliver calls f(ctx).
+func (f DelivererFunc) Deliver(ctx context.Context) (DeliverStream, error) {
+	return f(ctx)
+}
+
+// FromAtomicBroadcastClient returns a Deliverer that opens streams with
+// the gRPC client, e.g. one created with ob.NewAtomicBroadcastClient.
+func FromAtomicBroadcastClient(client ob.AtomicBroadcastClient) Deliverer {
+	return DelivererFunc(func(ctx context.Context) (DeliverStream, error) {
+		return client.Deliver(ctx)
+	})
+}
+
+// NewDeliverFetcher returns a Fetcher that fetches config blocks from an
+// ordering service node with the Deliver service: it fetches the newest
+// block of the channel, reads the index of the last config block from its
+// metadata and fetches that block. The seek requests are signed by the
+// signer, which must be allowed to read the channel.
+func NewDeliverFetcher(deliverer Deliverer, signer *configtx.SigningIdentity) Fetcher {
+	return FetcherFunc(func(ctx context.Context, channelID string) (*cb.Block, error) {
+		newest, err := deliverBlock(ctx, deliverer, signer, channelID, &ob.SeekPosition{
+			Type: &ob.SeekPosition_Newest{Newest: &ob.SeekNewest{}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetching newest block: %w", err)
+		}
+
+		index, err := lastConfigIndex(newest)
+		if err != nil {
+			return nil, err
+		}
+
+		if index == newest.GetHeader().GetNumber() {
+			return newest, nil
+		}
+
+		block, err := deliverBlock(ctx, deliverer, signer, channelID, &ob.SeekPosition{
+			Type: &ob.SeekPosition_Specified{Specified: &ob.SeekSpecified{Number: index}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetching config block %d: %w", index, err)
+		}
+
+		return block, nil
+	})
+}
+
+// deliverBlock fetches the block at the position from the channel.
+func deliverBlock(ctx context.Context, deliverer Deliverer, signer *configtx.SigningIdentity, channelID string, position *ob.SeekPosition) (*cb.Block, error) {
+	if signer == nil {
+		return nil, errors.New("signer is required")
+	}
+
+	seekInfo := &ob.SeekInfo{
+		Start:    position,
+		Stop:     position,
+		Behavior: ob.SeekInfo_BLOCK_UNTIL_READY,
+	}
+
+	envelope, err := configtx.NewEnvelopeOfType(cb.HeaderType_DELIVER_SEEK_INFO, channelID, seekInfo)
+	if err != nil {
+		return nil, fmt.Errorf("creating seek envelope: %w", err)
+	}
+
+	err = signer.SignEnvelope(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("signing seek envelope: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := deliverer.Deliver(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("opening deliver stream: %w", err)
+	}
+	defer stream.CloseSend()
+
+	err = stream.Send(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("sending seek envelope: %w", err)
+	}
+
+	var block *cb.Block
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("receiving block: %w", err)
+		}
+
+		switch t := resp.Type.(type) {
+		case *ob.DeliverResponse_Block:
+			block = t.Block
+		case *ob.DeliverResponse_Status:
+			if t.Status != cb.Status_SUCCESS {
+				return nil, fmt.Errorf("orderer responded with status %s", t.Status)
+			}
+			if block == nil {
+				return nil, errors.New("orderer did not deliver a block")
+			}
+			return block, nil
+		default:
+			return nil, fmt.Errorf("unexpected deliver response type %T", t)
+		}
+	}
+}
+
+// lastConfigIndex returns the index of the last config block recorded in
+// the metadata of the block: in the orderer block metadata of the
+// signatures metadata, or in the last config metadata written by older
+// orderers.
+func lastConfigIndex(block *cb.Block) (uint64, error) {
+	metadata := block.GetMetadata().GetMetadata()
+
+	if len(metadata) > int(cb.BlockMetadataIndex_SIGNATURES) && len(metadata[cb.BlockMetadataIndex_SIGNATURES]) > 0 {
+		m := &cb.Metadata{}
+		err := proto.Unmarshal(metadata[cb.BlockMetadataIndex_SIGNATURES], m)
+		if err != nil {
+			return 0, fmt.Errorf("unmarshaling signatures metadata: %w", err)
+		}
+
+		ordererMetadata := &cb.OrdererBlockMetadata{}
+		err = proto.Unmarshal(m.Value, ordererMetadata)
+		if err != nil {
+			return 0, fmt.Errorf("unmarshaling orderer block metadata: %w", err)
+		}
+
+		if ordererMetadata.LastConfig != nil {
+			return ordererMetadata.LastConfig.Index, nil
+		}
+	}
+
+	if len(metadata) > int(cb.BlockMetadataIndex_LAST_CONFIG) && len(metadata[cb.BlockMetadataIndex_LAST_CONFIG]) > 0 {
+		m := &cb.Metadata{}
+		err := proto.Unmarshal(metadata[cb.BlockMetadataIndex_LAST_CONFIG], m)
+		if err != nil {
+			return 0, fmt.Errorf("unmarshaling last config metadata: %w", err)
+		}
+
+		lastConfig := &cb.LastConfig{}
+		err = proto.Unmarshal(m.Value, lastConfig)
+		if err != nil {
+			return 0, fmt.Errorf("unmarshaling last config: %w", err)
+		}
+
+		return lastConfig.Index, nil
+	}
+
+	return 0, errors.New("block metadata does not contain the index of the last config block")
+}
@@ -0,0 +1,265 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ob "github.com/hyperledger/fabric-protos-go/orderer"
+	. "github.com/onsi/gomega"
+)
+
+// fakeDeliverStream responds to the seek envelope with responses, in
+// order, or fails with err when they are exhausted.
+type fakeDeliverStream struct {
+	responses []*ob.DeliverResponse
+	err       error
+	sent      []*cb.Envelope
+}
+
+func (s *fakeDeliverStream) Send(env *cb.Envelope) error {
+	s.sent = append(s.sent, env)
+	return nil
+}
+
+func (s *fakeDeliverStream) Recv() (*ob.DeliverResponse, error) {
+	if len(s.responses) == 0 {
+		return nil, s.err
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func (s *fakeDeliverStream) CloseSend() error {
+	return nil
+}
+
+// fakeDeliverer opens the streams, in order.
+type fakeDeliverer struct {
+	streams []*fakeDeliverStream
+	opened  int
+}
+
+func (d *fakeDeliverer) Deliver(ctx context.Context) (DeliverStream, error) {
+	if d.opened == len(d.streams) {
+		return nil, errors.New("no more streams")
+	}
+	stream := d.streams[d.opened]
+	d.opened++
+	return stream, nil
+}
+
+func TestDeliverFetcher(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName       string
+		newestMetadata [][]byte
+		expectedSeeks  []*ob.SeekPosition
+	}{
+		{
+			testName:       "when the newest block is the last config block",
+			newestMetadata: signaturesMetadata(t, 5),
+			expectedSeeks:  []*ob.SeekPosition{newestPosition()},
+		},
+		{
+			testName:       "when the orderer block metadata points at an older block",
+			newestMetadata: signaturesMetadata(t, 3),
+			expectedSeeks:  []*ob.SeekPosition{newestPosition(), specifiedPosition(3)},
+		},
+		{
+			testName:       "when only the last config metadata is set",
+			newestMetadata: lastConfigMetadata(t, 3),
+			expectedSeeks:  []*ob.SeekPosition{newestPosition(), specifiedPosition(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			newest := &cb.Block{
+				Header:   &cb.BlockHeader{Number: 5},
+				Metadata: &cb.BlockMetadata{Metadata: tt.newestMetadata},
+			}
+			config := &cb.Block{Header: &cb.BlockHeader{Number: 3}}
+
+			deliverer := &fakeDeliverer{
+				streams: []*fakeDeliverStream{
+					{responses: []*ob.DeliverResponse{blockResponse(newest), statusResponse(cb.Status_SUCCESS)}},
+					{responses: []*ob.DeliverResponse{blockResponse(config), statusResponse(cb.Status_SUCCESS)}},
+				},
+			}
+			fetcher := NewDeliverFetcher(deliverer, newSigningIdentity(t, "Org1MSP"))
+
+			block, err := fetcher.FetchConfigBlock(context.Background(), "testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(deliverer.opened).To(Equal(len(tt.expectedSeeks)))
+			if len(tt.expectedSeeks) == 1 {
+				gt.Expect(block).To(Equal(newest))
+			} else {
+				gt.Expect(block).To(Equal(config))
+			}
+
+			for i, position := range tt.expectedSeeks {
+				sent := deliverer.streams[i].sent
+				gt.Expect(sent).To(HaveLen(1))
+				gt.Expect(sent[0].Signature).NotTo(BeEmpty())
+
+				payload := &cb.Payload{}
+				err = proto.Unmarshal(sent[0].Payload, payload)
+				gt.Expect(err).NotTo(HaveOccurred())
+
+				channelHeader := &cb.ChannelHeader{}
+				err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+				gt.Expect(err).NotTo(HaveOccurred())
+				gt.Expect(channelHeader.Type).To(Equal(int32(cb.HeaderType_DELIVER_SEEK_INFO)))
+				gt.Expect(channelHeader.ChannelId).To(Equal("testchannel"))
+
+				seekInfo := &ob.SeekInfo{}
+				err = proto.Unmarshal(payload.Data, seekInfo)
+				gt.Expect(err).NotTo(HaveOccurred())
+				gt.Expect(proto.Equal(seekInfo.Start, position)).To(BeTrue())
+				gt.Expect(proto.Equal(seekInfo.Stop, position)).To(BeTrue())
+			}
+		})
+	}
+}
+
+func TestDeliverFetcherFailures(t *testing.T) {
+	t.Parallel()
+
+	newest := &cb.Block{
+		Header:   &cb.BlockHeader{Number: 5},
+		Metadata: &cb.BlockMetadata{Metadata: signaturesMetadata(t, 3)},
+	}
+
+	tests := []struct {
+		testName    string
+		noSigner    bool
+		streams     []*fakeDeliverStream
+		expectedErr string
+	}{
+		{
+			testName:    "when the signer is missing",
+			noSigner:    true,
+			expectedErr: "fetching newest block: signer is required",
+		},
+		{
+			testName:    "when the stream cannot be opened",
+			expectedErr: "fetching newest block: opening deliver stream: no more streams",
+		},
+		{
+			testName: "when receiving fails",
+			streams: []*fakeDeliverStream{
+				{err: errors.New("connection reset")},
+			},
+			expectedErr: "fetching newest block: receiving block: connection reset",
+		},
+		{
+			testName: "when the orderer responds with an error status",
+			streams: []*fakeDeliverStream{
+				{responses: []*ob.DeliverResponse{statusResponse(cb.Status_FORBIDDEN)}},
+			},
+			expectedErr: "fetching newest block: orderer responded with status FORBIDDEN",
+		},
+		{
+			testName: "when the orderer does not deliver a block",
+			streams: []*fakeDeliverStream{
+				{responses: []*ob.DeliverResponse{statusResponse(cb.Status_SUCCESS)}},
+			},
+			expectedErr: "fetching newest block: orderer did not deliver a block",
+		},
+		{
+			testName: "when the block metadata does not contain the last config index",
+			streams: []*fakeDeliverStream{
+				{responses: []*ob.DeliverResponse{
+					blockResponse(&cb.Block{Header: &cb.BlockHeader{Number: 5}}),
+					statusResponse(cb.Status_SUCCESS),
+				}},
+			},
+			expectedErr: "block metadata does not contain the index of the last config block",
+		},
+		{
+			testName: "when fetching the config block fails",
+			streams: []*fakeDeliverStream{
+				{responses: []*ob.DeliverResponse{blockResponse(newest), statusResponse(cb.Status_SUCCESS)}},
+				{responses: []*ob.DeliverResponse{statusResponse(cb.Status_NOT_FOUND)}},
+			},
+			expectedErr: "fetching config block 3: orderer responded with status NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			signer := newSigningIdentity(t, "Org1MSP")
+			if tt.noSigner {
+				signer = nil
+			}
+
+			fetcher := NewDeliverFetcher(&fakeDeliverer{streams: tt.streams}, signer)
+			_, err := fetcher.FetchConfigBlock(context.Background(), "testchannel")
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func newestPosition() *ob.SeekPosition {
+	return &ob.SeekPosition{Type: &ob.SeekPosition_Newest{Newest: &ob.SeekNewest{}}}
+}
+
+func specifiedPosition(number uint64) *ob.SeekPosition {
+	return &ob.SeekPosition{Type: &ob.SeekPosition_Specified{Specified: &ob.SeekSpecified{Number: number}}}
+}
+
+func blockResponse(block *cb.Block) *ob.DeliverResponse {
+	return &ob.DeliverResponse{Type: &ob.DeliverResponse_Block{Block: block}}
+}
+
+func statusResponse(status cb.Status) *ob.DeliverResponse {
+	return &ob.DeliverResponse{Type: &ob.DeliverResponse_Status{Status: status}}
+}
+
+// signaturesMetadata returns block metadata whose orderer block metadata
+// points at the last config block with the index.
+func signaturesMetadata(t *testing.T, index uint64) [][]byte {
+	metadata := make([][]byte, len(cb.BlockMetadataIndex_name))
+	metadata[cb.BlockMetadataIndex_SIGNATURES] = protoMarshal(t, &cb.Metadata{
+		Value: protoMarshal(t, &cb.OrdererBlockMetadata{LastConfig: &cb.LastConfig{Index: index}}),
+	})
+	return metadata
+}
+
+// lastConfigMetadata returns block metadata whose last config metadata
+// points at the last config block with the index.
+func lastConfigMetadata(t *testing.T, index uint64) [][]byte {
+	metadata := make([][]byte, len(cb.BlockMetadataIndex_name))
+	metadata[cb.BlockMetadataIndex_LAST_CONFIG] = protoMarshal(t, &cb.Metadata{
+		Value: protoMarshal(t, &cb.LastConfig{Index: index}),
+	})
+	return metadata
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	gt := NewGomegaWithT(t)
+
+	b, err := proto.Marshal(msg)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return b
+}
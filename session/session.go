@@ -0,0 +1,221 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package session combines fetching the config of a channel, editing it
+// with the configtx package, signing the config update and submitting it
+// to the ordering service into a single flow:
+//
+//	s := session.New("mychannel", fetcher, broadcast.NewClient(broadcaster),
+//		session.WithSigners(org1Admin, org2Admin))
+//	err := s.Update(ctx, func(c *configtx.ConfigTx) error {
+//		return c.Application().Organization("Org1").AddAnchorPeer(peer)
+//	})
+//
+// The transports are pluggable: config blocks are fetched by a Fetcher,
+// e.g. one returned by NewDeliverFetcher, config updates are submitted by a
+// Submitter, such as a broadcast.Client, and genesis blocks are joined to
+// ordering service nodes by Joiners, such as osnadmin clients.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/osnadmin"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Fetcher fetches the latest config block of a channel.
+type Fetcher interface {
+	FetchConfigBlock(ctx context.Context, channelID string) (*cb.Block, error)
+}
+
+// FetcherFunc is a function that implements Fetcher.
+type FetcherFunc func(ctx context.Context, channelID string) (*cb.Block, error)
+
+// FetchConfigBlock calls f(ctx, channelID).
+func (f FetcherFunc) FetchConfigBlock(ctx context.Context, channelID string) (*cb.Block, error) {
+	return f(ctx, channelID)
+}
+
+// Submitter submits envelopes to the ordering service. A broadcast.Client
+// implements it.
+type Submitter interface {
+	Submit(ctx context.Context, env *cb.Envelope) error
+}
+
+// Joiner joins an ordering service node to a channel with the channel's
+// genesis block. An osnadmin.Client implements it.
+type Joiner interface {
+	Join(configBlock *cb.Block) (osnadmin.ChannelInfo, error)
+}
+
+// Option configures a Session.
+type Option func(*Session)
+
+// WithSigners signs config updates with the signers, in order. The first
+// signer also signs the envelope of the update. By default updates are not
+// signed, which only suits transports that sign them themselves.
+func WithSigners(signers ...*configtx.SigningIdentity) Option {
+	return func(s *Session) {
+		s.signers = signers
+	}
+}
+
+// WithConfigTxOptions creates the ConfigTxs of the fetched configs and the
+// genesis blocks of created channels with the options.
+func WithConfigTxOptions(opts ...configtx.Option) Option {
+	return func(s *Session) {
+		s.configTxOptions = opts
+	}
+}
+
+// WithJoiners joins the ordering service nodes of the joiners to the
+// channels created with CreateChannel.
+func WithJoiners(joiners ...Joiner) Option {
+	return func(s *Session) {
+		s.joiners = joiners
+	}
+}
+
+// Session edits the config of a channel over the transports it was
+// created with.
+type Session struct {
+	channelID       string
+	fetcher         Fetcher
+	submitter       Submitter
+	joiners         []Joiner
+	signers         []*configtx.SigningIdentity
+	configTxOptions []configtx.Option
+}
+
+// New returns a session for the channel that fetches config blocks with
+// the fetcher and submits config updates with the submitter.
+func New(channelID string, fetcher Fetcher, submitter Submitter, opts ...Option) *Session {
+	s := &Session{
+		channelID: channelID,
+		fetcher:   fetcher,
+		submitter: submitter,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Fetch fetches the latest config block of the channel and returns a
+// ConfigTx to edit its config.
+func (s *Session) Fetch(ctx context.Context) (configtx.ConfigTx, error) {
+	if s.fetcher == nil {
+		return configtx.ConfigTx{}, errors.New("fetcher is required")
+	}
+
+	block, err := s.fetcher.FetchConfigBlock(ctx, s.channelID)
+	if err != nil {
+		return configtx.ConfigTx{}, fmt.Errorf("fetching config block of channel %s: %w", s.channelID, err)
+	}
+
+	config, err := configtx.ConfigFromBlock(block)
+	if err != nil {
+		return configtx.ConfigTx{}, fmt.Errorf("extracting config of channel %s: %w", s.channelID, err)
+	}
+
+	return configtx.New(config, s.configTxOptions...), nil
+}
+
+// Submit computes the config update of the ConfigTx, signs it with the
+// signers of the session and submits it to the ordering service.
+func (s *Session) Submit(ctx context.Context, c *configtx.ConfigTx) error {
+	if s.submitter == nil {
+		return errors.New("submitter is required")
+	}
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate(s.channelID)
+	if err != nil {
+		return fmt.Errorf("computing config update: %w", err)
+	}
+
+	var signatures []*cb.ConfigSignature
+	for _, signer := range s.signers {
+		signature, err := signer.CreateConfigSignature(marshaledUpdate)
+		if err != nil {
+			return fmt.Errorf("signing config update as %s: %w", signer.MSPID, err)
+		}
+		signatures = append(signatures, signature)
+	}
+
+	envelope, err := c.NewEnvelope(marshaledUpdate, signatures...)
+	if err != nil {
+		return fmt.Errorf("creating envelope: %w", err)
+	}
+
+	if len(s.signers) > 0 {
+		err = s.signers[0].SignEnvelope(envelope)
+		if err != nil {
+			return fmt.Errorf("signing envelope as %s: %w", s.signers[0].MSPID, err)
+		}
+	}
+
+	err = s.submitter.Submit(ctx, envelope)
+	if err != nil {
+		return fmt.Errorf("submitting config update to channel %s: %w", s.channelID, err)
+	}
+
+	return nil
+}
+
+// Update fetches the config of the channel, edits it with edit and
+// submits the resulting config update. Nothing is submitted if edit
+// returns an error.
+func (s *Session) Update(ctx context.Context, edit func(c *configtx.ConfigTx) error) error {
+	c, err := s.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = edit(&c)
+	if err != nil {
+		return fmt.Errorf("editing config of channel %s: %w", s.channelID, err)
+	}
+
+	return s.Submit(ctx, &c)
+}
+
+// CreateChannel creates the genesis block of the channel from the channel
+// configuration and joins the ordering service nodes of the joiners of the
+// session to it, in order. It returns the genesis block and the status of
+// the channel on each joined node. Joining stops at the first failure or
+// when ctx is done.
+func (s *Session) CreateChannel(ctx context.Context, channel configtx.Channel) (*cb.Block, []osnadmin.ChannelInfo, error) {
+	if len(s.joiners) == 0 {
+		return nil, nil, errors.New("joiners are required")
+	}
+
+	block, err := configtx.NewApplicationChannelGenesisBlock(channel, s.channelID, s.configTxOptions...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var infos []osnadmin.ChannelInfo
+	for i, joiner := range s.joiners {
+		err := ctx.Err()
+		if err != nil {
+			return block, infos, err
+		}
+
+		info, err := joiner.Join(block)
+		if err != nil {
+			return block, infos, fmt.Errorf("joining orderer %d to channel %s: %w", i+1, s.channelID, err)
+		}
+		infos = append(infos, info)
+	}
+
+	return block, infos, nil
+}
@@ -0,0 +1,359 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package session
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/osnadmin"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	mb "github.com/hyperledger/fabric-protos-go/msp"
+	. "github.com/onsi/gomega"
+)
+
+// fakeSubmitter records submitted envelopes, or fails with err if it is
+// not nil.
+type fakeSubmitter struct {
+	err       error
+	submitted []*cb.Envelope
+}
+
+func (s *fakeSubmitter) Submit(ctx context.Context, env *cb.Envelope) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.submitted = append(s.submitted, env)
+	return nil
+}
+
+// fakeJoiner records joined blocks and responds with info, or fails with
+// err if it is not nil.
+type fakeJoiner struct {
+	info   osnadmin.ChannelInfo
+	err    error
+	joined []*cb.Block
+}
+
+func (j *fakeJoiner) Join(configBlock *cb.Block) (osnadmin.ChannelInfo, error) {
+	if j.err != nil {
+		return osnadmin.ChannelInfo{}, j.err
+	}
+	j.joined = append(j.joined, configBlock)
+	return j.info, nil
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	block := genesisBlock(t)
+	var fetchedChannelID string
+	fetcher := FetcherFunc(func(ctx context.Context, channelID string) (*cb.Block, error) {
+		fetchedChannelID = channelID
+		return block, nil
+	})
+	submitter := &fakeSubmitter{}
+	signer1 := newSigningIdentity(t, "Org1MSP")
+	signer2 := newSigningIdentity(t, "Org2MSP")
+
+	s := New("testchannel", fetcher, submitter, WithSigners(signer1, signer2))
+	err := s.Update(context.Background(), func(c *configtx.ConfigTx) error {
+		return c.Orderer().SetBatchTimeout(time.Second)
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fetchedChannelID).To(Equal("testchannel"))
+	gt.Expect(submitter.submitted).To(HaveLen(1))
+
+	envelope := submitter.submitted[0]
+	gt.Expect(envelope.Signature).NotTo(BeEmpty())
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signatureHeader := &cb.SignatureHeader{}
+	err = proto.Unmarshal(payload.Header.SignatureHeader, signatureHeader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	creator := &mb.SerializedIdentity{}
+	err = proto.Unmarshal(signatureHeader.Creator, creator)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(creator.Mspid).To(Equal("Org1MSP"))
+
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+	err = proto.Unmarshal(payload.Data, configUpdateEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(configUpdateEnvelope.Signatures).To(HaveLen(2))
+
+	configUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, configUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(configUpdate.ChannelId).To(Equal("testchannel"))
+	gt.Expect(configUpdate.WriteSet.Groups).To(HaveKey(configtx.OrdererGroupKey))
+}
+
+func TestUpdateFailures(t *testing.T) {
+	t.Parallel()
+
+	block := genesisBlock(t)
+	fetcher := FetcherFunc(func(ctx context.Context, channelID string) (*cb.Block, error) {
+		return block, nil
+	})
+	edit := func(c *configtx.ConfigTx) error {
+		return c.Orderer().SetBatchTimeout(time.Second)
+	}
+
+	tests := []struct {
+		testName    string
+		fetcher     Fetcher
+		submitter   *fakeSubmitter
+		edit        func(c *configtx.ConfigTx) error
+		expectedErr string
+	}{
+		{
+			testName:    "when the fetcher is missing",
+			submitter:   &fakeSubmitter{},
+			edit:        edit,
+			expectedErr: "fetcher is required",
+		},
+		{
+			testName: "when fetching fails",
+			fetcher: FetcherFunc(func(ctx context.Context, channelID string) (*cb.Block, error) {
+				return nil, errors.New("unavailable")
+			}),
+			submitter:   &fakeSubmitter{},
+			edit:        edit,
+			expectedErr: "fetching config block of channel testchannel: unavailable",
+		},
+		{
+			testName: "when the block does not contain a config",
+			fetcher: FetcherFunc(func(ctx context.Context, channelID string) (*cb.Block, error) {
+				return &cb.Block{}, nil
+			}),
+			submitter:   &fakeSubmitter{},
+			edit:        edit,
+			expectedErr: "extracting config of channel testchannel: block contains no data",
+		},
+		{
+			testName:  "when editing fails",
+			fetcher:   fetcher,
+			submitter: &fakeSubmitter{},
+			edit: func(c *configtx.ConfigTx) error {
+				return errors.New("bad edit")
+			},
+			expectedErr: "editing config of channel testchannel: bad edit",
+		},
+		{
+			testName:  "when the config is not edited",
+			fetcher:   fetcher,
+			submitter: &fakeSubmitter{},
+			edit: func(c *configtx.ConfigTx) error {
+				return nil
+			},
+			expectedErr: "computing config update: failed to compute update: no differences detected between original and updated config",
+		},
+		{
+			testName:    "when submitting fails",
+			fetcher:     fetcher,
+			submitter:   &fakeSubmitter{err: errors.New("SERVICE_UNAVAILABLE")},
+			edit:        edit,
+			expectedErr: "submitting config update to channel testchannel: SERVICE_UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			s := New("testchannel", tt.fetcher, tt.submitter)
+			err := s.Update(context.Background(), tt.edit)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(tt.submitter.submitted).To(BeEmpty())
+		})
+	}
+}
+
+func TestSubmitWithoutSubmitter(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	s := New("testchannel", nil, nil)
+	c := configtx.New(&cb.Config{})
+	err := s.Submit(context.Background(), &c)
+	gt.Expect(err).To(MatchError("submitter is required"))
+}
+
+func TestCreateChannel(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	joiner1 := &fakeJoiner{info: osnadmin.ChannelInfo{Name: "testchannel", Status: "active"}}
+	joiner2 := &fakeJoiner{info: osnadmin.ChannelInfo{Name: "testchannel", Status: "onboarding"}}
+
+	s := New("testchannel", nil, nil, WithJoiners(joiner1, joiner2))
+	block, infos, err := s.CreateChannel(context.Background(), baseProfile())
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(infos).To(Equal([]osnadmin.ChannelInfo{joiner1.info, joiner2.info}))
+	gt.Expect(joiner1.joined).To(Equal([]*cb.Block{block}))
+	gt.Expect(joiner2.joined).To(Equal([]*cb.Block{block}))
+
+	config, err := configtx.ConfigFromBlock(block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(config.ChannelGroup.Groups).To(HaveKey(configtx.ApplicationGroupKey))
+}
+
+func TestCreateChannelFailures(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		testName      string
+		ctx           context.Context
+		channel       configtx.Channel
+		joiners       []*fakeJoiner
+		expectedInfos int
+		expectedErr   string
+	}{
+		{
+			testName:    "when there are no joiners",
+			ctx:         context.Background(),
+			channel:     baseProfile(),
+			expectedErr: "joiners are required",
+		},
+		{
+			testName:    "when the channel is invalid",
+			ctx:         context.Background(),
+			channel:     configtx.Channel{},
+			joiners:     []*fakeJoiner{{}},
+			expectedErr: "creating application channel group: setting channel policies: no policies defined",
+		},
+		{
+			testName: "when joining fails",
+			ctx:      context.Background(),
+			channel:  baseProfile(),
+			joiners: []*fakeJoiner{
+				{info: osnadmin.ChannelInfo{Name: "testchannel"}},
+				{err: errors.New("channel already exists")},
+			},
+			expectedInfos: 1,
+			expectedErr:   "joining orderer 2 to channel testchannel: channel already exists",
+		},
+		{
+			testName:    "when the context is done",
+			ctx:         canceled,
+			channel:     baseProfile(),
+			joiners:     []*fakeJoiner{{}},
+			expectedErr: context.Canceled.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			var joiners []Joiner
+			for _, joiner := range tt.joiners {
+				joiners = append(joiners, joiner)
+			}
+
+			s := New("testchannel", nil, nil, WithJoiners(joiners...))
+			_, infos, err := s.CreateChannel(tt.ctx, tt.channel)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(infos).To(HaveLen(tt.expectedInfos))
+		})
+	}
+}
+
+// baseProfile returns the configuration of an application channel without
+// organizations.
+func baseProfile() configtx.Channel {
+	return configtx.Channel{
+		Capabilities: []string{"V2_0"},
+		Policies:     implicitMetaPolicies(),
+		Orderer: configtx.Orderer{
+			OrdererType:  orderer.ConsensusTypeSolo,
+			BatchTimeout: 2 * time.Second,
+			BatchSize: orderer.BatchSize{
+				MaxMessageCount:   10,
+				AbsoluteMaxBytes:  100,
+				PreferredMaxBytes: 100,
+			},
+			Policies: func() map[string]configtx.Policy {
+				policies := implicitMetaPolicies()
+				policies[configtx.BlockValidationPolicyKey] = configtx.Policy{
+					Type: configtx.ImplicitMetaPolicyType,
+					Rule: "ANY Writers",
+				}
+				return policies
+			}(),
+			State: orderer.ConsensusStateNormal,
+		},
+		Application: configtx.Application{
+			Capabilities: []string{"V2_0"},
+			Policies:     implicitMetaPolicies(),
+		},
+	}
+}
+
+func implicitMetaPolicies() map[string]configtx.Policy {
+	return map[string]configtx.Policy{
+		configtx.ReadersPolicyKey: {Type: configtx.ImplicitMetaPolicyType, Rule: "ANY Readers"},
+		configtx.WritersPolicyKey: {Type: configtx.ImplicitMetaPolicyType, Rule: "ANY Writers"},
+		configtx.AdminsPolicyKey:  {Type: configtx.ImplicitMetaPolicyType, Rule: "MAJORITY Admins"},
+	}
+}
+
+func genesisBlock(t *testing.T) *cb.Block {
+	gt := NewGomegaWithT(t)
+
+	block, err := configtx.NewApplicationChannelGenesisBlock(baseProfile(), "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return block
+}
+
+func newSigningIdentity(t *testing.T, mspID string) *configtx.SigningIdentity {
+	gt := NewGomegaWithT(t)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "admin.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, template, &privKey.PublicKey, privKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	cert, err := x509.ParseCertificate(derBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return &configtx.SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privKey,
+		MSPID:       mspID,
+	}
+}